@@ -0,0 +1,221 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// envPrefix prefixes the environment variables setting the flags:
+	// the -tls-min-version flag is set by PORTAINER_MCP_TLS_MIN_VERSION
+	envPrefix = "PORTAINER_MCP_"
+
+	// configFlagName is the flag holding the path of the configuration file
+	configFlagName = "config"
+)
+
+// Sources of the value of a flag
+const (
+	sourceDefault     = "default"
+	sourceCommandLine = "command line"
+	sourceEnvironment = "environment"
+	sourceConfigFile  = "config file"
+)
+
+// sourcePrecedence ranks the sources of the value of a flag, the higher
+// ranks taking precedence
+var sourcePrecedence = map[string]int{
+	sourceDefault:     0,
+	sourceConfigFile:  1,
+	sourceEnvironment: 2,
+	sourceCommandLine: 3,
+}
+
+// applyConfiguration sets the flags that were not passed on the command line
+// from the environment variables and the configuration file.
+//
+// The value of a flag is taken, in order of precedence, from:
+//   - the command line
+//   - the PORTAINER_MCP_<NAME> environment variable, NAME being the flag name
+//     in upper case with dashes replaced by underscores
+//   - the configuration file set with -config (or PORTAINER_MCP_CONFIG), a
+//     YAML document whose keys are the flag names
+//   - the default value of the flag
+//
+// Parameters:
+//   - fs: The parsed flag set
+//   - lookupEnv: The function looking up environment variables, such as os.LookupEnv
+//
+// Returns:
+//   - The source of the value of every flag
+//   - An error if the configuration file cannot be read, has an unknown key, or
+//     if a value from the environment or the file is invalid
+func applyConfiguration(fs *flag.FlagSet, lookupEnv func(string) (string, bool)) (map[string]string, error) {
+	sources := map[string]string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		sources[f.Name] = sourceDefault
+	})
+	fs.Visit(func(f *flag.Flag) {
+		sources[f.Name] = sourceCommandLine
+	})
+
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		if envErr != nil || sources[f.Name] != sourceDefault {
+			return
+		}
+
+		name := envVarName(f.Name)
+		value, ok := lookupEnv(name)
+		if !ok {
+			return
+		}
+
+		if err := fs.Set(f.Name, value); err != nil {
+			envErr = fmt.Errorf("invalid value %q for environment variable %s: %w", value, name, err)
+			return
+		}
+		sources[f.Name] = sourceEnvironment
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+
+	configFlag := fs.Lookup(configFlagName)
+	if configFlag == nil || configFlag.Value.String() == "" {
+		return sources, nil
+	}
+
+	values, err := loadConfigFile(configFlag.Value.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys are applied in a stable order so that the first error is deterministic
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == configFlagName || fs.Lookup(key) == nil {
+			return nil, fmt.Errorf("unknown key %q in configuration file %s", key, configFlag.Value.String())
+		}
+
+		if sources[key] != sourceDefault {
+			continue
+		}
+
+		if err := fs.Set(key, values[key]); err != nil {
+			return nil, fmt.Errorf("invalid value %q for key %q in configuration file %s: %w", values[key], key, configFlag.Value.String(), err)
+		}
+		sources[key] = sourceConfigFile
+	}
+
+	return sources, nil
+}
+
+// loadConfigFile reads a YAML configuration file into flag values.
+// Lists are joined with commas, matching the comma-separated list flags.
+func loadConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(document))
+	for key, value := range document {
+		switch v := value.(type) {
+		case nil:
+			values[key] = ""
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if !isScalar(item) {
+					return nil, fmt.Errorf("invalid value for key %q in configuration file %s: lists must only contain scalar values", key, path)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			values[key] = strings.Join(items, ",")
+		default:
+			if !isScalar(v) {
+				return nil, fmt.Errorf("invalid value for key %q in configuration file %s: expected a scalar value or a list", key, path)
+			}
+			values[key] = fmt.Sprint(v)
+		}
+	}
+
+	return values, nil
+}
+
+// isScalar checks if a decoded YAML value is a string, a number or a boolean
+func isScalar(value any) bool {
+	switch value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// envVarName returns the environment variable setting a flag
+func envVarName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// readTokenFile reads the Portainer API token from a file, ignoring surrounding whitespace
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+
+	return token, nil
+}
+
+// resolveToken returns the Portainer API token, set with -token or read from
+// -token-file. When both flags are set, the one whose value comes from the
+// source of higher precedence is used, so that PORTAINER_MCP_TOKEN overrides
+// a token-file key of the configuration file.
+//
+// Parameters:
+//   - token: The value of the -token flag
+//   - tokenFile: The value of the -token-file flag
+//   - sources: The source of the value of every flag, as returned by applyConfiguration
+//
+// Returns:
+//   - The API token, empty when neither flag is set
+//   - An error if both flags are set from the same source or the token file cannot be read
+func resolveToken(token, tokenFile string, sources map[string]string) (string, error) {
+	if tokenFile == "" {
+		return token, nil
+	}
+
+	if token != "" {
+		tokenSource, tokenFileSource := sources["token"], sources["token-file"]
+		if tokenSource == tokenFileSource {
+			return "", fmt.Errorf("token and token-file are mutually exclusive, both are set from the %s", tokenSource)
+		}
+		if sourcePrecedence[tokenSource] > sourcePrecedence[tokenFileSource] {
+			return token, nil
+		}
+	}
+
+	return readTokenFile(tokenFile)
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFlags are the flags of a test flag set
+type testFlags struct {
+	server         *string
+	token          *string
+	readOnly       *bool
+	requestTimeout *time.Duration
+	allowTools     *string
+}
+
+func newTestFlagSet() (*flag.FlagSet, *testFlags) {
+	fs := flag.NewFlagSet("portainer-mcp", flag.ContinueOnError)
+	fs.String(configFlagName, "", "")
+
+	return fs, &testFlags{
+		server:         fs.String("server", "", ""),
+		token:          fs.String("token", "", ""),
+		readOnly:       fs.Bool("read-only", false, ""),
+		requestTimeout: fs.Duration("request-timeout", 2*time.Minute, ""),
+		allowTools:     fs.String("allow-tools", "", ""),
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestApplyConfigurationPrecedence(t *testing.T) {
+	configPath := writeConfigFile(t, `
+server: https://from-file:9443
+token: file-token
+read-only: true
+request-timeout: 30s
+allow-tools:
+  - list*
+  - alerting
+`)
+
+	fs, flags := newTestFlagSet()
+	require.NoError(t, fs.Parse([]string{"-config", configPath, "-server", "https://from-flag:9443"}))
+
+	env := map[string]string{
+		"PORTAINER_MCP_SERVER": "https://from-env:9443",
+		"PORTAINER_MCP_TOKEN":  "env-token",
+	}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	sources, err := applyConfiguration(fs, lookupEnv)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://from-flag:9443", *flags.server)
+	assert.Equal(t, "env-token", *flags.token)
+	assert.True(t, *flags.readOnly)
+	assert.Equal(t, 30*time.Second, *flags.requestTimeout)
+	assert.Equal(t, "list*,alerting", *flags.allowTools)
+
+	assert.Equal(t, map[string]string{
+		configFlagName:    sourceCommandLine,
+		"server":          sourceCommandLine,
+		"token":           sourceEnvironment,
+		"read-only":       sourceConfigFile,
+		"request-timeout": sourceConfigFile,
+		"allow-tools":     sourceConfigFile,
+	}, sources)
+}
+
+func TestApplyConfigurationFromEnvironmentConfigPath(t *testing.T) {
+	configPath := writeConfigFile(t, "server: https://from-file:9443\n")
+
+	fs, flags := newTestFlagSet()
+	require.NoError(t, fs.Parse(nil))
+
+	lookupEnv := func(name string) (string, bool) {
+		if name == "PORTAINER_MCP_CONFIG" {
+			return configPath, true
+		}
+		return "", false
+	}
+
+	_, err := applyConfiguration(fs, lookupEnv)
+	require.NoError(t, err)
+	assert.Equal(t, "https://from-file:9443", *flags.server)
+}
+
+func TestApplyConfigurationErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        string
+		env           map[string]string
+		errorContains string
+	}{
+		{
+			name:          "unknown key",
+			config:        "sever: https://portainer:9443\n",
+			errorContains: `unknown key "sever"`,
+		},
+		{
+			name:          "nested config file",
+			config:        "config: other.yaml\n",
+			errorContains: `unknown key "config"`,
+		},
+		{
+			name:          "invalid value in file",
+			config:        "request-timeout: soon\n",
+			errorContains: `invalid value "soon" for key "request-timeout"`,
+		},
+		{
+			name:          "map value",
+			config:        "server:\n  url: https://portainer:9443\n",
+			errorContains: "expected a scalar value or a list",
+		},
+		{
+			name:          "invalid YAML",
+			config:        "server: [",
+			errorContains: "failed to parse configuration file",
+		},
+		{
+			name:          "invalid environment value",
+			env:           map[string]string{"PORTAINER_MCP_READ_ONLY": "maybe"},
+			errorContains: `invalid value "maybe" for environment variable PORTAINER_MCP_READ_ONLY`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, _ := newTestFlagSet()
+
+			var args []string
+			if tt.config != "" {
+				args = []string{"-config", writeConfigFile(t, tt.config)}
+			}
+			require.NoError(t, fs.Parse(args))
+
+			lookupEnv := func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+
+			_, err := applyConfiguration(fs, lookupEnv)
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+}
+
+func TestReadTokenFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(path, []byte("ptr_secret\n"), 0600))
+
+	token, err := readTokenFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "ptr_secret", token)
+
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0600))
+
+	_, err = readTokenFile(empty)
+	assert.ErrorContains(t, err, "is empty")
+
+	_, err = readTokenFile(filepath.Join(dir, "missing"))
+	assert.ErrorContains(t, err, "failed to read token file")
+}
+
+func TestResolveToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("file-token\n"), 0600))
+
+	tests := []struct {
+		name            string
+		token           string
+		tokenFile       string
+		tokenSource     string
+		tokenFileSource string
+		expectedToken   string
+		expectedError   string
+	}{
+		{
+			name:          "token only",
+			token:         "flag-token",
+			tokenSource:   sourceCommandLine,
+			expectedToken: "flag-token",
+		},
+		{
+			name:            "token file only",
+			tokenFile:       path,
+			tokenFileSource: sourceConfigFile,
+			expectedToken:   "file-token",
+		},
+		{
+			name:          "neither",
+			expectedToken: "",
+		},
+		{
+			name:            "environment token overrides configuration file token file",
+			token:           "env-token",
+			tokenFile:       path,
+			tokenSource:     sourceEnvironment,
+			tokenFileSource: sourceConfigFile,
+			expectedToken:   "env-token",
+		},
+		{
+			name:            "command line token file overrides environment token",
+			token:           "env-token",
+			tokenFile:       path,
+			tokenSource:     sourceEnvironment,
+			tokenFileSource: sourceCommandLine,
+			expectedToken:   "file-token",
+		},
+		{
+			name:            "both from the same source",
+			token:           "env-token",
+			tokenFile:       path,
+			tokenSource:     sourceEnvironment,
+			tokenFileSource: sourceEnvironment,
+			expectedError:   "mutually exclusive, both are set from the environment",
+		},
+		{
+			name:            "missing token file",
+			tokenFile:       filepath.Join(t.TempDir(), "missing"),
+			tokenFileSource: sourceCommandLine,
+			expectedError:   "failed to read token file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources := map[string]string{
+				"token":      sourceDefault,
+				"token-file": sourceDefault,
+			}
+			if tt.tokenSource != "" {
+				sources["token"] = tt.tokenSource
+			}
+			if tt.tokenFileSource != "" {
+				sources["token-file"] = tt.tokenFileSource
+			}
+
+			token, err := resolveToken(tt.token, tt.tokenFile, sources)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedToken, token)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/portainer/portainer-mcp/internal/mcp"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
+	"gopkg.in/yaml.v3"
+)
+
+// instanceConfig is an additional Portainer instance of the instances file.
+// Its keys match the flags configuring the default instance.
+type instanceConfig struct {
+	Name                string `yaml:"name"`
+	Server              string `yaml:"server"`
+	Token               string `yaml:"token"`
+	TokenFile           string `yaml:"token-file"`
+	DisableVersionCheck bool   `yaml:"disable-version-check"`
+	Insecure            bool   `yaml:"insecure"`
+	CACert              string `yaml:"ca-cert"`
+	ClientCert          string `yaml:"client-cert"`
+	ClientKey           string `yaml:"client-key"`
+	TLSMinVersion       string `yaml:"tls-min-version"`
+}
+
+// instancesFile is the YAML document set with the -instances flag
+type instancesFile struct {
+	Instances []instanceConfig `yaml:"instances"`
+}
+
+// loadInstancesFile reads the additional Portainer instances from a YAML file.
+// The token of an instance is read from its token file when one is set.
+func loadInstancesFile(path string) ([]instanceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read instances file: %w", err)
+	}
+
+	var document instancesFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to parse instances file %s: %w", path, err)
+	}
+
+	for i := range document.Instances {
+		instance := &document.Instances[i]
+
+		if instance.Name == "" {
+			return nil, fmt.Errorf("instance %d of %s: name is required", i, path)
+		}
+		if instance.Server == "" {
+			return nil, fmt.Errorf("instance %s: server is required", instance.Name)
+		}
+		if (instance.ClientCert == "") != (instance.ClientKey == "") {
+			return nil, fmt.Errorf("instance %s: client-cert and client-key must be provided together", instance.Name)
+		}
+
+		if instance.TokenFile != "" {
+			if instance.Token != "" {
+				return nil, fmt.Errorf("instance %s: token and token-file are mutually exclusive", instance.Name)
+			}
+
+			instance.Token, err = readTokenFile(instance.TokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("instance %s: %w", instance.Name, err)
+			}
+		}
+
+		if instance.Token == "" {
+			return nil, fmt.Errorf("instance %s: token or token-file is required", instance.Name)
+		}
+	}
+
+	return document.Instances, nil
+}
+
+// instance returns the Portainer instance managed by the MCP server
+func (c instanceConfig) instance() mcp.Instance {
+	return mcp.Instance{
+		Name:                c.Name,
+		ServerURL:           c.Server,
+		Token:               c.Token,
+		DisableVersionCheck: c.DisableVersionCheck,
+		ClientOptions: []client.ClientOption{
+			client.WithSkipTLSVerify(c.Insecure),
+			client.WithCACertFile(c.CACert),
+			client.WithClientCertificate(c.ClientCert, c.ClientKey),
+			client.WithMinTLSVersion(c.TLSMinVersion),
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadInstancesFile(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("ptr_edge\n"), 0600))
+
+	path := writeConfigFile(t, `
+instances:
+  - name: edge
+    server: https://edge.example.com:9443
+    token-file: `+tokenPath+`
+    insecure: true
+  - name: staging
+    server: https://staging.example.com:9443
+    token: ptr_staging
+    disable-version-check: true
+`)
+
+	instances, err := loadInstancesFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []instanceConfig{
+		{
+			Name:      "edge",
+			Server:    "https://edge.example.com:9443",
+			Token:     "ptr_edge",
+			TokenFile: tokenPath,
+			Insecure:  true,
+		},
+		{
+			Name:                "staging",
+			Server:              "https://staging.example.com:9443",
+			Token:               "ptr_staging",
+			DisableVersionCheck: true,
+		},
+	}, instances)
+
+	instance := instances[1].instance()
+	assert.Equal(t, "staging", instance.Name)
+	assert.Equal(t, "https://staging.example.com:9443", instance.ServerURL)
+	assert.Equal(t, "ptr_staging", instance.Token)
+	assert.True(t, instance.DisableVersionCheck)
+}
+
+func TestLoadInstancesFileErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{
+			name:          "unknown key",
+			content:       "instances:\n  - name: edge\n    url: https://edge.example.com\n",
+			errorContains: "field url not found",
+		},
+		{
+			name:          "missing name",
+			content:       "instances:\n  - server: https://edge.example.com\n    token: ptr_edge\n",
+			errorContains: "name is required",
+		},
+		{
+			name:          "missing server",
+			content:       "instances:\n  - name: edge\n    token: ptr_edge\n",
+			errorContains: "instance edge: server is required",
+		},
+		{
+			name:          "missing token",
+			content:       "instances:\n  - name: edge\n    server: https://edge.example.com\n",
+			errorContains: "instance edge: token or token-file is required",
+		},
+		{
+			name:          "token and token file",
+			content:       "instances:\n  - name: edge\n    server: https://edge.example.com\n    token: ptr_edge\n    token-file: token\n",
+			errorContains: "token and token-file are mutually exclusive",
+		},
+		{
+			name:          "client certificate without key",
+			content:       "instances:\n  - name: edge\n    server: https://edge.example.com\n    token: ptr_edge\n    client-cert: cert.pem\n",
+			errorContains: "client-cert and client-key must be provided together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadInstancesFile(writeConfigFile(t, tt.content))
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+
+	_, err := loadInstancesFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read instances file")
+}
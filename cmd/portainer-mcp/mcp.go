@@ -1,14 +1,30 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/portainer/portainer-mcp/internal/mcp"
 	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-const defaultToolsPath = "tools.yaml"
+const (
+	defaultToolsPath          = "tools.yaml"
+	defaultAuditLogMaxSize    = 100
+	defaultAuditLogMaxBackups = 5
+	tracingShutdownTimeout    = 5 * time.Second
+)
 
 var (
 	Version   string
@@ -17,22 +33,110 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "tools" {
+		os.Exit(runToolsCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	log.Info().
 		Str("version", Version).
 		Str("build-date", BuildDate).
 		Str("commit", Commit).
 		Msg("Portainer MCP server")
 
+	configFlag := flag.String(configFlagName, "", "The path to a YAML configuration file whose keys are the flag names")
 	serverFlag := flag.String("server", "", "The Portainer server URL")
-	tokenFlag := flag.String("token", "", "The authentication token for the Portainer server")
+	tokenFlag := flag.String("token", "", "The authentication token for the Portainer server (prefer -token-file or the PORTAINER_MCP_TOKEN environment variable, command line arguments are visible to other users)")
+	tokenFileFlag := flag.String("token-file", "", "The path to a file containing the authentication token for the Portainer server")
+	instancesFlag := flag.String("instances", "", "The path to a YAML file of additional Portainer instances managed by the server")
+	instanceNameFlag := flag.String("instance-name", mcp.DefaultInstanceName, "The name of the Portainer instance set with -server, when additional instances are managed")
 	toolsFlag := flag.String("tools", "", "The path to the tools YAML file")
+	watchToolsFlag := flag.Bool("watch-tools", false, "Reload the tools file when it changes, the tools file is also reloaded on SIGHUP")
 	readOnlyFlag := flag.Bool("read-only", false, "Run in read-only mode")
+	allowToolsFlag := flag.String("allow-tools", "", "Comma-separated glob patterns of the tools or tool groups to register, all other tools are skipped")
+	denyToolsFlag := flag.String("deny-tools", "", "Comma-separated glob patterns of the tools or tool groups never to register")
+	toolFilterFlag := flag.String("tool-filter", "", "The path to a YAML file with allow and deny lists of tool patterns")
+	proxyPolicyFlag := flag.String("proxy-policy", "", "The path to a YAML policy file restricting the requests of the Docker and Kubernetes proxy tools")
+	scopeEnvironmentsFlag := flag.String("scope-environments", "", "Comma-separated IDs of the environments the server is restricted to")
+	scopeTagsFlag := flag.String("scope-tags", "", "Comma-separated tag IDs, restricting the server to the environments carrying any of these tags")
+	scopeEnvironmentGroupsFlag := flag.String("scope-environment-groups", "", "Comma-separated environment (edge) group IDs, restricting the server to the environments of these groups")
+	scopeAccessGroupsFlag := flag.String("scope-access-groups", "", "Comma-separated access (endpoint) group IDs, restricting the server to the environments of these groups")
+	confirmDestructiveFlag := flag.Bool("confirm-destructive", false, "Require destructive tool calls to be confirmed, through MCP elicitation when supported by the client or with a confirmation token")
+	dryRunFlag := flag.Bool("dry-run", false, "Return the planned Portainer API requests of write tools instead of sending them")
 	disableVersionCheckFlag := flag.Bool("disable-version-check", false, "Disable Portainer server version check")
+	transportFlag := flag.String("transport", mcp.TransportStdio, "The transport used to serve MCP clients (stdio, sse or http)")
+	listenFlag := flag.String("listen", mcp.DefaultListenAddr, "The listen address for the sse and http transports")
+	basePathFlag := flag.String("base-path", "", "The URL path prefix for the sse and http transport endpoints")
+	sessionTokenModeFlag := flag.String("session-token-mode", mcp.SessionTokenDisabled, "Whether MCP sessions can authenticate with their own Portainer API key sent in the X-Portainer-API-Key header (disabled, optional or required)")
+	metricsListenFlag := flag.String("metrics-listen", "", "The listen address of the Prometheus metrics endpoint, disabled when empty (e.g. :9090)")
+	requestTimeoutFlag := flag.Duration("request-timeout", mcp.DefaultRequestTimeout, "The maximum duration of a tool call, including its Portainer API requests (0 disables the limit)")
+	cacheTTLFlag := flag.Duration("cache-ttl", 0, "Cache the Portainer API list responses for this duration, such as 30s (0 disables the cache)")
+	cacheMethodTTLsFlag := flag.String("cache-method-ttls", "", "Comma-separated Method=duration overrides of -cache-ttl for some client methods, such as GetEnvironments=10s,GetUsers=0")
+	maxResponseBytesFlag := flag.Int("max-response-bytes", 0, "Truncate the tool results larger than this size in bytes, the rest being read with the readResultPage tool (0 disables the limit)")
+	toolMaxResponseBytesFlag := flag.String("tool-max-response-bytes", "", "Comma-separated tool=bytes overrides of -max-response-bytes for some tools, such as dockerProxy=65536,getSettings=0")
+	outputFormatFlag := flag.String("output-format", toolgen.OutputFormatJSON, "The default format of the text of the read tool results (json, yaml, table or csv)")
+	maxRetriesFlag := flag.Int("max-retries", client.DefaultMaxRetries, "The maximum number of retries of idempotent Portainer API requests failing with a network error, a 429 or a 5xx status (0 disables retries)")
+	insecureFlag := flag.Bool("insecure", false, "Skip verification of the Portainer server TLS certificate (not recommended)")
+	caCertFlag := flag.String("ca-cert", "", "The path to a PEM encoded CA bundle used to verify the Portainer server certificate")
+	clientCertFlag := flag.String("client-cert", "", "The path to a PEM encoded client certificate for mutual TLS with the Portainer server")
+	clientKeyFlag := flag.String("client-key", "", "The path to the PEM encoded private key of the client certificate")
+	tlsMinVersionFlag := flag.String("tls-min-version", "", "The minimum TLS version used to connect to the Portainer server (1.0, 1.1, 1.2 or 1.3)")
+	auditLogFlag := flag.String("audit-log", "", "Record every tool call as JSON lines to this file, or to stderr when set to \"stderr\"")
+	auditLogMaxSizeFlag := flag.Int("audit-log-max-size", defaultAuditLogMaxSize, "The maximum size in megabytes of the audit log file before it is rotated")
+	auditLogMaxBackupsFlag := flag.Int("audit-log-max-backups", defaultAuditLogMaxBackups, "The maximum number of rotated audit log files to keep")
+	auditMutationsOnlyFlag := flag.Bool("audit-mutations-only", false, "Only audit tools that are not annotated as read-only")
 
 	flag.Parse()
 
-	if *serverFlag == "" || *tokenFlag == "" {
-		log.Fatal().Msg("Both -server and -token flags are required")
+	sources, err := applyConfiguration(flag.CommandLine, os.LookupEnv)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid configuration")
+	}
+
+	if *configFlag != "" {
+		log.Info().Str("config", *configFlag).Msg("loaded configuration file")
+	}
+
+	if *serverFlag == "" {
+		log.Fatal().Msg("The -server flag is required")
+	}
+
+	*tokenFlag, err = resolveToken(*tokenFlag, *tokenFileFlag, sources)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to resolve the Portainer API token")
+	}
+
+	if sources["token"] == sourceCommandLine {
+		log.Warn().Msg("the -token flag exposes the API token to other users of this host, prefer -token-file or the PORTAINER_MCP_TOKEN environment variable")
+	}
+
+	if *sessionTokenModeFlag != mcp.SessionTokenDisabled && *transportFlag == mcp.TransportStdio {
+		log.Fatal().Msg("The -session-token-mode flag requires the sse or http transport")
+	}
+
+	if (*clientCertFlag == "") != (*clientKeyFlag == "") {
+		log.Fatal().Msg("The -client-cert and -client-key flags must be provided together")
+	}
+
+	if *dryRunFlag && *readOnlyFlag {
+		log.Warn().Msg("the -dry-run flag has no effect in read-only mode, write tools are not loaded")
+	}
+
+	if *maxRetriesFlag < 0 {
+		log.Fatal().Msg("The -max-retries flag must not be negative")
+	}
+
+	if *insecureFlag {
+		log.Warn().Msg("TLS certificate verification of the Portainer server is disabled")
+	}
+
+	// With required session tokens, every tool call is authenticated with the
+	// caller's own API key and the server token is only used for the version check
+	if *tokenFlag == "" {
+		if *sessionTokenModeFlag != mcp.SessionTokenRequired {
+			log.Fatal().Msg("The -token flag is required unless -session-token-mode is required")
+		}
+		log.Warn().Msg("no -token provided, skipping Portainer server version check")
+		*disableVersionCheckFlag = true
 	}
 
 	toolsPath := *toolsFlag
@@ -53,14 +157,170 @@ func main() {
 		log.Info().Msg("created tools.yaml file")
 	}
 
+	if outdated, version, err := tooldef.IsOutdated(toolsPath); err == nil && outdated {
+		log.Warn().
+			Str("tools-path", toolsPath).
+			Str("version", version).
+			Msg("the tools file is a copy of older tool definitions and lacks the tools added since, run 'portainer-mcp tools upgrade' to turn it into an overlay of the current definitions")
+	}
+
 	log.Info().
 		Str("portainer-host", *serverFlag).
 		Str("tools-path", toolsPath).
 		Bool("read-only", *readOnlyFlag).
+		Bool("dry-run", *dryRunFlag).
+		Bool("confirm-destructive", *confirmDestructiveFlag).
 		Bool("disable-version-check", *disableVersionCheckFlag).
+		Str("transport", *transportFlag).
+		Str("session-token-mode", *sessionTokenModeFlag).
+		Dur("request-timeout", *requestTimeoutFlag).
+		Str("output-format", *outputFormatFlag).
+		Int("max-retries", *maxRetriesFlag).
+		Bool("insecure", *insecureFlag).
+		Str("audit-log", *auditLogFlag).
+		Str("metrics-listen", *metricsListenFlag).
 		Msg("starting MCP server")
 
-	server, err := mcp.NewPortainerMCPServer(*serverFlag, *tokenFlag, toolsPath, mcp.WithReadOnly(*readOnlyFlag), mcp.WithDisableVersionCheck(*disableVersionCheckFlag))
+	retryPolicy := client.DefaultRetryPolicy()
+	retryPolicy.MaxRetries = *maxRetriesFlag
+
+	serverOptions := []mcp.ServerOption{
+		mcp.WithReadOnly(*readOnlyFlag),
+		mcp.WithDryRun(*dryRunFlag),
+		mcp.WithDestructiveConfirmation(*confirmDestructiveFlag),
+		mcp.WithDisableVersionCheck(*disableVersionCheckFlag),
+		mcp.WithSessionTokenMode(*sessionTokenModeFlag),
+		mcp.WithRequestTimeout(*requestTimeoutFlag),
+		mcp.WithOutputFormat(*outputFormatFlag),
+		mcp.WithClientOptions(
+			client.WithSkipTLSVerify(*insecureFlag),
+			client.WithCACertFile(*caCertFlag),
+			client.WithClientCertificate(*clientCertFlag, *clientKeyFlag),
+			client.WithMinTLSVersion(*tlsMinVersionFlag),
+			client.WithRetryPolicy(retryPolicy),
+		),
+	}
+
+	environmentScope := mcp.EnvironmentScope{
+		EnvironmentIDs:      parseIDList("scope-environments", *scopeEnvironmentsFlag),
+		TagIDs:              parseIDList("scope-tags", *scopeTagsFlag),
+		EnvironmentGroupIDs: parseIDList("scope-environment-groups", *scopeEnvironmentGroupsFlag),
+		AccessGroupIDs:      parseIDList("scope-access-groups", *scopeAccessGroupsFlag),
+	}
+	if !environmentScope.IsEmpty() {
+		log.Info().
+			Ints("environments", environmentScope.EnvironmentIDs).
+			Ints("tags", environmentScope.TagIDs).
+			Ints("environment-groups", environmentScope.EnvironmentGroupIDs).
+			Ints("access-groups", environmentScope.AccessGroupIDs).
+			Msg("restricting the server to an environment scope")
+	}
+	serverOptions = append(serverOptions, mcp.WithEnvironmentScope(environmentScope))
+
+	toolFilter := mcp.ToolFilter{
+		Allow: splitList(*allowToolsFlag),
+		Deny:  splitList(*denyToolsFlag),
+	}
+
+	if *toolFilterFlag != "" {
+		fileFilter, err := mcp.LoadToolFilter(*toolFilterFlag)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load tool filter")
+		}
+		serverOptions = append(serverOptions, mcp.WithToolFilter(fileFilter))
+	}
+	serverOptions = append(serverOptions, mcp.WithToolFilter(toolFilter))
+
+	if *proxyPolicyFlag != "" {
+		proxyPolicy, err := mcp.LoadProxyPolicy(*proxyPolicyFlag)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load proxy policy")
+		}
+		log.Info().Str("proxy-policy", *proxyPolicyFlag).Msg("enforcing proxy policy")
+		serverOptions = append(serverOptions, mcp.WithProxyPolicy(proxyPolicy))
+	}
+
+	if *instancesFlag != "" {
+		instanceConfigs, err := loadInstancesFile(*instancesFlag)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load Portainer instances")
+		}
+
+		names := []string{*instanceNameFlag}
+		for _, instanceConfig := range instanceConfigs {
+			if instanceConfig.Insecure {
+				log.Warn().Str("instance", instanceConfig.Name).Msg("TLS certificate verification of the Portainer server is disabled")
+			}
+
+			instance := instanceConfig.instance()
+			instance.ClientOptions = append(instance.ClientOptions, client.WithRetryPolicy(retryPolicy))
+
+			names = append(names, instanceConfig.Name)
+			serverOptions = append(serverOptions, mcp.WithInstances(instance))
+		}
+		log.Info().Strs("instances", names).Msg("managing multiple Portainer instances")
+
+		serverOptions = append(serverOptions, mcp.WithDefaultInstanceName(*instanceNameFlag))
+	}
+
+	if *cacheTTLFlag < 0 {
+		log.Fatal().Msg("The -cache-ttl flag must not be negative")
+	}
+
+	if *cacheTTLFlag > 0 {
+		cacheConfig := mcp.CacheConfig{
+			TTL:        *cacheTTLFlag,
+			MethodTTLs: parseMethodTTLs(*cacheMethodTTLsFlag),
+		}
+		log.Info().Dur("ttl", cacheConfig.TTL).Msg("caching Portainer API list responses")
+		serverOptions = append(serverOptions, mcp.WithResponseCache(cacheConfig))
+	} else if *cacheMethodTTLsFlag != "" {
+		log.Warn().Msg("the -cache-method-ttls flag has no effect without -cache-ttl")
+	}
+
+	if *maxResponseBytesFlag < 0 {
+		log.Fatal().Msg("The -max-response-bytes flag must not be negative")
+	}
+
+	if *maxResponseBytesFlag > 0 || *toolMaxResponseBytesFlag != "" {
+		budgetConfig := mcp.ResponseBudgetConfig{
+			MaxBytes:     *maxResponseBytesFlag,
+			ToolMaxBytes: parseToolMaxBytes(*toolMaxResponseBytesFlag),
+		}
+		log.Info().Int("max_bytes", budgetConfig.MaxBytes).Msg("truncating large tool results")
+		serverOptions = append(serverOptions, mcp.WithResponseBudget(budgetConfig))
+	}
+
+	var metrics *mcp.Metrics
+	if *metricsListenFlag != "" {
+		metrics = mcp.NewMetrics()
+		serverOptions = append(serverOptions, mcp.WithMetrics(metrics))
+	}
+
+	if *auditLogFlag != "" {
+		auditWriter := newAuditWriter(*auditLogFlag, *auditLogMaxSizeFlag, *auditLogMaxBackupsFlag)
+		defer auditWriter.Close()
+
+		serverOptions = append(serverOptions, mcp.WithAuditLogger(mcp.NewAuditLogger(auditWriter, *auditMutationsOnlyFlag)))
+	}
+
+	shutdownTracing, tracingEnabled, err := mcp.SetupTracing(context.Background(), Version)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to set up tracing")
+	}
+	if tracingEnabled {
+		log.Info().Msg("exporting OpenTelemetry traces")
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error().Err(err).Msg("failed to flush traces")
+		}
+	}()
+
+	server, err := mcp.NewPortainerMCPServer(*serverFlag, *tokenFlag, toolsPath, serverOptions...)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to create server")
 	}
@@ -84,9 +344,182 @@ func main() {
 	server.AddCustomResourceFeatures()
 	server.AddDockerProxyFeatures()
 	server.AddKubernetesProxyFeatures()
+	server.AddInstanceFeatures()
+	server.AddResultFeatures()
+
+	logToolReport(server.ToolReport())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go reloadToolsOnSignal(ctx, server, toolsPath)
+
+	if *watchToolsFlag {
+		log.Info().
+			Str("tools-path", toolsPath).
+			Dur("interval", mcp.DefaultToolsWatchInterval).
+			Msg("watching the tools file")
+
+		go server.WatchTools(ctx, toolsPath, mcp.DefaultToolsWatchInterval, func(err error) {
+			logToolsReload(server, toolsPath, err)
+		})
+	}
+
+	if metrics != nil {
+		log.Info().
+			Str("listen", *metricsListenFlag).
+			Str("path", mcp.MetricsPath).
+			Msg("serving Prometheus metrics")
 
-	err = server.Start()
+		go func() {
+			if err := mcp.ServeMetrics(ctx, *metricsListenFlag, metrics); err != nil {
+				log.Fatal().Err(err).Msg("failed to serve metrics")
+			}
+		}()
+	}
+
+	if *transportFlag != mcp.TransportStdio {
+		log.Info().
+			Str("listen", *listenFlag).
+			Str("base-path", *basePathFlag).
+			Msg("serving MCP clients over HTTP")
+	}
+
+	err = server.Serve(ctx, mcp.TransportConfig{
+		Type:       *transportFlag,
+		ListenAddr: *listenFlag,
+		BasePath:   *basePathFlag,
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to start server")
 	}
+
+	log.Info().Msg("MCP server stopped")
+}
+
+// logToolReport logs the registered tools, and the reason each other tool was skipped
+func logToolReport(report []mcp.ToolStatus) {
+	var registered []string
+	for _, status := range report {
+		if status.Registered {
+			registered = append(registered, status.Name)
+			continue
+		}
+
+		log.Info().
+			Str("tool", status.Name).
+			Str("group", status.Group).
+			Str("reason", status.Reason).
+			Msg("tool not registered")
+	}
+
+	log.Info().
+		Int("count", len(registered)).
+		Strs("tools", registered).
+		Msg("registered tools")
+}
+
+// reloadToolsOnSignal reloads the tools file whenever the process receives SIGHUP,
+// until the context is cancelled
+func reloadToolsOnSignal(ctx context.Context, server *mcp.PortainerMCPServer, toolsPath string) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			logToolsReload(server, toolsPath, server.ReloadTools(toolsPath))
+		}
+	}
+}
+
+// logToolsReload logs the outcome of a reload of the tools file
+func logToolsReload(server *mcp.PortainerMCPServer, toolsPath string, err error) {
+	if err != nil {
+		log.Error().Err(err).Str("tools-path", toolsPath).Msg("failed to reload tools, keeping the current tools")
+		return
+	}
+
+	log.Info().Str("tools-path", toolsPath).Msg("reloaded tools")
+	logToolReport(server.ToolReport())
+}
+
+// splitList splits a comma-separated flag value, ignoring empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseIDList parses a comma-separated list of IDs, exiting on invalid IDs
+func parseIDList(flagName, value string) []int {
+	var ids []int
+	for _, item := range splitList(value) {
+		id, err := strconv.Atoi(item)
+		if err != nil || id <= 0 {
+			log.Fatal().Str("value", item).Msgf("The -%s flag must be a comma-separated list of IDs", flagName)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// parseMethodTTLs parses a comma-separated list of Method=duration entries, exiting on invalid entries
+func parseMethodTTLs(value string) map[string]time.Duration {
+	ttls := map[string]time.Duration{}
+	for _, item := range splitList(value) {
+		method, rawTTL, ok := strings.Cut(item, "=")
+		ttl, err := time.ParseDuration(strings.TrimSpace(rawTTL))
+		if !ok || err != nil {
+			log.Fatal().Str("value", item).Msg("The -cache-method-ttls flag must be a comma-separated list of Method=duration entries")
+		}
+		ttls[strings.TrimSpace(method)] = ttl
+	}
+	return ttls
+}
+
+// parseToolMaxBytes parses a comma-separated list of tool=bytes entries, exiting on invalid entries
+func parseToolMaxBytes(value string) map[string]int {
+	budgets := map[string]int{}
+	for _, item := range splitList(value) {
+		tool, rawBytes, ok := strings.Cut(item, "=")
+		maxBytes, err := strconv.Atoi(strings.TrimSpace(rawBytes))
+		if !ok || err != nil || maxBytes < 0 {
+			log.Fatal().Str("value", item).Msg("The -tool-max-response-bytes flag must be a comma-separated list of tool=bytes entries")
+		}
+		budgets[strings.TrimSpace(tool)] = maxBytes
+	}
+	return budgets
+}
+
+// newAuditWriter returns the destination of the audit log.
+// Records are written to stderr when path is "stderr", and otherwise to a
+// file rotated once it reaches maxSizeMB megabytes.
+func newAuditWriter(path string, maxSizeMB, maxBackups int) io.WriteCloser {
+	if path == "stderr" {
+		return nopWriteCloser{os.Stderr}
+	}
+
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSizeMB,
+		MaxBackups: maxBackups,
+	}
+}
+
+// nopWriteCloser wraps a writer that must not be closed, such as stderr
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing
+func (nopWriteCloser) Close() error {
+	return nil
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+)
+
+// toolsCommandUsage documents the tools subcommands
+const toolsCommandUsage = `Usage: portainer-mcp tools upgrade [flags]
+
+Upgrade a tools file to an overlay of the tool definitions embedded in this
+release, keeping its customisations, and show the changes of the tool
+definitions.
+`
+
+// runToolsCommand runs a tools subcommand with its arguments, writing its
+// output to stdout and returns the exit code of the process
+func runToolsCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "upgrade" {
+		fmt.Fprint(stderr, toolsCommandUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("tools upgrade", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, toolsCommandUsage+"\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	toolsPath := fs.String("tools", defaultToolsPath, "The path to the tools YAML file to upgrade")
+	basePath := fs.String("base", "", "The path to the original tools YAML file the tools file was customised from, improving the detection of customisations")
+	dryRun := fs.Bool("dry-run", false, "Show the changes without writing the tools file")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if err := upgradeToolsFile(*toolsPath, *basePath, *dryRun, stdout); err != nil {
+		fmt.Fprintf(stderr, "failed to upgrade %s: %s\n", *toolsPath, err)
+		return 1
+	}
+
+	return 0
+}
+
+// upgradeToolsFile upgrades a tools file and reports the changes. The
+// previous tools file is kept with a .bak suffix.
+func upgradeToolsFile(toolsPath, basePath string, dryRun bool, out io.Writer) error {
+	result, err := tooldef.UpgradeToolsFile(toolsPath, basePath)
+	if err != nil {
+		return err
+	}
+
+	if result.Diff == "" {
+		fmt.Fprintln(out, "The tool definitions are unchanged.")
+	} else {
+		fmt.Fprint(out, result.Diff)
+	}
+
+	if result.WithoutBase {
+		fmt.Fprintln(out, "\nThe tools file was merged without the original tools file it was customised from, every difference with the new tool definitions is kept as a customisation and the tools missing from it are not disabled. Use -base to only keep your changes.")
+	}
+
+	for _, conflict := range result.Conflicts {
+		fmt.Fprintf(out, "conflict: the %s was changed by the new version, keeping your customisation\n", conflict)
+	}
+
+	for _, dropped := range result.Dropped {
+		fmt.Fprintf(out, "dropped: %s\n", dropped)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	current, err := os.ReadFile(toolsPath)
+	if err != nil {
+		return err
+	}
+
+	backupPath := toolsPath + ".bak"
+	if err := os.WriteFile(backupPath, current, 0644); err != nil {
+		return fmt.Errorf("failed to back up the tools file: %w", err)
+	}
+
+	if err := os.WriteFile(toolsPath, result.Content, 0644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Upgraded %s, the previous file is saved as %s\n", toolsPath, backupPath)
+	return nil
+}
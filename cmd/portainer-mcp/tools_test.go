@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCustomisedToolsFile writes a copy of the embedded tools file with a
+// customised description
+func writeCustomisedToolsFile(t *testing.T) (string, []byte) {
+	t.Helper()
+
+	content := []byte(strings.Replace(string(tooldef.ToolsFile),
+		"description: List all available environments",
+		"description: List the environments, prefer this tool to find environment IDs", 1))
+
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	require.NoError(t, os.WriteFile(path, content, 0644))
+
+	return path, content
+}
+
+func TestRunToolsCommandUpgrade(t *testing.T) {
+	path, content := writeCustomisedToolsFile(t)
+
+	var stdout, stderr bytes.Buffer
+	code := runToolsCommand([]string{"upgrade", "-tools", path}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	assert.Contains(t, stdout.String(), "The tool definitions are unchanged.")
+	assert.Contains(t, stdout.String(), "Upgraded "+path)
+
+	backup, err := os.ReadFile(path + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, content, backup)
+
+	tools, _, err := tooldef.LoadTools(path, "v1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "List the environments, prefer this tool to find environment IDs", tools["listEnvironments"].Description)
+
+	upgraded, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(upgraded), "overlay: true")
+}
+
+func TestRunToolsCommandDryRun(t *testing.T) {
+	path, content := writeCustomisedToolsFile(t)
+
+	var stdout, stderr bytes.Buffer
+	code := runToolsCommand([]string{"upgrade", "-tools", path, "-dry-run"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, current)
+	assert.NoFileExists(t, path+".bak")
+}
+
+func TestRunToolsCommandErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStderr string
+	}{
+		{
+			name:           "missing subcommand",
+			args:           nil,
+			expectedCode:   2,
+			expectedStderr: "Usage: portainer-mcp tools upgrade",
+		},
+		{
+			name:           "unknown subcommand",
+			args:           []string{"downgrade"},
+			expectedCode:   2,
+			expectedStderr: "Usage: portainer-mcp tools upgrade",
+		},
+		{
+			name:           "unknown flag",
+			args:           []string{"upgrade", "-force"},
+			expectedCode:   2,
+			expectedStderr: "flag provided but not defined: -force",
+		},
+		{
+			name:           "missing tools file",
+			args:           []string{"upgrade", "-tools", "nonexistent.yaml"},
+			expectedCode:   1,
+			expectedStderr: "failed to upgrade nonexistent.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runToolsCommand(tt.args, &stdout, &stderr)
+
+			assert.Equal(t, tt.expectedCode, code)
+			assert.Contains(t, stderr.String(), tt.expectedStderr)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// countTokensURL is the Anthropic API endpoint counting the tokens of a message
+const countTokensURL = "https://api.anthropic.com/v1/messages/count_tokens"
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// formatFixture is a representative tool result, decoded into the Go type of
+// the tool output
+type formatFixture struct {
+	tool       string
+	file       string
+	outputType reflect.Type
+}
+
+var formatFixtures = []formatFixture{
+	{tool: "listEnvironments", file: "environments.json", outputType: reflect.TypeFor[[]models.Environment]()},
+	{tool: "listDockerStacks", file: "docker_stacks.json", outputType: reflect.TypeFor[[]models.DockerStack]()},
+	{tool: "listUsers", file: "users.json", outputType: reflect.TypeFor[[]models.User]()},
+	{tool: "listAlertRules", file: "alert_rules.json", outputType: reflect.TypeFor[[]models.AlertingRule]()},
+	{tool: "getAlertRule", file: "alert_rule.json", outputType: reflect.TypeFor[models.AlertingRule]()},
+}
+
+// tokenCounter returns the number of tokens of a text
+type tokenCounter func(text string) (int, error)
+
+// compareFormats writes a Markdown report of the size of the fixtures in
+// every output format, and of their difference with the JSON format
+func compareFormats(w io.Writer, count tokenCounter, estimated bool) error {
+	tokensHeader := "Tokens"
+	if estimated {
+		tokensHeader = "Estimated tokens"
+	}
+
+	fmt.Fprintf(w, "| Tool | Format | Bytes | %s | Difference with JSON |\n", tokensHeader)
+	fmt.Fprintln(w, "|------|--------|-------|--------|----------------------|")
+
+	for _, fixture := range formatFixtures {
+		content, err := fixture.load()
+		if err != nil {
+			return fmt.Errorf("failed to load fixture %s: %w", fixture.file, err)
+		}
+
+		jsonTokens := 0
+		for _, format := range toolgen.OutputFormats {
+			text, err := toolgen.FormatOutput(content, format, toolgen.DefaultColumns(fixture.outputType))
+			if err != nil {
+				return fmt.Errorf("failed to format fixture %s as %s: %w", fixture.file, format, err)
+			}
+
+			tokens, err := count(text)
+			if err != nil {
+				return fmt.Errorf("failed to count the tokens of fixture %s as %s: %w", fixture.file, format, err)
+			}
+
+			difference := ""
+			if format == toolgen.OutputFormatJSON {
+				jsonTokens = tokens
+			} else if jsonTokens > 0 {
+				difference = fmt.Sprintf("%+d (%+.1f%%)", tokens-jsonTokens, float64(tokens-jsonTokens)*100/float64(jsonTokens))
+			}
+
+			fmt.Fprintf(w, "| %s | %s | %d | %d | %s |\n", fixture.tool, format, len(text), tokens, difference)
+		}
+	}
+
+	return nil
+}
+
+// load returns the structured content of the fixture, which must match the
+// Go type of the tool output
+func (f formatFixture) load() (any, error) {
+	data, err := fixtures.ReadFile("fixtures/" + f.file)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	value := reflect.New(f.outputType)
+	if err := decoder.Decode(value.Interface()); err != nil {
+		return nil, err
+	}
+
+	return toolgen.StructuredContent(value.Elem().Interface()), nil
+}
+
+// tokenPattern splits a text in words, numbers, whitespace and punctuation
+var tokenPattern = regexp.MustCompile(`[A-Za-z]+|[0-9]+|\s+|[^\sA-Za-z0-9]`)
+
+// estimateTokens roughly estimates the number of tokens of a text without
+// calling the API: a token for about every 6 letters of a word, 3 digits of a
+// number, run of whitespace and punctuation character
+func estimateTokens(text string) (int, error) {
+	tokens := 0
+	for _, match := range tokenPattern.FindAllString(text, -1) {
+		switch {
+		case match[0] >= '0' && match[0] <= '9':
+			tokens += (len(match) + 2) / 3
+		case strings.TrimLeft(match, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz") == "":
+			tokens += (len(match) + 5) / 6
+		default:
+			tokens++
+		}
+	}
+	return tokens, nil
+}
+
+// apiTokenCounter returns a counter of the tokens of a user message holding
+// the text, as counted by the Anthropic API for a model
+func apiTokenCounter(apiKey, model string) tokenCounter {
+	return func(text string) (int, error) {
+		payload, err := json.Marshal(map[string]any{
+			"model": model,
+			"messages": []map[string]any{
+				{"role": "user", "content": text},
+			},
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		request, err := http.NewRequest(http.MethodPost, countTokensURL, bytes.NewReader(payload))
+		if err != nil {
+			return 0, err
+		}
+		request.Header.Set("x-api-key", apiKey)
+		request.Header.Set("content-type", "application/json")
+		request.Header.Set("anthropic-version", "2023-06-01")
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return 0, err
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return 0, err
+		}
+		if response.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("unexpected status %s: %s", response.Status, body)
+		}
+
+		var result struct {
+			InputTokens int `json:"input_tokens"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return 0, err
+		}
+		return result.InputTokens, nil
+	}
+}
@@ -20,8 +20,23 @@ type AnthropicTool struct {
 func main() {
 	inputYamlPath := flag.String("input", "", "Path to the input tools YAML file (mandatory)")
 	outputPath := flag.String("output", "", "Path to the output JSON file (mandatory)")
+	compareFormatsFlag := flag.Bool("compare-formats", false, "Report the tokens of representative tool results in every output format instead of writing the tools JSON file")
+	apiKey := flag.String("api-key", "", "Anthropic API key counting the tokens of -compare-formats, which are estimated without it")
+	model := flag.String("model", "claude-3-7-sonnet-20250219", "The model whose tokens are counted by -compare-formats with -api-key")
 	flag.Parse()
 
+	if *compareFormatsFlag {
+		count, estimated := tokenCounter(estimateTokens), true
+		if *apiKey != "" {
+			count, estimated = apiTokenCounter(*apiKey, *model), false
+		}
+
+		if err := compareFormats(os.Stdout, count, estimated); err != nil {
+			log.Fatal().Err(err).Msg("failed to compare the output formats")
+		}
+		return
+	}
+
 	if *inputYamlPath == "" {
 		log.Fatal().Msg("Input YAML path is mandatory. Please specify using -input flag.")
 	}
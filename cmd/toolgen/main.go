@@ -0,0 +1,69 @@
+// Command toolgen generates the tool name constants and the typed tool
+// arguments of a package from a tools YAML file, and checks that every tool
+// of the file has a handler in the package. It is run by go generate:
+//
+//	//go:generate go run ../../cmd/toolgen -tools ../tooldef/tools.yaml -output tools_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	toolsPath := flag.String("tools", "", "Path to the input tools YAML file (mandatory)")
+	outputPath := flag.String("output", "", "Path to the generated Go file (mandatory)")
+	packageName := flag.String("package", os.Getenv("GOPACKAGE"), "Package of the generated Go file, the package running go generate by default")
+	register := flag.String("register", "addToolIfExists", "Method registering the tool handlers in the package")
+	check := flag.Bool("check", false, "Fail when the generated Go file is outdated instead of writing it")
+	flag.Parse()
+
+	if *toolsPath == "" {
+		log.Fatal().Msg("Tools YAML path is mandatory. Please specify using -tools flag.")
+	}
+	if *outputPath == "" {
+		log.Fatal().Msg("Output path is mandatory. Please specify using -output flag.")
+	}
+	if *packageName == "" {
+		log.Fatal().Msg("Package name is mandatory outside of go generate. Please specify using -package flag.")
+	}
+
+	data, err := os.ReadFile(*toolsPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to read the tools file")
+	}
+
+	config, err := toolgen.ParseToolsConfig(data, "v1.0")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to parse the tools file")
+	}
+
+	code, err := toolgen.GenerateCode(data, filepath.Base(*toolsPath), *packageName)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to generate the tools code")
+	}
+
+	outputDir := filepath.Dir(*outputPath)
+	if err := toolgen.CheckToolHandlers(config, outputDir, *register, filepath.Base(*outputPath)); err != nil {
+		log.Fatal().Err(err).Msg("the tools file and the tool handlers are out of sync")
+	}
+
+	if *check {
+		current, err := os.ReadFile(*outputPath)
+		if err != nil || !bytes.Equal(current, code) {
+			log.Fatal().Str("path", *outputPath).Msg("the generated tools code is outdated, run go generate")
+		}
+		return
+	}
+
+	if err := os.WriteFile(*outputPath, code, 0644); err != nil {
+		log.Fatal().Err(err).Str("path", *outputPath).Msg("failed to write the generated tools code")
+	}
+
+	log.Info().Str("path", *outputPath).Msg("Successfully generated the tools code")
+}
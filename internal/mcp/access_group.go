@@ -7,7 +7,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 )
 
 func (s *PortainerMCPServer) AddAccessGroupFeatures() {
@@ -26,35 +26,34 @@ func (s *PortainerMCPServer) AddAccessGroupFeatures() {
 
 func (s *PortainerMCPServer) HandleGetAccessGroups() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		accessGroups, err := s.cli.GetAccessGroups()
+		accessGroups, err := s.client(ctx).GetAccessGroups(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get access groups", err), nil
 		}
 
+		inScope, err := s.scopedEnvironmentIDs(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		accessGroups = filterScoped(inScope, accessGroups, func(g models.AccessGroup) []int { return g.EnvironmentIds })
+
 		data, err := json.Marshal(accessGroups)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal access groups", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(accessGroups, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleCreateAccessGroup() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		environmentIds, err := parser.GetArrayOfIntegers("environmentIds", false)
+		args, err := ParseCreateAccessGroupArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentIds parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		groupID, err := s.cli.CreateAccessGroup(name, environmentIds)
+		groupID, err := s.client(ctx).CreateAccessGroup(ctx, args.Name, args.EnvironmentIDs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create access group", err), nil
 		}
@@ -65,19 +64,12 @@ func (s *PortainerMCPServer) HandleCreateAccessGroup() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleUpdateAccessGroupName() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseUpdateAccessGroupNameArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateAccessGroupName(id, name)
+		err = s.client(ctx).UpdateAccessGroupName(ctx, args.ID, args.Name)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update access group name", err), nil
 		}
@@ -88,24 +80,17 @@ func (s *PortainerMCPServer) HandleUpdateAccessGroupName() server.ToolHandlerFun
 
 func (s *PortainerMCPServer) HandleUpdateAccessGroupUserAccesses() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		userAccesses, err := parser.GetArrayOfObjects("userAccesses", true)
+		args, err := ParseUpdateAccessGroupUserAccessesArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userAccesses parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		userAccessesMap, err := parseAccessMap(userAccesses)
+		userAccessesMap, err := parseAccessMap(args.UserAccesses)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid user accesses", err), nil
 		}
 
-		err = s.cli.UpdateAccessGroupUserAccesses(id, userAccessesMap)
+		err = s.client(ctx).UpdateAccessGroupUserAccesses(ctx, args.ID, userAccessesMap)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update access group user accesses", err), nil
 		}
@@ -116,24 +101,17 @@ func (s *PortainerMCPServer) HandleUpdateAccessGroupUserAccesses() server.ToolHa
 
 func (s *PortainerMCPServer) HandleUpdateAccessGroupTeamAccesses() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseUpdateAccessGroupTeamAccessesArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		teamAccesses, err := parser.GetArrayOfObjects("teamAccesses", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid teamAccesses parameter", err), nil
-		}
-
-		teamAccessesMap, err := parseAccessMap(teamAccesses)
+		teamAccessesMap, err := parseAccessMap(args.TeamAccesses)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid team accesses", err), nil
 		}
 
-		err = s.cli.UpdateAccessGroupTeamAccesses(id, teamAccessesMap)
+		err = s.client(ctx).UpdateAccessGroupTeamAccesses(ctx, args.ID, teamAccessesMap)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update access group team accesses", err), nil
 		}
@@ -144,19 +122,12 @@ func (s *PortainerMCPServer) HandleUpdateAccessGroupTeamAccesses() server.ToolHa
 
 func (s *PortainerMCPServer) HandleAddEnvironmentToAccessGroup() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseAddEnvironmentToAccessGroupArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.AddEnvironmentToAccessGroup(id, environmentId)
+		err = s.client(ctx).AddEnvironmentToAccessGroup(ctx, args.ID, args.EnvironmentID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to add environment to access group", err), nil
 		}
@@ -167,19 +138,12 @@ func (s *PortainerMCPServer) HandleAddEnvironmentToAccessGroup() server.ToolHand
 
 func (s *PortainerMCPServer) HandleRemoveEnvironmentFromAccessGroup() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseRemoveEnvironmentFromAccessGroupArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		environmentId, err := parser.GetInt("environmentId", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
-		}
-
-		err = s.cli.RemoveEnvironmentFromAccessGroup(id, environmentId)
+		err = s.client(ctx).RemoveEnvironmentFromAccessGroup(ctx, args.ID, args.EnvironmentID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to remove environment from access group", err), nil
 		}
@@ -190,14 +154,12 @@ func (s *PortainerMCPServer) HandleRemoveEnvironmentFromAccessGroup() server.Too
 
 func (s *PortainerMCPServer) HandleDeleteAccessGroup() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteAccessGroupArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteAccessGroup(id)
+		err = s.client(ctx).DeleteAccessGroup(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete access group", err), nil
 		}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 // AddAlertingFeatures registers all observability alerting related tools.
@@ -28,14 +27,12 @@ func (s *PortainerMCPServer) AddAlertingFeatures() {
 // HandleListAlerts returns a handler that lists active or silenced alerts.
 func (s *PortainerMCPServer) HandleListAlerts() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		status, err := parser.GetString("status", false)
+		args, err := ParseListAlertsArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid status parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		alerts, err := s.cli.GetAlerts(status)
+		alerts, err := s.client(ctx).GetAlerts(ctx, args.Status)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get alerts", err), nil
 		}
@@ -47,7 +44,7 @@ func (s *PortainerMCPServer) HandleListAlerts() server.ToolHandlerFunc {
 // HandleListAlertRules returns a handler that lists all alert rules.
 func (s *PortainerMCPServer) HandleListAlertRules() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		rules, err := s.cli.GetAlertRules()
+		rules, err := s.client(ctx).GetAlertRules(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get alert rules", err), nil
 		}
@@ -57,21 +54,19 @@ func (s *PortainerMCPServer) HandleListAlertRules() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal alert rules", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(rules, data), nil
 	}
 }
 
 // HandleGetAlertRule returns a handler that retrieves a specific alert rule.
 func (s *PortainerMCPServer) HandleGetAlertRule() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseGetAlertRuleArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		rule, err := s.cli.GetAlertRule(id)
+		rule, err := s.client(ctx).GetAlertRule(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get alert rule", err), nil
 		}
@@ -81,45 +76,36 @@ func (s *PortainerMCPServer) HandleGetAlertRule() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal alert rule", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(rule, data), nil
 	}
 }
 
 // HandleUpdateAlertRule returns a handler that updates an existing alert rule.
 func (s *PortainerMCPServer) HandleUpdateAlertRule() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		ruleJSON, err := parser.GetString("ruleJSON", true)
+		args, err := ParseUpdateAlertRuleArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid ruleJSON parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateAlertRule(id, ruleJSON)
+		err = s.client(ctx).UpdateAlertRule(ctx, args.ID, args.RuleJSON)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update alert rule", err), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Alert rule %d updated successfully", id)), nil
+		return mcp.NewToolResultText(fmt.Sprintf("Alert rule %d updated successfully", args.ID)), nil
 	}
 }
 
 // HandleDeleteAlertRule returns a handler that deletes an alert rule.
 func (s *PortainerMCPServer) HandleDeleteAlertRule() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteAlertRuleArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteAlertRule(id)
+		err = s.client(ctx).DeleteAlertRule(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete alert rule", err), nil
 		}
@@ -131,7 +117,7 @@ func (s *PortainerMCPServer) HandleDeleteAlertRule() server.ToolHandlerFunc {
 // HandleGetAlertingSettings returns a handler that retrieves alerting settings.
 func (s *PortainerMCPServer) HandleGetAlertingSettings() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		settings, err := s.cli.GetAlertingSettings()
+		settings, err := s.client(ctx).GetAlertingSettings(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get alerting settings", err), nil
 		}
@@ -141,26 +127,19 @@ func (s *PortainerMCPServer) HandleGetAlertingSettings() server.ToolHandlerFunc
 			return mcp.NewToolResultErrorFromErr("failed to marshal alerting settings", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(settings, data), nil
 	}
 }
 
 // HandleCreateAlertSilence returns a handler that creates a new alert silence.
 func (s *PortainerMCPServer) HandleCreateAlertSilence() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		silenceJSON, err := parser.GetString("silenceJSON", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid silenceJSON parameter", err), nil
-		}
-
-		alertManagerURL, err := parser.GetString("alertManagerURL", false)
+		args, err := ParseCreateAlertSilenceArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid alertManagerURL parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.CreateAlertSilence(silenceJSON, alertManagerURL)
+		err = s.client(ctx).CreateAlertSilence(ctx, args.SilenceJSON, args.AlertManagerURL)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create alert silence", err), nil
 		}
@@ -172,14 +151,12 @@ func (s *PortainerMCPServer) HandleCreateAlertSilence() server.ToolHandlerFunc {
 // HandleDeleteAlertSilence returns a handler that deletes an alert silence.
 func (s *PortainerMCPServer) HandleDeleteAlertSilence() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetString("id", true)
+		args, err := ParseDeleteAlertSilenceArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteAlertSilence(id)
+		err = s.client(ctx).DeleteAlertSilence(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete alert silence", err), nil
 		}
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// AuditStatusSuccess is the audit status of a tool call that succeeded
+	AuditStatusSuccess = "success"
+	// AuditStatusError is the audit status of a tool call that returned an error
+	AuditStatusError = "error"
+
+	// redactedValue replaces the value of sensitive arguments in audit records
+	redactedValue = "[REDACTED]"
+)
+
+// sensitiveArgumentKeys are the argument names, or parts of argument names,
+// whose values are redacted from audit records. Matching is case-insensitive.
+var sensitiveArgumentKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"apikey",
+	"api_key",
+	"privatekey",
+	"authorization",
+	"cookie",
+}
+
+// AuditRecord is a single entry of the audit log, describing one tool call
+type AuditRecord struct {
+	Timestamp  time.Time      `json:"timestamp"`
+	Tool       string         `json:"tool"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	DurationMs float64        `json:"durationMs"`
+	SessionID  string         `json:"sessionId,omitempty"`
+}
+
+// AuditLogger records every tool call as a JSON line written to an io.Writer
+type AuditLogger struct {
+	mu            sync.Mutex
+	encoder       *json.Encoder
+	mutationsOnly bool
+}
+
+// NewAuditLogger creates a new AuditLogger.
+//
+// Parameters:
+//   - w: The destination of the audit records, one JSON object per line
+//   - mutationsOnly: Only audit tools that are not annotated with readOnlyHint
+//
+// Returns:
+//   - A configured AuditLogger
+func NewAuditLogger(w io.Writer, mutationsOnly bool) *AuditLogger {
+	return &AuditLogger{
+		encoder:       json.NewEncoder(w),
+		mutationsOnly: mutationsOnly,
+	}
+}
+
+// WithAuditLogger enables the audit log of tool calls.
+// Every handler registered by the server is wrapped to record its calls.
+func WithAuditLogger(logger *AuditLogger) ServerOption {
+	return func(opts *serverOptions) {
+		opts.auditLogger = logger
+	}
+}
+
+// wrap returns a handler recording every call of the given tool.
+// Read-only tools are returned unwrapped when only mutations are audited.
+func (l *AuditLogger) wrap(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if l.mutationsOnly && isReadOnlyTool(tool) {
+		return handler
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, request)
+
+		record := AuditRecord{
+			Timestamp:  start.UTC(),
+			Tool:       tool.Name,
+			Arguments:  redactArguments(request.GetArguments()),
+			Status:     AuditStatusSuccess,
+			DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+			SessionID:  sessionIDFromContext(ctx),
+		}
+
+		switch {
+		case err != nil:
+			record.Status = AuditStatusError
+			record.Error = err.Error()
+		case result != nil && result.IsError:
+			record.Status = AuditStatusError
+			record.Error = resultText(result)
+		}
+
+		l.write(record)
+
+		return result, err
+	}
+}
+
+// write encodes the record as a single JSON line.
+// Audit failures never fail the tool call.
+func (l *AuditLogger) write(record AuditRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_ = l.encoder.Encode(record)
+}
+
+// isReadOnlyTool checks if a tool is annotated with readOnlyHint
+func isReadOnlyTool(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
+
+// sessionIDFromContext returns the ID of the MCP session of the request, if any
+func sessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// resultText returns the concatenated text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// redactArguments returns a copy of the tool arguments where the values of
+// sensitive arguments are replaced. Nested objects, arrays of key/value
+// objects such as HTTP headers, and string arguments holding JSON documents
+// are redacted recursively.
+func redactArguments(args map[string]any) map[string]any {
+	if args == nil {
+		return nil
+	}
+
+	redacted, _ := redactValue(args).(map[string]any)
+	return redacted
+}
+
+// redactValue redacts sensitive entries of a decoded JSON value
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				redacted[key] = redactedValue
+				continue
+			}
+			redacted[key] = redactValue(item)
+		}
+
+		// Key/value objects, e.g. {"key": "Authorization", "value": "..."}
+		if name, ok := v["key"].(string); ok && isSensitiveKey(name) {
+			if _, ok := v["value"]; ok {
+				redacted["value"] = redactedValue
+			}
+		}
+
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item)
+		}
+		return redacted
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+			return v
+		}
+
+		var document any
+		if err := json.Unmarshal([]byte(trimmed), &document); err != nil {
+			return v
+		}
+		return redactValue(document)
+	default:
+		return v
+	}
+}
+
+// isSensitiveKey checks if an argument name refers to a secret
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveArgumentKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
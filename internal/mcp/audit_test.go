@@ -0,0 +1,256 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditTestSession is a minimal MCP client session used to test session ID recording
+type auditTestSession struct {
+	id string
+}
+
+func (s *auditTestSession) Initialize()                                         {}
+func (s *auditTestSession) Initialized() bool                                   { return true }
+func (s *auditTestSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *auditTestSession) SessionID() string                                   { return s.id }
+
+func newAuditTestTool(name string, readOnly bool) mcp.Tool {
+	return mcp.Tool{
+		Name: name,
+		Annotations: mcp.ToolAnnotation{
+			ReadOnlyHint: mcp.ToBoolPtr(readOnly),
+		},
+	}
+}
+
+func readAuditRecords(t *testing.T, buf *bytes.Buffer) []AuditRecord {
+	t.Helper()
+
+	var records []AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record AuditRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAuditLoggerWrap(t *testing.T) {
+	tests := []struct {
+		name           string
+		tool           mcp.Tool
+		mutationsOnly  bool
+		args           map[string]any
+		result         *mcp.CallToolResult
+		err            error
+		expectRecord   bool
+		expectedStatus string
+		expectedError  string
+	}{
+		{
+			name:           "successful call",
+			tool:           newAuditTestTool("createTag", false),
+			args:           map[string]any{"name": "production"},
+			result:         mcp.NewToolResultText("ok"),
+			expectRecord:   true,
+			expectedStatus: AuditStatusSuccess,
+		},
+		{
+			name:           "tool result error",
+			tool:           newAuditTestTool("createTag", false),
+			args:           map[string]any{"name": "production"},
+			result:         mcp.NewToolResultError("failed to create tag"),
+			expectRecord:   true,
+			expectedStatus: AuditStatusError,
+			expectedError:  "failed to create tag",
+		},
+		{
+			name:           "handler error",
+			tool:           newAuditTestTool("createTag", false),
+			err:            errors.New("boom"),
+			expectRecord:   true,
+			expectedStatus: AuditStatusError,
+			expectedError:  "boom",
+		},
+		{
+			name:           "read-only tool audited by default",
+			tool:           newAuditTestTool("listEnvironments", true),
+			result:         mcp.NewToolResultText("[]"),
+			expectRecord:   true,
+			expectedStatus: AuditStatusSuccess,
+		},
+		{
+			name:          "read-only tool skipped when only mutations are audited",
+			tool:          newAuditTestTool("listEnvironments", true),
+			mutationsOnly: true,
+			result:        mcp.NewToolResultText("[]"),
+			expectRecord:  false,
+		},
+		{
+			name:           "mutation audited when only mutations are audited",
+			tool:           newAuditTestTool("deleteTag", false),
+			mutationsOnly:  true,
+			args:           map[string]any{"id": float64(1)},
+			result:         mcp.NewToolResultText("ok"),
+			expectRecord:   true,
+			expectedStatus: AuditStatusSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewAuditLogger(&buf, tt.mutationsOnly)
+
+			handler := logger.wrap(tt.tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tt.result, tt.err
+			})
+
+			result, err := handler(context.Background(), CreateMCPRequest(tt.args))
+			assert.Equal(t, tt.result, result)
+			assert.Equal(t, tt.err, err)
+
+			records := readAuditRecords(t, &buf)
+			if !tt.expectRecord {
+				assert.Empty(t, records)
+				return
+			}
+
+			require.Len(t, records, 1)
+			record := records[0]
+			assert.Equal(t, tt.tool.Name, record.Tool)
+			assert.Equal(t, tt.expectedStatus, record.Status)
+			assert.Equal(t, tt.expectedError, record.Error)
+			assert.Equal(t, tt.args, record.Arguments)
+			assert.False(t, record.Timestamp.IsZero())
+			assert.GreaterOrEqual(t, record.DurationMs, float64(0))
+		})
+	}
+}
+
+func TestAuditLoggerSessionID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewAuditLogger(&buf, false)
+
+	handler := logger.wrap(newAuditTestTool("createTag", false), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	srv := server.NewMCPServer("Test Server", "1.0.0")
+	ctx := srv.WithContext(context.Background(), &auditTestSession{id: "session-1"})
+
+	_, err := handler(ctx, CreateMCPRequest(nil))
+	require.NoError(t, err)
+
+	records := readAuditRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "session-1", records[0].SessionID)
+}
+
+func TestRedactArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]any
+		expected map[string]any
+	}{
+		{
+			name:     "nil arguments",
+			args:     nil,
+			expected: nil,
+		},
+		{
+			name: "password argument",
+			args: map[string]any{
+				"name":     "registry",
+				"username": "admin",
+				"password": "s3cr3t",
+			},
+			expected: map[string]any{
+				"name":     "registry",
+				"username": "admin",
+				"password": redactedValue,
+			},
+		},
+		{
+			name: "sensitive headers",
+			args: map[string]any{
+				"headers": []any{
+					map[string]any{"key": "Authorization", "value": "Bearer abc"},
+					map[string]any{"key": "Content-Type", "value": "application/json"},
+				},
+			},
+			expected: map[string]any{
+				"headers": []any{
+					map[string]any{"key": "Authorization", "value": redactedValue},
+					map[string]any{"key": "Content-Type", "value": "application/json"},
+				},
+			},
+		},
+		{
+			name: "JSON document argument",
+			args: map[string]any{
+				"settingsJSON": `{"ldapsettings":{"Password":"s3cr3t","URL":"ldap://example"}}`,
+			},
+			expected: map[string]any{
+				"settingsJSON": map[string]any{
+					"ldapsettings": map[string]any{
+						"Password": redactedValue,
+						"URL":      "ldap://example",
+					},
+				},
+			},
+		},
+		{
+			name: "plain string argument",
+			args: map[string]any{
+				"file": "services:\n  web:\n    image: nginx",
+			},
+			expected: map[string]any{
+				"file": "services:\n  web:\n    image: nginx",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactArguments(tt.args))
+		})
+	}
+}
+
+func TestAddToolIfExistsWithAudit(t *testing.T) {
+	var buf bytes.Buffer
+
+	s := &PortainerMCPServer{
+		srv:   server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		tools: map[string]mcp.Tool{"createTag": newAuditTestTool("createTag", false)},
+		audit: NewAuditLogger(&buf, false),
+	}
+
+	s.addToolIfExists("createTag", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	tool := s.srv.GetTool("createTag")
+	require.NotNil(t, tool)
+
+	_, err := tool.Handler(context.Background(), CreateMCPRequest(map[string]any{"name": "production"}))
+	require.NoError(t, err)
+
+	records := readAuditRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "createTag", records[0].Tool)
+}
@@ -0,0 +1,401 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+const (
+	// HandleParam is the argument of the readResultPage tool naming the page to read
+	HandleParam = "handle"
+
+	// MinResponseBytes is the smallest response budget, leaving room for the
+	// truncation marker and a useful part of the result, twice for a result
+	// with structured content
+	MinResponseBytes = 2048
+
+	// DefaultResultBufferTTL is the time the rest of a truncated result can be read
+	DefaultResultBufferTTL = 10 * time.Minute
+
+	// DefaultResultBufferBytes is the total size of the truncated results kept
+	// by the server, the oldest results being dropped first
+	DefaultResultBufferBytes = 64 << 20
+
+	// markerReserve is the part of the response budget kept for the truncation marker
+	markerReserve = 512
+)
+
+// ResponseBudgetConfig limits the size of the tool results
+type ResponseBudgetConfig struct {
+	// MaxBytes is the largest size of a tool result, for the tools without
+	// their own budget. Zero disables the budget.
+	MaxBytes int
+	// ToolMaxBytes overrides the budget of some tools, keyed by tool name.
+	// A zero budget disables the budget of the tool.
+	ToolMaxBytes map[string]int
+}
+
+// TruncationMarker describes a tool result truncated to fit the response
+// budget. It follows the part of the result returned by a tool call or by
+// the readResultPage tool.
+type TruncationMarker struct {
+	Truncated     bool            `json:"truncated"`
+	TotalBytes    int             `json:"totalBytes"`
+	ReturnedBytes int             `json:"returnedBytes"`
+	Offset        *int            `json:"offset,omitempty"`
+	Items         *TruncatedItems `json:"items,omitempty"`
+	Members       *TruncatedItems `json:"members,omitempty"`
+	Shortened     bool            `json:"shortened,omitempty"`
+	NextHandle    string          `json:"nextHandle,omitempty"`
+	Message       string          `json:"message"`
+}
+
+// TruncatedItems describes the items of a list, or the members of an object,
+// returned by a truncated result
+type TruncatedItems struct {
+	First    int `json:"first"`
+	Returned int `json:"returned"`
+	Total    int `json:"total"`
+}
+
+// WithResponseBudget limits the size of the tool results, their text and
+// structured content together. A result larger than the budget of its tool is
+// truncated: a JSON list keeps its first whole items, other JSON values are
+// shortened while staying valid JSON, and other text is cut. The result is
+// followed by a truncation marker holding a handle to read the rest of the
+// result with the readResultPage tool.
+func WithResponseBudget(config ResponseBudgetConfig) ServerOption {
+	return func(opts *serverOptions) {
+		opts.responseBudget = &config
+	}
+}
+
+// validate checks that the budgets are either zero or large enough, and only
+// name defined tools
+func (c ResponseBudgetConfig) validate(toolNames []string) error {
+	if err := checkResponseBytes(c.MaxBytes); err != nil {
+		return fmt.Errorf("invalid response budget: %w", err)
+	}
+
+	for tool, maxBytes := range c.ToolMaxBytes {
+		if !slices.Contains(toolNames, tool) {
+			return fmt.Errorf("invalid response budget of %s: unknown tool", tool)
+		}
+		if err := checkResponseBytes(maxBytes); err != nil {
+			return fmt.Errorf("invalid response budget of %s: %w", tool, err)
+		}
+	}
+
+	return nil
+}
+
+// checkResponseBytes checks that a budget is either zero or large enough
+func checkResponseBytes(maxBytes int) error {
+	if maxBytes != 0 && maxBytes < MinResponseBytes {
+		return fmt.Errorf("%d bytes, must be 0 or at least %d", maxBytes, MinResponseBytes)
+	}
+	return nil
+}
+
+// maxBytes returns the budget of a tool, zero when its results are not limited
+func (c ResponseBudgetConfig) maxBytes(tool string) int {
+	if maxBytes, ok := c.ToolMaxBytes[tool]; ok {
+		return maxBytes
+	}
+	return c.MaxBytes
+}
+
+// bufferedResult is the text of a truncated tool result
+type bufferedResult struct {
+	text      string
+	budget    int
+	sessionID string
+	expiresAt time.Time
+}
+
+// resultBuffer holds the truncated tool results until their rest is read
+type resultBuffer struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	maxBytes int
+	now      func() time.Time
+	results  map[string]bufferedResult
+	size     int
+}
+
+func newResultBuffer(ttl time.Duration, maxBytes int) *resultBuffer {
+	return &resultBuffer{
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		now:      time.Now,
+		results:  make(map[string]bufferedResult),
+	}
+}
+
+// store keeps the text of a truncated result and returns its identifier.
+// Expired results are dropped, then the oldest results until the text fits
+// in the buffer. A text larger than the buffer is not kept.
+func (b *resultBuffer) store(text string, budget int, sessionID string) (string, bool, error) {
+	if len(text) > b.maxBytes {
+		return "", false, nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, err
+	}
+	id := hex.EncodeToString(buf)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	for id, result := range b.results {
+		if now.After(result.expiresAt) {
+			b.drop(id)
+		}
+	}
+
+	for b.size+len(text) > b.maxBytes {
+		oldest := ""
+		for id, result := range b.results {
+			if oldest == "" || result.expiresAt.Before(b.results[oldest].expiresAt) {
+				oldest = id
+			}
+		}
+		b.drop(oldest)
+	}
+
+	b.results[id] = bufferedResult{
+		text:      text,
+		budget:    budget,
+		sessionID: sessionID,
+		expiresAt: now.Add(b.ttl),
+	}
+	b.size += len(text)
+
+	return id, true, nil
+}
+
+// drop removes a result from the buffer, the lock being held
+func (b *resultBuffer) drop(id string) {
+	b.size -= len(b.results[id].text)
+	delete(b.results, id)
+}
+
+// get returns a result kept for a session
+func (b *resultBuffer) get(id, sessionID string) (bufferedResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result, ok := b.results[id]
+	if !ok || b.now().After(result.expiresAt) {
+		return bufferedResult{}, fmt.Errorf("the result has expired or does not exist, call the tool again")
+	}
+	if result.sessionID != sessionID {
+		return bufferedResult{}, fmt.Errorf("the result belongs to another session")
+	}
+
+	return result, nil
+}
+
+// resultHandle locates a page of a buffered result
+type resultHandle struct {
+	ID       string `json:"i"`
+	Position int    `json:"p"`
+}
+
+func encodeHandle(handle resultHandle) string {
+	data, _ := json.Marshal(handle)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeHandle(value string) (resultHandle, error) {
+	var handle resultHandle
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || json.Unmarshal(data, &handle) != nil || handle.ID == "" || handle.Position < 0 {
+		return resultHandle{}, fmt.Errorf("malformed handle")
+	}
+
+	return handle, nil
+}
+
+// pageBudget returns the part of a response budget left to the result text
+func pageBudget(budget int) int {
+	return budget - markerReserve
+}
+
+// structuredPageBudget returns the part of a response budget left to the
+// page of a result with structured content, which holds the page and its
+// marker a second time
+func structuredPageBudget(budget int) int {
+	return (budget - 2*markerReserve) / 2
+}
+
+// responseSize returns the size of a result made of a text and, when not nil,
+// structured content
+func responseSize(text string, structured any) int {
+	if structured == nil {
+		return len(text)
+	}
+	return len(text) + encodedSize(structured)
+}
+
+// structuredText returns the JSON text of structured content, a list wrapped
+// in an object being written on its own as in the JSON text of the tools
+func structuredText(structured any) string {
+	if object, ok := structured.(map[string]any); ok && len(object) == 1 {
+		if items, ok := object[toolgen.StructuredItemsProperty]; ok {
+			structured = items
+		}
+	}
+	return string(encodeJSON(structured))
+}
+
+// newTruncationMarker describes a page of a result, the handle of the next
+// page being empty after the last page
+func newTruncationMarker(text string, page resultPage, nextHandle string) TruncationMarker {
+	marker := TruncationMarker{
+		Truncated:     nextHandle != "",
+		TotalBytes:    len(text),
+		ReturnedBytes: len(page.text),
+		Shortened:     page.shortened,
+		NextHandle:    nextHandle,
+	}
+
+	switch {
+	case page.totalItems > 0 && page.members:
+		marker.Members = &TruncatedItems{First: page.firstItem, Returned: page.items, Total: page.totalItems}
+	case page.totalItems > 0:
+		marker.Items = &TruncatedItems{First: page.firstItem, Returned: page.items, Total: page.totalItems}
+	case page.value == nil:
+		offset := page.offset
+		marker.Offset = &offset
+	}
+
+	switch {
+	case nextHandle != "":
+		marker.Message = fmt.Sprintf("The result of %d bytes was truncated. Call readResultPage with the nextHandle to read the rest.", len(text))
+	case page.next >= 0:
+		marker.Message = fmt.Sprintf("The result of %d bytes was truncated and its rest is too large to be kept.", len(text))
+	case page.shortened:
+		marker.Message = "The result was shortened to fit the response budget."
+	default:
+		marker.Message = "This is the last page of the result."
+	}
+
+	return marker
+}
+
+// withMarker returns a result holding a page of a result and its truncation
+// marker, with the structured content of the page when it is a JSON value
+func withMarker(page resultPage, marker TruncationMarker, structured bool) *mcp.CallToolResult {
+	data, _ := json.Marshal(marker)
+
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.NewTextContent(page.text), mcp.NewTextContent(string(data))},
+	}
+
+	if structured && page.value != nil {
+		content, ok := page.value.(map[string]any)
+		if !ok {
+			content = map[string]any{toolgen.StructuredItemsProperty: page.value}
+		}
+		content["truncation"] = marker
+		result.StructuredContent = content
+	}
+
+	return result
+}
+
+// responseBudgetHandler returns a handler that truncates the results of a
+// handler larger than budget bytes, keeping their text for the readResultPage
+// tool.
+//
+// The text of a result with structured content is made from it, in the output
+// format of the call. Such a result is truncated when its text and structured
+// content together exceed the budget, in which case the JSON of the structured
+// content is paged, and its first page returned both as text and as structured
+// content.
+func (s *PortainerMCPServer) responseBudgetHandler(budget int, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError || len(result.Content) != 1 {
+			return result, err
+		}
+
+		content, ok := result.Content[0].(mcp.TextContent)
+		if !ok || responseSize(content.Text, result.StructuredContent) <= budget {
+			return result, nil
+		}
+
+		text, pageBytes := content.Text, pageBudget(budget)
+		if result.StructuredContent != nil {
+			text, pageBytes = structuredText(result.StructuredContent), structuredPageBudget(budget)
+		}
+		page := resultPageAt(text, pageBytes, 0, true)
+
+		nextHandle := ""
+		if page.next >= 0 {
+			id, stored, err := s.results.store(text, budget, sessionIDFromContext(ctx))
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to keep the truncated result", err), nil
+			}
+			if stored {
+				nextHandle = encodeHandle(resultHandle{ID: id, Position: page.next})
+			}
+		}
+
+		marker := newTruncationMarker(text, page, nextHandle)
+		return withMarker(page, marker, result.StructuredContent != nil), nil
+	}
+}
+
+// AddResultFeatures registers the tool reading the rest of the truncated
+// results, when the response budget is enabled.
+func (s *PortainerMCPServer) AddResultFeatures() {
+	if s.results != nil {
+		s.addToolIfExists(ToolReadResultPage, s.HandleReadResultPage())
+	}
+}
+
+func (s *PortainerMCPServer) HandleReadResultPage() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := ParseReadResultPageArgs(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		handle, err := decodeHandle(args.Handle)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid handle parameter", err), nil
+		}
+
+		buffered, err := s.results.get(handle.ID, sessionIDFromContext(ctx))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to read the result page", err), nil
+		}
+
+		page := resultPageAt(buffered.text, pageBudget(buffered.budget), handle.Position, false)
+
+		nextHandle := ""
+		if page.next >= 0 {
+			nextHandle = encodeHandle(resultHandle{ID: handle.ID, Position: page.next})
+		}
+
+		marker := newTruncationMarker(buffered.text, page, nextHandle)
+		return withMarker(page, marker, false), nil
+	}
+}
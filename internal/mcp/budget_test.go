@@ -0,0 +1,318 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// truncationMarker returns the truncation marker following the text of a result
+func truncationMarker(t *testing.T, result *mcp.CallToolResult) TruncationMarker {
+	t.Helper()
+
+	require.Len(t, result.Content, 2)
+	text, ok := result.Content[1].(mcp.TextContent)
+	require.True(t, ok)
+
+	var marker TruncationMarker
+	require.NoError(t, json.Unmarshal([]byte(text.Text), &marker))
+	return marker
+}
+
+func TestResponseBudgetConfigValidate(t *testing.T) {
+	toolNames := []string{ToolDockerProxy, ToolGetSettings}
+
+	tests := []struct {
+		name          string
+		config        ResponseBudgetConfig
+		errorContains string
+	}{
+		{
+			name:   "valid",
+			config: ResponseBudgetConfig{MaxBytes: 65536, ToolMaxBytes: map[string]int{ToolDockerProxy: 1 << 20, ToolGetSettings: 0}},
+		},
+		{
+			name:          "budget too small",
+			config:        ResponseBudgetConfig{MaxBytes: 100},
+			errorContains: "invalid response budget: 100 bytes, must be 0 or at least 2048",
+		},
+		{
+			name:          "negative budget",
+			config:        ResponseBudgetConfig{MaxBytes: -1},
+			errorContains: "invalid response budget: -1 bytes",
+		},
+		{
+			name:          "unknown tool",
+			config:        ResponseBudgetConfig{MaxBytes: 65536, ToolMaxBytes: map[string]int{"getLogs": 4096}},
+			errorContains: "invalid response budget of getLogs: unknown tool",
+		},
+		{
+			name:          "tool budget too small",
+			config:        ResponseBudgetConfig{ToolMaxBytes: map[string]int{ToolDockerProxy: 10}},
+			errorContains: "invalid response budget of dockerProxy: 10 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate(toolNames)
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+}
+
+func TestResponseBudgetConfigMaxBytes(t *testing.T) {
+	config := ResponseBudgetConfig{MaxBytes: 4096, ToolMaxBytes: map[string]int{ToolDockerProxy: 65536, ToolGetSettings: 0}}
+
+	assert.Equal(t, 4096, config.maxBytes(ToolListUsers))
+	assert.Equal(t, 65536, config.maxBytes(ToolDockerProxy))
+	assert.Equal(t, 0, config.maxBytes(ToolGetSettings))
+}
+
+func TestResultBuffer(t *testing.T) {
+	now := time.Now()
+	buffer := newResultBuffer(time.Minute, 100)
+	buffer.now = func() time.Time { return now }
+
+	first, stored, err := buffer.store(strings.Repeat("a", 60), 2048, "session-1")
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	result, err := buffer.get(first, "session-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2048, result.budget)
+
+	_, err = buffer.get(first, "session-2")
+	assert.ErrorContains(t, err, "the result belongs to another session")
+
+	// The oldest result is dropped to make room for a new one
+	now = now.Add(time.Second)
+	second, stored, err := buffer.store(strings.Repeat("b", 60), 2048, "session-1")
+	require.NoError(t, err)
+	require.True(t, stored)
+	assert.Equal(t, 60, buffer.size)
+
+	_, err = buffer.get(first, "session-1")
+	assert.ErrorContains(t, err, "the result has expired or does not exist")
+
+	// A result larger than the buffer is not kept
+	_, stored, err = buffer.store(strings.Repeat("c", 101), 2048, "session-1")
+	require.NoError(t, err)
+	assert.False(t, stored)
+
+	now = now.Add(2 * time.Minute)
+	_, err = buffer.get(second, "session-1")
+	assert.ErrorContains(t, err, "the result has expired or does not exist")
+}
+
+func TestResultHandle(t *testing.T) {
+	handle := resultHandle{ID: "abc", Position: 42}
+
+	decoded, err := decodeHandle(encodeHandle(handle))
+	require.NoError(t, err)
+	assert.Equal(t, handle, decoded)
+
+	for _, value := range []string{"", "not a handle", encodeHandle(resultHandle{Position: 1}), encodeHandle(resultHandle{ID: "abc", Position: -1})} {
+		_, err := decodeHandle(value)
+		assert.ErrorContains(t, err, "malformed handle", value)
+	}
+}
+
+func TestResponseBudget(t *testing.T) {
+	config, err := tooldef.EmbeddedTools()
+	require.NoError(t, err)
+	tools, _ := toolgen.ConvertToolsConfig(config)
+
+	tags := make([]models.EnvironmentTag, 100)
+	for i := range tags {
+		tags[i] = models.EnvironmentTag{ID: i + 1, Name: fmt.Sprintf("tag-%03d", i+1), EnvironmentIds: []int{i + 1}}
+	}
+
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetEnvironmentTags").Return(tags, nil)
+	mockClient.On("GetSettings").Return(models.PortainerSettings{}, nil)
+
+	s := &PortainerMCPServer{
+		srv:     server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		cli:     mockClient,
+		tools:   tools,
+		budget:  ResponseBudgetConfig{MaxBytes: 2048, ToolMaxBytes: map[string]int{ToolGetSettings: 0}},
+		results: newResultBuffer(DefaultResultBufferTTL, DefaultResultBufferBytes),
+	}
+	s.AddTagFeatures()
+	s.AddSettingsFeatures()
+	s.AddResultFeatures()
+
+	ctx := context.Background()
+
+	t.Run("truncated list", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{})
+		require.False(t, result.IsError, resultText(result))
+
+		page := result.Content[0].(mcp.TextContent).Text
+		marker := truncationMarker(t, result)
+		assert.LessOrEqual(t, len(page)+len(result.Content[1].(mcp.TextContent).Text)+encodedSize(result.StructuredContent), 2048)
+		assert.True(t, marker.Truncated)
+		assert.Equal(t, len(page), marker.ReturnedBytes)
+		require.NotNil(t, marker.Items)
+		assert.Equal(t, 0, marker.Items.First)
+		assert.Equal(t, 100, marker.Items.Total)
+		require.NotEmpty(t, marker.NextHandle)
+
+		var items []any
+		require.NoError(t, json.Unmarshal([]byte(page), &items))
+		assert.Len(t, items, marker.Items.Returned)
+
+		content := result.StructuredContent.(map[string]any)
+		assert.Len(t, content[toolgen.StructuredItemsProperty], marker.Items.Returned)
+		assert.Contains(t, content, "truncation")
+		tool := s.srv.GetTool(ToolListEnvironmentTags)
+		require.NoError(t, toolgen.ValidateOutput(outputSchema(t, tool.Tool), content))
+
+		// The rest of the list is read page by page
+		ids := itemIDs(items)
+		for handle := marker.NextHandle; handle != ""; {
+			result := callTool(t, s, ctx, ToolReadResultPage, map[string]any{HandleParam: handle})
+			require.False(t, result.IsError, resultText(result))
+
+			marker := truncationMarker(t, result)
+			assert.Equal(t, len(ids), marker.Items.First)
+			assert.Nil(t, result.StructuredContent)
+
+			var items []any
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &items))
+			ids = append(ids, itemIDs(items)...)
+			handle = marker.NextHandle
+		}
+
+		require.Len(t, ids, 100)
+		for i, id := range ids {
+			assert.Equal(t, i+1, id)
+		}
+	})
+
+	t.Run("table with large structured content", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{OutputFormatParam: toolgen.OutputFormatTable})
+		require.False(t, result.IsError, resultText(result))
+
+		// The structured content counts towards the budget, and its JSON is
+		// paged whatever the output format
+		page := result.Content[0].(mcp.TextContent).Text
+		marker := truncationMarker(t, result)
+		assert.LessOrEqual(t, len(page)+len(result.Content[1].(mcp.TextContent).Text)+encodedSize(result.StructuredContent), 2048)
+		assert.True(t, marker.Truncated)
+
+		var items []any
+		require.NoError(t, json.Unmarshal([]byte(page), &items))
+		assert.Len(t, items, marker.Items.Returned)
+		assert.Len(t, result.StructuredContent.(map[string]any)[toolgen.StructuredItemsProperty], marker.Items.Returned)
+	})
+
+	t.Run("small result", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{LimitParam: float64(2)})
+		require.False(t, result.IsError, resultText(result))
+		assert.Len(t, result.Content, 1)
+	})
+
+	t.Run("tool without budget", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolGetSettings, map[string]any{})
+		require.False(t, result.IsError, resultText(result))
+		assert.Len(t, result.Content, 1)
+	})
+
+	t.Run("invalid handle", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolReadResultPage, map[string]any{HandleParam: "abc"})
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid handle parameter: malformed handle", resultText(result))
+	})
+
+	t.Run("unknown result", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolReadResultPage, map[string]any{HandleParam: encodeHandle(resultHandle{ID: "abc", Position: 1})})
+		assert.True(t, result.IsError)
+		assert.Equal(t, "failed to read the result page: the result has expired or does not exist, call the tool again", resultText(result))
+	})
+}
+
+func TestResponseBudgetRawText(t *testing.T) {
+	s := &PortainerMCPServer{results: newResultBuffer(DefaultResultBufferTTL, DefaultResultBufferBytes)}
+	text := strings.Repeat("0123456789", 300)
+
+	handler := s.responseBudgetHandler(2048, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(text), nil
+	})
+
+	result, err := handler(context.Background(), CreateMCPRequest(nil))
+	require.NoError(t, err)
+
+	marker := truncationMarker(t, result)
+	assert.True(t, marker.Truncated)
+	assert.Equal(t, 3000, marker.TotalBytes)
+	require.NotNil(t, marker.Offset)
+	assert.Equal(t, 0, *marker.Offset)
+	assert.Nil(t, marker.Items)
+	assert.Nil(t, result.StructuredContent)
+	assert.Equal(t, text[:marker.ReturnedBytes], result.Content[0].(mcp.TextContent).Text)
+
+	handle, err := decodeHandle(marker.NextHandle)
+	require.NoError(t, err)
+	assert.Equal(t, marker.ReturnedBytes, handle.Position)
+}
+
+func TestResponseBudgetObject(t *testing.T) {
+	s := &PortainerMCPServer{results: newResultBuffer(DefaultResultBufferTTL, DefaultResultBufferBytes)}
+
+	object := make(map[string]string)
+	for i := range 10 {
+		object[fmt.Sprintf("member-%d", i)] = strings.Repeat("v", 400)
+	}
+	data, err := json.Marshal(object)
+	require.NoError(t, err)
+
+	handler := s.responseBudgetHandler(2048, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(string(data)), nil
+	})
+
+	result, err := handler(context.Background(), CreateMCPRequest(nil))
+	require.NoError(t, err)
+
+	marker := truncationMarker(t, result)
+	assert.True(t, marker.Shortened)
+	assert.Nil(t, marker.Members)
+
+	// The next pages hold whole members
+	read := make(map[string]string)
+	for handle := marker.NextHandle; handle != ""; {
+		result, err := s.HandleReadResultPage()(context.Background(), CreateMCPRequest(map[string]any{HandleParam: handle}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, resultText(result))
+
+		marker := truncationMarker(t, result)
+		require.NotNil(t, marker.Members)
+		assert.Equal(t, len(read), marker.Members.First)
+		assert.Equal(t, 10, marker.Members.Total)
+
+		var members map[string]string
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &members))
+		assert.Len(t, members, marker.Members.Returned)
+		maps.Copy(read, members)
+		handle = marker.NextHandle
+	}
+
+	assert.Equal(t, object, read)
+}
@@ -0,0 +1,659 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+const (
+	// RefreshParam is the tool argument bypassing the response cache
+	RefreshParam = "refresh"
+
+	// DefaultCacheTTL is the default time a Portainer API response is cached
+	DefaultCacheTTL = 30 * time.Second
+)
+
+// Cached PortainerClient methods, as named in CacheConfig.MethodTTLs
+const (
+	cacheGetEnvironmentTags   = "GetEnvironmentTags"
+	cacheGetEnvironments      = "GetEnvironments"
+	cacheGetAgentVersions     = "GetAgentVersions"
+	cacheGetEnvironmentGroups = "GetEnvironmentGroups"
+	cacheGetAccessGroups      = "GetAccessGroups"
+	cacheGetStacks            = "GetStacks"
+	cacheGetDockerStacks      = "GetDockerStacks"
+	cacheGetTeams             = "GetTeams"
+	cacheGetUsers             = "GetUsers"
+	cacheGetSettings          = "GetSettings"
+	cacheGetRegistries        = "GetRegistries"
+	cacheGetEdgeJobs          = "GetEdgeJobs"
+	cacheGetCustomTemplates   = "GetCustomTemplates"
+	cacheGetWebhooks          = "GetWebhooks"
+	cacheGetGitCredentials    = "GetGitCredentials"
+	cacheGetAlertRules        = "GetAlertRules"
+	cacheGetPolicies          = "GetPolicies"
+)
+
+// cachedMethods lists the PortainerClient methods whose responses are cached
+var cachedMethods = []string{
+	cacheGetEnvironmentTags,
+	cacheGetEnvironments,
+	cacheGetAgentVersions,
+	cacheGetEnvironmentGroups,
+	cacheGetAccessGroups,
+	cacheGetStacks,
+	cacheGetDockerStacks,
+	cacheGetTeams,
+	cacheGetUsers,
+	cacheGetSettings,
+	cacheGetRegistries,
+	cacheGetEdgeJobs,
+	cacheGetCustomTemplates,
+	cacheGetWebhooks,
+	cacheGetGitCredentials,
+	cacheGetAlertRules,
+	cacheGetPolicies,
+}
+
+// refreshableTools are the tools whose responses come from the cache, and
+// which therefore accept the refresh argument
+var refreshableTools = map[string]bool{
+	ToolListEnvironmentTags:   true,
+	ToolListEnvironments:      true,
+	ToolListAgentVersions:     true,
+	ToolListEnvironmentGroups: true,
+	ToolListAccessGroups:      true,
+	ToolListStacks:            true,
+	ToolListDockerStacks:      true,
+	ToolListTeams:             true,
+	ToolListUsers:             true,
+	ToolGetSettings:           true,
+	ToolListRegistries:        true,
+	ToolListEdgeJobs:          true,
+	ToolListCustomTemplates:   true,
+	ToolListWebhooks:          true,
+	ToolListGitCredentials:    true,
+	ToolListAlertRules:        true,
+	ToolListPolicies:          true,
+}
+
+// CacheConfig configures the cache of the Portainer API responses of the list methods
+type CacheConfig struct {
+	// TTL is the time a response is cached, for the methods without their own TTL
+	TTL time.Duration
+	// MethodTTLs overrides the TTL of some methods, keyed by PortainerClient
+	// method name such as GetEnvironments. A zero TTL disables the cache of
+	// the method.
+	MethodTTLs map[string]time.Duration
+}
+
+// WithResponseCache caches the responses of the Portainer API list requests,
+// such as the environments or the users. A cached response is dropped when it
+// expires, or when a write to the same resources succeeds. The tools reading
+// cached responses accept a refresh argument bypassing the cache.
+//
+// Every client, including the clients of session tokens and of additional
+// instances, has its own cache so that responses are never shared between
+// API tokens.
+func WithResponseCache(config CacheConfig) ServerOption {
+	return func(opts *serverOptions) {
+		opts.cache = &config
+	}
+}
+
+// validate checks that the TTLs are not negative and only name cached methods
+func (c CacheConfig) validate() error {
+	if c.TTL < 0 {
+		return fmt.Errorf("invalid cache TTL: %s", c.TTL)
+	}
+
+	for method, ttl := range c.MethodTTLs {
+		if !slices.Contains(cachedMethods, method) {
+			return fmt.Errorf("invalid cache TTL of %s: unknown method, must be one of %s", method, strings.Join(cachedMethods, ", "))
+		}
+		if ttl < 0 {
+			return fmt.Errorf("invalid cache TTL of %s: %s", method, ttl)
+		}
+	}
+
+	return nil
+}
+
+// ttl returns the time the response of a method is cached
+func (c CacheConfig) ttl(method string) time.Duration {
+	if ttl, ok := c.MethodTTLs[method]; ok {
+		return ttl
+	}
+	return c.TTL
+}
+
+// cacheRefreshKey is the context key of the tool calls bypassing the cache
+type cacheRefreshKey struct{}
+
+// withCacheRefresh returns a copy of the context in which cached responses are
+// fetched again from Portainer, and the cache updated with them
+func withCacheRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheRefreshKey{}, true)
+}
+
+// isCacheRefresh checks if cached responses are bypassed with the context
+func isCacheRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(cacheRefreshKey{}).(bool)
+	return refresh
+}
+
+// cacheEntry is a cached response along with its expiry time
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// cachingClient is a PortainerClient decorator caching the responses of the
+// list methods. The methods that are not cached are passed to the decorated
+// client. Cached responses are shared between callers, which must not modify
+// them.
+type cachingClient struct {
+	PortainerClient
+
+	config CacheConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// generations counts the invalidations of every method, so that a response
+	// fetched before an invalidation is not cached after it
+	generations map[string]uint64
+}
+
+// newCachingClient returns a client caching the list responses of the given client
+func newCachingClient(next PortainerClient, config CacheConfig) *cachingClient {
+	return &cachingClient{
+		PortainerClient: next,
+		config:          config,
+		now:             time.Now,
+		entries:         map[string]cacheEntry{},
+		generations:     map[string]uint64{},
+	}
+}
+
+// cached returns the cached response of a method, or fetches and caches it
+func cached[T any](ctx context.Context, c *cachingClient, method string, fetch func() (T, error)) (T, error) {
+	ttl := c.config.ttl(method)
+	if ttl <= 0 {
+		return fetch()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[method]
+	generation := c.generations[method]
+	c.mu.Unlock()
+
+	if ok && !isCacheRefresh(ctx) && c.now().Before(entry.expires) {
+		return entry.value.(T), nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	if c.generations[method] == generation {
+		c.entries[method] = cacheEntry{value: value, expires: c.now().Add(ttl)}
+	}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// invalidate drops the cached responses of the given methods after a
+// successful write. Writes intercepted in dry-run mode change nothing and
+// keep the cache.
+func (c *cachingClient) invalidate(ctx context.Context, err error, methods ...string) {
+	if err != nil || client.IsDryRun(ctx) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, method := range methods {
+		delete(c.entries, method)
+		c.generations[method]++
+	}
+}
+
+// refreshHandler returns a handler bypassing the cache when the tool call has
+// the refresh argument set
+func refreshHandler(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		refresh, err := parser.GetBoolean(RefreshParam, false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid refresh parameter", err), nil
+		}
+
+		if refresh {
+			ctx = withCacheRefresh(ctx)
+		}
+
+		return handler(ctx, request)
+	}
+}
+
+// withRefreshParam returns a copy of the tool accepting the refresh argument
+func withRefreshParam(tool mcp.Tool) mcp.Tool {
+	return withToolProperty(tool, RefreshParam, map[string]any{
+		"type":        "boolean",
+		"description": "Fetch fresh data from Portainer instead of a recently cached response. Defaults to false.",
+	})
+}
+
+// Cached read methods
+
+func (c *cachingClient) GetEnvironmentTags(ctx context.Context) ([]models.EnvironmentTag, error) {
+	return cached(ctx, c, cacheGetEnvironmentTags, func() ([]models.EnvironmentTag, error) {
+		return c.PortainerClient.GetEnvironmentTags(ctx)
+	})
+}
+
+func (c *cachingClient) GetEnvironments(ctx context.Context) ([]models.Environment, error) {
+	return cached(ctx, c, cacheGetEnvironments, func() ([]models.Environment, error) {
+		return c.PortainerClient.GetEnvironments(ctx)
+	})
+}
+
+func (c *cachingClient) GetAgentVersions(ctx context.Context) ([]string, error) {
+	return cached(ctx, c, cacheGetAgentVersions, func() ([]string, error) {
+		return c.PortainerClient.GetAgentVersions(ctx)
+	})
+}
+
+func (c *cachingClient) GetEnvironmentGroups(ctx context.Context) ([]models.Group, error) {
+	return cached(ctx, c, cacheGetEnvironmentGroups, func() ([]models.Group, error) {
+		return c.PortainerClient.GetEnvironmentGroups(ctx)
+	})
+}
+
+func (c *cachingClient) GetAccessGroups(ctx context.Context) ([]models.AccessGroup, error) {
+	return cached(ctx, c, cacheGetAccessGroups, func() ([]models.AccessGroup, error) {
+		return c.PortainerClient.GetAccessGroups(ctx)
+	})
+}
+
+func (c *cachingClient) GetStacks(ctx context.Context) ([]models.Stack, error) {
+	return cached(ctx, c, cacheGetStacks, func() ([]models.Stack, error) {
+		return c.PortainerClient.GetStacks(ctx)
+	})
+}
+
+func (c *cachingClient) GetDockerStacks(ctx context.Context) ([]models.DockerStack, error) {
+	return cached(ctx, c, cacheGetDockerStacks, func() ([]models.DockerStack, error) {
+		return c.PortainerClient.GetDockerStacks(ctx)
+	})
+}
+
+func (c *cachingClient) GetTeams(ctx context.Context) ([]models.Team, error) {
+	return cached(ctx, c, cacheGetTeams, func() ([]models.Team, error) {
+		return c.PortainerClient.GetTeams(ctx)
+	})
+}
+
+func (c *cachingClient) GetUsers(ctx context.Context) ([]models.User, error) {
+	return cached(ctx, c, cacheGetUsers, func() ([]models.User, error) {
+		return c.PortainerClient.GetUsers(ctx)
+	})
+}
+
+func (c *cachingClient) GetSettings(ctx context.Context) (models.PortainerSettings, error) {
+	return cached(ctx, c, cacheGetSettings, func() (models.PortainerSettings, error) {
+		return c.PortainerClient.GetSettings(ctx)
+	})
+}
+
+func (c *cachingClient) GetRegistries(ctx context.Context) ([]models.Registry, error) {
+	return cached(ctx, c, cacheGetRegistries, func() ([]models.Registry, error) {
+		return c.PortainerClient.GetRegistries(ctx)
+	})
+}
+
+func (c *cachingClient) GetEdgeJobs(ctx context.Context) ([]models.EdgeJob, error) {
+	return cached(ctx, c, cacheGetEdgeJobs, func() ([]models.EdgeJob, error) {
+		return c.PortainerClient.GetEdgeJobs(ctx)
+	})
+}
+
+func (c *cachingClient) GetCustomTemplates(ctx context.Context) ([]models.CustomTemplate, error) {
+	return cached(ctx, c, cacheGetCustomTemplates, func() ([]models.CustomTemplate, error) {
+		return c.PortainerClient.GetCustomTemplates(ctx)
+	})
+}
+
+func (c *cachingClient) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return cached(ctx, c, cacheGetWebhooks, func() ([]models.Webhook, error) {
+		return c.PortainerClient.GetWebhooks(ctx)
+	})
+}
+
+func (c *cachingClient) GetGitCredentials(ctx context.Context) ([]models.GitCredential, error) {
+	return cached(ctx, c, cacheGetGitCredentials, func() ([]models.GitCredential, error) {
+		return c.PortainerClient.GetGitCredentials(ctx)
+	})
+}
+
+func (c *cachingClient) GetAlertRules(ctx context.Context) ([]models.AlertingRule, error) {
+	return cached(ctx, c, cacheGetAlertRules, func() ([]models.AlertingRule, error) {
+		return c.PortainerClient.GetAlertRules(ctx)
+	})
+}
+
+func (c *cachingClient) GetPolicies(ctx context.Context) ([]models.Policy, error) {
+	return cached(ctx, c, cacheGetPolicies, func() ([]models.Policy, error) {
+		return c.PortainerClient.GetPolicies(ctx)
+	})
+}
+
+// Invalidating write methods
+
+func (c *cachingClient) CreateEnvironmentTag(ctx context.Context, name string) (int, error) {
+	id, err := c.PortainerClient.CreateEnvironmentTag(ctx, name)
+	c.invalidate(ctx, err, cacheGetEnvironmentTags)
+	return id, err
+}
+
+// Tags are referenced by the environments and the environment groups
+func (c *cachingClient) DeleteTag(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteTag(ctx, id)
+	c.invalidate(ctx, err, cacheGetEnvironmentTags, cacheGetEnvironments, cacheGetEnvironmentGroups)
+	return err
+}
+
+func (c *cachingClient) UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error {
+	err := c.PortainerClient.UpdateEnvironmentTags(ctx, id, tagIds)
+	c.invalidate(ctx, err, cacheGetEnvironments)
+	return err
+}
+
+func (c *cachingClient) UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
+	err := c.PortainerClient.UpdateEnvironmentUserAccesses(ctx, id, userAccesses)
+	c.invalidate(ctx, err, cacheGetEnvironments)
+	return err
+}
+
+func (c *cachingClient) UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
+	err := c.PortainerClient.UpdateEnvironmentTeamAccesses(ctx, id, teamAccesses)
+	c.invalidate(ctx, err, cacheGetEnvironments)
+	return err
+}
+
+// The group of an environment is also listed by its access group
+func (c *cachingClient) UpdateEnvironment(ctx context.Context, id int, name, publicURL string, groupID int) error {
+	err := c.PortainerClient.UpdateEnvironment(ctx, id, name, publicURL, groupID)
+	c.invalidate(ctx, err, cacheGetEnvironments, cacheGetAccessGroups)
+	return err
+}
+
+func (c *cachingClient) CreateEnvironmentGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
+	id, err := c.PortainerClient.CreateEnvironmentGroup(ctx, name, environmentIds)
+	c.invalidate(ctx, err, cacheGetEnvironmentGroups)
+	return id, err
+}
+
+func (c *cachingClient) UpdateEnvironmentGroupName(ctx context.Context, id int, name string) error {
+	err := c.PortainerClient.UpdateEnvironmentGroupName(ctx, id, name)
+	c.invalidate(ctx, err, cacheGetEnvironmentGroups)
+	return err
+}
+
+func (c *cachingClient) UpdateEnvironmentGroupEnvironments(ctx context.Context, id int, environmentIds []int) error {
+	err := c.PortainerClient.UpdateEnvironmentGroupEnvironments(ctx, id, environmentIds)
+	c.invalidate(ctx, err, cacheGetEnvironmentGroups)
+	return err
+}
+
+func (c *cachingClient) UpdateEnvironmentGroupTags(ctx context.Context, id int, tagIds []int) error {
+	err := c.PortainerClient.UpdateEnvironmentGroupTags(ctx, id, tagIds)
+	c.invalidate(ctx, err, cacheGetEnvironmentGroups)
+	return err
+}
+
+func (c *cachingClient) DeleteEnvironmentGroup(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteEnvironmentGroup(ctx, id)
+	c.invalidate(ctx, err, cacheGetEnvironmentGroups)
+	return err
+}
+
+// Access groups are referenced by the environments
+func (c *cachingClient) CreateAccessGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
+	id, err := c.PortainerClient.CreateAccessGroup(ctx, name, environmentIds)
+	c.invalidate(ctx, err, cacheGetAccessGroups, cacheGetEnvironments)
+	return id, err
+}
+
+func (c *cachingClient) UpdateAccessGroupName(ctx context.Context, id int, name string) error {
+	err := c.PortainerClient.UpdateAccessGroupName(ctx, id, name)
+	c.invalidate(ctx, err, cacheGetAccessGroups)
+	return err
+}
+
+func (c *cachingClient) UpdateAccessGroupUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
+	err := c.PortainerClient.UpdateAccessGroupUserAccesses(ctx, id, userAccesses)
+	c.invalidate(ctx, err, cacheGetAccessGroups)
+	return err
+}
+
+func (c *cachingClient) UpdateAccessGroupTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
+	err := c.PortainerClient.UpdateAccessGroupTeamAccesses(ctx, id, teamAccesses)
+	c.invalidate(ctx, err, cacheGetAccessGroups)
+	return err
+}
+
+func (c *cachingClient) AddEnvironmentToAccessGroup(ctx context.Context, id int, environmentId int) error {
+	err := c.PortainerClient.AddEnvironmentToAccessGroup(ctx, id, environmentId)
+	c.invalidate(ctx, err, cacheGetAccessGroups, cacheGetEnvironments)
+	return err
+}
+
+func (c *cachingClient) RemoveEnvironmentFromAccessGroup(ctx context.Context, id int, environmentId int) error {
+	err := c.PortainerClient.RemoveEnvironmentFromAccessGroup(ctx, id, environmentId)
+	c.invalidate(ctx, err, cacheGetAccessGroups, cacheGetEnvironments)
+	return err
+}
+
+func (c *cachingClient) DeleteAccessGroup(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteAccessGroup(ctx, id)
+	c.invalidate(ctx, err, cacheGetAccessGroups, cacheGetEnvironments)
+	return err
+}
+
+func (c *cachingClient) CreateStack(ctx context.Context, name string, file string, environmentGroupIds []int) (int, error) {
+	id, err := c.PortainerClient.CreateStack(ctx, name, file, environmentGroupIds)
+	c.invalidate(ctx, err, cacheGetStacks)
+	return id, err
+}
+
+func (c *cachingClient) UpdateStack(ctx context.Context, id int, file string, environmentGroupIds []int) error {
+	err := c.PortainerClient.UpdateStack(ctx, id, file, environmentGroupIds)
+	c.invalidate(ctx, err, cacheGetStacks)
+	return err
+}
+
+func (c *cachingClient) DeleteEdgeStack(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteEdgeStack(ctx, id)
+	c.invalidate(ctx, err, cacheGetStacks)
+	return err
+}
+
+func (c *cachingClient) CreateDockerStack(ctx context.Context, endpointID int, name, composeFileContent string, env []models.StackEnvVar) (int, error) {
+	id, err := c.PortainerClient.CreateDockerStack(ctx, endpointID, name, composeFileContent, env)
+	c.invalidate(ctx, err, cacheGetDockerStacks)
+	return id, err
+}
+
+func (c *cachingClient) UpdateDockerStack(ctx context.Context, id, endpointID int, composeFileContent string, env []models.StackEnvVar, prune, pullImage bool) error {
+	err := c.PortainerClient.UpdateDockerStack(ctx, id, endpointID, composeFileContent, env, prune, pullImage)
+	c.invalidate(ctx, err, cacheGetDockerStacks)
+	return err
+}
+
+func (c *cachingClient) DeleteDockerStack(ctx context.Context, id, endpointID int) error {
+	err := c.PortainerClient.DeleteDockerStack(ctx, id, endpointID)
+	c.invalidate(ctx, err, cacheGetDockerStacks)
+	return err
+}
+
+func (c *cachingClient) StartDockerStack(ctx context.Context, id, endpointID int) error {
+	err := c.PortainerClient.StartDockerStack(ctx, id, endpointID)
+	c.invalidate(ctx, err, cacheGetDockerStacks)
+	return err
+}
+
+func (c *cachingClient) StopDockerStack(ctx context.Context, id, endpointID int) error {
+	err := c.PortainerClient.StopDockerStack(ctx, id, endpointID)
+	c.invalidate(ctx, err, cacheGetDockerStacks)
+	return err
+}
+
+func (c *cachingClient) CreateTeam(ctx context.Context, name string) (int, error) {
+	id, err := c.PortainerClient.CreateTeam(ctx, name)
+	c.invalidate(ctx, err, cacheGetTeams)
+	return id, err
+}
+
+func (c *cachingClient) UpdateTeamName(ctx context.Context, id int, name string) error {
+	err := c.PortainerClient.UpdateTeamName(ctx, id, name)
+	c.invalidate(ctx, err, cacheGetTeams)
+	return err
+}
+
+func (c *cachingClient) UpdateTeamMembers(ctx context.Context, id int, userIds []int) error {
+	err := c.PortainerClient.UpdateTeamMembers(ctx, id, userIds)
+	c.invalidate(ctx, err, cacheGetTeams)
+	return err
+}
+
+// Teams are referenced by the accesses of the environments and the access groups
+func (c *cachingClient) DeleteTeam(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteTeam(ctx, id)
+	c.invalidate(ctx, err, cacheGetTeams, cacheGetEnvironments, cacheGetAccessGroups)
+	return err
+}
+
+func (c *cachingClient) UpdateUserRole(ctx context.Context, id int, role string) error {
+	err := c.PortainerClient.UpdateUserRole(ctx, id, role)
+	c.invalidate(ctx, err, cacheGetUsers)
+	return err
+}
+
+func (c *cachingClient) UpdateSettings(ctx context.Context, settingsJSON string) error {
+	err := c.PortainerClient.UpdateSettings(ctx, settingsJSON)
+	c.invalidate(ctx, err, cacheGetSettings)
+	return err
+}
+
+func (c *cachingClient) CreateRegistry(ctx context.Context, req models.RegistryCreateRequest) (int, error) {
+	id, err := c.PortainerClient.CreateRegistry(ctx, req)
+	c.invalidate(ctx, err, cacheGetRegistries)
+	return id, err
+}
+
+func (c *cachingClient) DeleteRegistry(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteRegistry(ctx, id)
+	c.invalidate(ctx, err, cacheGetRegistries)
+	return err
+}
+
+func (c *cachingClient) CreateEdgeJob(ctx context.Context, req models.EdgeJobCreateRequest) (int, error) {
+	id, err := c.PortainerClient.CreateEdgeJob(ctx, req)
+	c.invalidate(ctx, err, cacheGetEdgeJobs)
+	return id, err
+}
+
+func (c *cachingClient) DeleteEdgeJob(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteEdgeJob(ctx, id)
+	c.invalidate(ctx, err, cacheGetEdgeJobs)
+	return err
+}
+
+func (c *cachingClient) CreateCustomTemplate(ctx context.Context, req models.CustomTemplateCreateRequest) (int, error) {
+	id, err := c.PortainerClient.CreateCustomTemplate(ctx, req)
+	c.invalidate(ctx, err, cacheGetCustomTemplates)
+	return id, err
+}
+
+func (c *cachingClient) DeleteCustomTemplate(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteCustomTemplate(ctx, id)
+	c.invalidate(ctx, err, cacheGetCustomTemplates)
+	return err
+}
+
+func (c *cachingClient) CreateWebhook(ctx context.Context, req models.WebhookCreateRequest) (int, error) {
+	id, err := c.PortainerClient.CreateWebhook(ctx, req)
+	c.invalidate(ctx, err, cacheGetWebhooks)
+	return id, err
+}
+
+func (c *cachingClient) DeleteWebhook(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteWebhook(ctx, id)
+	c.invalidate(ctx, err, cacheGetWebhooks)
+	return err
+}
+
+func (c *cachingClient) CreateGitCredential(ctx context.Context, req models.GitCredentialCreateRequest) (int, error) {
+	id, err := c.PortainerClient.CreateGitCredential(ctx, req)
+	c.invalidate(ctx, err, cacheGetGitCredentials)
+	return id, err
+}
+
+func (c *cachingClient) UpdateGitCredential(ctx context.Context, id int, req models.GitCredentialUpdateRequest) error {
+	err := c.PortainerClient.UpdateGitCredential(ctx, id, req)
+	c.invalidate(ctx, err, cacheGetGitCredentials)
+	return err
+}
+
+func (c *cachingClient) DeleteGitCredential(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteGitCredential(ctx, id)
+	c.invalidate(ctx, err, cacheGetGitCredentials)
+	return err
+}
+
+func (c *cachingClient) UpdateAlertRule(ctx context.Context, id int, ruleJSON string) error {
+	err := c.PortainerClient.UpdateAlertRule(ctx, id, ruleJSON)
+	c.invalidate(ctx, err, cacheGetAlertRules)
+	return err
+}
+
+func (c *cachingClient) DeleteAlertRule(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeleteAlertRule(ctx, id)
+	c.invalidate(ctx, err, cacheGetAlertRules)
+	return err
+}
+
+func (c *cachingClient) CreatePolicy(ctx context.Context, req models.PolicyCreateRequest) (int, error) {
+	id, err := c.PortainerClient.CreatePolicy(ctx, req)
+	c.invalidate(ctx, err, cacheGetPolicies)
+	return id, err
+}
+
+func (c *cachingClient) UpdatePolicy(ctx context.Context, id int, req models.PolicyUpdateRequest) error {
+	err := c.PortainerClient.UpdatePolicy(ctx, id, req)
+	c.invalidate(ctx, err, cacheGetPolicies)
+	return err
+}
+
+func (c *cachingClient) DeletePolicy(ctx context.Context, id int) error {
+	err := c.PortainerClient.DeletePolicy(ctx, id)
+	c.invalidate(ctx, err, cacheGetPolicies)
+	return err
+}
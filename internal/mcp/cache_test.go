@@ -0,0 +1,278 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCachingClient(config CacheConfig) (*cachingClient, *MockPortainerClient, *time.Time) {
+	mockClient := new(MockPortainerClient)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c := newCachingClient(mockClient, config)
+	c.now = func() time.Time { return now }
+
+	return c, mockClient, &now
+}
+
+func TestCachingClientCachesResponses(t *testing.T) {
+	c, mockClient, now := newTestCachingClient(CacheConfig{TTL: time.Minute})
+	mockClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{{ID: 1, Name: "prod"}}, nil)
+	ctx := context.Background()
+
+	for range 3 {
+		tags, err := c.GetEnvironmentTags(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.EnvironmentTag{{ID: 1, Name: "prod"}}, tags)
+	}
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 1)
+
+	*now = now.Add(time.Minute)
+	_, err := c.GetEnvironmentTags(ctx)
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 2)
+
+	_, err = c.GetEnvironmentTags(withCacheRefresh(ctx))
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 3)
+}
+
+func TestCachingClientDoesNotCacheErrors(t *testing.T) {
+	c, mockClient, _ := newTestCachingClient(CacheConfig{TTL: time.Minute})
+	mockClient.On("GetUsers").Return([]models.User(nil), errors.New("connection refused")).Once()
+	mockClient.On("GetUsers").Return([]models.User{{ID: 1, Username: "admin"}}, nil).Once()
+	ctx := context.Background()
+
+	_, err := c.GetUsers(ctx)
+	require.Error(t, err)
+
+	users, err := c.GetUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.User{{ID: 1, Username: "admin"}}, users)
+
+	_, err = c.GetUsers(ctx)
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetUsers", 2)
+}
+
+func TestCachingClientMethodTTLs(t *testing.T) {
+	c, mockClient, now := newTestCachingClient(CacheConfig{
+		TTL: time.Minute,
+		MethodTTLs: map[string]time.Duration{
+			cacheGetTeams: 0,
+			cacheGetUsers: 10 * time.Second,
+		},
+	})
+	mockClient.On("GetTeams").Return([]models.Team{}, nil)
+	mockClient.On("GetUsers").Return([]models.User{}, nil)
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := c.GetTeams(ctx)
+		require.NoError(t, err)
+		_, err = c.GetUsers(ctx)
+		require.NoError(t, err)
+	}
+	mockClient.AssertNumberOfCalls(t, "GetTeams", 2)
+	mockClient.AssertNumberOfCalls(t, "GetUsers", 1)
+
+	*now = now.Add(10 * time.Second)
+	_, err := c.GetUsers(ctx)
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetUsers", 2)
+}
+
+func TestCachingClientInvalidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           func() context.Context
+		writeError    error
+		expectedCalls int
+	}{
+		{
+			name:          "successful write invalidates",
+			ctx:           context.Background,
+			expectedCalls: 2,
+		},
+		{
+			name:          "failed write keeps the cache",
+			ctx:           context.Background,
+			writeError:    errors.New("tag already exists"),
+			expectedCalls: 1,
+		},
+		{
+			name: "dry-run write keeps the cache",
+			ctx: func() context.Context {
+				ctx, _ := client.WithDryRun(context.Background())
+				return ctx
+			},
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mockClient, _ := newTestCachingClient(CacheConfig{TTL: time.Minute})
+			mockClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{}, nil)
+			mockClient.On("GetEnvironments").Return([]models.Environment{}, nil)
+			mockClient.On("CreateEnvironmentTag", "staging").Return(2, tt.writeError)
+			ctx := tt.ctx()
+
+			_, err := c.GetEnvironmentTags(ctx)
+			require.NoError(t, err)
+			_, err = c.GetEnvironments(ctx)
+			require.NoError(t, err)
+
+			_, _ = c.CreateEnvironmentTag(ctx, "staging")
+
+			_, err = c.GetEnvironmentTags(ctx)
+			require.NoError(t, err)
+			_, err = c.GetEnvironments(ctx)
+			require.NoError(t, err)
+
+			mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", tt.expectedCalls)
+			mockClient.AssertNumberOfCalls(t, "GetEnvironments", 1)
+		})
+	}
+}
+
+func TestCachingClientRelatedInvalidation(t *testing.T) {
+	c, mockClient, _ := newTestCachingClient(CacheConfig{TTL: time.Minute})
+	mockClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{}, nil)
+	mockClient.On("GetEnvironments").Return([]models.Environment{}, nil)
+	mockClient.On("GetEnvironmentGroups").Return([]models.Group{}, nil)
+	mockClient.On("GetUsers").Return([]models.User{}, nil)
+	mockClient.On("DeleteTag", 1).Return(nil)
+	ctx := context.Background()
+
+	fetchAll := func() {
+		_, _ = c.GetEnvironmentTags(ctx)
+		_, _ = c.GetEnvironments(ctx)
+		_, _ = c.GetEnvironmentGroups(ctx)
+		_, _ = c.GetUsers(ctx)
+	}
+
+	fetchAll()
+	require.NoError(t, c.DeleteTag(ctx, 1))
+	fetchAll()
+
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 2)
+	mockClient.AssertNumberOfCalls(t, "GetEnvironments", 2)
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentGroups", 2)
+	mockClient.AssertNumberOfCalls(t, "GetUsers", 1)
+}
+
+func TestCacheConfigValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        CacheConfig
+		errorContains string
+	}{
+		{
+			name:   "valid",
+			config: CacheConfig{TTL: time.Minute, MethodTTLs: map[string]time.Duration{cacheGetEnvironments: 0}},
+		},
+		{
+			name:          "negative TTL",
+			config:        CacheConfig{TTL: -time.Second},
+			errorContains: "invalid cache TTL: -1s",
+		},
+		{
+			name:          "unknown method",
+			config:        CacheConfig{TTL: time.Minute, MethodTTLs: map[string]time.Duration{"GetStackFile": time.Minute}},
+			errorContains: "invalid cache TTL of GetStackFile: unknown method",
+		},
+		{
+			name:          "negative method TTL",
+			config:        CacheConfig{TTL: time.Minute, MethodTTLs: map[string]time.Duration{cacheGetUsers: -time.Second}},
+			errorContains: "invalid cache TTL of GetUsers: -1s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+}
+
+func TestRefreshParameter(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{{ID: 1, Name: "prod"}}, nil)
+
+	s := &PortainerMCPServer{
+		srv:   server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		cli:   newCachingClient(mockClient, CacheConfig{TTL: time.Minute}),
+		cache: true,
+		tools: map[string]mcp.Tool{
+			ToolListEnvironmentTags:  {Name: ToolListEnvironmentTags},
+			ToolCreateEnvironmentTag: {Name: ToolCreateEnvironmentTag},
+		},
+	}
+	s.addToolIfExists(ToolListEnvironmentTags, s.HandleGetEnvironmentTags())
+	s.addToolIfExists(ToolCreateEnvironmentTag, s.HandleCreateEnvironmentTag())
+
+	tool := s.srv.GetTool(ToolListEnvironmentTags)
+	require.NotNil(t, tool)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, RefreshParam)
+	assert.NotContains(t, s.srv.GetTool(ToolCreateEnvironmentTag).Tool.InputSchema.Properties, RefreshParam)
+
+	ctx := context.Background()
+
+	callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{})
+	callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{})
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 1)
+
+	callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{RefreshParam: true})
+	mockClient.AssertNumberOfCalls(t, "GetEnvironmentTags", 2)
+
+	result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{RefreshParam: "yes"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "invalid refresh parameter")
+}
+
+func TestScopeChecksBypassCache(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetEnvironmentGroups").Return([]models.Group{{ID: 5, EnvironmentIds: []int{1}}}, nil).Once()
+	mockClient.On("GetEnvironmentGroups").Return([]models.Group{{ID: 5, EnvironmentIds: []int{2}}}, nil)
+
+	cli := newCachingClient(mockClient, CacheConfig{TTL: time.Minute})
+	s := &PortainerMCPServer{
+		srv:   server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		cli:   cli,
+		cache: true,
+		tools: map[string]mcp.Tool{ToolDockerProxy: newAuditTestTool(ToolDockerProxy, false)},
+		scope: EnvironmentScope{EnvironmentGroupIDs: []int{5}},
+	}
+
+	called := false
+	s.addToolIfExists(ToolDockerProxy, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	// Environment 1 has left group 5 since the cached response
+	ctx := context.Background()
+	_, err := cli.GetEnvironmentGroups(ctx)
+	require.NoError(t, err)
+
+	result := callTool(t, s, ctx, ToolDockerProxy, map[string]any{"environmentId": float64(1), "method": "GET", "dockerAPIPath": "/containers/json"})
+
+	assert.False(t, called)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "environment 1 not found")
+}
@@ -0,0 +1,303 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+const (
+	// ConfirmationTokenParam is the tool argument carrying the confirmation
+	// token of a destructive tool call
+	ConfirmationTokenParam = "confirmationToken"
+
+	// DefaultConfirmationTTL is the duration during which a confirmation token can be used
+	DefaultConfirmationTTL = 5 * time.Minute
+)
+
+// ConfirmationRequest is the result of the first call of a destructive tool
+// when confirmation is required. The call is executed when the tool is
+// called again with the same arguments and the confirmation token.
+type ConfirmationRequest struct {
+	ConfirmationRequired bool           `json:"confirmationRequired"`
+	Tool                 string         `json:"tool"`
+	Summary              string         `json:"summary"`
+	Arguments            map[string]any `json:"arguments,omitempty"`
+	Target               any            `json:"target,omitempty"`
+	ConfirmationToken    string         `json:"confirmationToken"`
+	ExpiresAt            time.Time      `json:"expiresAt"`
+}
+
+// pendingConfirmation is a destructive tool call waiting for its confirmation
+type pendingConfirmation struct {
+	tool      string
+	arguments string
+	sessionID string
+	expiresAt time.Time
+}
+
+// confirmationStore holds the confirmation tokens issued for destructive tool calls
+type confirmationStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	pending map[string]pendingConfirmation
+}
+
+// WithDestructiveConfirmation requires destructive tool calls to be confirmed.
+// When the MCP client supports elicitation, the user is asked to confirm the
+// call directly. Otherwise, the first call returns a short-lived confirmation
+// token and a summary of what will be destroyed, and only a second call with
+// the same arguments carrying that token is executed.
+func WithDestructiveConfirmation(enabled bool) ServerOption {
+	return func(opts *serverOptions) {
+		opts.confirmDestructive = enabled
+	}
+}
+
+func newConfirmationStore(ttl time.Duration) *confirmationStore {
+	return &confirmationStore{
+		ttl:     ttl,
+		now:     time.Now,
+		pending: make(map[string]pendingConfirmation),
+	}
+}
+
+// issue creates a single-use confirmation token for a tool call
+func (c *confirmationStore) issue(tool, arguments, sessionID string) (string, time.Time, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(buf)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for t, p := range c.pending {
+		if now.After(p.expiresAt) {
+			delete(c.pending, t)
+		}
+	}
+
+	expiresAt := now.Add(c.ttl)
+	c.pending[token] = pendingConfirmation{
+		tool:      tool,
+		arguments: arguments,
+		sessionID: sessionID,
+		expiresAt: expiresAt,
+	}
+
+	return token, expiresAt, nil
+}
+
+// consume validates a confirmation token against a tool call.
+// A token is consumed by its first use, valid or not.
+func (c *confirmationStore) consume(token, tool, arguments, sessionID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.pending[token]
+	if !ok {
+		return fmt.Errorf("unknown or already used confirmation token")
+	}
+	delete(c.pending, token)
+
+	switch {
+	case c.now().After(pending.expiresAt):
+		return fmt.Errorf("confirmation token expired")
+	case pending.tool != tool:
+		return fmt.Errorf("confirmation token was issued for tool %s", pending.tool)
+	case pending.sessionID != sessionID:
+		return fmt.Errorf("confirmation token was issued for another session")
+	case pending.arguments != arguments:
+		return fmt.Errorf("arguments differ from the ones of the confirmed call")
+	}
+
+	return nil
+}
+
+// requiresConfirmation checks if a tool call must be confirmed.
+// Proxy tools are annotated as read-only and destructive, they only
+// destroy resources with the DELETE method.
+func requiresConfirmation(tool mcp.Tool, args map[string]any) bool {
+	if tool.Annotations.DestructiveHint == nil || !*tool.Annotations.DestructiveHint {
+		return false
+	}
+
+	if isReadOnlyTool(tool) {
+		method, _ := args["method"].(string)
+		return strings.EqualFold(method, http.MethodDelete)
+	}
+
+	return true
+}
+
+// withConfirmationParam returns a copy of the tool accepting the confirmation token argument
+func withConfirmationParam(tool mcp.Tool) mcp.Tool {
+	properties := maps.Clone(tool.InputSchema.Properties)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+
+	properties[ConfirmationTokenParam] = map[string]any{
+		"type":        "string",
+		"description": "The confirmation token returned by a previous call of this tool with the same arguments. Required to execute this destructive operation.",
+	}
+
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// confirmationHandler returns a handler that only runs the given destructive
+// handler once the call has been confirmed, either by the user through MCP
+// elicitation or with a confirmation token.
+func (s *PortainerMCPServer) confirmationHandler(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		if !requiresConfirmation(tool, args) {
+			return handler(ctx, request)
+		}
+
+		arguments, err := confirmationArguments(args)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal arguments", err), nil
+		}
+
+		if token, ok := args[ConfirmationTokenParam].(string); ok && token != "" {
+			if err := s.confirmations.consume(token, tool.Name, arguments, sessionIDFromContext(ctx)); err != nil {
+				return mcp.NewToolResultErrorFromErr("invalid confirmation token", err), nil
+			}
+			return handler(ctx, request)
+		}
+
+		confirmation := ConfirmationRequest{
+			ConfirmationRequired: true,
+			Tool:                 tool.Name,
+			Summary:              confirmationSummary(tool, args),
+			Arguments:            redactArguments(withoutConfirmationToken(args)),
+		}
+
+		if target, ok := dryRunTargets[tool.Name]; ok && target.fetch != nil {
+			current, err := target.fetch(ctx, s.client(ctx), toolgen.NewParameterParser(request))
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to fetch the resource to destroy", err), nil
+			}
+
+			normalized, err := normalizeJSON(current)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to marshal the resource to destroy", err), nil
+			}
+			confirmation.Target = redactValue(normalized)
+		}
+
+		if confirmed, ok := s.elicitConfirmation(ctx, confirmation); ok {
+			if !confirmed {
+				return mcp.NewToolResultError(fmt.Sprintf("%s was not confirmed by the user", tool.Name)), nil
+			}
+			return handler(ctx, request)
+		}
+
+		confirmation.ConfirmationToken, confirmation.ExpiresAt, err = s.confirmations.issue(tool.Name, arguments, sessionIDFromContext(ctx))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to create confirmation token", err), nil
+		}
+
+		data, err := json.Marshal(confirmation)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal confirmation request", err), nil
+		}
+
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// elicitConfirmation asks the user to confirm a destructive call when the
+// client supports elicitation. The second value is false when the client
+// cannot be asked, in which case the token protocol must be used.
+func (s *PortainerMCPServer) elicitConfirmation(ctx context.Context, confirmation ConfirmationRequest) (bool, bool) {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok || session.GetClientCapabilities().Elicitation == nil {
+		return false, false
+	}
+
+	message := confirmation.Summary
+	if confirmation.Target != nil {
+		if target, err := json.Marshal(confirmation.Target); err == nil {
+			message += "\n\n" + string(target)
+		}
+	}
+
+	result, err := s.srv.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message: message,
+			RequestedSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"confirm": map[string]any{
+						"type":        "boolean",
+						"description": "Confirm the operation",
+					},
+				},
+				"required": []string{"confirm"},
+			},
+		},
+	})
+	if err != nil {
+		return false, false
+	}
+
+	if result.Action != mcp.ElicitationResponseActionAccept {
+		return false, true
+	}
+
+	content, _ := result.Content.(map[string]any)
+	confirmed, _ := content["confirm"].(bool)
+	return confirmed, true
+}
+
+// confirmationSummary describes what a destructive tool call will destroy
+func confirmationSummary(tool mcp.Tool, args map[string]any) string {
+	if isReadOnlyTool(tool) {
+		path, _ := args["dockerAPIPath"].(string)
+		if path == "" {
+			path, _ = args["kubernetesAPIPath"].(string)
+		}
+		return fmt.Sprintf("%s will send a DELETE request to %s on environment %v. This cannot be undone.", tool.Name, path, args["environmentId"])
+	}
+
+	return fmt.Sprintf("%s will permanently remove the target resource. This cannot be undone.", tool.Name)
+}
+
+// confirmationArguments returns the canonical JSON representation of the
+// arguments of a tool call, without its confirmation token
+func confirmationArguments(args map[string]any) (string, error) {
+	data, err := json.Marshal(withoutConfirmationToken(args))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// withoutConfirmationToken returns a copy of the arguments without the confirmation token
+func withoutConfirmationToken(args map[string]any) map[string]any {
+	if _, ok := args[ConfirmationTokenParam]; !ok {
+		return args
+	}
+
+	clone := maps.Clone(args)
+	delete(clone, ConfirmationTokenParam)
+	return clone
+}
@@ -0,0 +1,283 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// elicitationTestSession is a MCP client session supporting elicitation,
+// answering every elicitation request with the configured result
+type elicitationTestSession struct {
+	auditTestSession
+	result   *mcp.ElicitationResult
+	requests []mcp.ElicitationRequest
+}
+
+func (s *elicitationTestSession) GetClientInfo() mcp.Implementation           { return mcp.Implementation{} }
+func (s *elicitationTestSession) SetClientInfo(clientInfo mcp.Implementation) {}
+func (s *elicitationTestSession) GetClientCapabilities() mcp.ClientCapabilities {
+	return mcp.ClientCapabilities{Elicitation: &mcp.ElicitationCapability{}}
+}
+func (s *elicitationTestSession) SetClientCapabilities(clientCapabilities mcp.ClientCapabilities) {}
+func (s *elicitationTestSession) RequestElicitation(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	s.requests = append(s.requests, request)
+	return s.result, nil
+}
+
+func newDestructiveTestTool(name string, readOnly bool) mcp.Tool {
+	return mcp.Tool{
+		Name: name,
+		Annotations: mcp.ToolAnnotation{
+			ReadOnlyHint:    mcp.ToBoolPtr(readOnly),
+			DestructiveHint: mcp.ToBoolPtr(true),
+		},
+	}
+}
+
+func newConfirmationTestServer(t *testing.T) (*PortainerMCPServer, *int) {
+	t.Helper()
+
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetEnvironmentGroups").Return([]models.Group{{ID: 1, Name: "production"}}, nil)
+
+	s := &PortainerMCPServer{
+		srv:           server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true), server.WithElicitation()),
+		cli:           mockClient,
+		tools:         map[string]mcp.Tool{ToolDeleteEnvironmentGroup: newDestructiveTestTool(ToolDeleteEnvironmentGroup, false)},
+		confirmations: newConfirmationStore(DefaultConfirmationTTL),
+	}
+
+	calls := 0
+	s.addToolIfExists(ToolDeleteEnvironmentGroup, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("Environment group deleted successfully"), nil
+	})
+
+	return s, &calls
+}
+
+func callTool(t *testing.T, s *PortainerMCPServer, ctx context.Context, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+
+	tool := s.srv.GetTool(name)
+	require.NotNil(t, tool)
+
+	result, err := tool.Handler(ctx, CreateMCPRequest(args))
+	require.NoError(t, err)
+	return result
+}
+
+func TestRequiresConfirmation(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     mcp.Tool
+		args     map[string]any
+		expected bool
+	}{
+		{
+			name:     "destructive tool",
+			tool:     newDestructiveTestTool(ToolDeleteDockerStack, false),
+			expected: true,
+		},
+		{
+			name:     "non destructive write tool",
+			tool:     newAuditTestTool(ToolCreateEnvironmentTag, false),
+			expected: false,
+		},
+		{
+			name:     "proxy tool with DELETE method",
+			tool:     newDestructiveTestTool(ToolDockerProxy, true),
+			args:     map[string]any{"method": "delete"},
+			expected: true,
+		},
+		{
+			name:     "proxy tool with GET method",
+			tool:     newDestructiveTestTool(ToolDockerProxy, true),
+			args:     map[string]any{"method": "GET"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, requiresConfirmation(tt.tool, tt.args))
+		})
+	}
+}
+
+func TestConfirmationStore(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		tool          string
+		arguments     string
+		sessionID     string
+		elapsed       time.Duration
+		errorContains string
+	}{
+		{
+			name:      "valid token",
+			tool:      ToolDeleteTag,
+			arguments: `{"id":1}`,
+			sessionID: "session-1",
+		},
+		{
+			name:          "expired token",
+			tool:          ToolDeleteTag,
+			arguments:     `{"id":1}`,
+			sessionID:     "session-1",
+			elapsed:       DefaultConfirmationTTL + time.Second,
+			errorContains: "expired",
+		},
+		{
+			name:          "other tool",
+			tool:          ToolDeleteTeam,
+			arguments:     `{"id":1}`,
+			sessionID:     "session-1",
+			errorContains: "issued for tool deleteTag",
+		},
+		{
+			name:          "other session",
+			tool:          ToolDeleteTag,
+			arguments:     `{"id":1}`,
+			sessionID:     "session-2",
+			errorContains: "another session",
+		},
+		{
+			name:          "other arguments",
+			tool:          ToolDeleteTag,
+			arguments:     `{"id":2}`,
+			sessionID:     "session-1",
+			errorContains: "arguments differ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newConfirmationStore(DefaultConfirmationTTL)
+			store.now = func() time.Time { return now }
+
+			token, expiresAt, err := store.issue(ToolDeleteTag, `{"id":1}`, "session-1")
+			require.NoError(t, err)
+			assert.Len(t, token, 32)
+			assert.Equal(t, now.Add(DefaultConfirmationTTL), expiresAt)
+
+			store.now = func() time.Time { return now.Add(tt.elapsed) }
+
+			err = store.consume(token, tt.tool, tt.arguments, tt.sessionID)
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Tokens are single-use
+			err = store.consume(token, ToolDeleteTag, `{"id":1}`, "session-1")
+			assert.ErrorContains(t, err, "unknown or already used")
+		})
+	}
+}
+
+func TestConfirmationTokenProtocol(t *testing.T) {
+	s, calls := newConfirmationTestServer(t)
+	ctx := context.Background()
+
+	tool := s.srv.GetTool(ToolDeleteEnvironmentGroup)
+	require.NotNil(t, tool)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, ConfirmationTokenParam)
+
+	// First call returns a confirmation token
+	result := callTool(t, s, ctx, ToolDeleteEnvironmentGroup, map[string]any{"id": float64(1)})
+	require.False(t, result.IsError)
+	assert.Equal(t, 0, *calls)
+
+	var confirmation ConfirmationRequest
+	require.NoError(t, json.Unmarshal([]byte(resultText(result)), &confirmation))
+	assert.True(t, confirmation.ConfirmationRequired)
+	assert.Equal(t, ToolDeleteEnvironmentGroup, confirmation.Tool)
+	assert.NotEmpty(t, confirmation.ConfirmationToken)
+	assert.Equal(t, map[string]any{"id": float64(1), "name": "production", "environment_ids": nil, "tag_ids": nil}, confirmation.Target)
+
+	// A token does not confirm a call with other arguments
+	result = callTool(t, s, ctx, ToolDeleteEnvironmentGroup, map[string]any{"id": float64(2), ConfirmationTokenParam: confirmation.ConfirmationToken})
+	assert.True(t, result.IsError)
+	assert.Equal(t, 0, *calls)
+
+	// The token was consumed by the failed attempt
+	result = callTool(t, s, ctx, ToolDeleteEnvironmentGroup, map[string]any{"id": float64(1), ConfirmationTokenParam: confirmation.ConfirmationToken})
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "already used")
+	assert.Equal(t, 0, *calls)
+
+	// A new token confirms the call
+	result = callTool(t, s, ctx, ToolDeleteEnvironmentGroup, map[string]any{"id": float64(1)})
+	require.NoError(t, json.Unmarshal([]byte(resultText(result)), &confirmation))
+
+	result = callTool(t, s, ctx, ToolDeleteEnvironmentGroup, map[string]any{"id": float64(1), ConfirmationTokenParam: confirmation.ConfirmationToken})
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Environment group deleted successfully", resultText(result))
+	assert.Equal(t, 1, *calls)
+}
+
+func TestConfirmationElicitation(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      *mcp.ElicitationResult
+		expectCall  bool
+		expectError bool
+	}{
+		{
+			name: "confirmed",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action:  mcp.ElicitationResponseActionAccept,
+				Content: map[string]any{"confirm": true},
+			}},
+			expectCall: true,
+		},
+		{
+			name: "accepted without confirming",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action:  mcp.ElicitationResponseActionAccept,
+				Content: map[string]any{"confirm": false},
+			}},
+			expectError: true,
+		},
+		{
+			name: "declined",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action: mcp.ElicitationResponseActionDecline,
+			}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, calls := newConfirmationTestServer(t)
+
+			session := &elicitationTestSession{auditTestSession: auditTestSession{id: "session-1"}, result: tt.result}
+			ctx := s.srv.WithContext(context.Background(), session)
+
+			result := callTool(t, s, ctx, ToolDeleteEnvironmentGroup, map[string]any{"id": float64(1)})
+
+			require.Len(t, session.requests, 1)
+			assert.Contains(t, session.requests[0].Params.Message, ToolDeleteEnvironmentGroup)
+			assert.Contains(t, session.requests[0].Params.Message, "production")
+			assert.Equal(t, tt.expectError, result.IsError)
+			if tt.expectCall {
+				assert.Equal(t, 1, *calls)
+			} else {
+				assert.Equal(t, 0, *calls)
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 // AddCustomResourceFeatures registers all Kubernetes CRD and custom resource related tools.
@@ -26,14 +25,12 @@ func (s *PortainerMCPServer) AddCustomResourceFeatures() {
 // HandleListCustomResourceDefinitions returns a handler that lists all CRDs in a Kubernetes environment.
 func (s *PortainerMCPServer) HandleListCustomResourceDefinitions() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseListCustomResourceDefinitionsArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		crds, err := s.cli.ListCustomResourceDefinitions(environmentId)
+		crds, err := s.client(ctx).ListCustomResourceDefinitions(ctx, args.EnvironmentID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to list custom resource definitions", err), nil
 		}
@@ -43,26 +40,19 @@ func (s *PortainerMCPServer) HandleListCustomResourceDefinitions() server.ToolHa
 			return mcp.NewToolResultErrorFromErr("failed to marshal custom resource definitions", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(crds, data), nil
 	}
 }
 
 // HandleGetCustomResourceDefinition returns a handler that retrieves a specific CRD.
 func (s *PortainerMCPServer) HandleGetCustomResourceDefinition() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseGetCustomResourceDefinitionArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		crd, err := s.cli.GetCustomResourceDefinition(environmentId, name)
+		crd, err := s.client(ctx).GetCustomResourceDefinition(ctx, args.EnvironmentID, args.Name)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get custom resource definition", err), nil
 		}
@@ -72,50 +62,36 @@ func (s *PortainerMCPServer) HandleGetCustomResourceDefinition() server.ToolHand
 			return mcp.NewToolResultErrorFromErr("failed to marshal custom resource definition", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(crd, data), nil
 	}
 }
 
 // HandleDeleteCustomResourceDefinition returns a handler that deletes a CRD.
 func (s *PortainerMCPServer) HandleDeleteCustomResourceDefinition() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseDeleteCustomResourceDefinitionArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteCustomResourceDefinition(environmentId, name)
+		err = s.client(ctx).DeleteCustomResourceDefinition(ctx, args.EnvironmentID, args.Name)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete custom resource definition", err), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Custom resource definition %s deleted successfully", name)), nil
+		return mcp.NewToolResultText(fmt.Sprintf("Custom resource definition %s deleted successfully", args.Name)), nil
 	}
 }
 
 // HandleListCustomResources returns a handler that lists custom resources for a given CRD.
 func (s *PortainerMCPServer) HandleListCustomResources() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseListCustomResourcesArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		definition, err := parser.GetString("definition", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid definition parameter", err), nil
-		}
-
-		resources, err := s.cli.ListCustomResources(environmentId, definition)
+		resources, err := s.client(ctx).ListCustomResources(ctx, args.EnvironmentID, args.Definition)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to list custom resources", err), nil
 		}
@@ -125,41 +101,19 @@ func (s *PortainerMCPServer) HandleListCustomResources() server.ToolHandlerFunc
 			return mcp.NewToolResultErrorFromErr("failed to marshal custom resources", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(resources, data), nil
 	}
 }
 
 // HandleGetCustomResource returns a handler that retrieves a specific custom resource.
 func (s *PortainerMCPServer) HandleGetCustomResource() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		definition, err := parser.GetString("definition", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid definition parameter", err), nil
-		}
-
-		namespace, err := parser.GetString("namespace", false)
+		args, err := ParseGetCustomResourceArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		format, err := parser.GetString("format", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid format parameter", err), nil
-		}
-
-		resource, err := s.cli.GetCustomResource(environmentId, namespace, name, definition, format)
+		resource, err := s.client(ctx).GetCustomResource(ctx, args.EnvironmentID, args.Namespace, args.Name, args.Definition, args.Format)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get custom resource", err), nil
 		}
@@ -171,33 +125,16 @@ func (s *PortainerMCPServer) HandleGetCustomResource() server.ToolHandlerFunc {
 // HandleDeleteCustomResource returns a handler that deletes a custom resource.
 func (s *PortainerMCPServer) HandleDeleteCustomResource() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		definition, err := parser.GetString("definition", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid definition parameter", err), nil
-		}
-
-		namespace, err := parser.GetString("namespace", false)
+		args, err := ParseDeleteCustomResourceArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid namespace parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteCustomResource(environmentId, namespace, name, definition)
+		err = s.client(ctx).DeleteCustomResource(ctx, args.EnvironmentID, args.Namespace, args.Name, args.Definition)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete custom resource", err), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Custom resource %s deleted successfully", name)), nil
+		return mcp.NewToolResultText(fmt.Sprintf("Custom resource %s deleted successfully", args.Name)), nil
 	}
 }
@@ -8,7 +8,6 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddCustomTemplateFeatures() {
@@ -22,7 +21,7 @@ func (s *PortainerMCPServer) AddCustomTemplateFeatures() {
 
 func (s *PortainerMCPServer) HandleListCustomTemplates() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		templates, err := s.cli.GetCustomTemplates()
+		templates, err := s.client(ctx).GetCustomTemplates(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get custom templates", err), nil
 		}
@@ -32,48 +31,26 @@ func (s *PortainerMCPServer) HandleListCustomTemplates() server.ToolHandlerFunc
 			return mcp.NewToolResultErrorFromErr("failed to marshal custom templates", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(templates, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleCreateCustomTemplate() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		title, err := parser.GetString("title", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid title parameter", err), nil
-		}
-
-		description, err := parser.GetString("description", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid description parameter", err), nil
-		}
-
-		fileContent, err := parser.GetString("fileContent", true)
+		args, err := ParseCreateCustomTemplateArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid fileContent parameter", err), nil
-		}
-
-		templateType, err := parser.GetInt("type", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
-		}
-
-		platform, err := parser.GetInt("platform", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid platform parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.CustomTemplateCreateRequest{
-			Title:       title,
-			Description: description,
-			FileContent: fileContent,
-			Type:        templateType,
-			Platform:    platform,
+			Title:       args.Title,
+			Description: args.Description,
+			FileContent: args.FileContent,
+			Type:        args.Type,
+			Platform:    args.Platform,
 		}
 
-		id, err := s.cli.CreateCustomTemplate(req)
+		id, err := s.client(ctx).CreateCustomTemplate(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create custom template", err), nil
 		}
@@ -84,14 +61,12 @@ func (s *PortainerMCPServer) HandleCreateCustomTemplate() server.ToolHandlerFunc
 
 func (s *PortainerMCPServer) HandleDeleteCustomTemplate() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteCustomTemplateArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteCustomTemplate(id)
+		err = s.client(ctx).DeleteCustomTemplate(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete custom template", err), nil
 		}
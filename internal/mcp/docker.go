@@ -8,8 +8,8 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddDockerProxyFeatures() {
@@ -20,69 +20,58 @@ func (s *PortainerMCPServer) AddDockerProxyFeatures() {
 
 func (s *PortainerMCPServer) HandleDockerProxy() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseDockerProxyArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		method, err := parser.GetString("method", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid method parameter", err), nil
-		}
-		if !isValidHTTPMethod(method) {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid method: %s", method)), nil
+		if !isValidHTTPMethod(args.Method) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid method: %s", args.Method)), nil
 		}
 
-		dockerAPIPath, err := parser.GetString("dockerAPIPath", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid dockerAPIPath parameter", err), nil
-		}
-		if !strings.HasPrefix(dockerAPIPath, "/") {
+		if !strings.HasPrefix(args.DockerAPIPath, "/") {
 			return mcp.NewToolResultError("dockerAPIPath must start with a leading slash"), nil
 		}
 
-		queryParams, err := parser.GetArrayOfObjects("queryParams", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid queryParams parameter", err), nil
+		if strings.Contains(args.DockerAPIPath, "?") {
+			return mcp.NewToolResultError("dockerAPIPath must not contain a query string, use queryParams instead"), nil
 		}
-		queryParamsMap, err := parseKeyValueMap(queryParams)
+
+		queryParamsMap, err := parseKeyValueMap(args.QueryParams)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid query params", err), nil
 		}
 
-		headers, err := parser.GetArrayOfObjects("headers", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid headers parameter", err), nil
-		}
-		headersMap, err := parseKeyValueMap(headers)
+		headersMap, err := parseKeyValueMap(args.Headers)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid headers", err), nil
 		}
 
-		body, err := parser.GetString("body", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid body parameter", err), nil
+		if err := s.proxyPolicy.check(proxyAPIDocker, args.EnvironmentID, args.Method, args.DockerAPIPath, queryParamsMap, args.Body); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		opts := models.DockerProxyRequestOptions{
-			EnvironmentID: environmentId,
-			Path:          dockerAPIPath,
-			Method:        method,
+			EnvironmentID: args.EnvironmentID,
+			Path:          args.DockerAPIPath,
+			Method:        args.Method,
 			QueryParams:   queryParamsMap,
 			Headers:       headersMap,
 		}
 
-		if body != "" {
-			opts.Body = strings.NewReader(body)
+		if args.Body != "" {
+			opts.Body = strings.NewReader(args.Body)
 		}
 
-		response, err := s.cli.ProxyDockerRequest(opts)
+		response, err := s.client(ctx).ProxyDockerRequest(ctx, opts)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", err), nil
 		}
 
+		if client.IsDryRunResponse(response) {
+			return mcp.NewToolResultErrorFromErr("failed to send Docker API request", client.ErrDryRun), nil
+		}
+
 		responseBody, err := io.ReadAll(response.Body)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to read Docker API response", err), nil
@@ -8,7 +8,6 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddDockerStackFeatures() {
@@ -26,30 +25,34 @@ func (s *PortainerMCPServer) AddDockerStackFeatures() {
 
 func (s *PortainerMCPServer) HandleListDockerStacks() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		stacks, err := s.cli.GetDockerStacks()
+		stacks, err := s.client(ctx).GetDockerStacks(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get docker stacks", err), nil
 		}
 
+		inScope, err := s.scopedEnvironmentIDs(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		stacks = filterScoped(inScope, stacks, func(s models.DockerStack) []int { return []int{s.EndpointID} })
+
 		data, err := json.Marshal(stacks)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal docker stacks", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(stacks, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleGetDockerStackFile() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseGetDockerStackFileArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		file, err := s.cli.GetDockerStackFile(id)
+		file, err := s.client(ctx).GetDockerStackFile(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get docker stack file", err), nil
 		}
@@ -60,24 +63,12 @@ func (s *PortainerMCPServer) HandleGetDockerStackFile() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleCreateDockerStack() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		file, err := parser.GetString("file", true)
+		args, err := ParseCreateDockerStackArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid file parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		id, err := s.cli.CreateDockerStack(environmentId, name, file, nil)
+		id, err := s.client(ctx).CreateDockerStack(ctx, args.EnvironmentID, args.Name, args.File, nil)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create docker stack", err), nil
 		}
@@ -88,34 +79,12 @@ func (s *PortainerMCPServer) HandleCreateDockerStack() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleUpdateDockerStack() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		environmentId, err := parser.GetInt("environmentId", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
-		}
-
-		file, err := parser.GetString("file", true)
+		args, err := ParseUpdateDockerStackArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid file parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		prune, err := parser.GetBoolean("prune", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid prune parameter", err), nil
-		}
-
-		pullImage, err := parser.GetBoolean("pullImage", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid pullImage parameter", err), nil
-		}
-
-		err = s.cli.UpdateDockerStack(id, environmentId, file, nil, prune, pullImage)
+		err = s.client(ctx).UpdateDockerStack(ctx, args.ID, args.EnvironmentID, args.File, nil, args.Prune, args.PullImage)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update docker stack", err), nil
 		}
@@ -126,19 +95,12 @@ func (s *PortainerMCPServer) HandleUpdateDockerStack() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleDeleteDockerStack() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseDeleteDockerStackArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteDockerStack(id, environmentId)
+		err = s.client(ctx).DeleteDockerStack(ctx, args.ID, args.EnvironmentID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete docker stack", err), nil
 		}
@@ -149,19 +111,12 @@ func (s *PortainerMCPServer) HandleDeleteDockerStack() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleStartDockerStack() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseStartDockerStackArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.StartDockerStack(id, environmentId)
+		err = s.client(ctx).StartDockerStack(ctx, args.ID, args.EnvironmentID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to start docker stack", err), nil
 		}
@@ -172,19 +127,12 @@ func (s *PortainerMCPServer) HandleStartDockerStack() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleStopDockerStack() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseStopDockerStackArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.StopDockerStack(id, environmentId)
+		err = s.client(ctx).StopDockerStack(ctx, args.ID, args.EnvironmentID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to stop docker stack", err), nil
 		}
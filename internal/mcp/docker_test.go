@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -81,6 +82,15 @@ func TestHandleDockerProxy_ParameterValidation(t *testing.T) {
 			},
 			expectedErrorMsg: "dockerAPIPath must start with a leading slash",
 		},
+		{
+			name: "invalid dockerAPIPath (query string)",
+			inputParams: map[string]any{
+				"environmentId": float64(1),
+				"dockerAPIPath": "/containers/json?all=true",
+				"method":        "GET",
+			},
+			expectedErrorMsg: "dockerAPIPath must not contain a query string",
+		},
 		{
 			name: "invalid HTTP method",
 			inputParams: map[string]any{
@@ -218,6 +228,31 @@ func TestHandleDockerProxy_ClientInteraction(t *testing.T) {
 				resultText: `{"Id":"456"}`,
 			},
 		},
+		{
+			name: "request intercepted in dry-run mode",
+			input: map[string]any{
+				"environmentId": float64(2),
+				"dockerAPIPath": "/containers/create",
+				"method":        "POST",
+				"body":          `{"name":"test"}`,
+			},
+			mock: struct {
+				response *http.Response
+				err      error
+			}{
+				response: &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{client.DryRunHeader: []string{"true"}},
+					Body:       io.NopCloser(strings.NewReader("{}")),
+				},
+			},
+			expect: struct {
+				errSubstring string
+				resultText   string
+			}{
+				errSubstring: "failed to send Docker API request: the request was not sent in dry-run mode",
+			},
+		},
 		{
 			name: "client API error",
 			input: map[string]any{
@@ -0,0 +1,284 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// DryRunPlan is the result returned by write tools in dry-run mode.
+// It describes the Portainer API requests the tool would have sent and
+// the expected effect on the targeted resource.
+type DryRunPlan struct {
+	DryRun   bool                    `json:"dryRun"`
+	Tool     string                  `json:"tool"`
+	Requests []client.PlannedRequest `json:"requests"`
+	Before   any                     `json:"before"`
+	After    any                     `json:"after"`
+	Changes  []DryRunChange          `json:"changes"`
+}
+
+// DryRunChange is a top-level field of the targeted resource changed by a tool call
+type DryRunChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// dryRunTarget describes the resource modified by a write tool
+type dryRunTarget struct {
+	// fetch returns the current state of the resource targeted by the tool call
+	fetch func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error)
+	// fields maps the tool arguments to the state fields they replace
+	fields map[string]string
+	// apply computes changes that cannot be expressed as replaced fields
+	apply func(state map[string]any, args map[string]any)
+}
+
+// WithDryRun enables the dry-run mode.
+// Write tools stay registered, but instead of modifying Portainer they
+// return the requests they would send along with a before/after diff.
+func WithDryRun(dryRun bool) ServerOption {
+	return func(opts *serverOptions) {
+		opts.dryRun = dryRun
+	}
+}
+
+// isMutatingTool checks if a tool may modify Portainer resources.
+// Proxy tools are annotated as read-only but are destructive with mutating HTTP methods.
+func isMutatingTool(tool mcp.Tool) bool {
+	destructive := tool.Annotations.DestructiveHint != nil && *tool.Annotations.DestructiveHint
+	return !isReadOnlyTool(tool) || destructive
+}
+
+// dryRunHandler returns a handler that runs the given handler in dry-run mode.
+//
+// The handler runs with a context in which mutating Portainer API requests
+// are recorded instead of being sent, so its parameter validation and any
+// read request are performed as usual. When it attempted at least one
+// mutation, its result is replaced by a DryRunPlan.
+func (s *PortainerMCPServer) dryRunHandler(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		dryRunCtx, recorder := client.WithDryRun(ctx)
+
+		result, err := handler(dryRunCtx, request)
+
+		requests := recorder.Requests()
+		if len(requests) == 0 {
+			// Invalid parameters, or a proxy tool called with a read method
+			return result, err
+		}
+
+		for i := range requests {
+			requests[i].Body = redactValue(requests[i].Body)
+		}
+
+		plan := DryRunPlan{
+			DryRun:   true,
+			Tool:     tool.Name,
+			Requests: requests,
+		}
+
+		args := request.GetArguments()
+
+		if target, ok := dryRunTargets[tool.Name]; ok && target.fetch != nil {
+			before, err := target.fetch(ctx, s.client(ctx), toolgen.NewParameterParser(request))
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to fetch current state", err), nil
+			}
+
+			plan.Before, err = normalizeJSON(before)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to marshal current state", err), nil
+			}
+		}
+
+		if !strings.HasPrefix(tool.Name, "delete") {
+			plan.After = plannedState(plan.Before, args, dryRunTargets[tool.Name])
+		}
+
+		plan.Before = redactValue(plan.Before)
+		plan.After = redactValue(plan.After)
+		plan.Changes = diffStates(plan.Before, plan.After)
+
+		data, err := json.Marshal(plan)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal dry-run plan", err), nil
+		}
+
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// plannedState computes the expected state of a resource after the tool call.
+// Without a current state, the tool creates a resource described by its arguments.
+func plannedState(before any, args map[string]any, target dryRunTarget) any {
+	current, ok := before.(map[string]any)
+	if !ok {
+		if before != nil {
+			return before
+		}
+		return redactValue(args)
+	}
+
+	state := make(map[string]any, len(current))
+	for k, v := range current {
+		state[k] = v
+	}
+
+	for arg, field := range target.fields {
+		if value, ok := args[arg]; ok {
+			state[field] = redactValue(value)
+		}
+	}
+
+	if target.apply != nil {
+		target.apply(state, args)
+	}
+
+	return state
+}
+
+// diffStates lists the top-level fields that differ between two states
+func diffStates(before, after any) []DryRunChange {
+	beforeFields, _ := before.(map[string]any)
+	afterFields, _ := after.(map[string]any)
+
+	if beforeFields == nil && afterFields == nil {
+		return []DryRunChange{}
+	}
+
+	var fields []string
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+	for field := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []DryRunChange{}
+	for _, field := range fields {
+		b, a := beforeFields[field], afterFields[field]
+		if !reflect.DeepEqual(b, a) {
+			changes = append(changes, DryRunChange{Field: field, Before: b, After: a})
+		}
+	}
+	return changes
+}
+
+// normalizeJSON converts a value to its generic JSON representation
+func normalizeJSON(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// mergeDocument returns an apply function merging the JSON document held by
+// the given argument into the top-level fields of the state
+func mergeDocument(arg string) func(state map[string]any, args map[string]any) {
+	return func(state map[string]any, args map[string]any) {
+		document, ok := args[arg].(string)
+		if !ok {
+			return
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(document), &fields); err != nil {
+			return
+		}
+
+		for k, v := range fields {
+			state[k] = v
+		}
+	}
+}
+
+// setField returns an apply function setting a field of the state to a fixed value
+func setField(field string, value any) func(state map[string]any, args map[string]any) {
+	return func(state map[string]any, args map[string]any) {
+		state[field] = value
+	}
+}
+
+// updateIDList returns an apply function adding or removing the ID held by
+// the given argument to or from a list field of the state
+func updateIDList(field, arg string, add bool) func(state map[string]any, args map[string]any) {
+	return func(state map[string]any, args map[string]any) {
+		id, ok := args[arg]
+		if !ok {
+			return
+		}
+
+		current, _ := state[field].([]any)
+		list := slices.DeleteFunc(slices.Clone(current), func(v any) bool {
+			return reflect.DeepEqual(v, id)
+		})
+		if add {
+			list = append(list, id)
+		}
+		state[field] = list
+	}
+}
+
+// findByID returns the item with the given ID, or an error if there is none
+func findByID[T any](items []T, id int, getID func(T) int) (T, error) {
+	for _, item := range items {
+		if getID(item) == id {
+			return item, nil
+		}
+	}
+
+	var zero T
+	return zero, fmt.Errorf("resource with ID %d not found", id)
+}
+
+// fetchByID returns a fetch function looking up the resource whose ID is held by the id argument
+func fetchByID[T any](list func(ctx context.Context, cli PortainerClient) ([]T, error), getID func(T) int) func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+	return func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+		id, err := parser.GetInt("id", true)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := list(ctx, cli)
+		if err != nil {
+			return nil, err
+		}
+
+		return findByID(items, id, getID)
+	}
+}
+
+// withFile adds the content of a stack file to the state of a stack
+func withFile(state any, file string) (any, error) {
+	normalized, err := normalizeJSON(state)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, ok := normalized.(map[string]any)
+	if !ok {
+		return normalized, nil
+	}
+	fields["file"] = file
+	return fields, nil
+}
@@ -0,0 +1,346 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// dryRunTargets describes, for each write tool, how to fetch the current
+// state of the resource it modifies and how its arguments change that state.
+// Tools creating resources have no entry: their planned state is built from
+// their arguments.
+var dryRunTargets = map[string]dryRunTarget{
+	// Access groups
+	ToolUpdateAccessGroupName: {
+		fetch:  fetchAccessGroup,
+		fields: map[string]string{"name": "name"},
+	},
+	ToolUpdateAccessGroupUserAccesses: {
+		fetch: fetchAccessGroup,
+		apply: setAccesses("user_accesses", "userAccesses"),
+	},
+	ToolUpdateAccessGroupTeamAccesses: {
+		fetch: fetchAccessGroup,
+		apply: setAccesses("team_accesses", "teamAccesses"),
+	},
+	ToolAddEnvironmentToAccessGroup: {
+		fetch: fetchAccessGroup,
+		apply: updateIDList("environment_ids", "environmentId", true),
+	},
+	ToolRemoveEnvironmentFromAccessGroup: {
+		fetch: fetchAccessGroup,
+		apply: updateIDList("environment_ids", "environmentId", false),
+	},
+	ToolDeleteAccessGroup: {
+		fetch: fetchAccessGroup,
+	},
+
+	// Environments
+	ToolUpdateEnvironment: {
+		fetch:  fetchEnvironment,
+		fields: map[string]string{"name": "name", "publicURL": "public_url", "groupID": "group_id"},
+	},
+	ToolUpdateEnvironmentTags: {
+		fetch:  fetchEnvironment,
+		fields: map[string]string{"tagIds": "tag_ids"},
+	},
+	ToolUpdateEnvironmentUserAccesses: {
+		fetch: fetchEnvironment,
+		apply: setAccesses("user_accesses", "userAccesses"),
+	},
+	ToolUpdateEnvironmentTeamAccesses: {
+		fetch: fetchEnvironment,
+		apply: setAccesses("team_accesses", "teamAccesses"),
+	},
+
+	// Environment groups
+	ToolUpdateEnvironmentGroupName: {
+		fetch:  fetchEnvironmentGroup,
+		fields: map[string]string{"name": "name"},
+	},
+	ToolUpdateEnvironmentGroupEnvironments: {
+		fetch:  fetchEnvironmentGroup,
+		fields: map[string]string{"environmentIds": "environment_ids"},
+	},
+	ToolUpdateEnvironmentGroupTags: {
+		fetch:  fetchEnvironmentGroup,
+		fields: map[string]string{"tagIds": "tag_ids"},
+	},
+	ToolDeleteEnvironmentGroup: {
+		fetch: fetchEnvironmentGroup,
+	},
+
+	// Settings
+	ToolUpdateSettings: {
+		fetch: func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+			return cli.GetSettings(ctx)
+		},
+		apply: mergeDocument("settingsJSON"),
+	},
+
+	// Edge stacks
+	ToolUpdateStack: {
+		fetch:  fetchStack,
+		fields: map[string]string{"file": "file", "environmentGroupIds": "group_ids"},
+	},
+	ToolDeleteStack: {
+		fetch: fetchStack,
+	},
+
+	// Docker stacks
+	ToolUpdateDockerStack: {
+		fetch:  fetchDockerStack,
+		fields: map[string]string{"file": "file"},
+	},
+	ToolDeleteDockerStack: {
+		fetch: fetchDockerStack,
+	},
+	ToolStartDockerStack: {
+		fetch: fetchDockerStack,
+		apply: setField("status", float64(1)),
+	},
+	ToolStopDockerStack: {
+		fetch: fetchDockerStack,
+		apply: setField("status", float64(2)),
+	},
+
+	// Tags
+	ToolDeleteTag: {
+		fetch: fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.EnvironmentTag, error) {
+			return cli.GetEnvironmentTags(ctx)
+		}, func(t models.EnvironmentTag) int { return t.ID }),
+	},
+
+	// Teams
+	ToolUpdateTeamName: {
+		fetch:  fetchTeam,
+		fields: map[string]string{"name": "name"},
+	},
+	ToolUpdateTeamMembers: {
+		fetch:  fetchTeam,
+		fields: map[string]string{"userIds": "members"},
+	},
+	ToolDeleteTeam: {
+		fetch: fetchTeam,
+	},
+
+	// Users
+	ToolUpdateUserRole: {
+		fetch: fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.User, error) {
+			return cli.GetUsers(ctx)
+		}, func(u models.User) int { return u.ID }),
+		fields: map[string]string{"role": "role"},
+	},
+
+	// Registries
+	ToolDeleteRegistry: {
+		fetch: fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.Registry, error) {
+			return cli.GetRegistries(ctx)
+		}, func(r models.Registry) int { return r.ID }),
+	},
+
+	// Edge jobs
+	ToolDeleteEdgeJob: {
+		fetch: func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+			id, err := parser.GetInt("id", true)
+			if err != nil {
+				return nil, err
+			}
+			return cli.GetEdgeJob(ctx, id)
+		},
+	},
+
+	// Custom templates
+	ToolDeleteCustomTemplate: {
+		fetch: fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.CustomTemplate, error) {
+			return cli.GetCustomTemplates(ctx)
+		}, func(t models.CustomTemplate) int { return t.ID }),
+	},
+
+	// Webhooks
+	ToolDeleteWebhook: {
+		fetch: fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.Webhook, error) {
+			return cli.GetWebhooks(ctx)
+		}, func(w models.Webhook) int { return w.ID }),
+	},
+
+	// Git credentials
+	ToolUpdateGitCredential: {
+		fetch:  fetchGitCredential,
+		fields: map[string]string{"name": "name", "username": "username", "authorizationType": "authorizationType"},
+	},
+	ToolDeleteGitCredential: {
+		fetch: fetchGitCredential,
+	},
+
+	// Alerting
+	ToolUpdateAlertRule: {
+		fetch: fetchAlertRule,
+		apply: mergeDocument("ruleJSON"),
+	},
+	ToolDeleteAlertRule: {
+		fetch: fetchAlertRule,
+	},
+
+	// Policies
+	ToolUpdatePolicy: {
+		fetch: fetchPolicy,
+		fields: map[string]string{
+			"name":              "Name",
+			"type":              "Type",
+			"environmentType":   "EnvironmentType",
+			"environmentGroups": "EnvironmentGroups",
+		},
+		apply: func(state map[string]any, args map[string]any) {
+			if data, ok := args["dataJSON"]; ok {
+				state["Data"] = redactValue(data)
+			}
+		},
+	},
+	ToolDeletePolicy: {
+		fetch: fetchPolicy,
+	},
+
+	// Kubernetes custom resources
+	ToolDeleteCustomResourceDefinition: {
+		fetch: func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+			environmentId, err := parser.GetInt("environmentId", true)
+			if err != nil {
+				return nil, err
+			}
+			name, err := parser.GetString("name", true)
+			if err != nil {
+				return nil, err
+			}
+			return cli.GetCustomResourceDefinition(ctx, environmentId, name)
+		},
+	},
+	ToolDeleteCustomResource: {
+		fetch: func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+			environmentId, err := parser.GetInt("environmentId", true)
+			if err != nil {
+				return nil, err
+			}
+			name, err := parser.GetString("name", true)
+			if err != nil {
+				return nil, err
+			}
+			definition, err := parser.GetString("definition", true)
+			if err != nil {
+				return nil, err
+			}
+			namespace, err := parser.GetString("namespace", false)
+			if err != nil {
+				return nil, err
+			}
+			return cli.GetCustomResource(ctx, environmentId, namespace, name, definition, "json")
+		},
+	},
+}
+
+var (
+	fetchAccessGroup = fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.AccessGroup, error) {
+		return cli.GetAccessGroups(ctx)
+	}, func(g models.AccessGroup) int { return g.ID })
+
+	fetchEnvironment = fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.Environment, error) {
+		return cli.GetEnvironments(ctx)
+	}, func(e models.Environment) int { return e.ID })
+
+	fetchEnvironmentGroup = fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.Group, error) {
+		return cli.GetEnvironmentGroups(ctx)
+	}, func(g models.Group) int { return g.ID })
+
+	fetchTeam = fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.Team, error) {
+		return cli.GetTeams(ctx)
+	}, func(t models.Team) int { return t.ID })
+)
+
+// fetchStack returns an edge stack along with its file
+func fetchStack(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+	stack, err := fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.Stack, error) {
+		return cli.GetStacks(ctx)
+	}, func(s models.Stack) int { return s.ID })(ctx, cli, parser)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := cli.GetStackFile(ctx, stack.(models.Stack).ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return withFile(stack, file)
+}
+
+// fetchDockerStack returns a docker stack along with its compose file
+func fetchDockerStack(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+	stack, err := fetchByID(func(ctx context.Context, cli PortainerClient) ([]models.DockerStack, error) {
+		return cli.GetDockerStacks(ctx)
+	}, func(s models.DockerStack) int { return s.ID })(ctx, cli, parser)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := cli.GetDockerStackFile(ctx, stack.(models.DockerStack).ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return withFile(stack, file)
+}
+
+// fetchGitCredential returns the git credential whose ID is held by the id argument
+func fetchGitCredential(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetGitCredential(ctx, id)
+}
+
+// fetchAlertRule returns the alert rule whose ID is held by the id argument
+func fetchAlertRule(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetAlertRule(ctx, id)
+}
+
+// fetchPolicy returns the policy whose ID is held by the id argument
+func fetchPolicy(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser) (any, error) {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetPolicy(ctx, id)
+}
+
+// setAccesses returns an apply function replacing an access map field of the
+// state with the accesses held by the given argument, e.g. [{id: 1, access: "standard_user"}]
+func setAccesses(field, arg string) func(state map[string]any, args map[string]any) {
+	return func(state map[string]any, args map[string]any) {
+		entries, ok := args[arg].([]any)
+		if !ok {
+			return
+		}
+
+		accesses := map[string]any{}
+		for _, entry := range entries {
+			access, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			id, ok := access["id"].(float64)
+			if !ok {
+				continue
+			}
+			accesses[fmt.Sprintf("%d", int(id))] = access["access"]
+		}
+		state[field] = accesses
+	}
+}
@@ -0,0 +1,275 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDryRunTestServer creates a server using a real Portainer client against a
+// test Portainer API, which fails the test if it receives a mutating request
+func newDryRunTestServer(t *testing.T) *PortainerMCPServer {
+	t.Helper()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s request to %s in dry-run mode", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/endpoints":
+			w.Write([]byte(`[{"Id":1,"Name":"production","Type":1,"Status":1,"TagIds":[1]}]`))
+		case "/api/tags":
+			w.Write([]byte(`[{"ID":3,"Name":"staging"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(api.Close)
+
+	cli, err := client.NewPortainerClient(api.URL, "test-token")
+	require.NoError(t, err)
+
+	return &PortainerMCPServer{
+		srv:    server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		cli:    cli,
+		dryRun: true,
+	}
+}
+
+func TestDryRunHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		tool            string
+		handler         func(s *PortainerMCPServer) server.ToolHandlerFunc
+		args            map[string]any
+		expectedMethod  string
+		expectedURL     string
+		expectedBefore  any
+		expectedChanges []DryRunChange
+	}{
+		{
+			name:           "update existing resource",
+			tool:           ToolUpdateEnvironmentTags,
+			handler:        (*PortainerMCPServer).HandleUpdateEnvironmentTags,
+			args:           map[string]any{"id": float64(1), "tagIds": []any{float64(1), float64(2)}},
+			expectedMethod: http.MethodPut,
+			expectedURL:    "/api/endpoints/1",
+			expectedBefore: map[string]any{
+				"id":            float64(1),
+				"name":          "production",
+				"status":        "active",
+				"type":          "docker-local",
+				"tag_ids":       []any{float64(1)},
+				"user_accesses": map[string]any{},
+				"team_accesses": map[string]any{},
+			},
+			expectedChanges: []DryRunChange{
+				{Field: "tag_ids", Before: []any{float64(1)}, After: []any{float64(1), float64(2)}},
+			},
+		},
+		{
+			name:           "create resource",
+			tool:           ToolCreateEnvironmentTag,
+			handler:        (*PortainerMCPServer).HandleCreateEnvironmentTag,
+			args:           map[string]any{"name": "production"},
+			expectedMethod: http.MethodPost,
+			expectedURL:    "/api/tags",
+			expectedChanges: []DryRunChange{
+				{Field: "name", Before: nil, After: "production"},
+			},
+		},
+		{
+			name:           "delete resource",
+			tool:           ToolDeleteTag,
+			handler:        (*PortainerMCPServer).HandleDeleteTag,
+			args:           map[string]any{"id": float64(3)},
+			expectedMethod: http.MethodDelete,
+			expectedURL:    "/api/tags/3",
+			expectedBefore: map[string]any{"id": float64(3), "name": "staging", "environment_ids": []any{}},
+			expectedChanges: []DryRunChange{
+				{Field: "environment_ids", Before: []any{}, After: nil},
+				{Field: "id", Before: float64(3), After: nil},
+				{Field: "name", Before: "staging", After: nil},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newDryRunTestServer(t)
+			handler := s.dryRunHandler(mcp.Tool{Name: tt.tool}, tt.handler(s))
+
+			result, err := handler(context.Background(), CreateMCPRequest(tt.args))
+			require.NoError(t, err)
+			require.False(t, result.IsError, resultText(result))
+
+			var plan DryRunPlan
+			require.NoError(t, json.Unmarshal([]byte(resultText(result)), &plan))
+
+			assert.True(t, plan.DryRun)
+			assert.Equal(t, tt.tool, plan.Tool)
+			require.Len(t, plan.Requests, 1)
+			assert.Equal(t, tt.expectedMethod, plan.Requests[0].Method)
+			assert.Contains(t, plan.Requests[0].URL, tt.expectedURL)
+			assert.Equal(t, tt.expectedBefore, plan.Before)
+			assert.Equal(t, tt.expectedChanges, plan.Changes)
+		})
+	}
+}
+
+func TestDryRunHandlerInvalidParameters(t *testing.T) {
+	s := newDryRunTestServer(t)
+	handler := s.dryRunHandler(mcp.Tool{Name: ToolDeleteTag}, s.HandleDeleteTag())
+
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{}))
+	require.NoError(t, err)
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "invalid id parameter")
+}
+
+func TestDryRunHandlerRedactsRequests(t *testing.T) {
+	s := newDryRunTestServer(t)
+	handler := s.dryRunHandler(mcp.Tool{Name: ToolCreateRegistry}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, err := s.client(ctx).CreateRegistry(ctx, models.RegistryCreateRequest{
+			Name:           "registry",
+			Type:           3,
+			URL:            "registry.example.com",
+			Authentication: true,
+			Username:       "admin",
+			Password:       "s3cr3t",
+		})
+		require.ErrorIs(t, err, client.ErrDryRun)
+		return mcp.NewToolResultErrorFromErr("failed to create registry", err), nil
+	})
+
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{"password": "s3cr3t"}))
+	require.NoError(t, err)
+
+	text := resultText(result)
+	assert.NotContains(t, text, "s3cr3t")
+	assert.Contains(t, text, redactedValue)
+}
+
+func TestPlannedState(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   any
+		args     map[string]any
+		target   dryRunTarget
+		expected any
+	}{
+		{
+			name:     "created resource",
+			args:     map[string]any{"name": "team", "password": "s3cr3t"},
+			expected: map[string]any{"name": "team", "password": redactedValue},
+		},
+		{
+			name:     "replaced fields",
+			before:   map[string]any{"name": "old", "members": []any{float64(1)}},
+			args:     map[string]any{"userIds": []any{float64(2)}},
+			target:   dryRunTarget{fields: map[string]string{"name": "name", "userIds": "members"}},
+			expected: map[string]any{"name": "old", "members": []any{float64(2)}},
+		},
+		{
+			name:   "accesses",
+			before: map[string]any{"user_accesses": map[string]any{"1": "readonly_user"}},
+			args: map[string]any{"userAccesses": []any{
+				map[string]any{"id": float64(2), "access": "standard_user"},
+			}},
+			target:   dryRunTarget{apply: setAccesses("user_accesses", "userAccesses")},
+			expected: map[string]any{"user_accesses": map[string]any{"2": "standard_user"}},
+		},
+		{
+			name:     "removed ID",
+			before:   map[string]any{"environment_ids": []any{float64(1), float64(2)}},
+			args:     map[string]any{"environmentId": float64(1)},
+			target:   dryRunTarget{apply: updateIDList("environment_ids", "environmentId", false)},
+			expected: map[string]any{"environment_ids": []any{float64(2)}},
+		},
+		{
+			name:     "added ID",
+			before:   map[string]any{"environment_ids": []any{float64(1)}},
+			args:     map[string]any{"environmentId": float64(2)},
+			target:   dryRunTarget{apply: updateIDList("environment_ids", "environmentId", true)},
+			expected: map[string]any{"environment_ids": []any{float64(1), float64(2)}},
+		},
+		{
+			name:     "merged document",
+			before:   map[string]any{"EnableTelemetry": true, "LogoURL": ""},
+			args:     map[string]any{"settingsJSON": `{"EnableTelemetry":false}`},
+			target:   dryRunTarget{apply: mergeDocument("settingsJSON")},
+			expected: map[string]any{"EnableTelemetry": false, "LogoURL": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, plannedState(tt.before, tt.args, tt.target))
+		})
+	}
+}
+
+func TestAddToolIfExistsWithDryRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		tool          mcp.Tool
+		expectWrapped bool
+	}{
+		{
+			name:          "write tool",
+			tool:          newAuditTestTool("deleteTag", false),
+			expectWrapped: true,
+		},
+		{
+			name:          "read-only tool",
+			tool:          newAuditTestTool("listEnvironments", true),
+			expectWrapped: false,
+		},
+		{
+			name: "destructive read-only tool",
+			tool: mcp.Tool{
+				Name: "dockerProxy",
+				Annotations: mcp.ToolAnnotation{
+					ReadOnlyHint:    mcp.ToBoolPtr(true),
+					DestructiveHint: mcp.ToBoolPtr(true),
+				},
+			},
+			expectWrapped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PortainerMCPServer{
+				srv:    server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+				tools:  map[string]mcp.Tool{tt.tool.Name: tt.tool},
+				dryRun: true,
+			}
+
+			var dryRun bool
+			s.addToolIfExists(tt.tool.Name, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				dryRun = client.IsDryRun(ctx)
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			tool := s.srv.GetTool(tt.tool.Name)
+			require.NotNil(t, tool)
+
+			_, err := tool.Handler(context.Background(), CreateMCPRequest(nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectWrapped, dryRun)
+		})
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddEdgeJobFeatures() {
@@ -23,30 +22,38 @@ func (s *PortainerMCPServer) AddEdgeJobFeatures() {
 
 func (s *PortainerMCPServer) HandleListEdgeJobs() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		jobs, err := s.cli.GetEdgeJobs()
+		jobs, err := s.client(ctx).GetEdgeJobs(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get edge jobs", err), nil
 		}
 
+		inScope, err := s.scopedEnvironmentIDs(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		scopedGroups, err := scopedEnvironmentGroupIDs(ctx, s.client(ctx), inScope)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		jobs = filterScoped(scopedGroups, jobs, func(j models.EdgeJob) []int { return j.EdgeGroups })
+
 		data, err := json.Marshal(jobs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal edge jobs", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(jobs, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleGetEdgeJob() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseGetEdgeJobArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		job, err := s.cli.GetEdgeJob(id)
+		job, err := s.client(ctx).GetEdgeJob(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get edge job", err), nil
 		}
@@ -56,48 +63,26 @@ func (s *PortainerMCPServer) HandleGetEdgeJob() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal edge job", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(job, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleCreateEdgeJob() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		cronExpression, err := parser.GetString("cronExpression", true)
+		args, err := ParseCreateEdgeJobArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid cronExpression parameter", err), nil
-		}
-
-		recurring, err := parser.GetBoolean("recurring", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid recurring parameter", err), nil
-		}
-
-		scriptContent, err := parser.GetString("scriptContent", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid scriptContent parameter", err), nil
-		}
-
-		edgeGroupIds, err := parser.GetArrayOfIntegers("edgeGroupIds", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid edgeGroupIds parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.EdgeJobCreateRequest{
-			Name:           name,
-			CronExpression: cronExpression,
-			Recurring:      recurring,
-			ScriptContent:  scriptContent,
-			EdgeGroups:     edgeGroupIds,
+			Name:           args.Name,
+			CronExpression: args.CronExpression,
+			Recurring:      args.Recurring,
+			ScriptContent:  args.ScriptContent,
+			EdgeGroups:     args.EdgeGroupIDs,
 		}
 
-		id, err := s.cli.CreateEdgeJob(req)
+		id, err := s.client(ctx).CreateEdgeJob(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create edge job", err), nil
 		}
@@ -108,14 +93,12 @@ func (s *PortainerMCPServer) HandleCreateEdgeJob() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleDeleteEdgeJob() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteEdgeJobArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteEdgeJob(id)
+		err = s.client(ctx).DeleteEdgeJob(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete edge job", err), nil
 		}
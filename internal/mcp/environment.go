@@ -3,10 +3,13 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 )
 
 func (s *PortainerMCPServer) AddEnvironmentFeatures() {
@@ -23,35 +26,122 @@ func (s *PortainerMCPServer) AddEnvironmentFeatures() {
 
 func (s *PortainerMCPServer) HandleGetEnvironments() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		environments, err := s.cli.GetEnvironments()
+		environments, err := s.listEnvironments(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get environments", err), nil
 		}
 
+		inScope, err := s.scopedEnvironmentIDs(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		environments = filterScoped(inScope, environments, func(e models.Environment) []int { return []int{e.ID} })
+
 		data, err := json.Marshal(environments)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal environments", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(environments, data), nil
 	}
 }
 
-func (s *PortainerMCPServer) HandleUpdateEnvironmentTags() server.ToolHandlerFunc {
-	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
+// listEnvironments lists the environments, having Portainer narrow them with
+// the conditions of the list query of the tool call it supports. Portainer
+// also paginates them when it supports the whole query, which then has no
+// sort, and the server is not restricted to an environment scope.
+func (s *PortainerMCPServer) listEnvironments(ctx context.Context) ([]models.Environment, error) {
+	cli := s.client(ctx)
 
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+	query := listQueryFrom(ctx)
+	if query == nil {
+		return cli.GetEnvironments(ctx)
+	}
+
+	environmentQuery, exact := environmentQueryFor(query)
+	if exact && len(query.sort) == 0 && query.limit > 0 && s.scope.IsEmpty() {
+		// The extra environment reveals whether there is a next page
+		environmentQuery.Start = query.offset
+		environmentQuery.Limit = query.limit + 1
+		query.paged = true
+	}
+
+	if reflect.DeepEqual(environmentQuery, models.EnvironmentQuery{}) {
+		return cli.GetEnvironments(ctx)
+	}
+
+	return cli.SearchEnvironments(ctx, environmentQuery)
+}
+
+// environmentQueryFor returns the Portainer query narrowing the environments
+// to the ones possibly matching the filter of a list query, and whether the
+// Portainer query matches exactly the same environments. A list parameter
+// of the Portainer query holds a single value at most.
+func environmentQueryFor(query *listQuery) (models.EnvironmentQuery, bool) {
+	var environmentQuery models.EnvironmentQuery
+	exact := true
+
+	for _, f := range query.filters {
+		if len(f.path) != 1 {
+			exact = false
+			continue
+		}
+
+		switch field := f.path[0]; {
+		case field == "id" && f.operator == filterEquals && len(environmentQuery.IDs) == 0:
+			id, err := strconv.Atoi(f.value)
+			if err != nil {
+				exact = false
+				continue
+			}
+			environmentQuery.IDs = []int{id}
+
+		case field == "name" && f.operator != filterNotEquals && environmentQuery.Search == "":
+			// The search also matches the names of the tags and group of
+			// the environments, which are filtered again by the server
+			environmentQuery.Search = f.value
+			exact = false
+
+		case field == "status" && f.operator == filterEquals && len(environmentQuery.Statuses) == 0:
+			status := strings.ToLower(f.value)
+			if _, ok := models.EnvironmentStatusID(status); !ok {
+				exact = false
+				continue
+			}
+			environmentQuery.Statuses = []string{status}
+
+		case field == "type" && f.operator == filterEquals && len(environmentQuery.Types) == 0:
+			environmentType := strings.ToLower(f.value)
+			if _, ok := models.EnvironmentTypeID(environmentType); !ok {
+				exact = false
+				continue
+			}
+			environmentQuery.Types = []string{environmentType}
+
+		case field == "tagids" && f.operator == filterContains && len(environmentQuery.TagIDs) == 0:
+			id, err := strconv.Atoi(f.value)
+			if err != nil {
+				exact = false
+				continue
+			}
+			environmentQuery.TagIDs = []int{id}
+
+		default:
+			exact = false
 		}
+	}
 
-		tagIds, err := parser.GetArrayOfIntegers("tagIds", true)
+	return environmentQuery, exact
+}
+
+func (s *PortainerMCPServer) HandleUpdateEnvironmentTags() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := ParseUpdateEnvironmentTagsArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid tagIds parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateEnvironmentTags(id, tagIds)
+		err = s.client(ctx).UpdateEnvironmentTags(ctx, args.ID, args.TagIDs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update environment tags", err), nil
 		}
@@ -62,24 +152,17 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentTags() server.ToolHandlerFun
 
 func (s *PortainerMCPServer) HandleUpdateEnvironmentUserAccesses() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseUpdateEnvironmentUserAccessesArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		userAccesses, err := parser.GetArrayOfObjects("userAccesses", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userAccesses parameter", err), nil
-		}
-
-		userAccessesMap, err := parseAccessMap(userAccesses)
+		userAccessesMap, err := parseAccessMap(args.UserAccesses)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid user accesses", err), nil
 		}
 
-		err = s.cli.UpdateEnvironmentUserAccesses(id, userAccessesMap)
+		err = s.client(ctx).UpdateEnvironmentUserAccesses(ctx, args.ID, userAccessesMap)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update environment user accesses", err), nil
 		}
@@ -90,24 +173,17 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentUserAccesses() server.ToolHa
 
 func (s *PortainerMCPServer) HandleUpdateEnvironmentTeamAccesses() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseUpdateEnvironmentTeamAccessesArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		teamAccesses, err := parser.GetArrayOfObjects("teamAccesses", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid teamAccesses parameter", err), nil
-		}
-
-		teamAccessesMap, err := parseAccessMap(teamAccesses)
+		teamAccessesMap, err := parseAccessMap(args.TeamAccesses)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid team accesses", err), nil
 		}
 
-		err = s.cli.UpdateEnvironmentTeamAccesses(id, teamAccessesMap)
+		err = s.client(ctx).UpdateEnvironmentTeamAccesses(ctx, args.ID, teamAccessesMap)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update environment team accesses", err), nil
 		}
@@ -118,29 +194,12 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentTeamAccesses() server.ToolHa
 
 func (s *PortainerMCPServer) HandleUpdateEnvironment() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		publicURL, err := parser.GetString("publicURL", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid publicURL parameter", err), nil
-		}
-
-		groupID, err := parser.GetInt("groupID", false)
+		args, err := ParseUpdateEnvironmentArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid groupID parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateEnvironment(id, name, publicURL, groupID)
+		err = s.client(ctx).UpdateEnvironment(ctx, args.ID, args.Name, args.PublicURL, args.GroupID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update environment", err), nil
 		}
@@ -152,7 +211,7 @@ func (s *PortainerMCPServer) HandleUpdateEnvironment() server.ToolHandlerFunc {
 // HandleListAgentVersions returns a handler that lists available agent versions.
 func (s *PortainerMCPServer) HandleListAgentVersions() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		versions, err := s.cli.GetAgentVersions()
+		versions, err := s.client(ctx).GetAgentVersions(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get agent versions", err), nil
 		}
@@ -162,6 +221,6 @@ func (s *PortainerMCPServer) HandleListAgentVersions() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal agent versions", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(versions, data), nil
 	}
 }
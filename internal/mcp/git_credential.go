@@ -8,7 +8,6 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 // AddGitCredentialFeatures registers all shared git credential related tools.
@@ -26,7 +25,7 @@ func (s *PortainerMCPServer) AddGitCredentialFeatures() {
 // HandleListGitCredentials returns a handler that lists all shared git credentials.
 func (s *PortainerMCPServer) HandleListGitCredentials() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		creds, err := s.cli.GetGitCredentials()
+		creds, err := s.client(ctx).GetGitCredentials(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get git credentials", err), nil
 		}
@@ -36,21 +35,19 @@ func (s *PortainerMCPServer) HandleListGitCredentials() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal git credentials", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(creds, data), nil
 	}
 }
 
 // HandleGetGitCredential returns a handler that retrieves a specific shared git credential.
 func (s *PortainerMCPServer) HandleGetGitCredential() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseGetGitCredentialArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		cred, err := s.cli.GetGitCredential(id)
+		cred, err := s.client(ctx).GetGitCredential(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get git credential", err), nil
 		}
@@ -60,43 +57,26 @@ func (s *PortainerMCPServer) HandleGetGitCredential() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal git credential", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(cred, data), nil
 	}
 }
 
 // HandleCreateGitCredential returns a handler that creates a new shared git credential.
 func (s *PortainerMCPServer) HandleCreateGitCredential() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseCreateGitCredentialArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		username, err := parser.GetString("username", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid username parameter", err), nil
-		}
-
-		password, err := parser.GetString("password", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
-		}
-
-		authType, err := parser.GetInt("authorizationType", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid authorizationType parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.GitCredentialCreateRequest{
-			Name:              name,
-			Username:          username,
-			Password:          password,
-			AuthorizationType: authType,
+			Name:              args.Name,
+			Username:          args.Username,
+			Password:          args.Password,
+			AuthorizationType: args.AuthorizationType,
 		}
 
-		id, err := s.cli.CreateGitCredential(req)
+		id, err := s.client(ctx).CreateGitCredential(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create git credential", err), nil
 		}
@@ -108,41 +88,19 @@ func (s *PortainerMCPServer) HandleCreateGitCredential() server.ToolHandlerFunc
 // HandleUpdateGitCredential returns a handler that updates an existing shared git credential.
 func (s *PortainerMCPServer) HandleUpdateGitCredential() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseUpdateGitCredentialArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		username, err := parser.GetString("username", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid username parameter", err), nil
-		}
-
-		password, err := parser.GetString("password", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
-		}
-
-		authType, err := parser.GetInt("authorizationType", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid authorizationType parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.GitCredentialUpdateRequest{
-			Name:              name,
-			Username:          username,
-			Password:          password,
-			AuthorizationType: authType,
+			Name:              args.Name,
+			Username:          args.Username,
+			Password:          args.Password,
+			AuthorizationType: args.AuthorizationType,
 		}
 
-		err = s.cli.UpdateGitCredential(id, req)
+		err = s.client(ctx).UpdateGitCredential(ctx, args.ID, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update git credential", err), nil
 		}
@@ -154,14 +112,12 @@ func (s *PortainerMCPServer) HandleUpdateGitCredential() server.ToolHandlerFunc
 // HandleDeleteGitCredential returns a handler that deletes a shared git credential.
 func (s *PortainerMCPServer) HandleDeleteGitCredential() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteGitCredentialArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteGitCredential(id)
+		err = s.client(ctx).DeleteGitCredential(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete git credential", err), nil
 		}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 )
 
 func (s *PortainerMCPServer) AddEnvironmentGroupFeatures() {
@@ -24,35 +25,36 @@ func (s *PortainerMCPServer) AddEnvironmentGroupFeatures() {
 
 func (s *PortainerMCPServer) HandleGetEnvironmentGroups() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		edgeGroups, err := s.cli.GetEnvironmentGroups()
+		edgeGroups, err := s.client(ctx).GetEnvironmentGroups(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get environment groups", err), nil
 		}
 
+		inScope, err := s.scopedEnvironmentIDs(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		if inScope != nil {
+			edgeGroups = slices.DeleteFunc(edgeGroups, func(g models.Group) bool { return !groupInScope(inScope, g) })
+		}
+
 		data, err := json.Marshal(edgeGroups)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal environment groups", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(edgeGroups, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleCreateEnvironmentGroup() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseCreateEnvironmentGroupArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		environmentIds, err := parser.GetArrayOfIntegers("environmentIds", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentIds parameter", err), nil
-		}
-
-		id, err := s.cli.CreateEnvironmentGroup(name, environmentIds)
+		id, err := s.client(ctx).CreateEnvironmentGroup(ctx, args.Name, args.EnvironmentIDs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create environment group", err), nil
 		}
@@ -63,19 +65,12 @@ func (s *PortainerMCPServer) HandleCreateEnvironmentGroup() server.ToolHandlerFu
 
 func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupName() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseUpdateEnvironmentGroupNameArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		err = s.cli.UpdateEnvironmentGroupName(id, name)
+		err = s.client(ctx).UpdateEnvironmentGroupName(ctx, args.ID, args.Name)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update environment group name", err), nil
 		}
@@ -86,19 +81,12 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupName() server.ToolHandl
 
 func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupEnvironments() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		environmentIds, err := parser.GetArrayOfIntegers("environmentIds", true)
+		args, err := ParseUpdateEnvironmentGroupEnvironmentsArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentIds parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateEnvironmentGroupEnvironments(id, environmentIds)
+		err = s.client(ctx).UpdateEnvironmentGroupEnvironments(ctx, args.ID, args.EnvironmentIDs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update environment group environments", err), nil
 		}
@@ -109,19 +97,12 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupEnvironments() server.T
 
 func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupTags() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseUpdateEnvironmentGroupTagsArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		tagIds, err := parser.GetArrayOfIntegers("tagIds", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid tagIds parameter", err), nil
-		}
-
-		err = s.cli.UpdateEnvironmentGroupTags(id, tagIds)
+		err = s.client(ctx).UpdateEnvironmentGroupTags(ctx, args.ID, args.TagIDs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update environment group tags", err), nil
 		}
@@ -132,14 +113,12 @@ func (s *PortainerMCPServer) HandleUpdateEnvironmentGroupTags() server.ToolHandl
 
 func (s *PortainerMCPServer) HandleDeleteEnvironmentGroup() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteEnvironmentGroupArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteEnvironmentGroup(id)
+		err = s.client(ctx).DeleteEnvironmentGroup(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete environment group", err), nil
 		}
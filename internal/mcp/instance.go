@@ -0,0 +1,283 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// InstanceParam is the tool argument selecting the Portainer instance a tool call is sent to
+	InstanceParam = "instance"
+
+	// DefaultInstanceName is the default name of the Portainer instance passed to NewPortainerMCPServer
+	DefaultInstanceName = "default"
+)
+
+// instanceNamePattern restricts instance names to identifiers usable in tool arguments
+var instanceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Instance is an additional Portainer server managed by the MCP server
+type Instance struct {
+	// Name identifies the instance in the instance argument of the tools
+	Name string
+	// ServerURL is the base URL of the Portainer server
+	ServerURL string
+	// Token is the API token for authenticating with the Portainer server
+	Token string
+	// DisableVersionCheck skips the version check of the Portainer server at startup
+	DisableVersionCheck bool
+	// ClientOptions configure the client of the instance, such as its TLS configuration.
+	// The client options of the server do not apply to additional instances.
+	ClientOptions []client.ClientOption
+	// Client is used instead of a client created from the server URL and token.
+	// This is primarily used for testing to inject mock clients.
+	Client PortainerClient
+}
+
+// InstanceStatus is the connectivity and version of a Portainer instance, as reported by listInstances
+type InstanceStatus struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Default   bool   `json:"default"`
+	Reachable bool   `json:"reachable"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// portainerInstance is a Portainer server along with the client used to reach it
+type portainerInstance struct {
+	name      string
+	serverURL string
+	cli       PortainerClient
+	// version is the version of the server, empty when the version check is disabled
+	version string
+	// edition is the edition of the server, only fetched when a tool is restricted to an edition
+	edition string
+}
+
+// instanceKey is the context key of the Portainer instance selected by a tool call
+type instanceKey struct{}
+
+// WithInstances adds Portainer servers managed by the MCP server, in addition
+// to the default instance passed to NewPortainerMCPServer. Every tool then
+// accepts an optional instance argument selecting the server it is sent to.
+func WithInstances(instances ...Instance) ServerOption {
+	return func(opts *serverOptions) {
+		opts.instances = append(opts.instances, instances...)
+	}
+}
+
+// WithDefaultInstanceName sets the name of the Portainer instance passed to
+// NewPortainerMCPServer. Defaults to DefaultInstanceName.
+func WithDefaultInstanceName(name string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.defaultInstanceName = name
+	}
+}
+
+// newInstances validates the additional instances and creates their clients.
+// The version of each instance is checked unless disabled for the instance,
+// along with its edition when a tool is restricted to an edition.
+func newInstances(ctx context.Context, opts *serverOptions, requirements map[string]toolgen.ToolRequirements) ([]*portainerInstance, error) {
+	if len(opts.instances) == 0 {
+		return nil, nil
+	}
+
+	if opts.sessionTokenMode != SessionTokenDisabled {
+		return nil, fmt.Errorf("session tokens are not supported with multiple Portainer instances")
+	}
+
+	if !opts.environmentScope.IsEmpty() {
+		return nil, fmt.Errorf("environment scopes are not supported with multiple Portainer instances")
+	}
+
+	names := map[string]bool{opts.defaultInstanceName: true}
+	if !instanceNamePattern.MatchString(opts.defaultInstanceName) {
+		return nil, fmt.Errorf("invalid instance name %q: must only contain lowercase letters, digits, dashes and underscores", opts.defaultInstanceName)
+	}
+
+	instances := make([]*portainerInstance, 0, len(opts.instances))
+	for _, inst := range opts.instances {
+		if !instanceNamePattern.MatchString(inst.Name) {
+			return nil, fmt.Errorf("invalid instance name %q: must only contain lowercase letters, digits, dashes and underscores", inst.Name)
+		}
+		if names[inst.Name] {
+			return nil, fmt.Errorf("duplicate instance name %q", inst.Name)
+		}
+		names[inst.Name] = true
+
+		cli := inst.Client
+		if cli == nil {
+			clientOptions := slices.Clone(inst.ClientOptions)
+			if opts.metrics != nil {
+				clientOptions = append(clientOptions, opts.metrics.requestObserver())
+			}
+
+			baseClient, err := client.NewPortainerClient(inst.ServerURL, inst.Token, clientOptions...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create Portainer client of instance %s: %w", inst.Name, err)
+			}
+			cli = baseClient
+		}
+
+		if opts.cache != nil {
+			cli = newCachingClient(cli, *opts.cache)
+		}
+
+		instance := &portainerInstance{
+			name:      inst.Name,
+			serverURL: inst.ServerURL,
+			cli:       cli,
+		}
+
+		if !inst.DisableVersionCheck || opts.metrics != nil {
+			version, err := cli.GetVersion(ctx)
+			if err != nil && !inst.DisableVersionCheck {
+				return nil, fmt.Errorf("failed to get Portainer server version of instance %s: %w", inst.Name, err)
+			}
+
+			if err == nil && opts.metrics != nil {
+				opts.metrics.setPortainerVersion(inst.Name, version)
+			}
+
+			if !inst.DisableVersionCheck {
+				if err := checkPortainerVersion(version); err != nil {
+					return nil, fmt.Errorf("instance %s: %w", inst.Name, err)
+				}
+
+				instance.version = version
+				if requiresEdition(requirements) {
+					instance.edition, err = cli.GetEdition(ctx)
+					if err != nil {
+						return nil, fmt.Errorf("failed to get Portainer server edition of instance %s: %w", inst.Name, err)
+					}
+				}
+			}
+		}
+
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// instanceNames returns the names of the Portainer instances, the default instance first
+func (s *PortainerMCPServer) instanceNames() []string {
+	names := []string{s.defaultInstance}
+	for _, instance := range s.instances {
+		names = append(names, instance.name)
+	}
+	return names
+}
+
+// instanceFromContext returns the additional Portainer instance selected by a
+// tool call, or nil when the call is sent to the default instance
+func instanceFromContext(ctx context.Context) *portainerInstance {
+	instance, _ := ctx.Value(instanceKey{}).(*portainerInstance)
+	return instance
+}
+
+// withInstanceParam returns a copy of the tool accepting the instance argument
+func withInstanceParam(tool mcp.Tool, names []string, defaultName string) mcp.Tool {
+	return withToolProperty(tool, InstanceParam, map[string]any{
+		"type":        "string",
+		"description": fmt.Sprintf("The name of the Portainer instance to send the request to. Defaults to %s.", defaultName),
+		"enum":        names,
+	})
+}
+
+// instanceHandler returns a handler sending the tool call to the Portainer
+// instance selected by its instance argument. The calls sent to an instance
+// listed in unsupported are rejected with the reason the instance cannot
+// serve the tool.
+func (s *PortainerMCPServer) instanceHandler(toolName string, unsupported map[string]error, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		name, err := parser.GetString(InstanceParam, false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid instance parameter", err), nil
+		}
+
+		if name == "" {
+			name = s.defaultInstance
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("portainer.instance", name))
+
+		if name == s.defaultInstance {
+			return handler(ctx, request)
+		}
+
+		for _, instance := range s.instances {
+			if instance.name == name {
+				if err := unsupported[name]; err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("tool %s is not available on instance %s: %v", toolName, name, err)), nil
+				}
+				return handler(context.WithValue(ctx, instanceKey{}, instance), request)
+			}
+		}
+
+		return mcp.NewToolResultError(fmt.Sprintf("unknown instance %q, must be one of %s", name, strings.Join(s.instanceNames(), ", "))), nil
+	}
+}
+
+// AddInstanceFeatures registers the tools describing the managed Portainer instances.
+func (s *PortainerMCPServer) AddInstanceFeatures() {
+	s.addToolIfExists(ToolListInstances, s.HandleListInstances())
+}
+
+func (s *PortainerMCPServer) HandleListInstances() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		instances := append([]*portainerInstance{{
+			name:      s.defaultInstance,
+			serverURL: s.serverURL,
+			cli:       s.client(ctx),
+		}}, s.instances...)
+
+		statuses := make([]InstanceStatus, len(instances))
+
+		var wg sync.WaitGroup
+		for i, instance := range instances {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				status := InstanceStatus{
+					Name:    instance.name,
+					URL:     instance.serverURL,
+					Default: i == 0,
+				}
+
+				version, err := instance.cli.GetVersion(ctx)
+				if err != nil {
+					status.Error = err.Error()
+				} else {
+					status.Reachable = true
+					status.Version = version
+				}
+
+				statuses[i] = status
+			}()
+		}
+		wg.Wait()
+
+		data, err := json.Marshal(statuses)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal instances", err), nil
+		}
+
+		return structuredResult(statuses, data), nil
+	}
+}
@@ -0,0 +1,245 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInstances(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []ServerOption
+		requirements  map[string]toolgen.ToolRequirements
+		mockSetup     func(*MockPortainerClient)
+		expectedNames []string
+		errorContains string
+	}{
+		{
+			name: "no additional instances",
+		},
+		{
+			name:          "valid instances",
+			options:       []ServerOption{WithDefaultInstanceName("prod")},
+			mockSetup:     func(m *MockPortainerClient) { m.On("GetVersion").Return("2.33.0", nil) },
+			expectedNames: []string{"edge"},
+		},
+		{
+			name:         "edition of a restricted tool",
+			requirements: map[string]toolgen.ToolRequirements{ToolDeleteTag: {Edition: toolgen.EditionBE}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetVersion").Return("2.33.0", nil)
+				m.On("GetEdition").Return("BE", nil)
+			},
+			expectedNames: []string{"edge"},
+		},
+		{
+			name:         "unreachable edition",
+			requirements: map[string]toolgen.ToolRequirements{ToolDeleteTag: {Edition: toolgen.EditionBE}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetVersion").Return("2.33.0", nil)
+				m.On("GetEdition").Return("", errors.New("connection refused"))
+			},
+			errorContains: "failed to get Portainer server edition of instance edge",
+		},
+		{
+			name:          "invalid instance name",
+			options:       []ServerOption{WithDefaultInstanceName("Prod")},
+			mockSetup:     func(m *MockPortainerClient) {},
+			errorContains: `invalid instance name "Prod"`,
+		},
+		{
+			name:          "duplicate instance name",
+			options:       []ServerOption{WithDefaultInstanceName("edge")},
+			mockSetup:     func(m *MockPortainerClient) {},
+			errorContains: `duplicate instance name "edge"`,
+		},
+		{
+			name:          "session tokens",
+			options:       []ServerOption{WithSessionTokenMode(SessionTokenOptional)},
+			mockSetup:     func(m *MockPortainerClient) {},
+			errorContains: "session tokens are not supported",
+		},
+		{
+			name:          "environment scope",
+			options:       []ServerOption{WithEnvironmentScope(EnvironmentScope{EnvironmentIDs: []int{1}})},
+			mockSetup:     func(m *MockPortainerClient) {},
+			errorContains: "environment scopes are not supported",
+		},
+		{
+			name:          "unreachable instance",
+			mockSetup:     func(m *MockPortainerClient) { m.On("GetVersion").Return("", errors.New("connection refused")) },
+			errorContains: "failed to get Portainer server version of instance edge",
+		},
+		{
+			name:          "unsupported version",
+			mockSetup:     func(m *MockPortainerClient) { m.On("GetVersion").Return("2.20.0", nil) },
+			errorContains: "instance edge: unsupported Portainer server version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &serverOptions{
+				defaultInstanceName: DefaultInstanceName,
+				sessionTokenMode:    SessionTokenDisabled,
+			}
+			for _, option := range tt.options {
+				option(opts)
+			}
+
+			mockClient := new(MockPortainerClient)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockClient)
+				WithInstances(Instance{Name: "edge", ServerURL: "https://edge.example.com", Client: mockClient})(opts)
+			}
+
+			instances, err := newInstances(context.Background(), opts, tt.requirements)
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, instance := range instances {
+				names = append(names, instance.name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func newInstanceTestServer(t *testing.T) (*PortainerMCPServer, *MockPortainerClient, *MockPortainerClient) {
+	t.Helper()
+
+	defaultClient := new(MockPortainerClient)
+	edgeClient := new(MockPortainerClient)
+
+	s := &PortainerMCPServer{
+		srv:             server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		cli:             defaultClient,
+		serverURL:       "https://portainer.example.com",
+		defaultInstance: DefaultInstanceName,
+		instances: []*portainerInstance{{
+			name:      "edge",
+			serverURL: "https://edge.example.com",
+			cli:       edgeClient,
+		}},
+		tools: map[string]mcp.Tool{
+			ToolListEnvironmentTags: {Name: ToolListEnvironmentTags},
+			ToolListInstances:       {Name: ToolListInstances},
+		},
+	}
+
+	s.addToolIfExists(ToolListEnvironmentTags, s.HandleGetEnvironmentTags())
+	s.AddInstanceFeatures()
+
+	return s, defaultClient, edgeClient
+}
+
+func TestInstanceParameter(t *testing.T) {
+	s, _, _ := newInstanceTestServer(t)
+
+	tool := s.srv.GetTool(ToolListEnvironmentTags)
+	require.NotNil(t, tool)
+	require.Contains(t, tool.Tool.InputSchema.Properties, InstanceParam)
+	assert.Equal(t, []string{DefaultInstanceName, "edge"}, tool.Tool.InputSchema.Properties[InstanceParam].(map[string]any)["enum"])
+
+	listTool := s.srv.GetTool(ToolListInstances)
+	require.NotNil(t, listTool)
+	assert.NotContains(t, listTool.Tool.InputSchema.Properties, InstanceParam)
+}
+
+func TestInstanceHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expectedTag   string
+		expectedError string
+	}{
+		{
+			name:        "default instance when omitted",
+			args:        map[string]any{},
+			expectedTag: "default-tag",
+		},
+		{
+			name:        "default instance by name",
+			args:        map[string]any{InstanceParam: DefaultInstanceName},
+			expectedTag: "default-tag",
+		},
+		{
+			name:        "additional instance",
+			args:        map[string]any{InstanceParam: "edge"},
+			expectedTag: "edge-tag",
+		},
+		{
+			name:          "unknown instance",
+			args:          map[string]any{InstanceParam: "staging"},
+			expectedError: `unknown instance "staging", must be one of default, edge`,
+		},
+		{
+			name:          "invalid instance type",
+			args:          map[string]any{InstanceParam: 1},
+			expectedError: "invalid instance parameter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, defaultClient, edgeClient := newInstanceTestServer(t)
+			defaultClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{{ID: 1, Name: "default-tag"}}, nil).Maybe()
+			edgeClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{{ID: 1, Name: "edge-tag"}}, nil).Maybe()
+
+			result := callTool(t, s, context.Background(), ToolListEnvironmentTags, tt.args)
+
+			if tt.expectedError != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, resultText(result), tt.expectedError)
+				assert.Empty(t, defaultClient.Calls)
+				assert.Empty(t, edgeClient.Calls)
+				return
+			}
+
+			assert.False(t, result.IsError)
+			assert.Contains(t, resultText(result), tt.expectedTag)
+		})
+	}
+}
+
+func TestHandleListInstances(t *testing.T) {
+	s, defaultClient, edgeClient := newInstanceTestServer(t)
+	defaultClient.On("GetVersion").Return("2.33.0", nil)
+	edgeClient.On("GetVersion").Return("", errors.New("connection refused"))
+
+	result := callTool(t, s, context.Background(), ToolListInstances, map[string]any{})
+	require.False(t, result.IsError)
+
+	var statuses []InstanceStatus
+	require.NoError(t, json.Unmarshal([]byte(resultText(result)), &statuses))
+
+	assert.Equal(t, []InstanceStatus{
+		{
+			Name:      DefaultInstanceName,
+			URL:       "https://portainer.example.com",
+			Default:   true,
+			Reachable: true,
+			Version:   "2.33.0",
+		},
+		{
+			Name:  "edge",
+			URL:   "https://edge.example.com",
+			Error: "connection refused",
+		},
+	}, statuses)
+}
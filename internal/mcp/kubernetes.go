@@ -4,17 +4,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/portainer/portainer-mcp/internal/k8sutil"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddKubernetesProxyFeatures() {
-	s.addToolIfExists(ToolKubernetesProxyStripped, s.HandleKubernetesProxyStripped())
+	s.addToolIfExists(ToolGetKubernetesResourceStripped, s.HandleKubernetesProxyStripped())
 
 	if !s.readOnly {
 		s.addToolIfExists(ToolKubernetesProxy, s.HandleKubernetesProxy())
@@ -23,48 +24,42 @@ func (s *PortainerMCPServer) AddKubernetesProxyFeatures() {
 
 func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseGetKubernetesResourceStrippedArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		kubernetesAPIPath, err := parser.GetString("kubernetesAPIPath", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid kubernetesAPIPath parameter", err), nil
-		}
-		if !strings.HasPrefix(kubernetesAPIPath, "/") {
+		if !strings.HasPrefix(args.KubernetesAPIPath, "/") {
 			return mcp.NewToolResultError("kubernetesAPIPath must start with a leading slash"), nil
 		}
 
-		queryParams, err := parser.GetArrayOfObjects("queryParams", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid queryParams parameter", err), nil
+		if strings.Contains(args.KubernetesAPIPath, "?") {
+			return mcp.NewToolResultError("kubernetesAPIPath must not contain a query string, use queryParams instead"), nil
 		}
-		queryParamsMap, err := parseKeyValueMap(queryParams)
+
+		queryParamsMap, err := parseKeyValueMap(args.QueryParams)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid query params", err), nil
 		}
 
-		headers, err := parser.GetArrayOfObjects("headers", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid headers parameter", err), nil
-		}
-		headersMap, err := parseKeyValueMap(headers)
+		headersMap, err := parseKeyValueMap(args.Headers)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid headers", err), nil
 		}
 
+		if err := s.proxyPolicy.check(proxyAPIKubernetes, args.EnvironmentID, http.MethodGet, args.KubernetesAPIPath, queryParamsMap, ""); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		opts := models.KubernetesProxyRequestOptions{
-			EnvironmentID: environmentId,
-			Path:          kubernetesAPIPath,
+			EnvironmentID: args.EnvironmentID,
+			Path:          args.KubernetesAPIPath,
 			Method:        "GET",
 			QueryParams:   queryParamsMap,
 			Headers:       headersMap,
 		}
 
-		response, err := s.cli.ProxyKubernetesRequest(opts)
+		response, err := s.client(ctx).ProxyKubernetesRequest(ctx, opts)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
 		}
@@ -80,69 +75,58 @@ func (s *PortainerMCPServer) HandleKubernetesProxyStripped() server.ToolHandlerF
 
 func (s *PortainerMCPServer) HandleKubernetesProxy() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		environmentId, err := parser.GetInt("environmentId", true)
+		args, err := ParseKubernetesProxyArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentId parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		method, err := parser.GetString("method", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid method parameter", err), nil
-		}
-		if !isValidHTTPMethod(method) {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid method: %s", method)), nil
+		if !isValidHTTPMethod(args.Method) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid method: %s", args.Method)), nil
 		}
 
-		kubernetesAPIPath, err := parser.GetString("kubernetesAPIPath", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid kubernetesAPIPath parameter", err), nil
-		}
-		if !strings.HasPrefix(kubernetesAPIPath, "/") {
+		if !strings.HasPrefix(args.KubernetesAPIPath, "/") {
 			return mcp.NewToolResultError("kubernetesAPIPath must start with a leading slash"), nil
 		}
 
-		queryParams, err := parser.GetArrayOfObjects("queryParams", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid queryParams parameter", err), nil
+		if strings.Contains(args.KubernetesAPIPath, "?") {
+			return mcp.NewToolResultError("kubernetesAPIPath must not contain a query string, use queryParams instead"), nil
 		}
-		queryParamsMap, err := parseKeyValueMap(queryParams)
+
+		queryParamsMap, err := parseKeyValueMap(args.QueryParams)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid query params", err), nil
 		}
 
-		headers, err := parser.GetArrayOfObjects("headers", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid headers parameter", err), nil
-		}
-		headersMap, err := parseKeyValueMap(headers)
+		headersMap, err := parseKeyValueMap(args.Headers)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("invalid headers", err), nil
 		}
 
-		body, err := parser.GetString("body", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid body parameter", err), nil
+		if err := s.proxyPolicy.check(proxyAPIKubernetes, args.EnvironmentID, args.Method, args.KubernetesAPIPath, queryParamsMap, args.Body); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		opts := models.KubernetesProxyRequestOptions{
-			EnvironmentID: environmentId,
-			Path:          kubernetesAPIPath,
-			Method:        method,
+			EnvironmentID: args.EnvironmentID,
+			Path:          args.KubernetesAPIPath,
+			Method:        args.Method,
 			QueryParams:   queryParamsMap,
 			Headers:       headersMap,
 		}
 
-		if body != "" {
-			opts.Body = strings.NewReader(body)
+		if args.Body != "" {
+			opts.Body = strings.NewReader(args.Body)
 		}
 
-		response, err := s.cli.ProxyKubernetesRequest(opts)
+		response, err := s.client(ctx).ProxyKubernetesRequest(ctx, opts)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", err), nil
 		}
 
+		if client.IsDryRunResponse(response) {
+			return mcp.NewToolResultErrorFromErr("failed to send Kubernetes API request", client.ErrDryRun), nil
+		}
+
 		responseBody, err := io.ReadAll(response.Body)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to read Kubernetes API response", err), nil
@@ -60,6 +60,15 @@ func TestHandleKubernetesProxy_ParameterValidation(t *testing.T) {
 			},
 			expectedErrorMsg: "kubernetesAPIPath must start with a leading slash",
 		},
+		{
+			name: "invalid kubernetesAPIPath (query string)",
+			inputParams: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/api/v1/pods?watch=true",
+				"method":            "GET",
+			},
+			expectedErrorMsg: "kubernetesAPIPath must not contain a query string",
+		},
 		{
 			name: "invalid HTTP method",
 			inputParams: map[string]any{
@@ -308,6 +317,14 @@ func TestHandleKubernetesProxyStripped_ParameterValidation(t *testing.T) {
 			},
 			expectedErrorMsg: "kubernetesAPIPath must start with a leading slash",
 		},
+		{
+			name: "invalid kubernetesAPIPath (query string)",
+			inputParams: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "/api/v1/pods?watch=true",
+			},
+			expectedErrorMsg: "kubernetesAPIPath must not contain a query string",
+		},
 		{
 			name: "invalid queryParams type (not an array)",
 			inputParams: map[string]any{
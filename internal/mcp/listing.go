@@ -0,0 +1,665 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// Arguments of the list tools
+const (
+	// FilterParam is the tool argument holding the conditions the listed items must match
+	FilterParam = "filter"
+	// SortParam is the tool argument holding the fields the listed items are sorted by
+	SortParam = "sort"
+	// LimitParam is the tool argument holding the maximum number of listed items
+	LimitParam = "limit"
+	// CursorParam is the tool argument holding the cursor of the page of items to list
+	CursorParam = "cursor"
+
+	// NextCursorProperty is the property of the result of a list tool holding
+	// the cursor of the next page of items
+	NextCursorProperty = "nextCursor"
+
+	// MaxListLimit is the maximum number of items listed by a single tool call
+	MaxListLimit = 1000
+)
+
+// Operators of the filter conditions
+const (
+	filterEquals    = "="
+	filterNotEquals = "!="
+	filterMatches   = "~"
+	filterContains  = "contains"
+)
+
+// listTools are the tools listing resources, which accept the filter, sort,
+// limit and cursor arguments
+var listTools = map[string]bool{
+	ToolListAccessGroups:              true,
+	ToolListAlertRules:                true,
+	ToolListCustomResourceDefinitions: true,
+	ToolListCustomResources:           true,
+	ToolListCustomTemplates:           true,
+	ToolListDockerStacks:              true,
+	ToolListEdgeJobs:                  true,
+	ToolListEnvironmentGroups:         true,
+	ToolListEnvironmentTags:           true,
+	ToolListEnvironments:              true,
+	ToolListGitCredentials:            true,
+	ToolListPolicies:                  true,
+	ToolListPolicyTemplates:           true,
+	ToolListRegistries:                true,
+	ToolListStacks:                    true,
+	ToolListTeams:                     true,
+	ToolListUsers:                     true,
+	ToolListWebhooks:                  true,
+}
+
+// listFilter is a condition of the filter argument
+type listFilter struct {
+	field    string
+	path     []string
+	operator string
+	value    string
+}
+
+// listSort is a field of the sort argument
+type listSort struct {
+	field      string
+	path       []string
+	descending bool
+}
+
+// listQuery holds the list arguments of a tool call
+type listQuery struct {
+	filters []listFilter
+	sort    []listSort
+	limit   int
+	offset  int
+	// key identifies the filter and sort of the query in its cursors
+	key string
+	// paged is set by a handler listing the page of the query itself, from
+	// the offset of the query and with an extra item revealing a next page
+	paged bool
+}
+
+// listCursor is the decoded content of an opaque cursor
+type listCursor struct {
+	Offset int    `json:"o"`
+	Key    string `json:"k"`
+}
+
+// listQueryKey is the context key of the list query of a tool call
+type listQueryKey struct{}
+
+// withListQuery returns a copy of the context holding the list query of the tool call
+func withListQuery(ctx context.Context, query *listQuery) context.Context {
+	return context.WithValue(ctx, listQueryKey{}, query)
+}
+
+// listQueryFrom returns the list query of the tool call of the context, nil
+// when the call has no list arguments. Handlers may use it to have Portainer
+// narrow the listed resources.
+func listQueryFrom(ctx context.Context) *listQuery {
+	query, _ := ctx.Value(listQueryKey{}).(*listQuery)
+	return query
+}
+
+// listHandler returns a handler filtering, sorting and paginating the items
+// returned by a list tool according to the list arguments of the tool call.
+// A call without list arguments returns every item, as before.
+//
+// The items are filtered and sorted before being paginated, so that the
+// cursor of the next page stays valid for the calls with the same filter and
+// sort, whatever their limit.
+func listHandler(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	fields := listItemFields(tool)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query, err := parseListQuery(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if query == nil {
+			return handler(ctx, request)
+		}
+
+		if err := query.checkFields(fields); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := handler(withListQuery(ctx, query), request)
+		if err != nil || result == nil || result.IsError || result.StructuredContent == nil {
+			return result, err
+		}
+
+		items, err := structuredItems(result.StructuredContent)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to list the items", err), nil
+		}
+
+		page, next := query.apply(items)
+
+		content := map[string]any{toolgen.StructuredItemsProperty: page}
+		var data []byte
+		if next != "" {
+			content[NextCursorProperty] = next
+			data, err = json.Marshal(content)
+		} else {
+			data, err = json.Marshal(page)
+		}
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal the items", err), nil
+		}
+
+		return mcp.NewToolResultStructured(content, string(data)), nil
+	}
+}
+
+// withListParams returns a copy of the list tool accepting the list
+// arguments, and whose output schema has the cursor of the next page
+func withListParams(tool mcp.Tool) mcp.Tool {
+	tool = withToolProperty(tool, FilterParam, map[string]any{
+		"type":        "string",
+		"description": "Comma-separated conditions the items must all match: field=value, field!=value, field~value for a case-insensitive substring, or field contains value for a value of a list. The fields are those of the items, such as status=inactive,tagIds contains 3.",
+	})
+	tool = withToolProperty(tool, SortParam, map[string]any{
+		"type":        "string",
+		"description": "Comma-separated fields the items are sorted by, in descending order when prefixed with -, such as -name.",
+	})
+	tool = withToolProperty(tool, LimitParam, map[string]any{
+		"type":        "integer",
+		"description": "The maximum number of items to return. When more items match, the result holds a nextCursor to get the next items.",
+		"minimum":     1,
+		"maximum":     MaxListLimit,
+	})
+	tool = withToolProperty(tool, CursorParam, map[string]any{
+		"type":        "string",
+		"description": "The nextCursor returned by a previous call with the same filter and sort, to get the next items.",
+	})
+
+	if len(tool.RawOutputSchema) == 0 {
+		return tool
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(tool.RawOutputSchema, &schema); err != nil {
+		return tool
+	}
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return tool
+	}
+	properties[NextCursorProperty] = map[string]any{
+		"type":        "string",
+		"description": "The cursor of the next items, set when more items match",
+	}
+
+	if data, err := json.Marshal(schema); err == nil {
+		tool.RawOutputSchema = data
+	}
+	return tool
+}
+
+// listItemFields returns the fields of the items of a list tool, from its
+// output schema, or nil when they are unknown
+func listItemFields(tool mcp.Tool) []string {
+	var schema struct {
+		Properties map[string]struct {
+			Items struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if len(tool.RawOutputSchema) == 0 || json.Unmarshal(tool.RawOutputSchema, &schema) != nil {
+		return nil
+	}
+
+	properties := schema.Properties[toolgen.StructuredItemsProperty].Items.Properties
+	if len(properties) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(properties))
+	for field := range properties {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// parseListQuery parses the list arguments of a tool call, returning nil
+// when the call has none
+func parseListQuery(request mcp.CallToolRequest) (*listQuery, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	filter, err := parser.GetString(FilterParam, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter parameter: %w", err)
+	}
+
+	sort, err := parser.GetString(SortParam, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sort parameter: %w", err)
+	}
+
+	limit, err := parser.GetInt(LimitParam, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit parameter: %w", err)
+	}
+	if _, ok := request.GetArguments()[LimitParam]; ok && (limit < 1 || limit > MaxListLimit) {
+		return nil, fmt.Errorf("invalid limit parameter: must be between 1 and %d", MaxListLimit)
+	}
+
+	cursor, err := parser.GetString(CursorParam, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor parameter: %w", err)
+	}
+
+	filter, sort, cursor = strings.TrimSpace(filter), strings.TrimSpace(sort), strings.TrimSpace(cursor)
+	if filter == "" && sort == "" && limit == 0 && cursor == "" {
+		return nil, nil
+	}
+
+	query := &listQuery{limit: limit, key: queryKey(filter, sort)}
+
+	if query.filters, err = parseFilter(filter); err != nil {
+		return nil, fmt.Errorf("invalid filter parameter: %w", err)
+	}
+
+	if query.sort, err = parseSort(sort); err != nil {
+		return nil, fmt.Errorf("invalid sort parameter: %w", err)
+	}
+
+	if cursor != "" {
+		if query.offset, err = decodeCursor(cursor, query.key); err != nil {
+			return nil, fmt.Errorf("invalid cursor parameter: %w", err)
+		}
+	}
+
+	return query, nil
+}
+
+// parseFilter parses the comma-separated conditions of the filter argument
+func parseFilter(filter string) ([]listFilter, error) {
+	var filters []listFilter
+
+	for _, condition := range strings.Split(filter, ",") {
+		condition = strings.TrimSpace(condition)
+		if condition == "" {
+			continue
+		}
+
+		f, err := parseCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+// parseCondition parses a single filter condition, the operator being the
+// first one found in the condition
+func parseCondition(condition string) (listFilter, error) {
+	var f listFilter
+
+	if i := strings.Index(strings.ToLower(condition), " "+filterContains+" "); i > 0 {
+		f.field = condition[:i]
+		f.operator = filterContains
+		f.value = condition[i+len(filterContains)+2:]
+	} else {
+		index := -1
+		for _, operator := range []string{filterNotEquals, filterMatches, filterEquals} {
+			if i := strings.Index(condition, operator); i >= 0 && (index < 0 || i < index) {
+				index = i
+				f.operator = operator
+			}
+		}
+		if index < 0 {
+			return f, fmt.Errorf("condition %q must be field=value, field!=value, field~value or field contains value", condition)
+		}
+		f.field = condition[:index]
+		f.value = condition[index+len(f.operator):]
+	}
+
+	f.field = strings.TrimSpace(f.field)
+	f.value = strings.TrimSpace(f.value)
+	if f.field == "" {
+		return f, fmt.Errorf("condition %q has no field", condition)
+	}
+
+	f.path = fieldPath(f.field)
+	return f, nil
+}
+
+// parseSort parses the comma-separated fields of the sort argument
+func parseSort(sort string) ([]listSort, error) {
+	var keys []listSort
+
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key := listSort{}
+		if rest, ok := strings.CutPrefix(field, "-"); ok {
+			key.descending = true
+			field = rest
+		} else {
+			field = strings.TrimPrefix(field, "+")
+		}
+
+		key.field = strings.TrimSpace(field)
+		if key.field == "" {
+			return nil, fmt.Errorf("sort field %q has no name", field)
+		}
+		key.path = fieldPath(key.field)
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// fieldPath splits a field name on dots into the normalized names of the
+// nested fields
+func fieldPath(field string) []string {
+	parts := strings.Split(field, ".")
+	for i, part := range parts {
+		parts[i] = normalizeFieldName(part)
+	}
+	return parts
+}
+
+// normalizeFieldName returns a field name without case, underscores and
+// dashes, so that tagIds names the tag_ids field
+func normalizeFieldName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "", "-", "").Replace(name)
+}
+
+// checkFields checks that the filter and sort fields of the query are fields
+// of the items. Fields are not checked when the fields of the items are unknown.
+func (q *listQuery) checkFields(fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[normalizeFieldName(field)] = true
+	}
+
+	for _, f := range q.filters {
+		if !known[f.path[0]] {
+			return fmt.Errorf("invalid filter parameter: unknown field %s, must be one of: %s", f.field, strings.Join(fields, ", "))
+		}
+	}
+
+	for _, key := range q.sort {
+		if !known[key.path[0]] {
+			return fmt.Errorf("invalid sort parameter: unknown field %s, must be one of: %s", key.field, strings.Join(fields, ", "))
+		}
+	}
+
+	return nil
+}
+
+// apply filters, sorts and paginates items, returning the page of the query
+// and the cursor of the next page, empty for the last page
+func (q *listQuery) apply(items []any) ([]any, string) {
+	matching := make([]any, 0, len(items))
+	for _, item := range items {
+		if q.matches(item) {
+			matching = append(matching, item)
+		}
+	}
+
+	if len(q.sort) > 0 {
+		slices.SortStableFunc(matching, q.compare)
+	}
+
+	start := q.offset
+	if q.paged {
+		start = 0
+	}
+	page := matching[min(start, len(matching)):]
+
+	if q.limit > 0 && len(page) > q.limit {
+		return page[:q.limit], encodeCursor(q.offset+q.limit, q.key)
+	}
+	return page, ""
+}
+
+// matches reports whether an item matches every condition of the filter
+func (q *listQuery) matches(item any) bool {
+	for _, f := range q.filters {
+		value, found := lookupField(item, f.path)
+
+		var match bool
+		switch f.operator {
+		case filterEquals:
+			match = found && valueEquals(value, f.value)
+		case filterNotEquals:
+			match = !found || !valueEquals(value, f.value)
+		case filterMatches:
+			match = found && containsFold(formatValue(value), f.value)
+		case filterContains:
+			match = found && valueContains(value, f.value)
+		}
+
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compare orders two items by the sort fields. Items missing a field come
+// last in both orders.
+func (q *listQuery) compare(a, b any) int {
+	for _, key := range q.sort {
+		x, _ := lookupField(a, key.path)
+		y, _ := lookupField(b, key.path)
+
+		switch {
+		case x == nil && y == nil:
+			continue
+		case x == nil:
+			return 1
+		case y == nil:
+			return -1
+		}
+
+		c := compareValues(x, y)
+		if key.descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// lookupField returns the value of a nested field of an item decoded from
+// JSON, matching the normalized field names
+func lookupField(item any, path []string) (any, bool) {
+	value := item
+
+	for _, name := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		found := false
+		for key, field := range object {
+			if normalizeFieldName(key) == name {
+				value, found = field, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// valueEquals reports whether a value decoded from JSON equals the value of a
+// condition. Strings are compared without case.
+func valueEquals(value any, expected string) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.EqualFold(v, expected)
+	case float64:
+		number, err := strconv.ParseFloat(expected, 64)
+		return err == nil && number == v
+	case bool:
+		b, err := strconv.ParseBool(expected)
+		return err == nil && b == v
+	case nil:
+		return expected == "" || expected == "null"
+	default:
+		return false
+	}
+}
+
+// valueContains reports whether a list holds the value of a condition, an
+// object has it as a key, or a string has it as a substring
+func valueContains(value any, expected string) bool {
+	switch v := value.(type) {
+	case []any:
+		return slices.ContainsFunc(v, func(item any) bool { return valueEquals(item, expected) })
+	case map[string]any:
+		for key := range v {
+			if strings.EqualFold(key, expected) {
+				return true
+			}
+		}
+		return false
+	case string:
+		return containsFold(v, expected)
+	default:
+		return false
+	}
+}
+
+// compareValues orders two values decoded from JSON, numbers by value and
+// strings without case first
+func compareValues(x, y any) int {
+	switch a := x.(type) {
+	case float64:
+		if b, ok := y.(float64); ok {
+			return cmp.Compare(a, b)
+		}
+	case string:
+		if b, ok := y.(string); ok {
+			if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+				return c
+			}
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := y.(bool); ok && a != b {
+			if a {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	return strings.Compare(formatValue(x), formatValue(y))
+}
+
+// formatValue returns the text of a value decoded from JSON
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// containsFold reports whether a string contains a substring without case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// structuredItems returns the items of the structured content of a list tool
+// result, as decoded from JSON
+func structuredItems(content any) ([]any, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	items, ok := decoded[toolgen.StructuredItemsProperty].([]any)
+	if !ok && decoded[toolgen.StructuredItemsProperty] != nil {
+		return nil, fmt.Errorf("the %s of the result is not a list", toolgen.StructuredItemsProperty)
+	}
+	return items, nil
+}
+
+// queryKey identifies the filter and sort arguments of a query
+func queryKey(filter, sort string) string {
+	h := fnv.New64a()
+	h.Write([]byte(filter))
+	h.Write([]byte{0})
+	h.Write([]byte(sort))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// encodeCursor returns the opaque cursor of the page of a query starting at an offset
+func encodeCursor(offset int, key string) string {
+	data, _ := json.Marshal(listCursor{Offset: offset, Key: key})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the offset of a cursor, checking that it was returned
+// by a query with the same filter and sort
+func decodeCursor(cursor, key string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("malformed cursor")
+	}
+
+	var decoded listCursor
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Offset < 0 {
+		return 0, fmt.Errorf("malformed cursor")
+	}
+
+	if decoded.Key != key {
+		return 0, fmt.Errorf("the cursor was returned by a call with another filter or sort")
+	}
+
+	return decoded.Offset, nil
+}
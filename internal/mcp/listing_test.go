@@ -0,0 +1,456 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listTestItems returns items as decoded from the JSON of a list tool result
+func listTestItems(t *testing.T) []any {
+	t.Helper()
+
+	var items []any
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id": 1, "name": "edge-b", "status": "inactive", "type": "docker-edge-agent", "tag_ids": [3]},
+		{"id": 2, "name": "prod", "status": "active", "type": "kubernetes-agent", "tag_ids": [1, 3]},
+		{"id": 3, "name": "Edge-a", "status": "inactive", "type": "kubernetes-agent", "tag_ids": null},
+		{"id": 4, "name": "dev", "status": "active", "type": "docker-agent", "tag_ids": [], "labels": {"team": "ops"}}
+	]`), &items))
+	return items
+}
+
+// itemIDs returns the IDs of items decoded from JSON
+func itemIDs(items []any) []int {
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, int(item.(map[string]any)["id"].(float64)))
+	}
+	return ids
+}
+
+func TestParseListQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expectNil     bool
+		expected      *listQuery
+		errorContains string
+	}{
+		{
+			name:      "no list arguments",
+			args:      map[string]any{"environmentId": float64(1)},
+			expectNil: true,
+		},
+		{
+			name: "filter and sort",
+			args: map[string]any{
+				FilterParam: "status=inactive, name~edge,type != docker-agent,tagIds CONTAINS 3",
+				SortParam:   "-status,name",
+				LimitParam:  float64(10),
+			},
+			expected: &listQuery{
+				filters: []listFilter{
+					{field: "status", path: []string{"status"}, operator: filterEquals, value: "inactive"},
+					{field: "name", path: []string{"name"}, operator: filterMatches, value: "edge"},
+					{field: "type", path: []string{"type"}, operator: filterNotEquals, value: "docker-agent"},
+					{field: "tagIds", path: []string{"tagids"}, operator: filterContains, value: "3"},
+				},
+				sort: []listSort{
+					{field: "status", path: []string{"status"}, descending: true},
+					{field: "name", path: []string{"name"}},
+				},
+				limit: 10,
+				key:   queryKey("status=inactive, name~edge,type != docker-agent,tagIds CONTAINS 3", "-status,name"),
+			},
+		},
+		{
+			name:          "condition without operator",
+			args:          map[string]any{FilterParam: "inactive"},
+			errorContains: `invalid filter parameter: condition "inactive" must be field=value`,
+		},
+		{
+			name:          "condition without field",
+			args:          map[string]any{FilterParam: "=inactive"},
+			errorContains: `invalid filter parameter: condition "=inactive" has no field`,
+		},
+		{
+			name:          "sort without field",
+			args:          map[string]any{SortParam: "name,-"},
+			errorContains: "invalid sort parameter",
+		},
+		{
+			name:          "limit too small",
+			args:          map[string]any{LimitParam: float64(0)},
+			errorContains: "invalid limit parameter: must be between 1 and 1000",
+		},
+		{
+			name:          "limit too large",
+			args:          map[string]any{LimitParam: float64(MaxListLimit + 1)},
+			errorContains: "invalid limit parameter: must be between 1 and 1000",
+		},
+		{
+			name:          "malformed cursor",
+			args:          map[string]any{CursorParam: "not a cursor"},
+			errorContains: "invalid cursor parameter: malformed cursor",
+		},
+		{
+			name: "cursor of another filter",
+			args: map[string]any{
+				FilterParam: "status=active",
+				CursorParam: encodeCursor(10, queryKey("status=inactive", "")),
+			},
+			errorContains: "invalid cursor parameter: the cursor was returned by a call with another filter or sort",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := parseListQuery(CreateMCPRequest(tt.args))
+
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.expectNil {
+				assert.Nil(t, query)
+				return
+			}
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestListQueryApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      string
+		sort        string
+		expectedIDs []int
+	}{
+		{
+			name:        "no filter keeps the order",
+			expectedIDs: []int{1, 2, 3, 4},
+		},
+		{
+			name:        "equality without case",
+			filter:      "status=INACTIVE",
+			expectedIDs: []int{1, 3},
+		},
+		{
+			name:        "several conditions",
+			filter:      "status=inactive,type=kubernetes-agent",
+			expectedIDs: []int{3},
+		},
+		{
+			name:        "not equal",
+			filter:      "type!=kubernetes-agent",
+			expectedIDs: []int{1, 4},
+		},
+		{
+			name:        "substring",
+			filter:      "name~edge",
+			expectedIDs: []int{1, 3},
+		},
+		{
+			name:        "list contains",
+			filter:      "tagIds contains 3",
+			expectedIDs: []int{1, 2},
+		},
+		{
+			name:        "object contains key",
+			filter:      "labels contains team",
+			expectedIDs: []int{4},
+		},
+		{
+			name:        "nested field",
+			filter:      "labels.team=ops",
+			expectedIDs: []int{4},
+		},
+		{
+			name:        "number",
+			filter:      "id=2",
+			expectedIDs: []int{2},
+		},
+		{
+			name:        "sort without case",
+			sort:        "name",
+			expectedIDs: []int{4, 3, 1, 2},
+		},
+		{
+			name:        "descending sort then ascending sort",
+			sort:        "-status,id",
+			expectedIDs: []int{1, 3, 2, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := parseListQuery(CreateMCPRequest(map[string]any{
+				FilterParam: tt.filter,
+				SortParam:   tt.sort,
+				LimitParam:  float64(MaxListLimit),
+			}))
+			require.NoError(t, err)
+
+			page, next := query.apply(listTestItems(t))
+			assert.Equal(t, tt.expectedIDs, itemIDs(page))
+			assert.Empty(t, next)
+		})
+	}
+}
+
+func TestListQueryPagination(t *testing.T) {
+	args := map[string]any{SortParam: "-id", LimitParam: float64(3)}
+
+	var pages [][]int
+	for {
+		query, err := parseListQuery(CreateMCPRequest(args))
+		require.NoError(t, err)
+
+		page, next := query.apply(listTestItems(t))
+		pages = append(pages, itemIDs(page))
+		if next == "" {
+			break
+		}
+
+		// The cursor is valid whatever the limit of the next call
+		args = map[string]any{SortParam: "-id", LimitParam: float64(2), CursorParam: next}
+	}
+
+	assert.Equal(t, [][]int{{4, 3, 2}, {1}}, pages)
+
+	query := &listQuery{limit: 2, offset: 10}
+	page, next := query.apply(listTestItems(t))
+	assert.Empty(t, page)
+	assert.Empty(t, next)
+}
+
+func TestListTools(t *testing.T) {
+	config, err := tooldef.EmbeddedTools()
+	require.NoError(t, err)
+	tools, _ := toolgen.ConvertToolsConfig(config)
+
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{
+		{ID: 1, Name: "prod", EnvironmentIds: []int{1, 2}},
+		{ID: 2, Name: "dev", EnvironmentIds: []int{3}},
+		{ID: 3, Name: "staging"},
+	}, nil)
+
+	s := &PortainerMCPServer{
+		srv:   server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		cli:   mockClient,
+		tools: tools,
+	}
+	s.AddTagFeatures()
+
+	tool := s.srv.GetTool(ToolListEnvironmentTags)
+	require.NotNil(t, tool)
+	for _, param := range []string{FilterParam, SortParam, LimitParam, CursorParam} {
+		assert.Contains(t, tool.Tool.InputSchema.Properties, param)
+	}
+	assert.Contains(t, string(tool.Tool.RawOutputSchema), `"nextCursor"`)
+	assert.NotContains(t, s.srv.GetTool(ToolCreateEnvironmentTag).Tool.InputSchema.Properties, FilterParam)
+
+	ctx := context.Background()
+
+	t.Run("without list arguments", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{})
+		require.False(t, result.IsError, resultText(result))
+		assert.Len(t, result.StructuredContent.(map[string]any)["items"], 3)
+	})
+
+	t.Run("first page", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{
+			SortParam:  "name",
+			LimitParam: float64(2),
+		})
+		require.False(t, result.IsError, resultText(result))
+
+		content := result.StructuredContent.(map[string]any)
+		assert.Equal(t, []int{2, 1}, itemIDs(content["items"].([]any)))
+		require.NotEmpty(t, content[NextCursorProperty])
+		require.NoError(t, toolgen.ValidateOutput(outputSchema(t, tool.Tool), content))
+
+		var text map[string]any
+		require.NoError(t, json.Unmarshal([]byte(resultText(result)), &text))
+		assert.Equal(t, content[NextCursorProperty], text[NextCursorProperty])
+
+		result = callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{
+			SortParam:   "name",
+			LimitParam:  float64(2),
+			CursorParam: content[NextCursorProperty],
+		})
+		require.False(t, result.IsError, resultText(result))
+
+		content = result.StructuredContent.(map[string]any)
+		assert.Equal(t, []int{3}, itemIDs(content["items"].([]any)))
+		assert.NotContains(t, content, NextCursorProperty)
+		assert.JSONEq(t, `[{"id":3,"name":"staging","environment_ids":null}]`, resultText(result))
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{
+			FilterParam: "environmentIds contains 3",
+		})
+		require.False(t, result.IsError, resultText(result))
+		assert.JSONEq(t, `[{"id":2,"name":"dev","environment_ids":[3]}]`, resultText(result))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{
+			FilterParam: "owner=admin",
+		})
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid filter parameter: unknown field owner, must be one of: environment_ids, id, name", resultText(result))
+	})
+}
+
+func TestListEnvironmentsQuery(t *testing.T) {
+	environments := []models.Environment{
+		{ID: 1, Name: "edge-1", Status: "inactive", Type: "docker-edge-agent", TagIds: []int{3}},
+		{ID: 2, Name: "edge-2", Status: "inactive", Type: "docker-edge-agent", TagIds: []int{3}},
+		{ID: 3, Name: "edge-3", Status: "inactive", Type: "docker-edge-agent", TagIds: []int{3}},
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		scope         EnvironmentScope
+		expectedQuery *models.EnvironmentQuery
+		returned      []models.Environment
+		expectedIDs   []int
+		expectNext    bool
+	}{
+		{
+			name: "filter and page pushed to Portainer",
+			args: map[string]any{
+				FilterParam: "status=inactive,type=docker-edge-agent,tagIds contains 3",
+				LimitParam:  float64(2),
+			},
+			expectedQuery: &models.EnvironmentQuery{
+				Statuses: []string{"inactive"},
+				Types:    []string{"docker-edge-agent"},
+				TagIDs:   []int{3},
+				Limit:    3,
+			},
+			returned:    environments,
+			expectedIDs: []int{1, 2},
+			expectNext:  true,
+		},
+		{
+			name: "sort keeps the pagination on the server",
+			args: map[string]any{
+				FilterParam: "status=inactive",
+				SortParam:   "-id",
+				LimitParam:  float64(2),
+			},
+			expectedQuery: &models.EnvironmentQuery{Statuses: []string{"inactive"}},
+			returned:      environments,
+			expectedIDs:   []int{3, 2},
+			expectNext:    true,
+		},
+		{
+			name: "scope keeps the pagination on the server",
+			args: map[string]any{
+				FilterParam: "status=inactive",
+				LimitParam:  float64(5),
+			},
+			scope:         EnvironmentScope{EnvironmentIDs: []int{1, 3}},
+			expectedQuery: &models.EnvironmentQuery{Statuses: []string{"inactive"}},
+			returned:      environments,
+			expectedIDs:   []int{1, 3},
+		},
+		{
+			name: "name searched by Portainer and filtered by the server",
+			args: map[string]any{
+				FilterParam: "name~EDGE-2",
+				LimitParam:  float64(1),
+			},
+			expectedQuery: &models.EnvironmentQuery{Search: "EDGE-2"},
+			returned:      environments,
+			expectedIDs:   []int{2},
+		},
+		{
+			name: "conditions Portainer does not support",
+			args: map[string]any{
+				FilterParam: "id!=2",
+			},
+			returned:    environments,
+			expectedIDs: []int{1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if tt.expectedQuery != nil {
+				mockClient.On("SearchEnvironments", *tt.expectedQuery).Return(tt.returned, nil)
+			} else {
+				mockClient.On("GetEnvironments").Return(tt.returned, nil)
+			}
+
+			s := &PortainerMCPServer{cli: mockClient, scope: tt.scope}
+			handler := listHandler(mcp.Tool{}, s.HandleGetEnvironments())
+
+			result, err := handler(context.Background(), CreateMCPRequest(tt.args))
+			require.NoError(t, err)
+			require.False(t, result.IsError, resultText(result))
+
+			content := result.StructuredContent.(map[string]any)
+			assert.Equal(t, tt.expectedIDs, itemIDs(content["items"].([]any)))
+			if tt.expectNext {
+				assert.NotEmpty(t, content[NextCursorProperty])
+			} else {
+				assert.NotContains(t, content, NextCursorProperty)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListEnvironmentsNextPage(t *testing.T) {
+	args := map[string]any{FilterParam: "status=active", LimitParam: float64(2)}
+	query, err := parseListQuery(CreateMCPRequest(args))
+	require.NoError(t, err)
+
+	mockClient := new(MockPortainerClient)
+	mockClient.On("SearchEnvironments", models.EnvironmentQuery{
+		Statuses: []string{"active"},
+		Start:    2,
+		Limit:    3,
+	}).Return([]models.Environment{{ID: 5, Status: "active"}}, nil)
+
+	s := &PortainerMCPServer{cli: mockClient}
+	handler := listHandler(mcp.Tool{}, s.HandleGetEnvironments())
+
+	args[CursorParam] = encodeCursor(2, query.key)
+	result, err := handler(context.Background(), CreateMCPRequest(args))
+	require.NoError(t, err)
+	require.False(t, result.IsError, resultText(result))
+
+	content := result.StructuredContent.(map[string]any)
+	assert.Equal(t, []int{5}, itemIDs(content["items"].([]any)))
+	assert.NotContains(t, content, NextCursorProperty)
+	mockClient.AssertExpectations(t)
+}
+
+// outputSchema returns the output schema of a tool
+func outputSchema(t *testing.T, tool mcp.Tool) map[string]any {
+	t.Helper()
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(tool.RawOutputSchema, &schema))
+	return schema
+}
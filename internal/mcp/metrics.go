@@ -0,0 +1,217 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// MetricsPath is the path of the Prometheus metrics endpoint
+	MetricsPath = "/metrics"
+
+	// metricsNamespace prefixes the name of every metric
+	metricsNamespace = "portainer_mcp"
+)
+
+// Results of a tool call, as reported in metrics
+const (
+	toolResultSuccess = "success"
+	toolResultError   = "error"
+)
+
+// Metrics collects the Prometheus metrics of the MCP server: the tool calls
+// and the Portainer API requests they make.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	toolCalls        *prometheus.CounterVec
+	toolDuration     *prometheus.HistogramVec
+	toolsInFlight    *prometheus.GaugeVec
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	requestsInFlight prometheus.Gauge
+	portainerInfo    *prometheus.GaugeVec
+}
+
+// NewMetrics creates the metrics of the MCP server in a dedicated registry,
+// along with the Go runtime and process metrics.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tool_calls_total",
+			Help:      "Number of tool calls, by tool and result.",
+		}, []string{"tool", "result"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Duration of tool calls, by tool and result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"tool", "result"}),
+		toolsInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "tool_calls_in_flight",
+			Help:      "Number of tool calls being handled, by tool.",
+		}, []string{"tool"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "portainer_requests_total",
+			Help:      "Number of Portainer API requests, by method, path and status code.",
+		}, []string{"method", "path", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "portainer_request_duration_seconds",
+			Help:      "Duration of Portainer API requests, by method, path and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "path", "status"}),
+		requestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "portainer_requests_in_flight",
+			Help:      "Number of Portainer API requests waiting for a response.",
+		}),
+		portainerInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "portainer_info",
+			Help:      "Version of the Portainer servers, by instance, always 1.",
+		}, []string{"instance", "version"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.toolCalls,
+		m.toolDuration,
+		m.toolsInFlight,
+		m.requests,
+		m.requestDuration,
+		m.requestsInFlight,
+		m.portainerInfo,
+	)
+
+	return m
+}
+
+// WithMetrics collects the metrics of the tool calls and of the Portainer API
+// requests they make. The metrics are served with ServeMetrics.
+func WithMetrics(metrics *Metrics) ServerOption {
+	return func(opts *serverOptions) {
+		opts.metrics = metrics
+	}
+}
+
+// Handler returns the HTTP handler exposing the metrics in the Prometheus format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// setPortainerVersion records the version of the Portainer server of an instance
+func (m *Metrics) setPortainerVersion(instance, version string) {
+	m.portainerInfo.DeletePartialMatch(prometheus.Labels{"instance": instance})
+	m.portainerInfo.WithLabelValues(instance, version).Set(1)
+}
+
+// observeRequest is the client.RequestObserver recording Portainer API requests
+func (m *Metrics) observeRequest(method, path string) func(statusCode int) {
+	start := time.Now()
+	m.requestsInFlight.Inc()
+
+	return func(statusCode int) {
+		m.requestsInFlight.Dec()
+
+		status := toolResultError
+		if statusCode != 0 {
+			status = strconv.Itoa(statusCode)
+		}
+
+		m.requests.WithLabelValues(method, path, status).Inc()
+		m.requestDuration.WithLabelValues(method, path, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// requestObserver returns the client option recording the Portainer API requests
+func (m *Metrics) requestObserver() client.ClientOption {
+	return client.WithRequestObserver(m.observeRequest)
+}
+
+// toolMiddleware records the number, duration and result of the tool calls
+func (m *Metrics) toolMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tool := request.Params.Name
+			start := time.Now()
+
+			inFlight := m.toolsInFlight.WithLabelValues(tool)
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			result, err := next(ctx, request)
+
+			outcome := toolResultSuccess
+			if err != nil || (result != nil && result.IsError) {
+				outcome = toolResultError
+			}
+
+			m.toolCalls.WithLabelValues(tool, outcome).Inc()
+			m.toolDuration.WithLabelValues(tool, outcome).Observe(time.Since(start).Seconds())
+
+			return result, err
+		}
+	}
+}
+
+// ServeMetrics serves the metrics on the given address at MetricsPath.
+// This is a blocking call that runs until the context is cancelled or the
+// listener fails. When the context is cancelled, the listener is shut down
+// gracefully and ServeMetrics returns nil.
+func ServeMetrics(ctx context.Context, listenAddr string, metrics *Metrics) error {
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, metrics.Handler())
+
+	httpSrv := &http.Server{
+		Addr:              listenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpSrv.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+
+	if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down metrics listener: %w", err)
+	}
+
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
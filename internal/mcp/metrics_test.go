@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsToolMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		result         *mcp.CallToolResult
+		err            error
+		expectedResult string
+	}{
+		{
+			name:           "successful call",
+			result:         mcp.NewToolResultText("ok"),
+			expectedResult: toolResultSuccess,
+		},
+		{
+			name:           "error result",
+			result:         mcp.NewToolResultError("failed to get tags"),
+			expectedResult: toolResultError,
+		},
+		{
+			name:           "handler error",
+			err:            errors.New("handler error"),
+			expectedResult: toolResultError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := NewMetrics()
+
+			handler := metrics.toolMiddleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				assert.Equal(t, float64(1), testutil.ToFloat64(metrics.toolsInFlight.WithLabelValues(ToolListEnvironmentTags)))
+				return tt.result, tt.err
+			})
+
+			request := CreateMCPRequest(nil)
+			request.Params.Name = ToolListEnvironmentTags
+
+			_, _ = handler(context.Background(), request)
+
+			assert.Equal(t, float64(1), testutil.ToFloat64(metrics.toolCalls.WithLabelValues(ToolListEnvironmentTags, tt.expectedResult)))
+			assert.Equal(t, 1, testutil.CollectAndCount(metrics.toolDuration))
+			assert.Equal(t, float64(0), testutil.ToFloat64(metrics.toolsInFlight.WithLabelValues(ToolListEnvironmentTags)))
+		})
+	}
+}
+
+func TestMetricsObserveRequest(t *testing.T) {
+	metrics := NewMetrics()
+
+	done := metrics.observeRequest(http.MethodGet, "/endpoints/{id}")
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requestsInFlight))
+	done(http.StatusOK)
+
+	metrics.observeRequest(http.MethodDelete, "/tags/{id}")(0)
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.requestsInFlight))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues(http.MethodGet, "/endpoints/{id}", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues(http.MethodDelete, "/tags/{id}", "error")))
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.requestDuration))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.setPortainerVersion("default", "2.30.0")
+	metrics.setPortainerVersion("default", "2.33.0")
+	metrics.setPortainerVersion("edge", "2.27.0")
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `portainer_mcp_portainer_info{instance="default",version="2.33.0"} 1`)
+	assert.Contains(t, string(body), `portainer_mcp_portainer_info{instance="edge",version="2.27.0"} 1`)
+	assert.NotContains(t, string(body), `version="2.30.0"`)
+	assert.Contains(t, string(body), "portainer_mcp_portainer_requests_in_flight 0")
+	assert.Contains(t, string(body), "go_goroutines")
+}
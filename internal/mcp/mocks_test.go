@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
@@ -11,13 +12,15 @@ import (
 // Mock Implementation Patterns:
 //
 // This file contains mock implementations of the PortainerClient interface.
+// Every method accepts the request context as its first parameter. The context
+// is not passed to m.Called(), so expectations are declared without it.
 // The following patterns are used throughout the mocks:
 //
 // 1. Methods returning (T, error):
 //    - Uses m.Called() to record the method call and get mock behavior
 //    - Includes nil check on first return value to avoid type assertion panics
 //    - Example:
-//      func (m *Mock) Method() (T, error) {
+//      func (m *Mock) Method(ctx context.Context) (T, error) {
 //          args := m.Called()
 //          if args.Get(0) == nil {
 //              return nil, args.Error(1)
@@ -29,7 +32,7 @@ import (
 //    - Uses m.Called() with any parameters
 //    - Returns only the error value
 //    - Example:
-//      func (m *Mock) Method(param string) error {
+//      func (m *Mock) Method(ctx context.Context, param string) error {
 //          args := m.Called(param)
 //          return args.Error(0)
 //      }
@@ -37,7 +40,7 @@ import (
 // Usage in Tests:
 //   mock := new(MockPortainerClient)
 //   mock.On("MethodName").Return(expectedValue, nil)
-//   result, err := mock.MethodName()
+//   result, err := mock.MethodName(context.Background())
 //   mock.AssertExpectations(t)
 
 // MockPortainerClient is a mock implementation of the PortainerClient interface
@@ -47,7 +50,7 @@ type MockPortainerClient struct {
 
 // Tag methods
 
-func (m *MockPortainerClient) GetEnvironmentTags() ([]models.EnvironmentTag, error) {
+func (m *MockPortainerClient) GetEnvironmentTags(ctx context.Context) ([]models.EnvironmentTag, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -55,19 +58,19 @@ func (m *MockPortainerClient) GetEnvironmentTags() ([]models.EnvironmentTag, err
 	return args.Get(0).([]models.EnvironmentTag), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateEnvironmentTag(name string) (int, error) {
+func (m *MockPortainerClient) CreateEnvironmentTag(ctx context.Context, name string) (int, error) {
 	args := m.Called(name)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteTag(id int) error {
+func (m *MockPortainerClient) DeleteTag(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Environment methods
 
-func (m *MockPortainerClient) GetEnvironments() ([]models.Environment, error) {
+func (m *MockPortainerClient) GetEnvironments(ctx context.Context) ([]models.Environment, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -75,27 +78,35 @@ func (m *MockPortainerClient) GetEnvironments() ([]models.Environment, error) {
 	return args.Get(0).([]models.Environment), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentTags(id int, tagIds []int) error {
+func (m *MockPortainerClient) SearchEnvironments(ctx context.Context, query models.EnvironmentQuery) ([]models.Environment, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Environment), args.Error(1)
+}
+
+func (m *MockPortainerClient) UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error {
 	args := m.Called(id, tagIds)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentUserAccesses(id int, userAccesses map[int]string) error {
+func (m *MockPortainerClient) UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
 	args := m.Called(id, userAccesses)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentTeamAccesses(id int, teamAccesses map[int]string) error {
+func (m *MockPortainerClient) UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
 	args := m.Called(id, teamAccesses)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironment(id int, name, publicURL string, groupID int) error {
+func (m *MockPortainerClient) UpdateEnvironment(ctx context.Context, id int, name, publicURL string, groupID int) error {
 	args := m.Called(id, name, publicURL, groupID)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) GetAgentVersions() ([]string, error) {
+func (m *MockPortainerClient) GetAgentVersions(ctx context.Context) ([]string, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -105,7 +116,7 @@ func (m *MockPortainerClient) GetAgentVersions() ([]string, error) {
 
 // Environment Group methods
 
-func (m *MockPortainerClient) GetEnvironmentGroups() ([]models.Group, error) {
+func (m *MockPortainerClient) GetEnvironmentGroups(ctx context.Context) ([]models.Group, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -113,34 +124,34 @@ func (m *MockPortainerClient) GetEnvironmentGroups() ([]models.Group, error) {
 	return args.Get(0).([]models.Group), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateEnvironmentGroup(name string, environmentIds []int) (int, error) {
+func (m *MockPortainerClient) CreateEnvironmentGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
 	args := m.Called(name, environmentIds)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentGroupName(id int, name string) error {
+func (m *MockPortainerClient) UpdateEnvironmentGroupName(ctx context.Context, id int, name string) error {
 	args := m.Called(id, name)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentGroupEnvironments(id int, environmentIds []int) error {
+func (m *MockPortainerClient) UpdateEnvironmentGroupEnvironments(ctx context.Context, id int, environmentIds []int) error {
 	args := m.Called(id, environmentIds)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateEnvironmentGroupTags(id int, tagIds []int) error {
+func (m *MockPortainerClient) UpdateEnvironmentGroupTags(ctx context.Context, id int, tagIds []int) error {
 	args := m.Called(id, tagIds)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteEnvironmentGroup(id int) error {
+func (m *MockPortainerClient) DeleteEnvironmentGroup(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Access Group methods
 
-func (m *MockPortainerClient) GetAccessGroups() ([]models.AccessGroup, error) {
+func (m *MockPortainerClient) GetAccessGroups(ctx context.Context) ([]models.AccessGroup, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -148,44 +159,44 @@ func (m *MockPortainerClient) GetAccessGroups() ([]models.AccessGroup, error) {
 	return args.Get(0).([]models.AccessGroup), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateAccessGroup(name string, environmentIds []int) (int, error) {
+func (m *MockPortainerClient) CreateAccessGroup(ctx context.Context, name string, environmentIds []int) (int, error) {
 	args := m.Called(name, environmentIds)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateAccessGroupName(id int, name string) error {
+func (m *MockPortainerClient) UpdateAccessGroupName(ctx context.Context, id int, name string) error {
 	args := m.Called(id, name)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateAccessGroupUserAccesses(id int, userAccesses map[int]string) error {
+func (m *MockPortainerClient) UpdateAccessGroupUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
 	args := m.Called(id, userAccesses)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateAccessGroupTeamAccesses(id int, teamAccesses map[int]string) error {
+func (m *MockPortainerClient) UpdateAccessGroupTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
 	args := m.Called(id, teamAccesses)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) AddEnvironmentToAccessGroup(id int, environmentId int) error {
+func (m *MockPortainerClient) AddEnvironmentToAccessGroup(ctx context.Context, id int, environmentId int) error {
 	args := m.Called(id, environmentId)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) RemoveEnvironmentFromAccessGroup(id int, environmentId int) error {
+func (m *MockPortainerClient) RemoveEnvironmentFromAccessGroup(ctx context.Context, id int, environmentId int) error {
 	args := m.Called(id, environmentId)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteAccessGroup(id int) error {
+func (m *MockPortainerClient) DeleteAccessGroup(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Stack methods
 
-func (m *MockPortainerClient) GetStacks() ([]models.Stack, error) {
+func (m *MockPortainerClient) GetStacks(ctx context.Context) ([]models.Stack, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -193,29 +204,29 @@ func (m *MockPortainerClient) GetStacks() ([]models.Stack, error) {
 	return args.Get(0).([]models.Stack), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetStackFile(id int) (string, error) {
+func (m *MockPortainerClient) GetStackFile(ctx context.Context, id int) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateStack(name string, file string, environmentGroupIds []int) (int, error) {
+func (m *MockPortainerClient) CreateStack(ctx context.Context, name string, file string, environmentGroupIds []int) (int, error) {
 	args := m.Called(name, file, environmentGroupIds)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateStack(id int, file string, environmentGroupIds []int) error {
+func (m *MockPortainerClient) UpdateStack(ctx context.Context, id int, file string, environmentGroupIds []int) error {
 	args := m.Called(id, file, environmentGroupIds)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteEdgeStack(id int) error {
+func (m *MockPortainerClient) DeleteEdgeStack(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Docker Stack methods
 
-func (m *MockPortainerClient) GetDockerStacks() ([]models.DockerStack, error) {
+func (m *MockPortainerClient) GetDockerStacks(ctx context.Context) ([]models.DockerStack, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -223,44 +234,44 @@ func (m *MockPortainerClient) GetDockerStacks() ([]models.DockerStack, error) {
 	return args.Get(0).([]models.DockerStack), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetDockerStackFile(id int) (string, error) {
+func (m *MockPortainerClient) GetDockerStackFile(ctx context.Context, id int) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateDockerStack(endpointID int, name, composeFileContent string, env []models.StackEnvVar) (int, error) {
+func (m *MockPortainerClient) CreateDockerStack(ctx context.Context, endpointID int, name, composeFileContent string, env []models.StackEnvVar) (int, error) {
 	args := m.Called(endpointID, name, composeFileContent, env)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateDockerStack(id, endpointID int, composeFileContent string, env []models.StackEnvVar, prune, pullImage bool) error {
+func (m *MockPortainerClient) UpdateDockerStack(ctx context.Context, id, endpointID int, composeFileContent string, env []models.StackEnvVar, prune, pullImage bool) error {
 	args := m.Called(id, endpointID, composeFileContent, env, prune, pullImage)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteDockerStack(id, endpointID int) error {
+func (m *MockPortainerClient) DeleteDockerStack(ctx context.Context, id, endpointID int) error {
 	args := m.Called(id, endpointID)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) StartDockerStack(id, endpointID int) error {
+func (m *MockPortainerClient) StartDockerStack(ctx context.Context, id, endpointID int) error {
 	args := m.Called(id, endpointID)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) StopDockerStack(id, endpointID int) error {
+func (m *MockPortainerClient) StopDockerStack(ctx context.Context, id, endpointID int) error {
 	args := m.Called(id, endpointID)
 	return args.Error(0)
 }
 
 // Team methods
 
-func (m *MockPortainerClient) CreateTeam(name string) (int, error) {
+func (m *MockPortainerClient) CreateTeam(ctx context.Context, name string) (int, error) {
 	args := m.Called(name)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetTeams() ([]models.Team, error) {
+func (m *MockPortainerClient) GetTeams(ctx context.Context) ([]models.Team, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -268,24 +279,24 @@ func (m *MockPortainerClient) GetTeams() ([]models.Team, error) {
 	return args.Get(0).([]models.Team), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateTeamName(id int, name string) error {
+func (m *MockPortainerClient) UpdateTeamName(ctx context.Context, id int, name string) error {
 	args := m.Called(id, name)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) UpdateTeamMembers(id int, userIds []int) error {
+func (m *MockPortainerClient) UpdateTeamMembers(ctx context.Context, id int, userIds []int) error {
 	args := m.Called(id, userIds)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteTeam(id int) error {
+func (m *MockPortainerClient) DeleteTeam(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // User methods
 
-func (m *MockPortainerClient) GetUsers() ([]models.User, error) {
+func (m *MockPortainerClient) GetUsers(ctx context.Context) ([]models.User, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -293,14 +304,14 @@ func (m *MockPortainerClient) GetUsers() ([]models.User, error) {
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateUserRole(id int, role string) error {
+func (m *MockPortainerClient) UpdateUserRole(ctx context.Context, id int, role string) error {
 	args := m.Called(id, role)
 	return args.Error(0)
 }
 
 // Settings methods
 
-func (m *MockPortainerClient) GetSettings() (models.PortainerSettings, error) {
+func (m *MockPortainerClient) GetSettings(ctx context.Context) (models.PortainerSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.PortainerSettings{}, args.Error(1)
@@ -308,12 +319,12 @@ func (m *MockPortainerClient) GetSettings() (models.PortainerSettings, error) {
 	return args.Get(0).(models.PortainerSettings), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateSettings(settingsJSON string) error {
+func (m *MockPortainerClient) UpdateSettings(ctx context.Context, settingsJSON string) error {
 	args := m.Called(settingsJSON)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) GetVersion() (string, error) {
+func (m *MockPortainerClient) GetVersion(ctx context.Context) (string, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return "", args.Error(1)
@@ -321,9 +332,14 @@ func (m *MockPortainerClient) GetVersion() (string, error) {
 	return args.Get(0).(string), args.Error(1)
 }
 
+func (m *MockPortainerClient) GetEdition(ctx context.Context) (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
 // Registry methods
 
-func (m *MockPortainerClient) GetRegistries() ([]models.Registry, error) {
+func (m *MockPortainerClient) GetRegistries(ctx context.Context) ([]models.Registry, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -331,17 +347,17 @@ func (m *MockPortainerClient) GetRegistries() ([]models.Registry, error) {
 	return args.Get(0).([]models.Registry), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateRegistry(req models.RegistryCreateRequest) (int, error) {
+func (m *MockPortainerClient) CreateRegistry(ctx context.Context, req models.RegistryCreateRequest) (int, error) {
 	args := m.Called(req)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteRegistry(id int) error {
+func (m *MockPortainerClient) DeleteRegistry(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) PingRegistry(req models.RegistryPingRequest) (models.RegistryPingResponse, error) {
+func (m *MockPortainerClient) PingRegistry(ctx context.Context, req models.RegistryPingRequest) (models.RegistryPingResponse, error) {
 	args := m.Called(req)
 	if args.Get(0) == nil {
 		return models.RegistryPingResponse{}, args.Error(1)
@@ -351,7 +367,7 @@ func (m *MockPortainerClient) PingRegistry(req models.RegistryPingRequest) (mode
 
 // Edge Job methods
 
-func (m *MockPortainerClient) GetEdgeJobs() ([]models.EdgeJob, error) {
+func (m *MockPortainerClient) GetEdgeJobs(ctx context.Context) ([]models.EdgeJob, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -359,7 +375,7 @@ func (m *MockPortainerClient) GetEdgeJobs() ([]models.EdgeJob, error) {
 	return args.Get(0).([]models.EdgeJob), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetEdgeJob(id int) (models.EdgeJob, error) {
+func (m *MockPortainerClient) GetEdgeJob(ctx context.Context, id int) (models.EdgeJob, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.EdgeJob{}, args.Error(1)
@@ -367,19 +383,19 @@ func (m *MockPortainerClient) GetEdgeJob(id int) (models.EdgeJob, error) {
 	return args.Get(0).(models.EdgeJob), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateEdgeJob(req models.EdgeJobCreateRequest) (int, error) {
+func (m *MockPortainerClient) CreateEdgeJob(ctx context.Context, req models.EdgeJobCreateRequest) (int, error) {
 	args := m.Called(req)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteEdgeJob(id int) error {
+func (m *MockPortainerClient) DeleteEdgeJob(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Custom Template methods
 
-func (m *MockPortainerClient) GetCustomTemplates() ([]models.CustomTemplate, error) {
+func (m *MockPortainerClient) GetCustomTemplates(ctx context.Context) ([]models.CustomTemplate, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -387,19 +403,19 @@ func (m *MockPortainerClient) GetCustomTemplates() ([]models.CustomTemplate, err
 	return args.Get(0).([]models.CustomTemplate), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateCustomTemplate(req models.CustomTemplateCreateRequest) (int, error) {
+func (m *MockPortainerClient) CreateCustomTemplate(ctx context.Context, req models.CustomTemplateCreateRequest) (int, error) {
 	args := m.Called(req)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteCustomTemplate(id int) error {
+func (m *MockPortainerClient) DeleteCustomTemplate(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Webhook methods
 
-func (m *MockPortainerClient) GetWebhooks() ([]models.Webhook, error) {
+func (m *MockPortainerClient) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -407,19 +423,19 @@ func (m *MockPortainerClient) GetWebhooks() ([]models.Webhook, error) {
 	return args.Get(0).([]models.Webhook), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateWebhook(req models.WebhookCreateRequest) (int, error) {
+func (m *MockPortainerClient) CreateWebhook(ctx context.Context, req models.WebhookCreateRequest) (int, error) {
 	args := m.Called(req)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteWebhook(id int) error {
+func (m *MockPortainerClient) DeleteWebhook(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Git Credential methods
 
-func (m *MockPortainerClient) GetGitCredentials() ([]models.GitCredential, error) {
+func (m *MockPortainerClient) GetGitCredentials(ctx context.Context) ([]models.GitCredential, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -427,7 +443,7 @@ func (m *MockPortainerClient) GetGitCredentials() ([]models.GitCredential, error
 	return args.Get(0).([]models.GitCredential), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetGitCredential(id int) (models.GitCredential, error) {
+func (m *MockPortainerClient) GetGitCredential(ctx context.Context, id int) (models.GitCredential, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.GitCredential{}, args.Error(1)
@@ -435,24 +451,24 @@ func (m *MockPortainerClient) GetGitCredential(id int) (models.GitCredential, er
 	return args.Get(0).(models.GitCredential), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateGitCredential(req models.GitCredentialCreateRequest) (int, error) {
+func (m *MockPortainerClient) CreateGitCredential(ctx context.Context, req models.GitCredentialCreateRequest) (int, error) {
 	args := m.Called(req)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateGitCredential(id int, req models.GitCredentialUpdateRequest) error {
+func (m *MockPortainerClient) UpdateGitCredential(ctx context.Context, id int, req models.GitCredentialUpdateRequest) error {
 	args := m.Called(id, req)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteGitCredential(id int) error {
+func (m *MockPortainerClient) DeleteGitCredential(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Alerting methods
 
-func (m *MockPortainerClient) GetAlerts(status string) (json.RawMessage, error) {
+func (m *MockPortainerClient) GetAlerts(ctx context.Context, status string) (json.RawMessage, error) {
 	args := m.Called(status)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -460,7 +476,7 @@ func (m *MockPortainerClient) GetAlerts(status string) (json.RawMessage, error)
 	return args.Get(0).(json.RawMessage), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetAlertRules() ([]models.AlertingRule, error) {
+func (m *MockPortainerClient) GetAlertRules(ctx context.Context) ([]models.AlertingRule, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -468,7 +484,7 @@ func (m *MockPortainerClient) GetAlertRules() ([]models.AlertingRule, error) {
 	return args.Get(0).([]models.AlertingRule), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetAlertRule(id int) (models.AlertingRule, error) {
+func (m *MockPortainerClient) GetAlertRule(ctx context.Context, id int) (models.AlertingRule, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.AlertingRule{}, args.Error(1)
@@ -476,17 +492,17 @@ func (m *MockPortainerClient) GetAlertRule(id int) (models.AlertingRule, error)
 	return args.Get(0).(models.AlertingRule), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdateAlertRule(id int, ruleJSON string) error {
+func (m *MockPortainerClient) UpdateAlertRule(ctx context.Context, id int, ruleJSON string) error {
 	args := m.Called(id, ruleJSON)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteAlertRule(id int) error {
+func (m *MockPortainerClient) DeleteAlertRule(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) GetAlertingSettings() ([]models.AlertingSettings, error) {
+func (m *MockPortainerClient) GetAlertingSettings(ctx context.Context) ([]models.AlertingSettings, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -494,19 +510,19 @@ func (m *MockPortainerClient) GetAlertingSettings() ([]models.AlertingSettings,
 	return args.Get(0).([]models.AlertingSettings), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreateAlertSilence(silenceJSON string, alertManagerURL string) error {
+func (m *MockPortainerClient) CreateAlertSilence(ctx context.Context, silenceJSON string, alertManagerURL string) error {
 	args := m.Called(silenceJSON, alertManagerURL)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeleteAlertSilence(id string) error {
+func (m *MockPortainerClient) DeleteAlertSilence(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 // Policy methods
 
-func (m *MockPortainerClient) GetPolicies() ([]models.Policy, error) {
+func (m *MockPortainerClient) GetPolicies(ctx context.Context) ([]models.Policy, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -514,7 +530,7 @@ func (m *MockPortainerClient) GetPolicies() ([]models.Policy, error) {
 	return args.Get(0).([]models.Policy), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetPolicy(id int) (models.Policy, error) {
+func (m *MockPortainerClient) GetPolicy(ctx context.Context, id int) (models.Policy, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.Policy{}, args.Error(1)
@@ -522,22 +538,22 @@ func (m *MockPortainerClient) GetPolicy(id int) (models.Policy, error) {
 	return args.Get(0).(models.Policy), args.Error(1)
 }
 
-func (m *MockPortainerClient) CreatePolicy(req models.PolicyCreateRequest) (int, error) {
+func (m *MockPortainerClient) CreatePolicy(ctx context.Context, req models.PolicyCreateRequest) (int, error) {
 	args := m.Called(req)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockPortainerClient) UpdatePolicy(id int, req models.PolicyUpdateRequest) error {
+func (m *MockPortainerClient) UpdatePolicy(ctx context.Context, id int, req models.PolicyUpdateRequest) error {
 	args := m.Called(id, req)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) DeletePolicy(id int) error {
+func (m *MockPortainerClient) DeletePolicy(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) GetPolicyTemplates(category, policyType string) ([]models.PolicyTemplate, error) {
+func (m *MockPortainerClient) GetPolicyTemplates(ctx context.Context, category, policyType string) ([]models.PolicyTemplate, error) {
 	args := m.Called(category, policyType)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -545,7 +561,7 @@ func (m *MockPortainerClient) GetPolicyTemplates(category, policyType string) ([
 	return args.Get(0).([]models.PolicyTemplate), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetPolicyTemplate(id string) (models.PolicyTemplate, error) {
+func (m *MockPortainerClient) GetPolicyTemplate(ctx context.Context, id string) (models.PolicyTemplate, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return models.PolicyTemplate{}, args.Error(1)
@@ -553,7 +569,7 @@ func (m *MockPortainerClient) GetPolicyTemplate(id string) (models.PolicyTemplat
 	return args.Get(0).(models.PolicyTemplate), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetPolicyMetadata() (models.PolicyMetadata, error) {
+func (m *MockPortainerClient) GetPolicyMetadata(ctx context.Context) (models.PolicyMetadata, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return models.PolicyMetadata{}, args.Error(1)
@@ -561,7 +577,7 @@ func (m *MockPortainerClient) GetPolicyMetadata() (models.PolicyMetadata, error)
 	return args.Get(0).(models.PolicyMetadata), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetPolicyConflicts(req models.PolicyConflictsRequest) (models.PolicyConflictsResponse, error) {
+func (m *MockPortainerClient) GetPolicyConflicts(ctx context.Context, req models.PolicyConflictsRequest) (models.PolicyConflictsResponse, error) {
 	args := m.Called(req)
 	if args.Get(0) == nil {
 		return models.PolicyConflictsResponse{}, args.Error(1)
@@ -571,7 +587,7 @@ func (m *MockPortainerClient) GetPolicyConflicts(req models.PolicyConflictsReque
 
 // Kubernetes Custom Resource methods
 
-func (m *MockPortainerClient) ListCustomResourceDefinitions(environmentID int) ([]models.CustomResourceDefinition, error) {
+func (m *MockPortainerClient) ListCustomResourceDefinitions(ctx context.Context, environmentID int) ([]models.CustomResourceDefinition, error) {
 	args := m.Called(environmentID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -579,7 +595,7 @@ func (m *MockPortainerClient) ListCustomResourceDefinitions(environmentID int) (
 	return args.Get(0).([]models.CustomResourceDefinition), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetCustomResourceDefinition(environmentID int, name string) (models.CustomResourceDefinition, error) {
+func (m *MockPortainerClient) GetCustomResourceDefinition(ctx context.Context, environmentID int, name string) (models.CustomResourceDefinition, error) {
 	args := m.Called(environmentID, name)
 	if args.Get(0) == nil {
 		return models.CustomResourceDefinition{}, args.Error(1)
@@ -587,12 +603,12 @@ func (m *MockPortainerClient) GetCustomResourceDefinition(environmentID int, nam
 	return args.Get(0).(models.CustomResourceDefinition), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteCustomResourceDefinition(environmentID int, name string) error {
+func (m *MockPortainerClient) DeleteCustomResourceDefinition(ctx context.Context, environmentID int, name string) error {
 	args := m.Called(environmentID, name)
 	return args.Error(0)
 }
 
-func (m *MockPortainerClient) ListCustomResources(environmentID int, definition string) ([]models.CustomResource, error) {
+func (m *MockPortainerClient) ListCustomResources(ctx context.Context, environmentID int, definition string) ([]models.CustomResource, error) {
 	args := m.Called(environmentID, definition)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -600,7 +616,7 @@ func (m *MockPortainerClient) ListCustomResources(environmentID int, definition
 	return args.Get(0).([]models.CustomResource), args.Error(1)
 }
 
-func (m *MockPortainerClient) GetCustomResource(environmentID int, namespace, name, definition, format string) (json.RawMessage, error) {
+func (m *MockPortainerClient) GetCustomResource(ctx context.Context, environmentID int, namespace, name, definition, format string) (json.RawMessage, error) {
 	args := m.Called(environmentID, namespace, name, definition, format)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -608,13 +624,13 @@ func (m *MockPortainerClient) GetCustomResource(environmentID int, namespace, na
 	return args.Get(0).(json.RawMessage), args.Error(1)
 }
 
-func (m *MockPortainerClient) DeleteCustomResource(environmentID int, namespace, name, definition string) error {
+func (m *MockPortainerClient) DeleteCustomResource(ctx context.Context, environmentID int, namespace, name, definition string) error {
 	args := m.Called(environmentID, namespace, name, definition)
 	return args.Error(0)
 }
 
 // Docker Proxy methods
-func (m *MockPortainerClient) ProxyDockerRequest(opts models.DockerProxyRequestOptions) (*http.Response, error) {
+func (m *MockPortainerClient) ProxyDockerRequest(ctx context.Context, opts models.DockerProxyRequestOptions) (*http.Response, error) {
 	args := m.Called(opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -623,7 +639,7 @@ func (m *MockPortainerClient) ProxyDockerRequest(opts models.DockerProxyRequestO
 }
 
 // Kubernetes Proxy methods
-func (m *MockPortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
+func (m *MockPortainerClient) ProxyKubernetesRequest(ctx context.Context, opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
 	args := m.Called(opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// Arguments of the tools returning structured content
+const (
+	// OutputFormatParam is the tool argument selecting the format of the result text
+	OutputFormatParam = "outputFormat"
+	// ColumnsParam is the tool argument selecting the fields shown by the table
+	// and CSV formats
+	ColumnsParam = "columns"
+)
+
+// toolOutputTypes maps the tools returning structured content to the Go type
+// of their output
+var toolOutputTypes = map[string]reflect.Type{
+	ToolListAccessGroups:              reflect.TypeFor[[]models.AccessGroup](),
+	ToolListAlertRules:                reflect.TypeFor[[]models.AlertingRule](),
+	ToolGetAlertRule:                  reflect.TypeFor[models.AlertingRule](),
+	ToolGetAlertingSettings:           reflect.TypeFor[[]models.AlertingSettings](),
+	ToolListCustomResourceDefinitions: reflect.TypeFor[[]models.CustomResourceDefinition](),
+	ToolGetCustomResourceDefinition:   reflect.TypeFor[models.CustomResourceDefinition](),
+	ToolListCustomResources:           reflect.TypeFor[[]models.CustomResource](),
+	ToolListCustomTemplates:           reflect.TypeFor[[]models.CustomTemplate](),
+	ToolListDockerStacks:              reflect.TypeFor[[]models.DockerStack](),
+	ToolListEdgeJobs:                  reflect.TypeFor[[]models.EdgeJob](),
+	ToolGetEdgeJob:                    reflect.TypeFor[models.EdgeJob](),
+	ToolListEnvironments:              reflect.TypeFor[[]models.Environment](),
+	ToolListAgentVersions:             reflect.TypeFor[[]string](),
+	ToolListGitCredentials:            reflect.TypeFor[[]models.GitCredential](),
+	ToolGetGitCredential:              reflect.TypeFor[models.GitCredential](),
+	ToolListEnvironmentGroups:         reflect.TypeFor[[]models.Group](),
+	ToolListInstances:                 reflect.TypeFor[[]InstanceStatus](),
+	ToolListPolicies:                  reflect.TypeFor[[]models.Policy](),
+	ToolGetPolicy:                     reflect.TypeFor[models.Policy](),
+	ToolListPolicyTemplates:           reflect.TypeFor[[]models.PolicyTemplate](),
+	ToolGetPolicyTemplate:             reflect.TypeFor[models.PolicyTemplate](),
+	ToolGetPolicyMetadata:             reflect.TypeFor[models.PolicyMetadata](),
+	ToolGetPolicyConflicts:            reflect.TypeFor[models.PolicyConflictsResponse](),
+	ToolListRegistries:                reflect.TypeFor[[]models.Registry](),
+	ToolTestRegistryConnection:        reflect.TypeFor[models.RegistryPingResponse](),
+	ToolGetSettings:                   reflect.TypeFor[models.PortainerSettings](),
+	ToolListStacks:                    reflect.TypeFor[[]models.Stack](),
+	ToolListEnvironmentTags:           reflect.TypeFor[[]models.EnvironmentTag](),
+	ToolListTeams:                     reflect.TypeFor[[]models.Team](),
+	ToolListUsers:                     reflect.TypeFor[[]models.User](),
+	ToolListWebhooks:                  reflect.TypeFor[[]models.Webhook](),
+}
+
+// WithOutputFormat sets the default format of the text of the results of the
+// read tools returning structured content, one of toolgen.OutputFormats. The
+// tools accept an outputFormat argument overriding it, and a columns argument
+// selecting the fields shown by the table and CSV formats.
+func WithOutputFormat(format string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.outputFormat = format
+	}
+}
+
+// formatTool reports whether a tool accepts the output format arguments: the
+// read tools returning structured content
+func formatTool(tool mcp.Tool) bool {
+	_, ok := toolOutputTypes[tool.Name]
+	return ok && isReadOnlyTool(tool)
+}
+
+// withOutputFormatParams returns a copy of the tool accepting the output
+// format arguments. The columns argument is only accepted by the tools
+// returning structs.
+func withOutputFormatParams(tool mcp.Tool, defaultFormat string) mcp.Tool {
+	tool = withToolProperty(tool, OutputFormatParam, map[string]any{
+		"type":        "string",
+		"enum":        toolgen.OutputFormats,
+		"description": fmt.Sprintf("The format of the result text: json, yaml, table for a Markdown table, or csv. Defaults to %s. The structured content is not affected.", defaultFormat),
+	})
+
+	outputType := toolOutputTypes[tool.Name]
+	if len(toolgen.OutputFields(outputType)) > 0 {
+		tool = withToolProperty(tool, ColumnsParam, map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Comma-separated fields shown by the table and csv formats, in this order. Defaults to %s.", strings.Join(toolgen.DefaultColumns(outputType), ",")),
+		})
+	}
+
+	return tool
+}
+
+// outputFormatHandler returns a handler writing the text of the results of a
+// handler in the format selected by the outputFormat argument, or in the
+// default format of the server. The text is made from the structured content
+// of the result, which is left as is.
+func outputFormatHandler(tool mcp.Tool, defaultFormat string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	outputType := toolOutputTypes[tool.Name]
+	fields := toolgen.OutputFields(outputType)
+	defaultColumns := toolgen.DefaultColumns(outputType)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParser(request)
+
+		format, err := parser.GetString(OutputFormatParam, false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid outputFormat parameter", err), nil
+		}
+		if format == "" {
+			format = defaultFormat
+		}
+		if !toolgen.IsValidOutputFormat(format) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid outputFormat parameter: unknown format %s, must be one of: %s", format, strings.Join(toolgen.OutputFormats, ", "))), nil
+		}
+
+		rawColumns, err := parser.GetString(ColumnsParam, false)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid columns parameter", err), nil
+		}
+		columns, err := parseColumns(rawColumns, fields, defaultColumns)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid columns parameter", err), nil
+		}
+
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError || result.StructuredContent == nil || format == toolgen.OutputFormatJSON {
+			return result, err
+		}
+
+		text, err := toolgen.FormatOutput(typedContent(result.StructuredContent, outputType), format, columns)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to format the result", err), nil
+		}
+
+		result.Content = []mcp.Content{mcp.NewTextContent(text)}
+		return result, nil
+	}
+}
+
+// typedContent returns the structured content of a result with its items
+// decoded into the Go output type of the tool, so that their fields are
+// formatted in the order of the struct. The list arguments leave the items
+// decoded from JSON.
+func typedContent(content any, outputType reflect.Type) any {
+	object, ok := content.(map[string]any)
+	if !ok {
+		return content
+	}
+	items, ok := object[toolgen.StructuredItemsProperty]
+	if !ok {
+		return content
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return content
+	}
+	typed := reflect.New(outputType)
+	if err := json.Unmarshal(data, typed.Interface()); err != nil {
+		return content
+	}
+
+	typedObject := maps.Clone(object)
+	typedObject[toolgen.StructuredItemsProperty] = typed.Elem().Interface()
+	return typedObject
+}
+
+// parseColumns returns the fields named by the columns argument, their case,
+// underscores and dashes being ignored as in the list arguments, or the
+// default columns when the argument is empty
+func parseColumns(value string, fields, defaultColumns []string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return defaultColumns, nil
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("the result has no fields")
+	}
+
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+
+		index := slices.IndexFunc(fields, func(field string) bool {
+			return normalizeFieldName(field) == normalizeFieldName(column)
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("unknown field %s, must be one of: %s", column, strings.Join(fields, ", "))
+		}
+		columns = append(columns, fields[index])
+	}
+
+	return columns, nil
+}
+
+// defaultOutputFormat returns the default format of the text of the results
+func (s *PortainerMCPServer) defaultOutputFormat() string {
+	if s.outputFormat == "" {
+		return toolgen.OutputFormatJSON
+	}
+	return s.outputFormat
+}
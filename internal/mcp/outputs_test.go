@@ -0,0 +1,202 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestToolOutputSchemas checks that the output schema declared by each tool
+// matches the Go type of its output
+func TestToolOutputSchemas(t *testing.T) {
+	config, err := tooldef.EmbeddedTools()
+	require.NoError(t, err)
+
+	for _, def := range config.Tools {
+		outputType, ok := toolOutputTypes[def.Name]
+		if !ok {
+			assert.Nil(t, def.OutputSchema, "tool %s declares an output schema but has no output type", def.Name)
+			continue
+		}
+
+		t.Run(def.Name, func(t *testing.T) {
+			require.NotNil(t, def.OutputSchema, "tool %s has an output type but declares no output schema", def.Name)
+
+			expected := toolgen.OutputSchemaFor(outputType)
+			if !assert.Equal(t, normalizeSchema(t, expected), normalizeSchema(t, def.OutputSchema)) {
+				data, err := yaml.Marshal(map[string]any{"outputSchema": expected})
+				require.NoError(t, err)
+				t.Logf("expected output schema of tool %s:\n%s", def.Name, data)
+			}
+
+			// A populated and an empty output match the schema
+			for _, value := range []reflect.Value{sampleValue(outputType), reflect.Zero(outputType)} {
+				content := toolgen.StructuredContent(value.Interface())
+				assert.NoError(t, toolgen.ValidateOutput(def.OutputSchema, content))
+			}
+		})
+	}
+}
+
+func TestStructuredResult(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	tags := []models.EnvironmentTag{{ID: 1, Name: "prod"}}
+	mockClient.On("GetEnvironmentTags").Return(tags, nil)
+
+	s := &PortainerMCPServer{cli: mockClient}
+
+	result, err := s.HandleGetEnvironmentTags()(context.Background(), CreateMCPRequest(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"items": tags}, result.StructuredContent)
+	assert.JSONEq(t, `[{"id":1,"name":"prod","environment_ids":null}]`, resultText(result))
+}
+
+func TestOutputFormat(t *testing.T) {
+	config, err := tooldef.EmbeddedTools()
+	require.NoError(t, err)
+	tools, _ := toolgen.ConvertToolsConfig(config)
+
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetEnvironmentTags").Return([]models.EnvironmentTag{
+		{ID: 1, Name: "prod", EnvironmentIds: []int{1, 2}},
+		{ID: 2, Name: "dev"},
+	}, nil)
+
+	newServer := func(outputFormat string) *PortainerMCPServer {
+		s := &PortainerMCPServer{
+			srv:          server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+			cli:          mockClient,
+			tools:        tools,
+			outputFormat: outputFormat,
+		}
+		s.AddTagFeatures()
+		return s
+	}
+
+	s := newServer("")
+	ctx := context.Background()
+
+	tool := s.srv.GetTool(ToolListEnvironmentTags)
+	require.NotNil(t, tool)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, OutputFormatParam)
+	assert.Contains(t, tool.Tool.InputSchema.Properties[ColumnsParam].(map[string]any)["description"], "Defaults to id,name,environment_ids.")
+	assert.NotContains(t, s.srv.GetTool(ToolCreateEnvironmentTag).Tool.InputSchema.Properties, OutputFormatParam)
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		expected string
+	}{
+		{
+			name:     "default format",
+			args:     map[string]any{},
+			expected: `[{"id":1,"name":"prod","environment_ids":[1,2]},{"id":2,"name":"dev","environment_ids":null}]`,
+		},
+		{
+			name:     "yaml",
+			args:     map[string]any{OutputFormatParam: "yaml", LimitParam: float64(1)},
+			expected: "items:\n  - id: 1\n    name: prod\n    environment_ids:\n      - 1\n      - 2\nnextCursor: ",
+		},
+		{
+			name:     "table",
+			args:     map[string]any{OutputFormatParam: "table"},
+			expected: "| id | name | environment_ids |\n| --- | --- | --- |\n| 1 | prod | 1, 2 |\n| 2 | dev |  |\n",
+		},
+		{
+			name:     "csv with columns",
+			args:     map[string]any{OutputFormatParam: "csv", ColumnsParam: "Name, environmentIds", SortParam: "name"},
+			expected: "name,environment_ids\ndev,\nprod,\"1, 2\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, ctx, ToolListEnvironmentTags, tt.args)
+			require.False(t, result.IsError, resultText(result))
+			assert.Contains(t, resultText(result), tt.expected)
+			assert.NotNil(t, result.StructuredContent)
+		})
+	}
+
+	t.Run("server default", func(t *testing.T) {
+		result := callTool(t, newServer(toolgen.OutputFormatCSV), ctx, ToolListEnvironmentTags, map[string]any{})
+		require.False(t, result.IsError, resultText(result))
+		assert.Equal(t, "id,name,environment_ids\n1,prod,\"1, 2\"\n2,dev,\n", resultText(result))
+
+		result = callTool(t, newServer(toolgen.OutputFormatCSV), ctx, ToolListEnvironmentTags, map[string]any{OutputFormatParam: "json"})
+		require.False(t, result.IsError, resultText(result))
+		assert.True(t, json.Valid([]byte(resultText(result))))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{OutputFormatParam: "xml"})
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid outputFormat parameter: unknown format xml, must be one of: json, yaml, table, csv", resultText(result))
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		result := callTool(t, s, ctx, ToolListEnvironmentTags, map[string]any{OutputFormatParam: "table", ColumnsParam: "id,owner"})
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid columns parameter: unknown field owner, must be one of: id, name, environment_ids", resultText(result))
+	})
+}
+
+// normalizeSchema returns a schema as decoded from JSON, so that schemas
+// built in Go and decoded from YAML can be compared
+func normalizeSchema(t *testing.T, schema map[string]any) map[string]any {
+	t.Helper()
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	var normalized map[string]any
+	require.NoError(t, json.Unmarshal(data, &normalized))
+	return normalized
+}
+
+// sampleValue returns a value of a type with every field, slice and map populated
+func sampleValue(t reflect.Type) reflect.Value {
+	value := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		value.Set(sampleValue(t.Elem()).Addr())
+	case reflect.String:
+		value.SetString("sample")
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(1)
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(1.5)
+	case reflect.Slice:
+		if t == reflect.TypeFor[json.RawMessage]() {
+			value.SetBytes([]byte(`{"sample":true}`))
+			break
+		}
+		value.Set(reflect.Append(value, sampleValue(t.Elem())))
+	case reflect.Map:
+		value.Set(reflect.MakeMap(t))
+		value.SetMapIndex(sampleValue(t.Key()), sampleValue(t.Elem()))
+	case reflect.Interface:
+		value.Set(reflect.ValueOf("sample"))
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				value.Field(i).Set(sampleValue(t.Field(i).Type))
+			}
+		}
+	}
+
+	return value
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 // AddPolicyFeatures registers all fleetwide policy related tools.
@@ -30,31 +29,39 @@ func (s *PortainerMCPServer) AddPolicyFeatures() {
 // HandleListPolicies returns a handler that lists all fleetwide policies.
 func (s *PortainerMCPServer) HandleListPolicies() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		policies, err := s.cli.GetPolicies()
+		policies, err := s.client(ctx).GetPolicies(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get policies", err), nil
 		}
 
+		inScope, err := s.scopedEnvironmentIDs(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		scopedGroups, err := scopedEnvironmentGroupIDs(ctx, s.client(ctx), inScope)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		policies = filterScoped(scopedGroups, policies, func(p models.Policy) []int { return p.EnvironmentGroups })
+
 		data, err := json.Marshal(policies)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal policies", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(policies, data), nil
 	}
 }
 
 // HandleGetPolicy returns a handler that retrieves a specific policy.
 func (s *PortainerMCPServer) HandleGetPolicy() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseGetPolicyArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		policy, err := s.cli.GetPolicy(id)
+		policy, err := s.client(ctx).GetPolicy(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get policy", err), nil
 		}
@@ -64,52 +71,30 @@ func (s *PortainerMCPServer) HandleGetPolicy() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal policy", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(policy, data), nil
 	}
 }
 
 // HandleCreatePolicy returns a handler that creates a new fleetwide policy.
 func (s *PortainerMCPServer) HandleCreatePolicy() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseCreatePolicyArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		policyType, err := parser.GetString("type", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
-		}
-
-		environmentType, err := parser.GetString("environmentType", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentType parameter", err), nil
-		}
-
-		environmentGroups, err := parser.GetArrayOfIntegers("environmentGroups", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentGroups parameter", err), nil
-		}
-
-		dataJSON, err := parser.GetString("dataJSON", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid dataJSON parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.PolicyCreateRequest{
-			Name:              name,
-			Type:              policyType,
-			EnvironmentType:   environmentType,
-			EnvironmentGroups: environmentGroups,
+			Name:              args.Name,
+			Type:              args.Type,
+			EnvironmentType:   args.EnvironmentType,
+			EnvironmentGroups: args.EnvironmentGroups,
 		}
 
-		if dataJSON != "" {
-			req.Data = json.RawMessage(dataJSON)
+		if args.DataJSON != "" {
+			req.Data = json.RawMessage(args.DataJSON)
 		}
 
-		id, err := s.cli.CreatePolicy(req)
+		id, err := s.client(ctx).CreatePolicy(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create policy", err), nil
 		}
@@ -121,69 +106,40 @@ func (s *PortainerMCPServer) HandleCreatePolicy() server.ToolHandlerFunc {
 // HandleUpdatePolicy returns a handler that updates an existing fleetwide policy.
 func (s *PortainerMCPServer) HandleUpdatePolicy() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		policyType, err := parser.GetString("type", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
-		}
-
-		environmentType, err := parser.GetString("environmentType", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentType parameter", err), nil
-		}
-
-		environmentGroups, err := parser.GetArrayOfIntegers("environmentGroups", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentGroups parameter", err), nil
-		}
-
-		dataJSON, err := parser.GetString("dataJSON", false)
+		args, err := ParseUpdatePolicyArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid dataJSON parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.PolicyUpdateRequest{
-			Name:              name,
-			Type:              policyType,
-			EnvironmentType:   environmentType,
-			EnvironmentGroups: environmentGroups,
+			Name:              args.Name,
+			Type:              args.Type,
+			EnvironmentType:   args.EnvironmentType,
+			EnvironmentGroups: args.EnvironmentGroups,
 		}
 
-		if dataJSON != "" {
-			req.Data = json.RawMessage(dataJSON)
+		if args.DataJSON != "" {
+			req.Data = json.RawMessage(args.DataJSON)
 		}
 
-		err = s.cli.UpdatePolicy(id, req)
+		err = s.client(ctx).UpdatePolicy(ctx, args.ID, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update policy", err), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Policy %d updated successfully", id)), nil
+		return mcp.NewToolResultText(fmt.Sprintf("Policy %d updated successfully", args.ID)), nil
 	}
 }
 
 // HandleDeletePolicy returns a handler that deletes a fleetwide policy.
 func (s *PortainerMCPServer) HandleDeletePolicy() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeletePolicyArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeletePolicy(id)
+		err = s.client(ctx).DeletePolicy(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete policy", err), nil
 		}
@@ -195,19 +151,12 @@ func (s *PortainerMCPServer) HandleDeletePolicy() server.ToolHandlerFunc {
 // HandleListPolicyTemplates returns a handler that lists policy templates.
 func (s *PortainerMCPServer) HandleListPolicyTemplates() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		category, err := parser.GetString("category", false)
+		args, err := ParseListPolicyTemplatesArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid category parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		policyType, err := parser.GetString("type", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
-		}
-
-		templates, err := s.cli.GetPolicyTemplates(category, policyType)
+		templates, err := s.client(ctx).GetPolicyTemplates(ctx, args.Category, args.Type)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get policy templates", err), nil
 		}
@@ -217,21 +166,19 @@ func (s *PortainerMCPServer) HandleListPolicyTemplates() server.ToolHandlerFunc
 			return mcp.NewToolResultErrorFromErr("failed to marshal policy templates", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(templates, data), nil
 	}
 }
 
 // HandleGetPolicyTemplate returns a handler that retrieves a specific policy template.
 func (s *PortainerMCPServer) HandleGetPolicyTemplate() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetString("id", true)
+		args, err := ParseGetPolicyTemplateArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		template, err := s.cli.GetPolicyTemplate(id)
+		template, err := s.client(ctx).GetPolicyTemplate(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get policy template", err), nil
 		}
@@ -241,14 +188,14 @@ func (s *PortainerMCPServer) HandleGetPolicyTemplate() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal policy template", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(template, data), nil
 	}
 }
 
 // HandleGetPolicyMetadata returns a handler that retrieves policy metadata.
 func (s *PortainerMCPServer) HandleGetPolicyMetadata() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		metadata, err := s.cli.GetPolicyMetadata()
+		metadata, err := s.client(ctx).GetPolicyMetadata(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get policy metadata", err), nil
 		}
@@ -258,52 +205,30 @@ func (s *PortainerMCPServer) HandleGetPolicyMetadata() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal policy metadata", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(metadata, data), nil
 	}
 }
 
 // HandleGetPolicyConflicts returns a handler that checks for policy conflicts.
 func (s *PortainerMCPServer) HandleGetPolicyConflicts() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		policyType, err := parser.GetString("type", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
-		}
-
-		environmentType, err := parser.GetString("environmentType", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentType parameter", err), nil
-		}
-
-		environmentGroups, err := parser.GetArrayOfIntegers("environmentGroups", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentGroups parameter", err), nil
-		}
-
-		dataJSON, err := parser.GetString("dataJSON", false)
+		args, err := ParseGetPolicyConflictsArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid dataJSON parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.PolicyConflictsRequest{
-			Name:              name,
-			Type:              policyType,
-			EnvironmentType:   environmentType,
-			EnvironmentGroups: environmentGroups,
+			Name:              args.Name,
+			Type:              args.Type,
+			EnvironmentType:   args.EnvironmentType,
+			EnvironmentGroups: args.EnvironmentGroups,
 		}
 
-		if dataJSON != "" {
-			req.Data = json.RawMessage(dataJSON)
+		if args.DataJSON != "" {
+			req.Data = json.RawMessage(args.DataJSON)
 		}
 
-		conflicts, err := s.cli.GetPolicyConflicts(req)
+		conflicts, err := s.client(ctx).GetPolicyConflicts(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get policy conflicts", err), nil
 		}
@@ -313,6 +238,6 @@ func (s *PortainerMCPServer) HandleGetPolicyConflicts() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal policy conflicts", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(conflicts, data), nil
 	}
 }
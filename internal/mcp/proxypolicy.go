@@ -0,0 +1,429 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Proxy APIs a proxy policy applies to
+const (
+	proxyAPIDocker     = "docker"
+	proxyAPIKubernetes = "kubernetes"
+)
+
+// dockerAPIVersionPrefix matches the optional API version prefix of Docker API paths
+var dockerAPIVersionPrefix = regexp.MustCompile(`^/v[0-9]+(\.[0-9]+)*(/|$)`)
+
+// ProxyPolicy restricts the requests sent by the Docker and Kubernetes proxy tools.
+//
+// Requests are matched by method, path and query parameters. Headers are not
+// inspected. A request matching a deny rule is
+// rejected. When allow rules are defined, a request must also match one of
+// them. Body rules inspect the JSON body of the matching requests and reject
+// the ones with a forbidden field value, for instance privileged containers.
+//
+// The rules defined for an environment apply in addition to the global rules.
+type ProxyPolicy struct {
+	Docker       ProxyRules                    `yaml:"docker"`
+	Kubernetes   ProxyRules                    `yaml:"kubernetes"`
+	Environments map[int]EnvironmentProxyRules `yaml:"environments"`
+}
+
+// EnvironmentProxyRules are the proxy rules of a single environment
+type EnvironmentProxyRules struct {
+	Docker     ProxyRules `yaml:"docker"`
+	Kubernetes ProxyRules `yaml:"kubernetes"`
+}
+
+// ProxyRules are the rules applied to the requests sent to one proxy API
+type ProxyRules struct {
+	Allow []ProxyRequestRule `yaml:"allow"`
+	Deny  []ProxyRequestRule `yaml:"deny"`
+	Body  []ProxyBodyRule    `yaml:"body"`
+}
+
+// ProxyRequestRule matches requests by method, path and query parameters.
+// An empty list of methods matches every method and an empty path matches every path.
+//
+// Query maps query parameter names to regular expressions: a request matches
+// when each of these parameters is set to a value matching its expression.
+type ProxyRequestRule struct {
+	Methods []string          `yaml:"methods"`
+	Path    string            `yaml:"path"`
+	Query   map[string]string `yaml:"query"`
+	Reason  string            `yaml:"reason"`
+
+	path  *regexp.Regexp
+	query map[string]*regexp.Regexp
+}
+
+// ProxyBodyRule rejects the requests matching its methods and path whose JSON
+// body has a forbidden value at the given field.
+//
+// The field is a dot-separated path in the body, such as HostConfig.Privileged.
+// Arrays are traversed, so HostConfig.Mounts.Type designates the type of every
+// mount. A value is forbidden when it is equal to Equals, or when it is a
+// string matching the Matches regular expression.
+type ProxyBodyRule struct {
+	Methods []string `yaml:"methods"`
+	Path    string   `yaml:"path"`
+	Field   string   `yaml:"field"`
+	Equals  any      `yaml:"equals"`
+	Matches string   `yaml:"matches"`
+	Reason  string   `yaml:"reason"`
+
+	path    *regexp.Regexp
+	matches *regexp.Regexp
+	equals  any
+}
+
+// LoadProxyPolicy loads a proxy policy from a YAML file.
+//
+// Parameters:
+//   - path: The path of a YAML file with the docker, kubernetes and environments rules
+//
+// Returns:
+//   - The proxy policy defined in the file, ready to be enforced
+//   - An error if the file cannot be read or parsed, or if a rule is invalid
+func LoadProxyPolicy(path string) (*ProxyPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy policy file: %w", err)
+	}
+
+	var policy ProxyPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse proxy policy file: %w", err)
+	}
+
+	if err := policy.compile(); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// WithProxyPolicy restricts the requests sent by the Docker and Kubernetes proxy tools.
+// The policy must have been loaded with LoadProxyPolicy.
+func WithProxyPolicy(policy *ProxyPolicy) ServerOption {
+	return func(opts *serverOptions) {
+		opts.proxyPolicy = policy
+	}
+}
+
+// compile validates the rules of the policy and compiles their regular expressions
+func (p *ProxyPolicy) compile() error {
+	if err := p.Docker.compile(proxyAPIDocker); err != nil {
+		return err
+	}
+	if err := p.Kubernetes.compile(proxyAPIKubernetes); err != nil {
+		return err
+	}
+
+	for id, rules := range p.Environments {
+		if err := rules.Docker.compile(fmt.Sprintf("environment %d %s", id, proxyAPIDocker)); err != nil {
+			return err
+		}
+		if err := rules.Kubernetes.compile(fmt.Sprintf("environment %d %s", id, proxyAPIKubernetes)); err != nil {
+			return err
+		}
+		p.Environments[id] = rules
+	}
+
+	return nil
+}
+
+func (r *ProxyRules) compile(section string) error {
+	for i := range r.Allow {
+		if err := r.Allow[i].compile(); err != nil {
+			return fmt.Errorf("invalid %s allow rule %d: %w", section, i+1, err)
+		}
+	}
+
+	for i := range r.Deny {
+		if err := r.Deny[i].compile(); err != nil {
+			return fmt.Errorf("invalid %s deny rule %d: %w", section, i+1, err)
+		}
+	}
+
+	for i := range r.Body {
+		if err := r.Body[i].compile(); err != nil {
+			return fmt.Errorf("invalid %s body rule %d: %w", section, i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ProxyRequestRule) compile() error {
+	var err error
+	r.Methods, r.path, err = compileRequestMatcher(r.Methods, r.Path)
+	if err != nil {
+		return err
+	}
+
+	r.query = make(map[string]*regexp.Regexp, len(r.Query))
+	for name, pattern := range r.Query {
+		if r.query[name], err = regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid query regular expression of %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// matches checks if a request matches the rule
+func (r *ProxyRequestRule) matches(method, apiPath string, query map[string]string) bool {
+	if !requestMatches(r.Methods, r.path, method, apiPath) {
+		return false
+	}
+
+	for name, re := range r.query {
+		value, ok := query[name]
+		if !ok || !re.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *ProxyBodyRule) compile() error {
+	var err error
+	r.Methods, r.path, err = compileRequestMatcher(r.Methods, r.Path)
+	if err != nil {
+		return err
+	}
+
+	if r.Field == "" {
+		return fmt.Errorf("field is required")
+	}
+
+	if (r.Equals == nil) == (r.Matches == "") {
+		return fmt.Errorf("exactly one of equals and matches is required")
+	}
+
+	if r.Matches != "" {
+		if r.matches, err = regexp.Compile(r.Matches); err != nil {
+			return fmt.Errorf("invalid matches regular expression: %w", err)
+		}
+	}
+
+	if r.Equals != nil {
+		// Normalize the YAML value to the types of decoded JSON bodies, so that
+		// numbers compare equal whatever their YAML representation
+		if r.equals, err = normalizeJSON(r.Equals); err != nil {
+			return fmt.Errorf("invalid equals value: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// compileRequestMatcher validates the methods of a rule and compiles its path regular expression
+func compileRequestMatcher(methods []string, pattern string) ([]string, *regexp.Regexp, error) {
+	normalized := make([]string, 0, len(methods))
+	for _, method := range methods {
+		method = strings.ToUpper(method)
+		if !isValidHTTPMethod(method) {
+			return nil, nil, fmt.Errorf("invalid method: %s", method)
+		}
+		normalized = append(normalized, method)
+	}
+
+	if pattern == "" {
+		return normalized, nil, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid path regular expression: %w", err)
+	}
+
+	return normalized, re, nil
+}
+
+// requestMatches checks if a request matches the given methods and path regular expression
+func requestMatches(methods []string, re *regexp.Regexp, method, apiPath string) bool {
+	if len(methods) > 0 && !slices.Contains(methods, method) {
+		return false
+	}
+	return re == nil || re.MatchString(apiPath)
+}
+
+// check verifies that a proxy request complies with the policy.
+// A nil policy allows every request.
+//
+// Parameters:
+//   - api: The proxied API, docker or kubernetes
+//   - environmentID: The environment the request is sent to
+//   - method: The HTTP method of the request
+//   - apiPath: The path of the request in the proxied API, without query string
+//   - query: The query parameters of the request
+//   - body: The body of the request, possibly empty
+//
+// Returns:
+//   - An error explaining why the request is rejected, nil when it is allowed
+func (p *ProxyPolicy) check(api string, environmentID int, method, apiPath string, query map[string]string, body string) error {
+	if p == nil {
+		return nil
+	}
+
+	rules := []ProxyRules{proxyRulesOf(api, p.Docker, p.Kubernetes)}
+	if environment, ok := p.Environments[environmentID]; ok {
+		rules = append(rules, proxyRulesOf(api, environment.Docker, environment.Kubernetes))
+	}
+
+	request := fmt.Sprintf("%s %s on environment %d", method, apiPath, environmentID)
+	normalizedPath, err := normalizeProxyPath(api, apiPath)
+	if err != nil {
+		return proxyPolicyError(request, err.Error(), "")
+	}
+
+	hasAllowRules := false
+	allowed := false
+	for _, r := range rules {
+		for _, rule := range r.Deny {
+			if rule.matches(method, normalizedPath, query) {
+				return proxyPolicyError(request, "is denied", rule.Reason)
+			}
+		}
+
+		for _, rule := range r.Allow {
+			hasAllowRules = true
+			if rule.matches(method, normalizedPath, query) {
+				allowed = true
+			}
+		}
+	}
+
+	if hasAllowRules && !allowed {
+		return proxyPolicyError(request, "does not match any allow rule", "")
+	}
+
+	for _, r := range rules {
+		for _, rule := range r.Body {
+			if !requestMatches(rule.Methods, rule.path, method, normalizedPath) {
+				continue
+			}
+
+			if err := rule.check(body); err != nil {
+				return proxyPolicyError(request, err.Error(), rule.Reason)
+			}
+		}
+	}
+
+	return nil
+}
+
+// proxyRulesOf returns the rules of the given proxy API
+func proxyRulesOf(api string, docker, kubernetes ProxyRules) ProxyRules {
+	if api == proxyAPIDocker {
+		return docker
+	}
+	return kubernetes
+}
+
+// check inspects a request body, returning an error when it holds a forbidden value
+func (r *ProxyBodyRule) check(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return nil
+	}
+
+	var document any
+	if err := json.Unmarshal([]byte(body), &document); err != nil {
+		return fmt.Errorf("has a body that is not valid JSON and cannot be inspected")
+	}
+
+	for _, value := range fieldValues(document, strings.Split(r.Field, ".")) {
+		if r.matches != nil {
+			if s, ok := value.(string); ok && r.matches.MatchString(s) {
+				return fmt.Errorf("sets %s to %q", r.Field, s)
+			}
+			continue
+		}
+
+		if reflect.DeepEqual(value, r.equals) {
+			return fmt.Errorf("sets %s to %v", r.Field, value)
+		}
+	}
+
+	return nil
+}
+
+// fieldValues collects the values found at a field path in a decoded JSON document.
+// Arrays are traversed, both along the path and at its end. Keys are matched
+// case-insensitively, as the Docker API decodes them, and every matching key
+// is followed.
+func fieldValues(value any, fieldPath []string) []any {
+	if items, ok := value.([]any); ok {
+		var values []any
+		for _, item := range items {
+			values = append(values, fieldValues(item, fieldPath)...)
+		}
+		return values
+	}
+
+	if len(fieldPath) == 0 {
+		return []any{value}
+	}
+
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var values []any
+	for key, field := range object {
+		if field != nil && strings.EqualFold(key, fieldPath[0]) {
+			values = append(values, fieldValues(field, fieldPath[1:])...)
+		}
+	}
+	return values
+}
+
+// normalizeProxyPath returns the path matched by the policy rules. The path is
+// decoded and cleaned, as the proxied APIs see it, so that rules cannot be
+// bypassed with escaped characters or dot segments, and the optional API
+// version prefix of Docker paths is removed.
+func normalizeProxyPath(api, apiPath string) (string, error) {
+	// The query string is forwarded as is, so it cannot hide in the path
+	if strings.Contains(apiPath, "?") {
+		return "", fmt.Errorf("has a query string in its path")
+	}
+
+	decoded, err := url.PathUnescape(apiPath)
+	if err != nil {
+		return "", fmt.Errorf("has an invalid escape sequence in its path")
+	}
+	// A path still escaped once decoded could be decoded again on its way to
+	// the proxied API, and match a different rule
+	if strings.Contains(decoded, "%") {
+		return "", fmt.Errorf("has a doubly escaped path")
+	}
+	apiPath = path.Clean(decoded)
+
+	if api == proxyAPIDocker {
+		if loc := dockerAPIVersionPrefix.FindStringIndex(apiPath); loc != nil {
+			apiPath = "/" + apiPath[loc[1]:]
+		}
+	}
+
+	return apiPath, nil
+}
+
+// proxyPolicyError builds the explanation of a rejected proxy request
+func proxyPolicyError(request, violation, reason string) error {
+	if reason != "" {
+		return fmt.Errorf("request rejected by proxy policy: %s %s: %s", request, violation, reason)
+	}
+	return fmt.Errorf("request rejected by proxy policy: %s %s", request, violation)
+}
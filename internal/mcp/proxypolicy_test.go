@@ -0,0 +1,374 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProxyPolicy = `
+docker:
+  deny:
+    - methods: [DELETE]
+      path: ^/volumes/
+      reason: volumes hold persistent data
+    - methods: [POST]
+      path: ^/images/create$
+      query:
+        fromImage: ^docker\.io/
+      reason: images must come from the private registry
+  body:
+    - methods: [POST]
+      path: ^/containers/create$
+      field: HostConfig.Privileged
+      equals: true
+      reason: privileged containers are not allowed
+    - methods: [POST]
+      path: ^/containers/create$
+      field: HostConfig.Binds
+      matches: ^/
+      reason: host bind mounts are not allowed
+    - methods: [POST]
+      path: ^/containers/create$
+      field: HostConfig.Mounts.Type
+      equals: bind
+kubernetes:
+  allow:
+    - methods: [GET]
+environments:
+  2:
+    kubernetes:
+      allow:
+        - methods: [POST, PUT]
+          path: ^/api/v1/namespaces/staging/
+`
+
+func loadTestProxyPolicy(t *testing.T, content string) (*ProxyPolicy, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "proxy-policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	return LoadProxyPolicy(path)
+}
+
+func TestLoadProxyPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{
+			name:    "valid policy",
+			content: testProxyPolicy,
+		},
+		{
+			name:          "invalid path regular expression",
+			content:       "docker:\n  deny:\n    - path: ^/containers/(\n",
+			errorContains: "invalid docker deny rule 1: invalid path regular expression",
+		},
+		{
+			name:          "invalid query regular expression",
+			content:       "docker:\n  allow:\n    - query:\n        fromImage: ^(\n",
+			errorContains: "invalid docker allow rule 1: invalid query regular expression of fromImage",
+		},
+		{
+			name:          "invalid method",
+			content:       "kubernetes:\n  allow:\n    - methods: [PATCH]\n",
+			errorContains: "invalid kubernetes allow rule 1: invalid method: PATCH",
+		},
+		{
+			name:          "body rule without field",
+			content:       "docker:\n  body:\n    - equals: true\n",
+			errorContains: "invalid docker body rule 1: field is required",
+		},
+		{
+			name:          "body rule with equals and matches",
+			content:       "docker:\n  body:\n    - field: Image\n      equals: nginx\n      matches: ^nginx\n",
+			errorContains: "exactly one of equals and matches is required",
+		},
+		{
+			name:          "invalid environment rule",
+			content:       "environments:\n  3:\n    docker:\n      allow:\n        - path: \"[\"\n",
+			errorContains: "invalid environment 3 docker allow rule 1",
+		},
+		{
+			name:          "invalid YAML",
+			content:       "docker: [",
+			errorContains: "failed to parse proxy policy file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := loadTestProxyPolicy(t, tt.content)
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, policy)
+		})
+	}
+
+	_, err := LoadProxyPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read proxy policy file")
+}
+
+func TestProxyPolicyCheck(t *testing.T) {
+	policy, err := loadTestProxyPolicy(t, testProxyPolicy)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		api           string
+		environmentID int
+		method        string
+		path          string
+		query         map[string]string
+		body          string
+		errorContains string
+	}{
+		{
+			name:   "request matching no rule",
+			api:    proxyAPIDocker,
+			method: "GET",
+			path:   "/containers/json",
+		},
+		{
+			name:          "denied request",
+			api:           proxyAPIDocker,
+			method:        "DELETE",
+			path:          "/volumes/data",
+			errorContains: "request rejected by proxy policy: DELETE /volumes/data on environment 1 is denied: volumes hold persistent data",
+		},
+		{
+			name:          "denied request with API version prefix",
+			api:           proxyAPIDocker,
+			method:        "DELETE",
+			path:          "/v1.45/volumes/data",
+			errorContains: "is denied",
+		},
+		{
+			name:          "denied request with dot segments",
+			api:           proxyAPIDocker,
+			method:        "DELETE",
+			path:          "/containers/../volumes/data",
+			errorContains: "is denied",
+		},
+		{
+			name:          "denied request by query parameter",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/images/create",
+			query:         map[string]string{"fromImage": "docker.io/library/nginx", "tag": "latest"},
+			errorContains: "POST /images/create on environment 1 is denied: images must come from the private registry",
+		},
+		{
+			name:   "request with a query parameter not matching a deny rule",
+			api:    proxyAPIDocker,
+			method: "POST",
+			path:   "/images/create",
+			query:  map[string]string{"fromImage": "registry.example.com/nginx"},
+		},
+		{
+			name:          "query string in the path",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/images/create?fromImage=docker.io/library/nginx",
+			errorContains: "has a query string in its path",
+		},
+		{
+			name:          "denied request with an escaped character",
+			api:           proxyAPIDocker,
+			method:        "DELETE",
+			path:          "/volumes/dat%61",
+			errorContains: "is denied",
+		},
+		{
+			name:          "denied request with an escaped slash",
+			api:           proxyAPIDocker,
+			method:        "DELETE",
+			path:          "/volumes%2Fdata",
+			errorContains: "is denied",
+		},
+		{
+			name:          "doubly escaped path",
+			api:           proxyAPIDocker,
+			method:        "DELETE",
+			path:          "/volumes%252Fdata",
+			errorContains: "has a doubly escaped path",
+		},
+		{
+			name:          "invalid escape sequence",
+			api:           proxyAPIDocker,
+			method:        "GET",
+			path:          "/containers/%zz",
+			errorContains: "has an invalid escape sequence in its path",
+		},
+		{
+			name:          "privileged container create with an escaped path",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/containers/creat%65",
+			body:          `{"Image":"nginx","HostConfig":{"Privileged":true}}`,
+			errorContains: "sets HostConfig.Privileged to true",
+		},
+		{
+			name:          "Kubernetes write with an escaped path not allowed on an environment",
+			api:           proxyAPIKubernetes,
+			environmentID: 2,
+			method:        "POST",
+			path:          "/api/v1/namespaces/staging/..%2Fproduction/pods",
+			errorContains: "does not match any allow rule",
+		},
+		{
+			name:   "container create",
+			api:    proxyAPIDocker,
+			method: "POST",
+			path:   "/containers/create",
+			body:   `{"Image":"nginx","HostConfig":{"Privileged":false,"Binds":["data:/data"]}}`,
+		},
+		{
+			name:          "privileged container create",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/containers/create",
+			body:          `{"Image":"nginx","HostConfig":{"Privileged":true}}`,
+			errorContains: "POST /containers/create on environment 1 sets HostConfig.Privileged to true: privileged containers are not allowed",
+		},
+		{
+			name:          "privileged container create with lowercase keys",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/containers/create",
+			body:          `{"image":"nginx","hostconfig":{"privileged":true}}`,
+			errorContains: "sets HostConfig.Privileged to true",
+		},
+		{
+			name:          "privileged container create with keys differing in case",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/containers/create",
+			body:          `{"Image":"nginx","HostConfig":{"Privileged":false},"hostConfig":{"PRIVILEGED":true}}`,
+			errorContains: "sets HostConfig.Privileged to true",
+		},
+		{
+			name:          "host bind mount",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/containers/create",
+			body:          `{"Image":"nginx","HostConfig":{"Binds":["data:/data","/etc:/host-etc"]}}`,
+			errorContains: `sets HostConfig.Binds to "/etc:/host-etc": host bind mounts are not allowed`,
+		},
+		{
+			name:          "bind mount in an array of objects",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/containers/create",
+			body:          `{"Image":"nginx","HostConfig":{"Mounts":[{"Type":"volume"},{"Type":"bind"}]}}`,
+			errorContains: "sets HostConfig.Mounts.Type to bind",
+		},
+		{
+			name:          "body that is not JSON",
+			api:           proxyAPIDocker,
+			method:        "POST",
+			path:          "/containers/create",
+			body:          `Image: nginx`,
+			errorContains: "not valid JSON",
+		},
+		{
+			name:   "allowed Kubernetes read",
+			api:    proxyAPIKubernetes,
+			method: "GET",
+			path:   "/api/v1/pods",
+		},
+		{
+			name:          "Kubernetes write not allowed",
+			api:           proxyAPIKubernetes,
+			method:        "POST",
+			path:          "/api/v1/namespaces/staging/pods",
+			errorContains: "POST /api/v1/namespaces/staging/pods on environment 1 does not match any allow rule",
+		},
+		{
+			name:          "Kubernetes write allowed on an environment",
+			api:           proxyAPIKubernetes,
+			environmentID: 2,
+			method:        "POST",
+			path:          "/api/v1/namespaces/staging/pods",
+		},
+		{
+			name:          "Kubernetes write outside the namespace allowed on an environment",
+			api:           proxyAPIKubernetes,
+			environmentID: 2,
+			method:        "DELETE",
+			path:          "/api/v1/namespaces/production/pods/web",
+			errorContains: "does not match any allow rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			environmentID := tt.environmentID
+			if environmentID == 0 {
+				environmentID = 1
+			}
+
+			err := policy.check(tt.api, environmentID, tt.method, tt.path, tt.query, tt.body)
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProxyPolicyNilAllowsEverything(t *testing.T) {
+	var policy *ProxyPolicy
+	assert.NoError(t, policy.check(proxyAPIDocker, 1, "DELETE", "/volumes/data", nil, ""))
+}
+
+func TestProxyHandlersEnforcePolicy(t *testing.T) {
+	policy, err := loadTestProxyPolicy(t, testProxyPolicy)
+	require.NoError(t, err)
+
+	mockClient := new(MockPortainerClient)
+	s := &PortainerMCPServer{cli: mockClient, proxyPolicy: policy}
+
+	result, err := s.HandleDockerProxy()(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"method":        "POST",
+		"dockerAPIPath": "/containers/create",
+		"body":          `{"Image":"nginx","HostConfig":{"Privileged":true}}`,
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "privileged containers are not allowed")
+
+	result, err = s.HandleDockerProxy()(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId": float64(1),
+		"method":        "POST",
+		"dockerAPIPath": "/images/create",
+		"queryParams":   []any{map[string]any{"key": "fromImage", "value": "docker.io/library/nginx"}},
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "images must come from the private registry")
+
+	result, err = s.HandleKubernetesProxy()(context.Background(), CreateMCPRequest(map[string]any{
+		"environmentId":     float64(1),
+		"method":            "DELETE",
+		"kubernetesAPIPath": "/api/v1/namespaces/default/pods/web",
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "does not match any allow rule")
+
+	// Rejected requests are never sent
+	assert.Empty(t, mockClient.Calls)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddRegistryFeatures() {
@@ -23,7 +22,7 @@ func (s *PortainerMCPServer) AddRegistryFeatures() {
 
 func (s *PortainerMCPServer) HandleListRegistries() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		registries, err := s.cli.GetRegistries()
+		registries, err := s.client(ctx).GetRegistries(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get registries", err), nil
 		}
@@ -33,54 +32,27 @@ func (s *PortainerMCPServer) HandleListRegistries() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal registries", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(registries, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleCreateRegistry() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
-		}
-
-		registryType, err := parser.GetInt("type", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
-		}
-
-		url, err := parser.GetString("url", true)
+		args, err := ParseCreateRegistryArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid url parameter", err), nil
-		}
-
-		authentication, err := parser.GetBoolean("authentication", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid authentication parameter", err), nil
-		}
-
-		username, err := parser.GetString("username", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid username parameter", err), nil
-		}
-
-		password, err := parser.GetString("password", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.RegistryCreateRequest{
-			Name:           name,
-			Type:           registryType,
-			URL:            url,
-			Authentication: authentication,
-			Username:       username,
-			Password:       password,
+			Name:           args.Name,
+			Type:           args.Type,
+			URL:            args.URL,
+			Authentication: args.Authentication,
+			Username:       args.Username,
+			Password:       args.Password,
 		}
 
-		id, err := s.cli.CreateRegistry(req)
+		id, err := s.client(ctx).CreateRegistry(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create registry", err), nil
 		}
@@ -91,14 +63,12 @@ func (s *PortainerMCPServer) HandleCreateRegistry() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleDeleteRegistry() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteRegistryArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteRegistry(id)
+		err = s.client(ctx).DeleteRegistry(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete registry", err), nil
 		}
@@ -110,36 +80,19 @@ func (s *PortainerMCPServer) HandleDeleteRegistry() server.ToolHandlerFunc {
 // HandleTestRegistryConnection returns a handler that tests a registry connection.
 func (s *PortainerMCPServer) HandleTestRegistryConnection() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		url, err := parser.GetString("url", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid url parameter", err), nil
-		}
-
-		registryType, err := parser.GetInt("type", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid type parameter", err), nil
-		}
-
-		username, err := parser.GetString("username", false)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid username parameter", err), nil
-		}
-
-		password, err := parser.GetString("password", false)
+		args, err := ParseTestRegistryConnectionArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid password parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.RegistryPingRequest{
-			URL:      url,
-			Type:     registryType,
-			Username: username,
-			Password: password,
+			URL:      args.URL,
+			Type:     args.Type,
+			Username: args.Username,
+			Password: args.Password,
 		}
 
-		result, err := s.cli.PingRegistry(req)
+		result, err := s.client(ctx).PingRegistry(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to test registry connection", err), nil
 		}
@@ -149,6 +102,6 @@ func (s *PortainerMCPServer) HandleTestRegistryConnection() server.ToolHandlerFu
 			return mcp.NewToolResultErrorFromErr("failed to marshal ping result", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(result, data), nil
 	}
 }
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+)
+
+// DefaultToolsWatchInterval is the default interval at which WatchTools checks the tools file
+const DefaultToolsWatchInterval = 2 * time.Second
+
+// toolHandler is the handler added by a feature for a tool, before it is
+// wrapped according to the tool definition
+type toolHandler struct {
+	name    string
+	handler server.ToolHandlerFunc
+}
+
+// ReloadTools loads the tools file again and registers the tools it defines
+// in place of the current ones, with the handlers added by the features.
+// Connected clients are notified that the tool list changed.
+//
+// The new file is validated like at startup: its version must be at least
+// MinimumToolsVersion and the tool filter patterns must still match. When it
+// is invalid, or the edition of the Portainer server required by its tools
+// cannot be fetched, the current tools are kept and an error is returned.
+//
+// Parameters:
+//   - toolsPath: Path to the tools.yaml file
+//
+// Returns:
+//   - An error if the tools file cannot be loaded or is invalid
+func (s *PortainerMCPServer) ReloadTools(toolsPath string) error {
+	tools, requirements, err := tooldef.LoadTools(toolsPath, MinimumToolsVersion)
+	if err != nil {
+		return fmt.Errorf("failed to load tools: %w", err)
+	}
+
+	if err := s.toolFilter.validate(slices.Collect(maps.Keys(tools))); err != nil {
+		return err
+	}
+
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+
+	if err := s.fetchPortainerEdition(requirements); err != nil {
+		return err
+	}
+
+	s.tools = tools
+	s.requirements = requirements
+	s.toolStatuses = nil
+
+	serverTools := make([]server.ServerTool, 0, len(s.handlers))
+	for _, h := range s.handlers {
+		if serverTool, ok := s.prepareTool(h.name, h.handler); ok {
+			serverTools = append(serverTools, serverTool)
+		}
+	}
+
+	s.srv.SetTools(serverTools...)
+
+	return nil
+}
+
+// WatchTools reloads the tools file with ReloadTools whenever its content
+// changes, checking it at the given interval. This is a blocking call that
+// runs until the context is cancelled.
+//
+// Parameters:
+//   - ctx: The context stopping the watch when cancelled
+//   - toolsPath: Path to the tools.yaml file
+//   - interval: The interval at which the file is checked
+//   - onReload: Called after every reload with its error, nil on success
+func (s *PortainerMCPServer) WatchTools(ctx context.Context, toolsPath string, interval time.Duration, onReload func(error)) {
+	// The file is read before the first tick, so that the tools loaded at
+	// startup are not reloaded
+	lastSum, _ := fileSum(toolsPath)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// A missing or unreadable file is usually being replaced by an
+		// editor, the next tick picks up the new file
+		sum, err := fileSum(toolsPath)
+		if err != nil || sum == lastSum {
+			continue
+		}
+		lastSum = sum
+
+		onReload(s.ReloadTools(toolsPath))
+	}
+}
+
+// fileSum returns the SHA-256 checksum of the content of a file
+func fileSum(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadTestToolsV1 = `version: v1.0
+tools:
+  - name: listEnvironmentTags
+    description: List the tags
+    annotations:
+      title: List Environment Tags
+      readOnlyHint: true
+      destructiveHint: false
+      idempotentHint: true
+      openWorldHint: false
+`
+
+const reloadTestToolsV2 = `version: v1.0
+tools:
+  - name: listEnvironmentTags
+    description: List every environment tag, prefer this tool to find tag IDs
+    annotations:
+      title: List Environment Tags
+      readOnlyHint: true
+      destructiveHint: false
+      idempotentHint: true
+      openWorldHint: false
+  - name: createEnvironmentTag
+    description: Create an environment tag
+    parameters:
+      - name: name
+        type: string
+        description: The name of the tag
+        required: true
+    annotations:
+      title: Create Environment Tag
+      readOnlyHint: false
+      destructiveHint: false
+      idempotentHint: false
+      openWorldHint: false
+`
+
+// notificationTestSession is a MCP client session collecting its notifications
+type notificationTestSession struct {
+	auditTestSession
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *notificationTestSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func newReloadTestServer(t *testing.T, content string, options ...ServerOption) (*PortainerMCPServer, string) {
+	t.Helper()
+
+	toolsPath := filepath.Join(t.TempDir(), "tools.yaml")
+	require.NoError(t, os.WriteFile(toolsPath, []byte(content), 0600))
+
+	options = append([]ServerOption{WithClient(new(MockPortainerClient)), WithDisableVersionCheck(true)}, options...)
+	s, err := NewPortainerMCPServer("https://portainer.example.com", "token", toolsPath, options...)
+	require.NoError(t, err)
+
+	s.AddTagFeatures()
+
+	return s, toolsPath
+}
+
+func TestReloadTools(t *testing.T) {
+	s, toolsPath := newReloadTestServer(t, reloadTestToolsV1)
+	require.Nil(t, s.srv.GetTool(ToolCreateEnvironmentTag))
+
+	session := &notificationTestSession{
+		auditTestSession: auditTestSession{id: "session-1"},
+		notifications:    make(chan mcp.JSONRPCNotification, 10),
+	}
+	require.NoError(t, s.srv.RegisterSession(context.Background(), session))
+
+	require.NoError(t, os.WriteFile(toolsPath, []byte(reloadTestToolsV2), 0600))
+	require.NoError(t, s.ReloadTools(toolsPath))
+
+	tool := s.srv.GetTool(ToolListEnvironmentTags)
+	require.NotNil(t, tool)
+	assert.Equal(t, "List every environment tag, prefer this tool to find tag IDs", tool.Tool.Description)
+
+	// The handler added by the feature is registered once the tool is defined
+	require.NotNil(t, s.srv.GetTool(ToolCreateEnvironmentTag))
+	assert.Nil(t, s.srv.GetTool(ToolDeleteTag))
+
+	var statuses []string
+	for _, status := range s.ToolReport() {
+		if status.Registered {
+			statuses = append(statuses, status.Name)
+		}
+	}
+	assert.Equal(t, []string{ToolCreateEnvironmentTag, ToolListEnvironmentTags}, statuses)
+
+	select {
+	case notification := <-session.notifications:
+		assert.Equal(t, mcp.MethodNotificationToolsListChanged, notification.Method)
+	case <-time.After(time.Second):
+		t.Fatal("expected a tools/list_changed notification")
+	}
+}
+
+func TestReloadToolsKeepsToolsOnError(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		options       []ServerOption
+		errorContains string
+	}{
+		{
+			name:          "unsupported version",
+			content:       "version: v0.5\ntools: []\n",
+			errorContains: "failed to load tools",
+		},
+		{
+			name:          "invalid YAML",
+			content:       "version: v1.0\ntools: [\n",
+			errorContains: "failed to load tools",
+		},
+		{
+			name:          "tool filter pattern matching no tool",
+			content:       "version: v1.0\ntools: []\n",
+			options:       []ServerOption{WithToolFilter(ToolFilter{Deny: []string{"list*Tags"}})},
+			errorContains: `tool filter pattern "list*Tags" matches no tool`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, toolsPath := newReloadTestServer(t, reloadTestToolsV2, tt.options...)
+			before := s.ToolReport()
+
+			require.NoError(t, os.WriteFile(toolsPath, []byte(tt.content), 0600))
+			err := s.ReloadTools(toolsPath)
+
+			assert.ErrorContains(t, err, tt.errorContains)
+			assert.Equal(t, before, s.ToolReport())
+			assert.NotNil(t, s.srv.GetTool(ToolCreateEnvironmentTag))
+		})
+	}
+}
+
+func TestWatchTools(t *testing.T) {
+	s, toolsPath := newReloadTestServer(t, reloadTestToolsV1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan error, 10)
+	go s.WatchTools(ctx, toolsPath, 10*time.Millisecond, func(err error) { reloads <- err })
+
+	// Wait for the watcher to read the initial file
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-reloads:
+		t.Fatal("unchanged tools file must not be reloaded")
+	default:
+	}
+
+	require.NoError(t, os.WriteFile(toolsPath, []byte("version: v0.5\ntools: []\n"), 0600))
+	select {
+	case err := <-reloads:
+		assert.ErrorContains(t, err, "failed to load tools")
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a reload of the invalid tools file")
+	}
+
+	require.NoError(t, os.WriteFile(toolsPath, []byte(reloadTestToolsV2), 0600))
+	select {
+	case err := <-reloads:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a reload of the valid tools file")
+	}
+
+	assert.NotNil(t, s.srv.GetTool(ToolCreateEnvironmentTag))
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// requiresEdition reports whether any tool is restricted to a Portainer edition
+func requiresEdition(requirements map[string]toolgen.ToolRequirements) bool {
+	for _, req := range requirements {
+		if req.Edition != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkToolRequirements returns an error explaining why the Portainer server
+// cannot serve a tool, according to the versions and edition the tool is
+// restricted to in the tools file.
+// The requirements are not checked when the version check is disabled, in
+// which case the version of the server is unknown.
+// When several instances are managed, this only checks the default instance,
+// the tool is not registered when the default instance cannot serve it.
+func (s *PortainerMCPServer) checkToolRequirements(toolName string) error {
+	req, ok := s.requirements[toolName]
+	if !ok || s.portainerVersion == "" {
+		return nil
+	}
+
+	return req.Check(s.portainerVersion, s.portainerEdition)
+}
+
+// unsupportedInstances returns the reason each additional instance cannot
+// serve a tool, keyed by instance name. Like for the default instance, the
+// requirements are not checked for the instances whose version is unknown.
+// It must be called with the tools lock held.
+func (s *PortainerMCPServer) unsupportedInstances(toolName string) map[string]error {
+	req, ok := s.requirements[toolName]
+	if !ok {
+		return nil
+	}
+
+	unsupported := make(map[string]error)
+	for _, instance := range s.instances {
+		if instance.version == "" {
+			continue
+		}
+		if err := req.Check(instance.version, instance.edition); err != nil {
+			unsupported[instance.name] = err
+		}
+	}
+	return unsupported
+}
+
+// fetchPortainerEdition fetches the edition of the Portainer servers when a
+// tool of a reloaded tools file is restricted to an edition, and it was not
+// needed by the tools loaded before.
+// It must be called with the tools lock held.
+func (s *PortainerMCPServer) fetchPortainerEdition(requirements map[string]toolgen.ToolRequirements) error {
+	if !requiresEdition(requirements) {
+		return nil
+	}
+
+	ctx, cancel := newRequestContext(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	if s.portainerVersion != "" && s.portainerEdition == "" {
+		edition, err := s.cli.GetEdition(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get Portainer server edition: %w", err)
+		}
+		s.portainerEdition = edition
+	}
+
+	for _, instance := range s.instances {
+		if instance.version == "" || instance.edition != "" {
+			continue
+		}
+
+		edition, err := instance.cli.GetEdition(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get Portainer server edition of instance %s: %w", instance.name, err)
+		}
+		instance.edition = edition
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const requirementsTestTools = `version: v1.0
+tools:
+  - name: listEnvironmentTags
+    description: List the tags
+    annotations:
+      title: List Environment Tags
+      readOnlyHint: true
+      destructiveHint: false
+      idempotentHint: true
+      openWorldHint: false
+  - name: createEnvironmentTag
+    description: Create an environment tag
+    minPortainerVersion: 2.37.0
+    parameters:
+      - name: name
+        type: string
+        description: The name of the tag
+        required: true
+    annotations:
+      title: Create Environment Tag
+      readOnlyHint: false
+      destructiveHint: false
+      idempotentHint: false
+      openWorldHint: false
+  - name: deleteTag
+    description: Delete a tag
+    maxPortainerVersion: "2.36"
+    edition: BE
+    parameters:
+      - name: id
+        type: number
+        description: The ID of the tag
+        required: true
+    annotations:
+      title: Delete Tag
+      readOnlyHint: false
+      destructiveHint: true
+      idempotentHint: true
+      openWorldHint: false
+`
+
+func writeRequirementsTestTools(t *testing.T, content string) string {
+	t.Helper()
+
+	toolsPath := filepath.Join(t.TempDir(), "tools.yaml")
+	require.NoError(t, os.WriteFile(toolsPath, []byte(content), 0600))
+
+	return toolsPath
+}
+
+func TestToolRequirements(t *testing.T) {
+	tests := []struct {
+		name               string
+		version            string
+		edition            string
+		options            []ServerOption
+		expectedRegistered []string
+		expectedReasons    map[string]string
+	}{
+		{
+			name:               "older community edition",
+			version:            "2.36.2",
+			edition:            "CE",
+			expectedRegistered: []string{ToolListEnvironmentTags},
+			expectedReasons: map[string]string{
+				ToolCreateEnvironmentTag: "requires Portainer 2.37.0 or later, the server runs 2.36.2",
+				ToolDeleteTag:            "requires Portainer BE, the server runs Portainer CE",
+			},
+		},
+		{
+			name:               "older business edition",
+			version:            "2.36.2",
+			edition:            "BE",
+			expectedRegistered: []string{ToolDeleteTag, ToolListEnvironmentTags},
+			expectedReasons: map[string]string{
+				ToolCreateEnvironmentTag: "requires Portainer 2.37.0 or later, the server runs 2.36.2",
+			},
+		},
+		{
+			name:               "newer business edition",
+			version:            "2.37.0",
+			edition:            "BE",
+			expectedRegistered: []string{ToolCreateEnvironmentTag, ToolListEnvironmentTags},
+			expectedReasons: map[string]string{
+				ToolDeleteTag: "requires Portainer 2.36 or earlier, the server runs 2.37.0",
+			},
+		},
+		{
+			name:               "disabled version check",
+			options:            []ServerOption{WithDisableVersionCheck(true)},
+			expectedRegistered: []string{ToolCreateEnvironmentTag, ToolDeleteTag, ToolListEnvironmentTags},
+			expectedReasons:    map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			if tt.version != "" {
+				mockClient.On("GetVersion").Return(tt.version, nil)
+				mockClient.On("GetEdition").Return(tt.edition, nil)
+			}
+
+			options := append([]ServerOption{WithClient(mockClient)}, tt.options...)
+			s, err := NewPortainerMCPServer("https://portainer.example.com", "token", writeRequirementsTestTools(t, requirementsTestTools), options...)
+			require.NoError(t, err)
+
+			s.AddTagFeatures()
+
+			var registered []string
+			reasons := map[string]string{}
+			for _, status := range s.ToolReport() {
+				if status.Registered {
+					registered = append(registered, status.Name)
+					continue
+				}
+				reasons[status.Name] = status.Reason
+			}
+
+			assert.Equal(t, tt.expectedRegistered, registered)
+			assert.Equal(t, tt.expectedReasons, reasons)
+			for _, name := range tt.expectedRegistered {
+				assert.NotNil(t, s.srv.GetTool(name))
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestToolRequirementsEditionError(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetVersion").Return("2.37.0", nil)
+	mockClient.On("GetEdition").Return("", errors.New("connection refused"))
+
+	_, err := NewPortainerMCPServer("https://portainer.example.com", "token", writeRequirementsTestTools(t, requirementsTestTools), WithClient(mockClient))
+	assert.ErrorContains(t, err, "failed to get Portainer server edition: connection refused")
+}
+
+func TestReloadToolsFetchesEdition(t *testing.T) {
+	mockClient := new(MockPortainerClient)
+	mockClient.On("GetVersion").Return("2.37.0", nil)
+	mockClient.On("GetEdition").Return("CE", nil).Once()
+
+	toolsPath := writeRequirementsTestTools(t, reloadTestToolsV1)
+	s, err := NewPortainerMCPServer("https://portainer.example.com", "token", toolsPath, WithClient(mockClient))
+	require.NoError(t, err)
+
+	s.AddTagFeatures()
+	mockClient.AssertNotCalled(t, "GetEdition")
+
+	require.NoError(t, os.WriteFile(toolsPath, []byte(requirementsTestTools), 0600))
+	require.NoError(t, s.ReloadTools(toolsPath))
+
+	assert.NotNil(t, s.srv.GetTool(ToolCreateEnvironmentTag))
+	assert.Nil(t, s.srv.GetTool(ToolDeleteTag))
+
+	// The edition is only fetched once
+	require.NoError(t, s.ReloadTools(toolsPath))
+	mockClient.AssertExpectations(t)
+}
+
+func TestToolRequirementsPerInstance(t *testing.T) {
+	defaultClient := new(MockPortainerClient)
+	defaultClient.On("GetVersion").Return("2.37.0", nil)
+	defaultClient.On("GetEdition").Return("CE", nil)
+	defaultClient.On("CreateEnvironmentTag", "production").Return(1, nil)
+
+	edgeClient := new(MockPortainerClient)
+	edgeClient.On("GetVersion").Return("2.36.2", nil)
+	edgeClient.On("GetEdition").Return("CE", nil)
+
+	s, err := NewPortainerMCPServer("https://portainer.example.com", "token", writeRequirementsTestTools(t, requirementsTestTools),
+		WithClient(defaultClient),
+		WithInstances(Instance{Name: "edge", ServerURL: "https://edge.example.com", Client: edgeClient}),
+	)
+	require.NoError(t, err)
+
+	s.AddTagFeatures()
+	require.NotNil(t, s.srv.GetTool(ToolCreateEnvironmentTag))
+
+	result := callTool(t, s, context.Background(), ToolCreateEnvironmentTag, map[string]any{"name": "production"})
+	assert.False(t, result.IsError, resultText(result))
+
+	result = callTool(t, s, context.Background(), ToolCreateEnvironmentTag, map[string]any{"name": "production", InstanceParam: "edge"})
+	assert.True(t, result.IsError)
+	assert.Equal(t, "tool createEnvironmentTag is not available on instance edge: requires Portainer 2.37.0 or later, the server runs 2.36.2", resultText(result))
+	edgeClient.AssertNotCalled(t, "CreateEnvironmentTag", "production")
+
+	defaultClient.AssertExpectations(t)
+	edgeClient.AssertExpectations(t)
+}
@@ -2,135 +2,10 @@ package mcp
 
 import "slices"
 
-// Tool names as defined in the YAML file
-const (
-	// Access Groups
-	ToolCreateAccessGroup                = "createAccessGroup"
-	ToolListAccessGroups                 = "listAccessGroups"
-	ToolUpdateAccessGroupName            = "updateAccessGroupName"
-	ToolUpdateAccessGroupUserAccesses    = "updateAccessGroupUserAccesses"
-	ToolUpdateAccessGroupTeamAccesses    = "updateAccessGroupTeamAccesses"
-	ToolAddEnvironmentToAccessGroup      = "addEnvironmentToAccessGroup"
-	ToolRemoveEnvironmentFromAccessGroup = "removeEnvironmentFromAccessGroup"
-	ToolDeleteAccessGroup                = "deleteAccessGroup"
-
-	// Environments
-	ToolListEnvironments              = "listEnvironments"
-	ToolUpdateEnvironment             = "updateEnvironment"
-	ToolUpdateEnvironmentTags         = "updateEnvironmentTags"
-	ToolUpdateEnvironmentUserAccesses = "updateEnvironmentUserAccesses"
-	ToolUpdateEnvironmentTeamAccesses = "updateEnvironmentTeamAccesses"
-	ToolListAgentVersions             = "listAgentVersions"
-
-	// Environment Groups (Edge Groups)
-	ToolCreateEnvironmentGroup             = "createEnvironmentGroup"
-	ToolListEnvironmentGroups              = "listEnvironmentGroups"
-	ToolUpdateEnvironmentGroupName         = "updateEnvironmentGroupName"
-	ToolUpdateEnvironmentGroupEnvironments = "updateEnvironmentGroupEnvironments"
-	ToolUpdateEnvironmentGroupTags         = "updateEnvironmentGroupTags"
-	ToolDeleteEnvironmentGroup             = "deleteEnvironmentGroup"
-
-	// Edge Stacks
-	ToolListStacks   = "listStacks"
-	ToolGetStackFile = "getStackFile"
-	ToolCreateStack  = "createStack"
-	ToolUpdateStack  = "updateStack"
-	ToolDeleteStack  = "deleteStack"
-
-	// Docker Stacks
-	ToolListDockerStacks   = "listDockerStacks"
-	ToolGetDockerStackFile = "getDockerStackFile"
-	ToolCreateDockerStack  = "createDockerStack"
-	ToolUpdateDockerStack  = "updateDockerStack"
-	ToolDeleteDockerStack  = "deleteDockerStack"
-	ToolStartDockerStack   = "startDockerStack"
-	ToolStopDockerStack    = "stopDockerStack"
-
-	// Tags
-	ToolCreateEnvironmentTag = "createEnvironmentTag"
-	ToolListEnvironmentTags  = "listEnvironmentTags"
-	ToolDeleteTag            = "deleteTag"
-
-	// Teams
-	ToolCreateTeam        = "createTeam"
-	ToolListTeams         = "listTeams"
-	ToolUpdateTeamName    = "updateTeamName"
-	ToolUpdateTeamMembers = "updateTeamMembers"
-	ToolDeleteTeam        = "deleteTeam"
-
-	// Users
-	ToolListUsers      = "listUsers"
-	ToolUpdateUserRole = "updateUserRole"
-
-	// Settings
-	ToolGetSettings    = "getSettings"
-	ToolUpdateSettings = "updateSettings"
-
-	// Registries
-	ToolListRegistries         = "listRegistries"
-	ToolCreateRegistry         = "createRegistry"
-	ToolDeleteRegistry         = "deleteRegistry"
-	ToolTestRegistryConnection = "testRegistryConnection"
-
-	// Edge Jobs
-	ToolListEdgeJobs  = "listEdgeJobs"
-	ToolGetEdgeJob    = "getEdgeJob"
-	ToolCreateEdgeJob = "createEdgeJob"
-	ToolDeleteEdgeJob = "deleteEdgeJob"
-
-	// Custom Templates
-	ToolListCustomTemplates  = "listCustomTemplates"
-	ToolCreateCustomTemplate = "createCustomTemplate"
-	ToolDeleteCustomTemplate = "deleteCustomTemplate"
-
-	// Webhooks
-	ToolListWebhooks  = "listWebhooks"
-	ToolCreateWebhook = "createWebhook"
-	ToolDeleteWebhook = "deleteWebhook"
-
-	// Git Credentials
-	ToolListGitCredentials  = "listGitCredentials"
-	ToolGetGitCredential    = "getGitCredential"
-	ToolCreateGitCredential = "createGitCredential"
-	ToolUpdateGitCredential = "updateGitCredential"
-	ToolDeleteGitCredential = "deleteGitCredential"
-
-	// Alerting
-	ToolListAlerts          = "listAlerts"
-	ToolListAlertRules      = "listAlertRules"
-	ToolGetAlertRule        = "getAlertRule"
-	ToolUpdateAlertRule     = "updateAlertRule"
-	ToolDeleteAlertRule     = "deleteAlertRule"
-	ToolGetAlertingSettings = "getAlertingSettings"
-	ToolCreateAlertSilence  = "createAlertSilence"
-	ToolDeleteAlertSilence  = "deleteAlertSilence"
-
-	// Policies
-	ToolListPolicies        = "listPolicies"
-	ToolGetPolicy           = "getPolicy"
-	ToolCreatePolicy        = "createPolicy"
-	ToolUpdatePolicy        = "updatePolicy"
-	ToolDeletePolicy        = "deletePolicy"
-	ToolListPolicyTemplates = "listPolicyTemplates"
-	ToolGetPolicyTemplate   = "getPolicyTemplate"
-	ToolGetPolicyMetadata   = "getPolicyMetadata"
-	ToolGetPolicyConflicts  = "getPolicyConflicts"
-
-	// Kubernetes Custom Resources
-	ToolListCustomResourceDefinitions  = "listCustomResourceDefinitions"
-	ToolGetCustomResourceDefinition    = "getCustomResourceDefinition"
-	ToolDeleteCustomResourceDefinition = "deleteCustomResourceDefinition"
-	ToolListCustomResources            = "listCustomResources"
-	ToolGetCustomResource              = "getCustomResource"
-	ToolDeleteCustomResource           = "deleteCustomResource"
-
-	// Docker Proxy
-	ToolDockerProxy = "dockerProxy"
-
-	// Kubernetes Proxy
-	ToolKubernetesProxy         = "kubernetesProxy"
-	ToolKubernetesProxyStripped = "getKubernetesResourceStripped"
-)
+// The tool name constants and the typed tool arguments are generated from the
+// embedded tools file, which fails when a tool has no handler or a handler
+// registers a tool missing from the file.
+//go:generate go run ../../cmd/toolgen -tools ../tooldef/tools.yaml -output tools_gen.go
 
 // Access levels for users and teams
 const (
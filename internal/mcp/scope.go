@@ -0,0 +1,493 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// EnvironmentScope restricts the server to a subset of the Portainer environments.
+//
+// An environment is within the scope when it matches any of the criteria.
+// Environments outside the scope, and the resources bound to them, are
+// invisible to the tools: list tools omit them and tools targeting them fail
+// as if they did not exist. An empty scope does not restrict the server.
+type EnvironmentScope struct {
+	// EnvironmentIDs are the IDs of the environments within the scope
+	EnvironmentIDs []int
+	// TagIDs selects the environments carrying any of these tags
+	TagIDs []int
+	// EnvironmentGroupIDs selects the environments of these environment (edge) groups
+	EnvironmentGroupIDs []int
+	// AccessGroupIDs selects the environments of these access (endpoint) groups
+	AccessGroupIDs []int
+}
+
+// IsEmpty checks if the scope does not restrict the server
+func (sc EnvironmentScope) IsEmpty() bool {
+	return len(sc.EnvironmentIDs) == 0 &&
+		len(sc.TagIDs) == 0 &&
+		len(sc.EnvironmentGroupIDs) == 0 &&
+		len(sc.AccessGroupIDs) == 0
+}
+
+// WithEnvironmentScope restricts the server to the environments within the scope
+func WithEnvironmentScope(scope EnvironmentScope) ServerOption {
+	return func(opts *serverOptions) {
+		opts.environmentScope = scope
+	}
+}
+
+// scopeCheck verifies that a tool call only targets environments within the
+// scope, given as a set of environment IDs. The call is rejected when its
+// arguments cannot be parsed or the resources they hold cannot be found, so
+// that a target that cannot be resolved never escapes the scope.
+type scopeCheck func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error
+
+// scopeChecks lists, for each tool targeting environments or resources bound
+// to environments, the checks enforcing the environment scope
+var scopeChecks = map[string][]scopeCheck{
+	// Environments
+	ToolUpdateEnvironment:             {environmentArg("id")},
+	ToolUpdateEnvironmentTags:         {environmentArg("id")},
+	ToolUpdateEnvironmentUserAccesses: {environmentArg("id")},
+	ToolUpdateEnvironmentTeamAccesses: {environmentArg("id")},
+
+	// Access groups
+	ToolCreateAccessGroup:                {environmentsArg("environmentIds")},
+	ToolUpdateAccessGroupName:            {accessGroupArg},
+	ToolUpdateAccessGroupUserAccesses:    {accessGroupArg},
+	ToolUpdateAccessGroupTeamAccesses:    {accessGroupArg},
+	ToolAddEnvironmentToAccessGroup:      {environmentArg("environmentId")},
+	ToolRemoveEnvironmentFromAccessGroup: {environmentArg("environmentId")},
+	ToolDeleteAccessGroup:                {accessGroupArg},
+
+	// Environment groups
+	ToolCreateEnvironmentGroup:             {environmentsArg("environmentIds")},
+	ToolUpdateEnvironmentGroupName:         {environmentGroupArg},
+	ToolUpdateEnvironmentGroupEnvironments: {environmentGroupArg, environmentsArg("environmentIds")},
+	ToolUpdateEnvironmentGroupTags:         {unscopedOnly},
+	ToolDeleteEnvironmentGroup:             {environmentGroupArg},
+
+	// Edge stacks
+	ToolGetStackFile: {edgeStackArg},
+	ToolCreateStack:  {environmentGroupsArg("environmentGroupIds")},
+	ToolUpdateStack:  {edgeStackArg, environmentGroupsArg("environmentGroupIds")},
+	ToolDeleteStack:  {edgeStackArg},
+
+	// Docker stacks
+	ToolGetDockerStackFile: {dockerStackArg},
+	ToolCreateDockerStack:  {environmentArg("environmentId")},
+	ToolUpdateDockerStack:  {environmentArg("environmentId"), dockerStackArg},
+	ToolDeleteDockerStack:  {environmentArg("environmentId"), dockerStackArg},
+	ToolStartDockerStack:   {environmentArg("environmentId"), dockerStackArg},
+	ToolStopDockerStack:    {environmentArg("environmentId"), dockerStackArg},
+
+	// Edge jobs
+	ToolGetEdgeJob:    {edgeJobArg},
+	ToolCreateEdgeJob: {environmentGroupsArg("edgeGroupIds")},
+	ToolDeleteEdgeJob: {edgeJobArg},
+
+	// Policies
+	ToolGetPolicy:          {policyArg},
+	ToolCreatePolicy:       {environmentGroupsArg("environmentGroups")},
+	ToolUpdatePolicy:       {policyArg, environmentGroupsArg("environmentGroups")},
+	ToolDeletePolicy:       {policyArg},
+	ToolGetPolicyConflicts: {environmentGroupsArg("environmentGroups")},
+
+	// Webhooks
+	ToolCreateWebhook: {environmentArg("endpointId")},
+	ToolDeleteWebhook: {webhookArg},
+
+	// Kubernetes custom resources
+	ToolListCustomResourceDefinitions:  {environmentArg("environmentId")},
+	ToolGetCustomResourceDefinition:    {environmentArg("environmentId")},
+	ToolDeleteCustomResourceDefinition: {environmentArg("environmentId")},
+	ToolListCustomResources:            {environmentArg("environmentId")},
+	ToolGetCustomResource:              {environmentArg("environmentId")},
+	ToolDeleteCustomResource:           {environmentArg("environmentId")},
+
+	// Proxies
+	ToolDockerProxy:                   {environmentArg("environmentId")},
+	ToolKubernetesProxy:               {environmentArg("environmentId")},
+	ToolGetKubernetesResourceStripped: {environmentArg("environmentId")},
+}
+
+// scopeHandler returns a handler rejecting the calls of the given handler
+// that target environments outside the scope of the server.
+//
+// The scope and the targets of the call are resolved with fresh Portainer
+// responses, bypassing the response cache, so that a change of the tags or
+// groups of an environment cannot let a stale response authorize a call.
+func (s *PortainerMCPServer) scopeHandler(checks []scopeCheck, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		checkCtx := withCacheRefresh(ctx)
+
+		inScope, err := s.scopedEnvironmentIDs(checkCtx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+
+		parser := toolgen.NewParameterParser(request)
+		for _, check := range checks {
+			if err := check(checkCtx, s.client(ctx), parser, inScope); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		return handler(ctx, request)
+	}
+}
+
+// scopedEnvironmentIDs resolves the IDs of the environments within the scope.
+// The scope is resolved on every call, so that newly tagged or grouped
+// environments are taken into account. It returns nil when the server is not scoped.
+func (s *PortainerMCPServer) scopedEnvironmentIDs(ctx context.Context) (map[int]bool, error) {
+	if s.scope.IsEmpty() {
+		return nil, nil
+	}
+
+	cli := s.client(ctx)
+	inScope := make(map[int]bool)
+
+	for _, id := range s.scope.EnvironmentIDs {
+		inScope[id] = true
+	}
+
+	if len(s.scope.TagIDs) > 0 {
+		environments, err := cli.GetEnvironments(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environments: %w", err)
+		}
+
+		for _, environment := range environments {
+			for _, tagID := range environment.TagIds {
+				if slices.Contains(s.scope.TagIDs, tagID) {
+					inScope[environment.ID] = true
+				}
+			}
+		}
+	}
+
+	if len(s.scope.EnvironmentGroupIDs) > 0 {
+		groups, err := cli.GetEnvironmentGroups(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environment groups: %w", err)
+		}
+
+		for _, group := range groups {
+			if slices.Contains(s.scope.EnvironmentGroupIDs, group.ID) {
+				for _, id := range group.EnvironmentIds {
+					inScope[id] = true
+				}
+			}
+		}
+	}
+
+	if len(s.scope.AccessGroupIDs) > 0 {
+		accessGroups, err := cli.GetAccessGroups(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get access groups: %w", err)
+		}
+
+		for _, group := range accessGroups {
+			if slices.Contains(s.scope.AccessGroupIDs, group.ID) {
+				for _, id := range group.EnvironmentIds {
+					inScope[id] = true
+				}
+			}
+		}
+	}
+
+	return inScope, nil
+}
+
+// scopedEnvironmentGroupIDs returns the IDs of the environment groups within
+// the scope, as defined by groupInScope. It returns nil when the server is not scoped.
+func scopedEnvironmentGroupIDs(ctx context.Context, cli PortainerClient, inScope map[int]bool) (map[int]bool, error) {
+	if inScope == nil {
+		return nil, nil
+	}
+
+	groups, err := cli.GetEnvironmentGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment groups: %w", err)
+	}
+
+	scoped := make(map[int]bool)
+	for _, group := range groups {
+		if groupInScope(inScope, group) {
+			scoped[group.ID] = true
+		}
+	}
+	return scoped, nil
+}
+
+// groupInScope checks if the environments of an environment group are all
+// within the scope. Dynamic groups, whose environments are selected by tags,
+// and groups without environments are out of scope: they may come to hold
+// environments outside the scope without any of the checked tools being called.
+func groupInScope(inScope map[int]bool, group models.Group) bool {
+	return len(group.TagIds) == 0 && len(group.EnvironmentIds) > 0 && allInScope(inScope, group.EnvironmentIds)
+}
+
+// filterScoped keeps the items whose IDs are all in the given set.
+// Every item is kept when the set is nil.
+func filterScoped[T any](scoped map[int]bool, items []T, ids func(T) []int) []T {
+	if scoped == nil {
+		return items
+	}
+
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if allInScope(scoped, ids(item)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// allInScope checks if all IDs are in the given set
+func allInScope(scoped map[int]bool, ids []int) bool {
+	for _, id := range ids {
+		if !scoped[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// unscopedOnly rejects the calls of a scoped server. It guards the tools whose
+// effect on the environments cannot be bounded by the scope, such as the tags
+// selecting the environments of a dynamic environment group.
+func unscopedOnly(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+	if inScope != nil {
+		return fmt.Errorf("this tool is not available when the server is restricted to an environment scope")
+	}
+	return nil
+}
+
+// environmentArg returns a check of the environment ID held by the given argument
+func environmentArg(name string) scopeCheck {
+	return func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+		id, err := parser.GetInt(name, true)
+		if err != nil {
+			return fmt.Errorf("invalid %s parameter: %w", name, err)
+		}
+		if !inScope[id] {
+			return fmt.Errorf("environment %d not found", id)
+		}
+		return nil
+	}
+}
+
+// environmentsArg returns a check of the environment IDs held by the given argument
+func environmentsArg(name string) scopeCheck {
+	return func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+		ids, err := parser.GetArrayOfIntegers(name, false)
+		if err != nil {
+			return fmt.Errorf("invalid %s parameter: %w", name, err)
+		}
+		for _, id := range ids {
+			if !inScope[id] {
+				return fmt.Errorf("environment %d not found", id)
+			}
+		}
+		return nil
+	}
+}
+
+// environmentGroupsArg returns a check of the environment group IDs held by the given argument
+func environmentGroupsArg(name string) scopeCheck {
+	return func(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+		ids, err := parser.GetArrayOfIntegers(name, false)
+		if err != nil {
+			return fmt.Errorf("invalid %s parameter: %w", name, err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		scoped, err := scopedEnvironmentGroupIDs(ctx, cli, inScope)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if !scoped[id] {
+				return fmt.Errorf("environment group %d not found", id)
+			}
+		}
+		return nil
+	}
+}
+
+// environmentGroupArg checks that the environment group held by the id
+// argument is within the scope
+func environmentGroupArg(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	groups, err := cli.GetEnvironmentGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get environment groups: %w", err)
+	}
+
+	group, err := findByID(groups, id, func(g models.Group) int { return g.ID })
+	if err != nil || !groupInScope(inScope, group) {
+		return fmt.Errorf("environment group %d not found", id)
+	}
+	return nil
+}
+
+// accessGroupArg checks that the environments of the access group held by
+// the id argument are all within the scope
+func accessGroupArg(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	groups, err := cli.GetAccessGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access groups: %w", err)
+	}
+
+	group, err := findByID(groups, id, func(g models.AccessGroup) int { return g.ID })
+	if err != nil || !allInScope(inScope, group.EnvironmentIds) {
+		return fmt.Errorf("access group %d not found", id)
+	}
+	return nil
+}
+
+// edgeStackArg checks that the edge stack held by the id argument is only
+// deployed to environment groups within the scope
+func edgeStackArg(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	stacks, err := cli.GetStacks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get stacks: %w", err)
+	}
+
+	stack, err := findByID(stacks, id, func(s models.Stack) int { return s.ID })
+	if err != nil {
+		return fmt.Errorf("stack %d not found", id)
+	}
+
+	scoped, err := scopedEnvironmentGroupIDs(ctx, cli, inScope)
+	if err != nil {
+		return err
+	}
+
+	if !allInScope(scoped, stack.EnvironmentGroupIds) {
+		return fmt.Errorf("stack %d not found", id)
+	}
+	return nil
+}
+
+// dockerStackArg checks that the docker stack held by the id argument is
+// deployed to an environment within the scope
+func dockerStackArg(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	stacks, err := cli.GetDockerStacks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get docker stacks: %w", err)
+	}
+
+	stack, err := findByID(stacks, id, func(s models.DockerStack) int { return s.ID })
+	if err != nil {
+		return fmt.Errorf("docker stack %d not found", id)
+	}
+
+	if !inScope[stack.EndpointID] {
+		return fmt.Errorf("docker stack %d not found", id)
+	}
+	return nil
+}
+
+// edgeJobArg checks that the edge job held by the id argument only targets
+// environment groups within the scope
+func edgeJobArg(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	job, err := cli.GetEdgeJob(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get edge job: %w", err)
+	}
+
+	scoped, err := scopedEnvironmentGroupIDs(ctx, cli, inScope)
+	if err != nil {
+		return err
+	}
+
+	if !allInScope(scoped, job.EdgeGroups) {
+		return fmt.Errorf("edge job %d not found", id)
+	}
+	return nil
+}
+
+// policyArg checks that the policy held by the id argument only applies to
+// environment groups within the scope
+func policyArg(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	policy, err := cli.GetPolicy(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get policy: %w", err)
+	}
+
+	scoped, err := scopedEnvironmentGroupIDs(ctx, cli, inScope)
+	if err != nil {
+		return err
+	}
+
+	if !allInScope(scoped, policy.EnvironmentGroups) {
+		return fmt.Errorf("policy %d not found", id)
+	}
+	return nil
+}
+
+// webhookArg checks that the webhook held by the id argument is bound to an
+// environment within the scope
+func webhookArg(ctx context.Context, cli PortainerClient, parser *toolgen.ParameterParser, inScope map[int]bool) error {
+	id, err := parser.GetInt("id", true)
+	if err != nil {
+		return fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	webhooks, err := cli.GetWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	webhook, err := findByID(webhooks, id, func(w models.Webhook) int { return w.ID })
+	if err != nil {
+		return fmt.Errorf("webhook %d not found", id)
+	}
+
+	if !inScope[webhook.EndpointID] {
+		return fmt.Errorf("webhook %d not found", id)
+	}
+	return nil
+}
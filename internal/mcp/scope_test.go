@@ -0,0 +1,546 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopedEnvironmentIDs(t *testing.T) {
+	tests := []struct {
+		name          string
+		scope         EnvironmentScope
+		mockSetup     func(*MockPortainerClient)
+		expected      map[int]bool
+		errorContains string
+	}{
+		{
+			name:      "empty scope",
+			mockSetup: func(m *MockPortainerClient) {},
+			expected:  nil,
+		},
+		{
+			name:      "environment IDs",
+			scope:     EnvironmentScope{EnvironmentIDs: []int{1, 2}},
+			mockSetup: func(m *MockPortainerClient) {},
+			expected:  map[int]bool{1: true, 2: true},
+		},
+		{
+			name:  "tags",
+			scope: EnvironmentScope{TagIDs: []int{10}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironments").Return([]models.Environment{
+					{ID: 1, TagIds: []int{10, 11}},
+					{ID: 2, TagIds: []int{11}},
+					{ID: 3},
+				}, nil)
+			},
+			expected: map[int]bool{1: true},
+		},
+		{
+			name:  "environment and access groups",
+			scope: EnvironmentScope{EnvironmentGroupIDs: []int{5}, AccessGroupIDs: []int{7}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentGroups").Return([]models.Group{
+					{ID: 5, EnvironmentIds: []int{1, 2}},
+					{ID: 6, EnvironmentIds: []int{3}},
+				}, nil)
+				m.On("GetAccessGroups").Return([]models.AccessGroup{
+					{ID: 7, EnvironmentIds: []int{4}},
+				}, nil)
+			},
+			expected: map[int]bool{1: true, 2: true, 4: true},
+		},
+		{
+			name:  "resolution error",
+			scope: EnvironmentScope{TagIDs: []int{10}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironments").Return(nil, errors.New("api error"))
+			},
+			errorContains: "failed to get environments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.mockSetup(mockClient)
+
+			s := &PortainerMCPServer{cli: mockClient, scope: tt.scope}
+
+			inScope, err := s.scopedEnvironmentIDs(context.Background())
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, inScope)
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScopeHandler(t *testing.T) {
+	environmentGroups := func(m *MockPortainerClient) {
+		m.On("GetEnvironmentGroups").Return([]models.Group{
+			{ID: 5, EnvironmentIds: []int{1}},
+			{ID: 6, EnvironmentIds: []int{1, 2}},
+			{ID: 10, EnvironmentIds: []int{}, TagIds: []int{3}},
+			{ID: 11, EnvironmentIds: []int{}},
+		}, nil)
+	}
+	accessGroups := func(m *MockPortainerClient) {
+		m.On("GetAccessGroups").Return([]models.AccessGroup{
+			{ID: 7, EnvironmentIds: []int{1}},
+			{ID: 8, EnvironmentIds: []int{1, 2}},
+		}, nil)
+	}
+
+	tests := []struct {
+		name          string
+		tool          string
+		args          map[string]any
+		mockSetup     func(*MockPortainerClient)
+		expectCall    bool
+		errorContains string
+	}{
+		{
+			name:       "environment in scope",
+			tool:       ToolDockerProxy,
+			args:       map[string]any{"environmentId": float64(1), "method": "GET", "dockerAPIPath": "/containers/json"},
+			mockSetup:  func(m *MockPortainerClient) {},
+			expectCall: true,
+		},
+		{
+			name:          "environment out of scope",
+			tool:          ToolKubernetesProxy,
+			args:          map[string]any{"environmentId": float64(2), "method": "GET", "kubernetesAPIPath": "/api/v1/pods"},
+			mockSetup:     func(m *MockPortainerClient) {},
+			errorContains: "environment 2 not found",
+		},
+		{
+			name:          "invalid argument",
+			tool:          ToolDockerProxy,
+			args:          map[string]any{},
+			mockSetup:     func(m *MockPortainerClient) {},
+			errorContains: "invalid environmentId parameter",
+		},
+		{
+			name:          "invalid environment list",
+			tool:          ToolCreateAccessGroup,
+			args:          map[string]any{"name": "group", "environmentIds": "1"},
+			mockSetup:     func(m *MockPortainerClient) {},
+			errorContains: "invalid environmentIds parameter",
+		},
+		{
+			name: "unknown docker stack",
+			tool: ToolDeleteDockerStack,
+			args: map[string]any{"id": float64(9), "environmentId": float64(1)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetDockerStacks").Return([]models.DockerStack{{ID: 3, EndpointID: 1}}, nil)
+			},
+			errorContains: "docker stack 9 not found",
+		},
+		{
+			name: "unknown webhook",
+			tool: ToolDeleteWebhook,
+			args: map[string]any{"id": float64(9)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetWebhooks").Return([]models.Webhook{}, nil)
+			},
+			errorContains: "webhook 9 not found",
+		},
+		{
+			name: "unknown edge stack",
+			tool: ToolDeleteStack,
+			args: map[string]any{"id": float64(9)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetStacks").Return([]models.Stack{}, nil)
+			},
+			errorContains: "stack 9 not found",
+		},
+		{
+			name: "edge job lookup failure",
+			tool: ToolGetEdgeJob,
+			args: map[string]any{"id": float64(9)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEdgeJob", 9).Return(models.EdgeJob{}, errors.New("api error"))
+			},
+			errorContains: "failed to get edge job: api error",
+		},
+		{
+			name: "docker stack of another environment",
+			tool: ToolDeleteDockerStack,
+			args: map[string]any{"id": float64(3), "environmentId": float64(1)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetDockerStacks").Return([]models.DockerStack{{ID: 3, EndpointID: 2}}, nil)
+			},
+			errorContains: "docker stack 3 not found",
+		},
+		{
+			name: "webhook in scope",
+			tool: ToolDeleteWebhook,
+			args: map[string]any{"id": float64(4)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetWebhooks").Return([]models.Webhook{{ID: 4, EndpointID: 1}}, nil)
+			},
+			expectCall: true,
+		},
+		{
+			name: "edge job targeting a group out of scope",
+			tool: ToolCreateEdgeJob,
+			args: map[string]any{"edgeGroupIds": []any{float64(5), float64(6)}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentGroups").Return([]models.Group{
+					{ID: 5, EnvironmentIds: []int{1}},
+					{ID: 6, EnvironmentIds: []int{1, 2}},
+				}, nil)
+			},
+			errorContains: "environment group 6 not found",
+		},
+		{
+			name:          "updateEnvironmentGroupName of a group out of scope",
+			tool:          ToolUpdateEnvironmentGroupName,
+			args:          map[string]any{"id": float64(6), "name": "group"},
+			mockSetup:     environmentGroups,
+			errorContains: "environment group 6 not found",
+		},
+		{
+			name:          "updateEnvironmentGroupTags of a group in scope",
+			tool:          ToolUpdateEnvironmentGroupTags,
+			args:          map[string]any{"id": float64(5), "tagIds": []any{float64(1)}},
+			mockSetup:     func(m *MockPortainerClient) {},
+			errorContains: "not available when the server is restricted to an environment scope",
+		},
+		{
+			name:          "updateEnvironmentGroupName of a dynamic group",
+			tool:          ToolUpdateEnvironmentGroupName,
+			args:          map[string]any{"id": float64(10), "name": "group"},
+			mockSetup:     environmentGroups,
+			errorContains: "environment group 10 not found",
+		},
+		{
+			name:          "stack deployed to a dynamic group",
+			tool:          ToolCreateStack,
+			args:          map[string]any{"name": "stack", "file": "services: {}", "environmentGroupIds": []any{float64(5), float64(10)}},
+			mockSetup:     environmentGroups,
+			errorContains: "environment group 10 not found",
+		},
+		{
+			name:          "edge job targeting an empty group",
+			tool:          ToolCreateEdgeJob,
+			args:          map[string]any{"edgeGroupIds": []any{float64(11)}},
+			mockSetup:     environmentGroups,
+			errorContains: "environment group 11 not found",
+		},
+		{
+			name:          "deleteEnvironmentGroup of a group out of scope",
+			tool:          ToolDeleteEnvironmentGroup,
+			args:          map[string]any{"id": float64(6)},
+			mockSetup:     environmentGroups,
+			errorContains: "environment group 6 not found",
+		},
+		{
+			name:          "updateEnvironmentGroupEnvironments of a group out of scope",
+			tool:          ToolUpdateEnvironmentGroupEnvironments,
+			args:          map[string]any{"id": float64(6), "environmentIds": []any{float64(1)}},
+			mockSetup:     environmentGroups,
+			errorContains: "environment group 6 not found",
+		},
+		{
+			name:          "updateAccessGroupName of a group out of scope",
+			tool:          ToolUpdateAccessGroupName,
+			args:          map[string]any{"id": float64(8), "name": "group"},
+			mockSetup:     accessGroups,
+			errorContains: "access group 8 not found",
+		},
+		{
+			name:          "updateAccessGroupUserAccesses of a group out of scope",
+			tool:          ToolUpdateAccessGroupUserAccesses,
+			args:          map[string]any{"id": float64(8), "userAccesses": []any{}},
+			mockSetup:     accessGroups,
+			errorContains: "access group 8 not found",
+		},
+		{
+			name:          "updateAccessGroupTeamAccesses of a group out of scope",
+			tool:          ToolUpdateAccessGroupTeamAccesses,
+			args:          map[string]any{"id": float64(8), "teamAccesses": []any{}},
+			mockSetup:     accessGroups,
+			errorContains: "access group 8 not found",
+		},
+		{
+			name:          "deleteAccessGroup of a group out of scope",
+			tool:          ToolDeleteAccessGroup,
+			args:          map[string]any{"id": float64(8)},
+			mockSetup:     accessGroups,
+			errorContains: "access group 8 not found",
+		},
+		{
+			name:          "unknown environment group",
+			tool:          ToolDeleteEnvironmentGroup,
+			args:          map[string]any{"id": float64(9)},
+			mockSetup:     environmentGroups,
+			errorContains: "environment group 9 not found",
+		},
+		{
+			name:       "environment group in scope",
+			tool:       ToolUpdateEnvironmentGroupName,
+			args:       map[string]any{"id": float64(5), "name": "group"},
+			mockSetup:  environmentGroups,
+			expectCall: true,
+		},
+		{
+			name:          "environment group in scope given environments out of scope",
+			tool:          ToolUpdateEnvironmentGroupEnvironments,
+			args:          map[string]any{"id": float64(5), "environmentIds": []any{float64(1), float64(2)}},
+			mockSetup:     environmentGroups,
+			errorContains: "environment 2 not found",
+		},
+		{
+			name:       "access group in scope",
+			tool:       ToolDeleteAccessGroup,
+			args:       map[string]any{"id": float64(7)},
+			mockSetup:  accessGroups,
+			expectCall: true,
+		},
+		{
+			name: "getPolicy of a policy out of scope",
+			tool: ToolGetPolicy,
+			args: map[string]any{"id": float64(2)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetPolicy", 2).Return(models.Policy{ID: 2, EnvironmentGroups: []int{5, 6}}, nil)
+				environmentGroups(m)
+			},
+			errorContains: "policy 2 not found",
+		},
+		{
+			name: "deletePolicy of a policy out of scope",
+			tool: ToolDeletePolicy,
+			args: map[string]any{"id": float64(2)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetPolicy", 2).Return(models.Policy{ID: 2, EnvironmentGroups: []int{6}}, nil)
+				environmentGroups(m)
+			},
+			errorContains: "policy 2 not found",
+		},
+		{
+			name:          "createPolicy for a group out of scope",
+			tool:          ToolCreatePolicy,
+			args:          map[string]any{"name": "policy", "type": "rbac-docker", "environmentType": "docker", "environmentGroups": []any{float64(6)}},
+			mockSetup:     environmentGroups,
+			errorContains: "environment group 6 not found",
+		},
+		{
+			name: "updatePolicy moving a policy in scope to a group out of scope",
+			tool: ToolUpdatePolicy,
+			args: map[string]any{"id": float64(2), "environmentGroups": []any{float64(5), float64(6)}},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetPolicy", 2).Return(models.Policy{ID: 2, EnvironmentGroups: []int{5}}, nil)
+				environmentGroups(m)
+			},
+			errorContains: "environment group 6 not found",
+		},
+		{
+			name: "policy lookup failure",
+			tool: ToolGetPolicy,
+			args: map[string]any{"id": float64(9)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetPolicy", 9).Return(models.Policy{}, errors.New("api error"))
+			},
+			errorContains: "failed to get policy: api error",
+		},
+		{
+			name: "policy in scope",
+			tool: ToolUpdatePolicy,
+			args: map[string]any{"id": float64(2), "name": "policy"},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetPolicy", 2).Return(models.Policy{ID: 2, EnvironmentGroups: []int{5}}, nil)
+				environmentGroups(m)
+			},
+			expectCall: true,
+		},
+		{
+			name: "edge job in scope",
+			tool: ToolDeleteEdgeJob,
+			args: map[string]any{"id": float64(8)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEdgeJob", 8).Return(models.EdgeJob{ID: 8, EdgeGroups: []int{5}}, nil)
+				m.On("GetEnvironmentGroups").Return([]models.Group{{ID: 5, EnvironmentIds: []int{1}}}, nil)
+			},
+			expectCall: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.mockSetup(mockClient)
+
+			s := &PortainerMCPServer{
+				srv:   server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+				cli:   mockClient,
+				tools: map[string]mcp.Tool{tt.tool: newAuditTestTool(tt.tool, false)},
+				scope: EnvironmentScope{EnvironmentIDs: []int{1}},
+			}
+
+			called := false
+			s.addToolIfExists(tt.tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			result := callTool(t, s, context.Background(), tt.tool, tt.args)
+
+			assert.Equal(t, tt.expectCall, called)
+			if tt.errorContains != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, resultText(result), tt.errorContains)
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListToolsFilteredByScope(t *testing.T) {
+	groups := []models.Group{
+		{ID: 5, EnvironmentIds: []int{1}},
+		{ID: 6, EnvironmentIds: []int{1, 2}},
+		{ID: 10, EnvironmentIds: []int{}, TagIds: []int{3}},
+	}
+
+	tests := []struct {
+		name      string
+		handler   func(s *PortainerMCPServer) server.ToolHandlerFunc
+		mockSetup func(*MockPortainerClient)
+		expected  string
+	}{
+		{
+			name:    "environments",
+			handler: (*PortainerMCPServer).HandleGetEnvironments,
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironments").Return([]models.Environment{{ID: 1, Name: "in"}, {ID: 2, Name: "out"}}, nil)
+			},
+			expected: `[{"id":1,"name":"in","status":"","type":"","tag_ids":null,"user_accesses":null,"team_accesses":null}]`,
+		},
+		{
+			name:    "docker stacks",
+			handler: (*PortainerMCPServer).HandleListDockerStacks,
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetDockerStacks").Return([]models.DockerStack{{ID: 1, EndpointID: 1}, {ID: 2, EndpointID: 2}}, nil)
+			},
+			expected: `[{"id":1,"name":"","type":0,"status":0,"endpoint_id":1,"entry_point":"","created_by":"","creation_date":0,"is_compose_format":false}]`,
+		},
+		{
+			name:    "webhooks",
+			handler: (*PortainerMCPServer).HandleListWebhooks,
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetWebhooks").Return([]models.Webhook{{ID: 1, EndpointID: 2}}, nil)
+			},
+			expected: `[]`,
+		},
+		{
+			name:    "edge stacks",
+			handler: (*PortainerMCPServer).HandleGetStacks,
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetStacks").Return([]models.Stack{
+					{ID: 1, Name: "in", EnvironmentGroupIds: []int{5}},
+					{ID: 2, Name: "out", EnvironmentGroupIds: []int{5, 6}},
+				}, nil)
+				m.On("GetEnvironmentGroups").Return(groups, nil)
+			},
+			expected: `[{"id":1,"name":"in","created_at":"","group_ids":[5]}]`,
+		},
+		{
+			name:    "edge jobs",
+			handler: (*PortainerMCPServer).HandleListEdgeJobs,
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEdgeJobs").Return([]models.EdgeJob{
+					{ID: 1, Name: "in", EdgeGroups: []int{5}},
+					{ID: 2, Name: "out", EdgeGroups: []int{6}},
+				}, nil)
+				m.On("GetEnvironmentGroups").Return(groups, nil)
+			},
+			expected: `[{"id":1,"name":"in","cron_expression":"","recurring":false,"created":0,"edge_groups":[5]}]`,
+		},
+		{
+			name:    "environment groups",
+			handler: (*PortainerMCPServer).HandleGetEnvironmentGroups,
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetEnvironmentGroups").Return(groups, nil)
+			},
+			expected: `[{"id":5,"name":"","environment_ids":[1],"tag_ids":null}]`,
+		},
+		{
+			name:    "policies",
+			handler: (*PortainerMCPServer).HandleListPolicies,
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetPolicies").Return([]models.Policy{
+					{ID: 1, Name: "in", EnvironmentGroups: []int{5}},
+					{ID: 2, Name: "out", EnvironmentGroups: []int{5, 10}},
+				}, nil)
+				m.On("GetEnvironmentGroups").Return(groups, nil)
+			},
+			expected: `[{"Id":1,"Name":"in","Type":"","EnvironmentType":"","EnvironmentGroups":[5],"CreatedAt":"","UpdatedAt":""}]`,
+		},
+		{
+			name:    "access groups",
+			handler: (*PortainerMCPServer).HandleGetAccessGroups,
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetAccessGroups").Return([]models.AccessGroup{
+					{ID: 1, Name: "in", EnvironmentIds: []int{1}},
+					{ID: 2, Name: "out", EnvironmentIds: []int{1, 2}},
+				}, nil)
+			},
+			expected: `[{"id":1,"name":"in","environment_ids":[1],"user_accesses":null,"team_accesses":null}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockPortainerClient)
+			tt.mockSetup(mockClient)
+
+			s := &PortainerMCPServer{cli: mockClient, scope: EnvironmentScope{EnvironmentIDs: []int{1}}}
+
+			result, err := tt.handler(s)(context.Background(), CreateMCPRequest(nil))
+			require.NoError(t, err)
+			require.False(t, result.IsError, resultText(result))
+			assert.JSONEq(t, tt.expected, resultText(result))
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScopeChecksCoverEnvironmentParameters(t *testing.T) {
+	environmentParameters := []string{"environmentId", "environmentIds", "endpointId", "environmentGroupIds", "edgeGroupIds", "environmentGroups"}
+
+	tools, err := toolgen.LoadToolsFromYAML("../tooldef/tools.yaml", MinimumToolsVersion)
+	require.NoError(t, err)
+
+	for name, tool := range tools {
+		schema, err := json.Marshal(tool.InputSchema)
+		require.NoError(t, err)
+
+		var parsed struct {
+			Properties map[string]any `json:"properties"`
+		}
+		require.NoError(t, json.Unmarshal(schema, &parsed))
+
+		for _, param := range environmentParameters {
+			if _, ok := parsed.Properties[param]; ok {
+				assert.Contains(t, scopeChecks, name, "tool %s takes %s but does not enforce the environment scope", name, param)
+			}
+		}
+	}
+}
@@ -1,13 +1,19 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/tooldef"
 	"github.com/portainer/portainer-mcp/pkg/portainer/client"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 	"github.com/portainer/portainer-mcp/pkg/toolgen"
@@ -16,152 +22,180 @@ import (
 
 const (
 	// MinimumToolsVersion is the minimum supported version of the tools.yaml file
-	MinimumToolsVersion = "1.0"
+	MinimumToolsVersion = "v1.0"
 	// MinSupportedPortainerVersion is the minimum version of Portainer supported by this tool
 	MinSupportedPortainerVersion = "2.27.0"
 	// MaxSupportedPortainerVersion is the maximum version of Portainer supported by this tool
 	MaxSupportedPortainerVersion = "2.38"
+	// DefaultRequestTimeout is the default maximum duration of a single tool call
+	DefaultRequestTimeout = 2 * time.Minute
 )
 
-// PortainerClient defines the interface for the wrapper client used by the MCP server
+// PortainerClient defines the interface for the wrapper client used by the MCP server.
+// Every method takes the context of the tool call so that Portainer requests are
+// aborted when the call is cancelled or times out.
 type PortainerClient interface {
 	// Tag methods
-	GetEnvironmentTags() ([]models.EnvironmentTag, error)
-	CreateEnvironmentTag(name string) (int, error)
-	DeleteTag(id int) error
+	GetEnvironmentTags(ctx context.Context) ([]models.EnvironmentTag, error)
+	CreateEnvironmentTag(ctx context.Context, name string) (int, error)
+	DeleteTag(ctx context.Context, id int) error
 
 	// Environment methods
-	GetEnvironments() ([]models.Environment, error)
-	UpdateEnvironmentTags(id int, tagIds []int) error
-	UpdateEnvironmentUserAccesses(id int, userAccesses map[int]string) error
-	UpdateEnvironmentTeamAccesses(id int, teamAccesses map[int]string) error
-	UpdateEnvironment(id int, name, publicURL string, groupID int) error
-	GetAgentVersions() ([]string, error)
+	GetEnvironments(ctx context.Context) ([]models.Environment, error)
+	SearchEnvironments(ctx context.Context, query models.EnvironmentQuery) ([]models.Environment, error)
+	UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error
+	UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error
+	UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error
+	UpdateEnvironment(ctx context.Context, id int, name, publicURL string, groupID int) error
+	GetAgentVersions(ctx context.Context) ([]string, error)
 
 	// Environment Group methods
-	GetEnvironmentGroups() ([]models.Group, error)
-	CreateEnvironmentGroup(name string, environmentIds []int) (int, error)
-	UpdateEnvironmentGroupName(id int, name string) error
-	UpdateEnvironmentGroupEnvironments(id int, environmentIds []int) error
-	UpdateEnvironmentGroupTags(id int, tagIds []int) error
-	DeleteEnvironmentGroup(id int) error
+	GetEnvironmentGroups(ctx context.Context) ([]models.Group, error)
+	CreateEnvironmentGroup(ctx context.Context, name string, environmentIds []int) (int, error)
+	UpdateEnvironmentGroupName(ctx context.Context, id int, name string) error
+	UpdateEnvironmentGroupEnvironments(ctx context.Context, id int, environmentIds []int) error
+	UpdateEnvironmentGroupTags(ctx context.Context, id int, tagIds []int) error
+	DeleteEnvironmentGroup(ctx context.Context, id int) error
 
 	// Access Group methods
-	GetAccessGroups() ([]models.AccessGroup, error)
-	CreateAccessGroup(name string, environmentIds []int) (int, error)
-	UpdateAccessGroupName(id int, name string) error
-	UpdateAccessGroupUserAccesses(id int, userAccesses map[int]string) error
-	UpdateAccessGroupTeamAccesses(id int, teamAccesses map[int]string) error
-	AddEnvironmentToAccessGroup(id int, environmentId int) error
-	RemoveEnvironmentFromAccessGroup(id int, environmentId int) error
-	DeleteAccessGroup(id int) error
+	GetAccessGroups(ctx context.Context) ([]models.AccessGroup, error)
+	CreateAccessGroup(ctx context.Context, name string, environmentIds []int) (int, error)
+	UpdateAccessGroupName(ctx context.Context, id int, name string) error
+	UpdateAccessGroupUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error
+	UpdateAccessGroupTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error
+	AddEnvironmentToAccessGroup(ctx context.Context, id int, environmentId int) error
+	RemoveEnvironmentFromAccessGroup(ctx context.Context, id int, environmentId int) error
+	DeleteAccessGroup(ctx context.Context, id int) error
 
 	// Edge Stack methods
-	GetStacks() ([]models.Stack, error)
-	GetStackFile(id int) (string, error)
-	CreateStack(name string, file string, environmentGroupIds []int) (int, error)
-	UpdateStack(id int, file string, environmentGroupIds []int) error
-	DeleteEdgeStack(id int) error
+	GetStacks(ctx context.Context) ([]models.Stack, error)
+	GetStackFile(ctx context.Context, id int) (string, error)
+	CreateStack(ctx context.Context, name string, file string, environmentGroupIds []int) (int, error)
+	UpdateStack(ctx context.Context, id int, file string, environmentGroupIds []int) error
+	DeleteEdgeStack(ctx context.Context, id int) error
 
 	// Docker Stack methods
-	GetDockerStacks() ([]models.DockerStack, error)
-	GetDockerStackFile(id int) (string, error)
-	CreateDockerStack(endpointID int, name, composeFileContent string, env []models.StackEnvVar) (int, error)
-	UpdateDockerStack(id, endpointID int, composeFileContent string, env []models.StackEnvVar, prune, pullImage bool) error
-	DeleteDockerStack(id, endpointID int) error
-	StartDockerStack(id, endpointID int) error
-	StopDockerStack(id, endpointID int) error
+	GetDockerStacks(ctx context.Context) ([]models.DockerStack, error)
+	GetDockerStackFile(ctx context.Context, id int) (string, error)
+	CreateDockerStack(ctx context.Context, endpointID int, name, composeFileContent string, env []models.StackEnvVar) (int, error)
+	UpdateDockerStack(ctx context.Context, id, endpointID int, composeFileContent string, env []models.StackEnvVar, prune, pullImage bool) error
+	DeleteDockerStack(ctx context.Context, id, endpointID int) error
+	StartDockerStack(ctx context.Context, id, endpointID int) error
+	StopDockerStack(ctx context.Context, id, endpointID int) error
 
 	// Team methods
-	CreateTeam(name string) (int, error)
-	GetTeams() ([]models.Team, error)
-	UpdateTeamName(id int, name string) error
-	UpdateTeamMembers(id int, userIds []int) error
-	DeleteTeam(id int) error
+	CreateTeam(ctx context.Context, name string) (int, error)
+	GetTeams(ctx context.Context) ([]models.Team, error)
+	UpdateTeamName(ctx context.Context, id int, name string) error
+	UpdateTeamMembers(ctx context.Context, id int, userIds []int) error
+	DeleteTeam(ctx context.Context, id int) error
 
 	// User methods
-	GetUsers() ([]models.User, error)
-	UpdateUserRole(id int, role string) error
+	GetUsers(ctx context.Context) ([]models.User, error)
+	UpdateUserRole(ctx context.Context, id int, role string) error
 
 	// Settings methods
-	GetSettings() (models.PortainerSettings, error)
-	UpdateSettings(settingsJSON string) error
+	GetSettings(ctx context.Context) (models.PortainerSettings, error)
+	UpdateSettings(ctx context.Context, settingsJSON string) error
 
 	// Version methods
-	GetVersion() (string, error)
+	GetVersion(ctx context.Context) (string, error)
+	GetEdition(ctx context.Context) (string, error)
 
 	// Registry methods
-	GetRegistries() ([]models.Registry, error)
-	CreateRegistry(req models.RegistryCreateRequest) (int, error)
-	DeleteRegistry(id int) error
-	PingRegistry(req models.RegistryPingRequest) (models.RegistryPingResponse, error)
+	GetRegistries(ctx context.Context) ([]models.Registry, error)
+	CreateRegistry(ctx context.Context, req models.RegistryCreateRequest) (int, error)
+	DeleteRegistry(ctx context.Context, id int) error
+	PingRegistry(ctx context.Context, req models.RegistryPingRequest) (models.RegistryPingResponse, error)
 
 	// Edge Job methods
-	GetEdgeJobs() ([]models.EdgeJob, error)
-	GetEdgeJob(id int) (models.EdgeJob, error)
-	CreateEdgeJob(req models.EdgeJobCreateRequest) (int, error)
-	DeleteEdgeJob(id int) error
+	GetEdgeJobs(ctx context.Context) ([]models.EdgeJob, error)
+	GetEdgeJob(ctx context.Context, id int) (models.EdgeJob, error)
+	CreateEdgeJob(ctx context.Context, req models.EdgeJobCreateRequest) (int, error)
+	DeleteEdgeJob(ctx context.Context, id int) error
 
 	// Custom Template methods
-	GetCustomTemplates() ([]models.CustomTemplate, error)
-	CreateCustomTemplate(req models.CustomTemplateCreateRequest) (int, error)
-	DeleteCustomTemplate(id int) error
+	GetCustomTemplates(ctx context.Context) ([]models.CustomTemplate, error)
+	CreateCustomTemplate(ctx context.Context, req models.CustomTemplateCreateRequest) (int, error)
+	DeleteCustomTemplate(ctx context.Context, id int) error
 
 	// Webhook methods
-	GetWebhooks() ([]models.Webhook, error)
-	CreateWebhook(req models.WebhookCreateRequest) (int, error)
-	DeleteWebhook(id int) error
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	CreateWebhook(ctx context.Context, req models.WebhookCreateRequest) (int, error)
+	DeleteWebhook(ctx context.Context, id int) error
 
 	// Git Credential methods
-	GetGitCredentials() ([]models.GitCredential, error)
-	GetGitCredential(id int) (models.GitCredential, error)
-	CreateGitCredential(req models.GitCredentialCreateRequest) (int, error)
-	UpdateGitCredential(id int, req models.GitCredentialUpdateRequest) error
-	DeleteGitCredential(id int) error
+	GetGitCredentials(ctx context.Context) ([]models.GitCredential, error)
+	GetGitCredential(ctx context.Context, id int) (models.GitCredential, error)
+	CreateGitCredential(ctx context.Context, req models.GitCredentialCreateRequest) (int, error)
+	UpdateGitCredential(ctx context.Context, id int, req models.GitCredentialUpdateRequest) error
+	DeleteGitCredential(ctx context.Context, id int) error
 
 	// Alerting methods
-	GetAlerts(status string) (json.RawMessage, error)
-	GetAlertRules() ([]models.AlertingRule, error)
-	GetAlertRule(id int) (models.AlertingRule, error)
-	UpdateAlertRule(id int, ruleJSON string) error
-	DeleteAlertRule(id int) error
-	GetAlertingSettings() ([]models.AlertingSettings, error)
-	CreateAlertSilence(silenceJSON string, alertManagerURL string) error
-	DeleteAlertSilence(id string) error
+	GetAlerts(ctx context.Context, status string) (json.RawMessage, error)
+	GetAlertRules(ctx context.Context) ([]models.AlertingRule, error)
+	GetAlertRule(ctx context.Context, id int) (models.AlertingRule, error)
+	UpdateAlertRule(ctx context.Context, id int, ruleJSON string) error
+	DeleteAlertRule(ctx context.Context, id int) error
+	GetAlertingSettings(ctx context.Context) ([]models.AlertingSettings, error)
+	CreateAlertSilence(ctx context.Context, silenceJSON string, alertManagerURL string) error
+	DeleteAlertSilence(ctx context.Context, id string) error
 
 	// Policy methods
-	GetPolicies() ([]models.Policy, error)
-	GetPolicy(id int) (models.Policy, error)
-	CreatePolicy(req models.PolicyCreateRequest) (int, error)
-	UpdatePolicy(id int, req models.PolicyUpdateRequest) error
-	DeletePolicy(id int) error
-	GetPolicyTemplates(category, policyType string) ([]models.PolicyTemplate, error)
-	GetPolicyTemplate(id string) (models.PolicyTemplate, error)
-	GetPolicyMetadata() (models.PolicyMetadata, error)
-	GetPolicyConflicts(req models.PolicyConflictsRequest) (models.PolicyConflictsResponse, error)
+	GetPolicies(ctx context.Context) ([]models.Policy, error)
+	GetPolicy(ctx context.Context, id int) (models.Policy, error)
+	CreatePolicy(ctx context.Context, req models.PolicyCreateRequest) (int, error)
+	UpdatePolicy(ctx context.Context, id int, req models.PolicyUpdateRequest) error
+	DeletePolicy(ctx context.Context, id int) error
+	GetPolicyTemplates(ctx context.Context, category, policyType string) ([]models.PolicyTemplate, error)
+	GetPolicyTemplate(ctx context.Context, id string) (models.PolicyTemplate, error)
+	GetPolicyMetadata(ctx context.Context) (models.PolicyMetadata, error)
+	GetPolicyConflicts(ctx context.Context, req models.PolicyConflictsRequest) (models.PolicyConflictsResponse, error)
 
 	// Kubernetes Custom Resource methods
-	ListCustomResourceDefinitions(environmentID int) ([]models.CustomResourceDefinition, error)
-	GetCustomResourceDefinition(environmentID int, name string) (models.CustomResourceDefinition, error)
-	DeleteCustomResourceDefinition(environmentID int, name string) error
-	ListCustomResources(environmentID int, definition string) ([]models.CustomResource, error)
-	GetCustomResource(environmentID int, namespace, name, definition, format string) (json.RawMessage, error)
-	DeleteCustomResource(environmentID int, namespace, name, definition string) error
+	ListCustomResourceDefinitions(ctx context.Context, environmentID int) ([]models.CustomResourceDefinition, error)
+	GetCustomResourceDefinition(ctx context.Context, environmentID int, name string) (models.CustomResourceDefinition, error)
+	DeleteCustomResourceDefinition(ctx context.Context, environmentID int, name string) error
+	ListCustomResources(ctx context.Context, environmentID int, definition string) ([]models.CustomResource, error)
+	GetCustomResource(ctx context.Context, environmentID int, namespace, name, definition, format string) (json.RawMessage, error)
+	DeleteCustomResource(ctx context.Context, environmentID int, namespace, name, definition string) error
 
 	// Docker Proxy methods
-	ProxyDockerRequest(opts models.DockerProxyRequestOptions) (*http.Response, error)
+	ProxyDockerRequest(ctx context.Context, opts models.DockerProxyRequestOptions) (*http.Response, error)
 
 	// Kubernetes Proxy methods
-	ProxyKubernetesRequest(opts models.KubernetesProxyRequestOptions) (*http.Response, error)
+	ProxyKubernetesRequest(ctx context.Context, opts models.KubernetesProxyRequestOptions) (*http.Response, error)
 }
 
 // PortainerMCPServer is the main server that handles MCP protocol communication
 // with AI assistants and translates them into Portainer API calls.
 type PortainerMCPServer struct {
-	srv      *server.MCPServer
-	cli      PortainerClient
-	tools    map[string]mcp.Tool
-	readOnly bool
+	srv              *server.MCPServer
+	cli              PortainerClient
+	sessionClients   *sessionClientCache
+	sessionTokenMode string
+	toolsMu          sync.Mutex
+	tools            map[string]mcp.Tool
+	requirements     map[string]toolgen.ToolRequirements
+	handlers         []toolHandler
+	portainerVersion string
+	portainerEdition string
+	requestTimeout   time.Duration
+	readOnly         bool
+	dryRun           bool
+	audit            *AuditLogger
+	toolFilter       ToolFilter
+	toolStatuses     map[string]ToolStatus
+	confirmations    *confirmationStore
+	scope            EnvironmentScope
+	proxyPolicy      *ProxyPolicy
+	serverURL        string
+	defaultInstance  string
+	instances        []*portainerInstance
+	cache            bool
+	budget           ResponseBudgetConfig
+	results          *resultBuffer
+	outputFormat     string
 }
 
 // ServerOption is a function that configures the server
@@ -170,8 +204,24 @@ type ServerOption func(*serverOptions)
 // serverOptions contains all configurable options for the server
 type serverOptions struct {
 	client              PortainerClient
+	clientFactory       ClientFactory
 	readOnly            bool
 	disableVersionCheck bool
+	sessionTokenMode    string
+	requestTimeout      time.Duration
+	clientOptions       []client.ClientOption
+	auditLogger         *AuditLogger
+	dryRun              bool
+	toolFilter          ToolFilter
+	confirmDestructive  bool
+	environmentScope    EnvironmentScope
+	proxyPolicy         *ProxyPolicy
+	metrics             *Metrics
+	instances           []Instance
+	defaultInstanceName string
+	cache               *CacheConfig
+	responseBudget      *ResponseBudgetConfig
+	outputFormat        string
 }
 
 // WithClient sets a custom client for the server.
@@ -198,6 +248,41 @@ func WithDisableVersionCheck(disable bool) ServerOption {
 	}
 }
 
+// WithClientFactory sets the function used to create Portainer clients
+// for MCP sessions that provide their own API token.
+// This is primarily used for testing to inject mock clients.
+func WithClientFactory(factory ClientFactory) ServerOption {
+	return func(opts *serverOptions) {
+		opts.clientFactory = factory
+	}
+}
+
+// WithSessionTokenMode sets whether MCP sessions served over a network transport
+// can provide their own Portainer API token in the X-Portainer-API-Key header.
+// Valid modes are disabled (default), optional and required.
+func WithSessionTokenMode(mode string) ServerOption {
+	return func(opts *serverOptions) {
+		opts.sessionTokenMode = mode
+	}
+}
+
+// WithClientOptions sets the options used to create the Portainer clients,
+// such as the TLS configuration used to connect to the Portainer server.
+func WithClientOptions(clientOptions ...client.ClientOption) ServerOption {
+	return func(opts *serverOptions) {
+		opts.clientOptions = append(opts.clientOptions, clientOptions...)
+	}
+}
+
+// WithRequestTimeout sets the maximum duration of a single tool call,
+// including every Portainer API request it makes.
+// A zero timeout disables the limit. Defaults to DefaultRequestTimeout.
+func WithRequestTimeout(timeout time.Duration) ServerOption {
+	return func(opts *serverOptions) {
+		opts.requestTimeout = timeout
+	}
+}
+
 // NewPortainerMCPServer creates a new Portainer MCP server.
 //
 // This server provides an implementation of the MCP protocol for Portainer,
@@ -217,49 +302,214 @@ func WithDisableVersionCheck(disable bool) ServerOption {
 //   - Failed to load tools from the specified path
 //   - Failed to communicate with the Portainer server
 //   - Incompatible Portainer server version
+//   - Invalid session token mode
+//   - Negative request timeout
+//   - Invalid TLS configuration
+//   - Invalid tool filter pattern
+//   - Invalid cache TTL
+//   - Invalid response budget
+//   - Invalid output format
+//   - Invalid, duplicate or unreachable additional instance
 func NewPortainerMCPServer(serverURL, token, toolsPath string, options ...ServerOption) (*PortainerMCPServer, error) {
-	opts := &serverOptions{}
+	opts := &serverOptions{
+		outputFormat:        toolgen.OutputFormatJSON,
+		sessionTokenMode:    SessionTokenDisabled,
+		requestTimeout:      DefaultRequestTimeout,
+		defaultInstanceName: DefaultInstanceName,
+	}
 
 	for _, option := range options {
 		option(opts)
 	}
 
-	tools, err := toolgen.LoadToolsFromYAML(toolsPath, MinimumToolsVersion)
+	if !isValidSessionTokenMode(opts.sessionTokenMode) {
+		return nil, fmt.Errorf("invalid session token mode: %s", opts.sessionTokenMode)
+	}
+
+	if opts.requestTimeout < 0 {
+		return nil, fmt.Errorf("invalid request timeout: %s", opts.requestTimeout)
+	}
+
+	if !toolgen.IsValidOutputFormat(opts.outputFormat) {
+		return nil, fmt.Errorf("invalid output format: %s, must be one of %s", opts.outputFormat, strings.Join(toolgen.OutputFormats, ", "))
+	}
+
+	if opts.cache != nil {
+		if err := opts.cache.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	tools, requirements, err := tooldef.LoadTools(toolsPath, MinimumToolsVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load tools: %w", err)
 	}
 
-	var portainerClient PortainerClient
-	if opts.client != nil {
-		portainerClient = opts.client
-	} else {
-		portainerClient = client.NewPortainerClient(serverURL, token, client.WithSkipTLSVerify(true))
+	if err := opts.toolFilter.validate(slices.Collect(maps.Keys(tools))); err != nil {
+		return nil, err
+	}
+
+	var budget ResponseBudgetConfig
+	var results *resultBuffer
+	if opts.responseBudget != nil {
+		if err := opts.responseBudget.validate(slices.Collect(maps.Keys(tools))); err != nil {
+			return nil, err
+		}
+		budget = *opts.responseBudget
+		results = newResultBuffer(DefaultResultBufferTTL, DefaultResultBufferBytes)
+	}
+
+	if opts.metrics != nil {
+		opts.clientOptions = append(opts.clientOptions, opts.metrics.requestObserver())
 	}
 
-	if !opts.disableVersionCheck {
-		version, err := portainerClient.GetVersion()
+	portainerClient := opts.client
+	clientFactory := opts.clientFactory
+
+	if portainerClient == nil || clientFactory == nil {
+		baseClient, err := client.NewPortainerClient(serverURL, token, opts.clientOptions...)
 		if err != nil {
+			return nil, fmt.Errorf("failed to create Portainer client: %w", err)
+		}
+
+		if portainerClient == nil {
+			portainerClient = baseClient
+		}
+
+		// Session clients share the HTTP client, and therefore the TLS
+		// configuration, of the server client
+		if clientFactory == nil {
+			clientFactory = func(token string) PortainerClient {
+				return baseClient.WithToken(token)
+			}
+		}
+	}
+
+	// Every client caches its own responses, so that a response is never
+	// returned to a session authenticated with another token
+	if opts.cache != nil {
+		portainerClient = newCachingClient(portainerClient, *opts.cache)
+
+		uncachedFactory := clientFactory
+		clientFactory = func(token string) PortainerClient {
+			return newCachingClient(uncachedFactory(token), *opts.cache)
+		}
+	}
+
+	var sessionClients *sessionClientCache
+	if opts.sessionTokenMode != SessionTokenDisabled {
+		sessionClients = newSessionClientCache(clientFactory, sessionClientIdleTTL)
+	}
+
+	// The version is also fetched for the metrics, in which case it is only
+	// required when the version check is enabled. The tool requirements are
+	// only checked along with the version.
+	var portainerVersion, portainerEdition string
+	if !opts.disableVersionCheck || opts.metrics != nil {
+		ctx, cancel := newRequestContext(context.Background(), opts.requestTimeout)
+		defer cancel()
+
+		version, err := portainerClient.GetVersion(ctx)
+		if err != nil && !opts.disableVersionCheck {
 			return nil, fmt.Errorf("failed to get Portainer server version: %w", err)
 		}
 
-		if err := checkPortainerVersion(version); err != nil {
-			return nil, err
+		if err == nil && opts.metrics != nil {
+			opts.metrics.setPortainerVersion(opts.defaultInstanceName, version)
 		}
+
+		if !opts.disableVersionCheck {
+			if err := checkPortainerVersion(version); err != nil {
+				return nil, err
+			}
+
+			portainerVersion = version
+			if requiresEdition(requirements) {
+				portainerEdition, err = portainerClient.GetEdition(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get Portainer server edition: %w", err)
+				}
+			}
+		}
+	}
+
+	instancesCtx, cancelInstances := newRequestContext(context.Background(), opts.requestTimeout)
+	defer cancelInstances()
+
+	instances, err := newInstances(instancesCtx, opts, requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	mcpServerOptions := []server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithLogging(),
+		server.WithToolHandlerMiddleware(tracingMiddleware()),
+	}
+
+	if opts.metrics != nil {
+		mcpServerOptions = append(mcpServerOptions, server.WithToolHandlerMiddleware(opts.metrics.toolMiddleware()))
+	}
+
+	mcpServerOptions = append(mcpServerOptions, server.WithToolHandlerMiddleware(requestTimeoutMiddleware(opts.requestTimeout)))
+
+	var confirmations *confirmationStore
+	if opts.confirmDestructive {
+		confirmations = newConfirmationStore(DefaultConfirmationTTL)
+		mcpServerOptions = append(mcpServerOptions, server.WithElicitation())
 	}
 
 	return &PortainerMCPServer{
-		srv: server.NewMCPServer(
-			"Portainer MCP Server",
-			"0.5.1",
-			server.WithToolCapabilities(true),
-			server.WithLogging(),
-		),
-		cli:      portainerClient,
-		tools:    tools,
-		readOnly: opts.readOnly,
+		srv:              server.NewMCPServer("Portainer MCP Server", "0.5.1", mcpServerOptions...),
+		cli:              portainerClient,
+		sessionClients:   sessionClients,
+		sessionTokenMode: opts.sessionTokenMode,
+		tools:            tools,
+		requirements:     requirements,
+		portainerVersion: portainerVersion,
+		portainerEdition: portainerEdition,
+		requestTimeout:   opts.requestTimeout,
+		readOnly:         opts.readOnly,
+		dryRun:           opts.dryRun,
+		audit:            opts.auditLogger,
+		toolFilter:       opts.toolFilter,
+		confirmations:    confirmations,
+		scope:            opts.environmentScope,
+		proxyPolicy:      opts.proxyPolicy,
+		serverURL:        serverURL,
+		defaultInstance:  opts.defaultInstanceName,
+		instances:        instances,
+		cache:            opts.cache != nil,
+		budget:           budget,
+		results:          results,
+		outputFormat:     opts.outputFormat,
 	}, nil
 }
 
+// newRequestContext derives a context bounded by the given timeout.
+// A zero timeout only makes the context cancellable.
+func newRequestContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// requestTimeoutMiddleware bounds every tool call by the given timeout.
+// The tool call context is also cancelled when the client sends an MCP
+// cancellation notification for the request, which aborts any in-flight
+// Portainer API request made by the handler.
+func requestTimeoutMiddleware(timeout time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, cancel := newRequestContext(ctx, timeout)
+			defer cancel()
+
+			return next(ctx, request)
+		}
+	}
+}
+
 // checkPortainerVersion validates that the given Portainer server version
 // falls within the supported range [MinSupportedPortainerVersion, MaxSupportedPortainerVersion].
 func checkPortainerVersion(version string) error {
@@ -286,15 +536,92 @@ func checkPortainerVersion(version string) error {
 
 // Start begins listening for MCP protocol messages on standard input/output.
 // This is a blocking call that will run until the connection is closed.
+// Use Serve to expose the server over a network transport.
 func (s *PortainerMCPServer) Start() error {
 	return server.ServeStdio(s.srv)
 }
 
 // addToolIfExists adds a tool to the server if it exists in the tools map
+// and is allowed by the tool filter. The outcome is recorded for the tool report.
+// The handler is kept, so that the tool can be registered again when the tools
+// file is reloaded.
 func (s *PortainerMCPServer) addToolIfExists(toolName string, handler server.ToolHandlerFunc) {
-	if tool, exists := s.tools[toolName]; exists {
-		s.srv.AddTool(tool, handler)
-	} else {
-		log.Printf("Tool %s not found, will not be registered for MCP usage", toolName)
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+
+	s.handlers = append(s.handlers, toolHandler{name: toolName, handler: handler})
+
+	if serverTool, ok := s.prepareTool(toolName, handler); ok {
+		s.srv.AddTool(serverTool.Tool, serverTool.Handler)
+	}
+}
+
+// prepareTool returns the tool definition and handler to register for a tool,
+// or false when the tool is not defined in the tools map, not allowed by the
+// tool filter or not supported by the version or edition of the Portainer
+// server. The outcome is recorded for the tool report.
+// In dry-run mode, the handler of a tool that may modify Portainer resources
+// is wrapped to plan its changes instead of executing them. Otherwise, when
+// destructive calls must be confirmed, the handler of a destructive tool is
+// wrapped to require a confirmation.
+// When the server is restricted to an environment scope, the handler of a
+// tool targeting environments is wrapped to enforce the scope first.
+// The read tools returning structured content are wrapped to write their
+// results in the selected output format.
+// When a response budget applies to the tool, the handler is wrapped to
+// truncate the results exceeding it.
+// When the audit log is enabled, the handler is wrapped to record its calls.
+func (s *PortainerMCPServer) prepareTool(toolName string, handler server.ToolHandlerFunc) (server.ServerTool, bool) {
+	tool, exists := s.tools[toolName]
+	if !exists {
+		s.recordToolStatus(toolName, false, toolSkippedNotDefined)
+		return server.ServerTool{}, false
+	}
+
+	if allowed, reason := s.toolFilter.allows(toolName); !allowed {
+		s.recordToolStatus(toolName, false, reason)
+		return server.ServerTool{}, false
+	}
+
+	if err := s.checkToolRequirements(toolName); err != nil {
+		s.recordToolStatus(toolName, false, err.Error())
+		return server.ServerTool{}, false
+	}
+
+	if s.dryRun && isMutatingTool(tool) {
+		handler = s.dryRunHandler(tool, handler)
+	} else if s.confirmations != nil && tool.Annotations.DestructiveHint != nil && *tool.Annotations.DestructiveHint {
+		tool = withConfirmationParam(tool)
+		handler = s.confirmationHandler(tool, handler)
+	}
+	if checks, ok := scopeChecks[toolName]; ok && !s.scope.IsEmpty() {
+		handler = s.scopeHandler(checks, handler)
+	}
+	if s.cache && refreshableTools[toolName] {
+		tool = withRefreshParam(tool)
+		handler = refreshHandler(handler)
+	}
+	if listTools[toolName] {
+		tool = withListParams(tool)
+		handler = listHandler(tool, handler)
 	}
+	if formatTool(tool) {
+		tool = withOutputFormatParams(tool, s.defaultOutputFormat())
+		handler = outputFormatHandler(tool, s.defaultOutputFormat(), handler)
+	}
+	if len(s.instances) > 0 && toolName != ToolListInstances && toolName != ToolReadResultPage {
+		tool = withInstanceParam(tool, s.instanceNames(), s.defaultInstance)
+		handler = s.instanceHandler(toolName, s.unsupportedInstances(toolName), handler)
+	}
+	if maxBytes := s.budget.maxBytes(toolName); maxBytes > 0 && toolName != ToolReadResultPage {
+		handler = s.responseBudgetHandler(maxBytes, handler)
+	}
+	// The arguments added by the server are checked by their own handlers
+	handler = validationHandler(s.tools[toolName], handler)
+	if s.audit != nil {
+		handler = s.audit.wrap(tool, handler)
+	}
+
+	s.recordToolStatus(toolName, true, "")
+	return server.ServerTool{Tool: tool, Handler: handler}, true
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -21,6 +23,7 @@ func TestNewPortainerMCPServer(t *testing.T) {
 		serverURL     string
 		token         string
 		toolsPath     string
+		options       []ServerOption
 		mockSetup     func(*MockPortainerClient)
 		expectError   bool
 		errorContains string
@@ -85,6 +88,68 @@ func TestNewPortainerMCPServer(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:          "invalid session token mode",
+			serverURL:     "https://portainer.example.com",
+			token:         "valid-token",
+			toolsPath:     validToolsPath,
+			options:       []ServerOption{WithSessionTokenMode("sometimes")},
+			mockSetup:     func(m *MockPortainerClient) {},
+			expectError:   true,
+			errorContains: "invalid session token mode",
+		},
+		{
+			name:          "negative request timeout",
+			serverURL:     "https://portainer.example.com",
+			token:         "valid-token",
+			toolsPath:     validToolsPath,
+			options:       []ServerOption{WithRequestTimeout(-time.Second)},
+			mockSetup:     func(m *MockPortainerClient) {},
+			expectError:   true,
+			errorContains: "invalid request timeout",
+		},
+		{
+			name:          "invalid TLS configuration",
+			serverURL:     "https://portainer.example.com",
+			token:         "valid-token",
+			toolsPath:     validToolsPath,
+			options:       []ServerOption{WithClientOptions(client.WithMinTLSVersion("0.9"))},
+			mockSetup:     func(m *MockPortainerClient) {},
+			expectError:   true,
+			errorContains: "failed to create Portainer client",
+		},
+		{
+			name:          "invalid tool filter",
+			serverURL:     "https://portainer.example.com",
+			token:         "valid-token",
+			toolsPath:     validToolsPath,
+			options:       []ServerOption{WithToolFilter(ToolFilter{Deny: []string{"dokcer-proxy"}})},
+			mockSetup:     func(m *MockPortainerClient) {},
+			expectError:   true,
+			errorContains: "matches no tool or tool group",
+		},
+		{
+			name:      "metrics with disabled version check and unreachable server",
+			serverURL: "https://portainer.example.com",
+			token:     "valid-token",
+			toolsPath: validToolsPath,
+			options:   []ServerOption{WithMetrics(NewMetrics()), WithDisableVersionCheck(true)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetVersion").Return("", errors.New("connection error"))
+			},
+			expectError: false,
+		},
+		{
+			name:      "session tokens enabled",
+			serverURL: "https://portainer.example.com",
+			token:     "valid-token",
+			toolsPath: validToolsPath,
+			options:   []ServerOption{WithSessionTokenMode(SessionTokenOptional)},
+			mockSetup: func(m *MockPortainerClient) {
+				m.On("GetVersion").Return("2.33.0", nil)
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,6 +161,7 @@ func TestNewPortainerMCPServer(t *testing.T) {
 			// Create server with mock client using the WithClient option
 			var options []ServerOption
 			options = append(options, WithClient(mockClient))
+			options = append(options, tt.options...)
 
 			// Add WithDisableVersionCheck for the specific test case
 			if tt.name == "unsupported version with disabled version check" {
@@ -194,3 +260,43 @@ func TestAddToolIfExists(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		timeout          time.Duration
+		expectedDeadline bool
+	}{
+		{
+			name:             "timeout sets a deadline",
+			timeout:          time.Minute,
+			expectedDeadline: true,
+		},
+		{
+			name:             "zero timeout leaves the context without deadline",
+			timeout:          0,
+			expectedDeadline: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlerCtx context.Context
+			handler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				handlerCtx = ctx
+				return &mcp.CallToolResult{}, nil
+			}
+
+			_, err := requestTimeoutMiddleware(tt.timeout)(handler)(context.Background(), mcp.CallToolRequest{})
+			require.NoError(t, err)
+
+			deadline, ok := handlerCtx.Deadline()
+			assert.Equal(t, tt.expectedDeadline, ok)
+			if tt.expectedDeadline {
+				assert.WithinDuration(t, time.Now().Add(tt.timeout), deadline, time.Second)
+			}
+
+			assert.Error(t, handlerCtx.Err(), "the context should be released once the handler returns")
+		})
+	}
+}
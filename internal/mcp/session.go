@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Session token modes control whether MCP sessions served over a network
+// transport can authenticate against Portainer with their own API key.
+const (
+	// SessionTokenDisabled ignores session tokens, all tool calls use the server token
+	SessionTokenDisabled = "disabled"
+	// SessionTokenOptional uses the session token when present and falls back to the server token
+	SessionTokenOptional = "optional"
+	// SessionTokenRequired rejects HTTP requests that do not carry a session token
+	SessionTokenRequired = "required"
+)
+
+const (
+	// SessionTokenHeader is the HTTP header carrying the Portainer API key of an MCP session
+	SessionTokenHeader = "X-Portainer-API-Key"
+
+	// sessionClientIdleTTL is how long an unused per-session client is kept in the cache
+	sessionClientIdleTTL = 30 * time.Minute
+)
+
+// All available session token modes
+var AllSessionTokenModes = []string{
+	SessionTokenDisabled,
+	SessionTokenOptional,
+	SessionTokenRequired,
+}
+
+// ClientFactory creates a Portainer client authenticated with the given API token
+type ClientFactory func(token string) PortainerClient
+
+// sessionTokenKey is the context key for the Portainer API token of the current MCP session
+type sessionTokenKey struct{}
+
+// isValidSessionTokenMode checks if a given string is a valid session token mode
+func isValidSessionTokenMode(mode string) bool {
+	return slices.Contains(AllSessionTokenModes, mode)
+}
+
+// withSessionToken returns a copy of the context carrying the given session token
+func withSessionToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, sessionTokenKey{}, token)
+}
+
+// sessionTokenFromContext returns the session token carried by the context, if any
+func sessionTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(sessionTokenKey{}).(string)
+	return token
+}
+
+// sessionTokenContextFunc extracts the session token from the HTTP request headers.
+// It satisfies both server.HTTPContextFunc and server.SSEContextFunc.
+func sessionTokenContextFunc(ctx context.Context, r *http.Request) context.Context {
+	if token := r.Header.Get(SessionTokenHeader); token != "" {
+		return withSessionToken(ctx, token)
+	}
+	return ctx
+}
+
+// requireSessionToken rejects HTTP requests that do not carry a session token
+func requireSessionToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(SessionTokenHeader) == "" {
+			http.Error(w, "missing "+SessionTokenHeader+" header", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// client returns the Portainer client to use for the current request.
+// When the request targets an additional Portainer instance, the client of
+// that instance is returned. When the request carries a session token, a client authenticated with that
+// token is returned so that Portainer RBAC applies to the actual caller.
+// Otherwise the server-wide client is returned.
+func (s *PortainerMCPServer) client(ctx context.Context) PortainerClient {
+	if instance := instanceFromContext(ctx); instance != nil {
+		return instance.cli
+	}
+
+	token := sessionTokenFromContext(ctx)
+	if token == "" || s.sessionClients == nil {
+		return s.cli
+	}
+	return s.sessionClients.get(token)
+}
+
+// sessionClientCache holds one Portainer client per session token.
+// Clients that have not been used for sessionClientIdleTTL are evicted.
+type sessionClientCache struct {
+	mu      sync.Mutex
+	factory ClientFactory
+	ttl     time.Duration
+	clients map[string]*sessionClient
+}
+
+// sessionClient is a cached client along with its last usage time
+type sessionClient struct {
+	cli      PortainerClient
+	lastUsed time.Time
+}
+
+// newSessionClientCache creates an empty cache building clients with the given factory
+func newSessionClientCache(factory ClientFactory, ttl time.Duration) *sessionClientCache {
+	return &sessionClientCache{
+		factory: factory,
+		ttl:     ttl,
+		clients: map[string]*sessionClient{},
+	}
+}
+
+// get returns the cached client for the token, creating it if needed
+func (c *sessionClientCache) get(token string) PortainerClient {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, entry := range c.clients {
+		if now.Sub(entry.lastUsed) > c.ttl {
+			delete(c.clients, k)
+		}
+	}
+
+	entry, ok := c.clients[key]
+	if !ok {
+		entry = &sessionClient{cli: c.factory(token)}
+		c.clients[key] = entry
+	}
+	entry.lastUsed = now
+
+	return entry.cli
+}
+
+// len returns the number of cached clients
+func (c *sessionClientCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.clients)
+}
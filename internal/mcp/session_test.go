@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionTokenContextFunc(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		expectedToken string
+	}{
+		{
+			name:          "header present",
+			header:        "session-token",
+			expectedToken: "session-token",
+		},
+		{
+			name:          "header missing",
+			header:        "",
+			expectedToken: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set(SessionTokenHeader, tt.header)
+			}
+
+			ctx := sessionTokenContextFunc(context.Background(), req)
+
+			assert.Equal(t, tt.expectedToken, sessionTokenFromContext(ctx))
+		})
+	}
+}
+
+func TestRequireSessionToken(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := requireSessionToken(next)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set(SessionTokenHeader, "session-token")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestSessionClientCache(t *testing.T) {
+	created := map[string]int{}
+	factory := func(token string) PortainerClient {
+		created[token]++
+		return new(MockPortainerClient)
+	}
+
+	cache := newSessionClientCache(factory, time.Hour)
+
+	first := cache.get("token-a")
+	second := cache.get("token-a")
+	other := cache.get("token-b")
+
+	assert.Same(t, first, second, "the same token should reuse the cached client")
+	assert.NotSame(t, first, other, "different tokens should use different clients")
+	assert.Equal(t, 1, created["token-a"])
+	assert.Equal(t, 1, created["token-b"])
+	assert.Equal(t, 2, cache.len())
+}
+
+func TestSessionClientCacheEviction(t *testing.T) {
+	factory := func(token string) PortainerClient {
+		return new(MockPortainerClient)
+	}
+
+	cache := newSessionClientCache(factory, time.Millisecond)
+
+	cache.get("token-a")
+	time.Sleep(5 * time.Millisecond)
+	cache.get("token-b")
+
+	assert.Equal(t, 1, cache.len(), "idle clients should be evicted")
+}
+
+func TestClientResolution(t *testing.T) {
+	defaultClient := new(MockPortainerClient)
+	sessionClient := new(MockPortainerClient)
+
+	factory := func(token string) PortainerClient {
+		return sessionClient
+	}
+
+	tests := []struct {
+		name           string
+		sessionClients *sessionClientCache
+		token          string
+		expected       PortainerClient
+	}{
+		{
+			name:     "no session token uses the server client",
+			expected: defaultClient,
+		},
+		{
+			name:     "session token ignored when session tokens are disabled",
+			token:    "session-token",
+			expected: defaultClient,
+		},
+		{
+			name:           "session token uses a session client",
+			sessionClients: newSessionClientCache(factory, time.Hour),
+			token:          "session-token",
+			expected:       sessionClient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PortainerMCPServer{
+				cli:            defaultClient,
+				sessionClients: tt.sessionClients,
+			}
+
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = withSessionToken(ctx, tt.token)
+			}
+
+			assert.Same(t, tt.expected, s.client(ctx))
+		})
+	}
+}
+
+func TestHandlerUsesSessionClient(t *testing.T) {
+	defaultClient := new(MockPortainerClient)
+	sessionClient := new(MockPortainerClient)
+	sessionClient.On("GetEnvironments").Return([]models.Environment{{ID: 7, Name: "session-env"}}, nil)
+
+	var receivedToken string
+	factory := func(token string) PortainerClient {
+		receivedToken = token
+		return sessionClient
+	}
+
+	s := &PortainerMCPServer{
+		cli:            defaultClient,
+		sessionClients: newSessionClientCache(factory, time.Hour),
+	}
+
+	ctx := withSessionToken(context.Background(), "caller-token")
+	result, err := s.HandleGetEnvironments()(ctx, CreateMCPRequest(nil))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "session-env")
+	assert.Equal(t, "caller-token", receivedToken)
+
+	sessionClient.AssertExpectations(t)
+	defaultClient.AssertNotCalled(t, "GetEnvironments")
+}
+
+func TestRequiredSessionTokenOverHTTP(t *testing.T) {
+	s := newTransportTestServer()
+	s.sessionTokenMode = SessionTokenRequired
+	httpSrv := &http.Server{}
+
+	_, err := s.newHTTPTransport(TransportConfig{Type: TransportStreamableHTTP}, httpSrv)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(initializeRequest))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	httpSrv.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(initializeRequest))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SessionTokenHeader, "caller-token")
+	rec = httptest.NewRecorder()
+	httpSrv.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddSettingsFeatures() {
@@ -19,7 +18,7 @@ func (s *PortainerMCPServer) AddSettingsFeatures() {
 
 func (s *PortainerMCPServer) HandleGetSettings() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		settings, err := s.cli.GetSettings()
+		settings, err := s.client(ctx).GetSettings(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get settings", err), nil
 		}
@@ -29,20 +28,18 @@ func (s *PortainerMCPServer) HandleGetSettings() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal settings", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(settings, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleUpdateSettings() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		settingsJSON, err := parser.GetString("settingsJSON", true)
+		args, err := ParseUpdateSettingsArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid settingsJSON parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateSettings(settingsJSON)
+		err = s.client(ctx).UpdateSettings(ctx, args.SettingsJSON)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update settings", err), nil
 		}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 )
 
 func (s *PortainerMCPServer) AddStackFeatures() {
@@ -23,30 +23,38 @@ func (s *PortainerMCPServer) AddStackFeatures() {
 
 func (s *PortainerMCPServer) HandleGetStacks() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		stacks, err := s.cli.GetStacks()
+		stacks, err := s.client(ctx).GetStacks(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get stacks", err), nil
 		}
 
+		inScope, err := s.scopedEnvironmentIDs(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		scopedGroups, err := scopedEnvironmentGroupIDs(ctx, s.client(ctx), inScope)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		stacks = filterScoped(scopedGroups, stacks, func(s models.Stack) []int { return s.EnvironmentGroupIds })
+
 		data, err := json.Marshal(stacks)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal stacks", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(stacks, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleGetStackFile() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseGetStackFileArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		stackFile, err := s.cli.GetStackFile(id)
+		stackFile, err := s.client(ctx).GetStackFile(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get stack file", err), nil
 		}
@@ -57,24 +65,12 @@ func (s *PortainerMCPServer) HandleGetStackFile() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleCreateStack() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseCreateStackArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		file, err := parser.GetString("file", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid file parameter", err), nil
-		}
-
-		environmentGroupIds, err := parser.GetArrayOfIntegers("environmentGroupIds", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentGroupIds parameter", err), nil
-		}
-
-		id, err := s.cli.CreateStack(name, file, environmentGroupIds)
+		id, err := s.client(ctx).CreateStack(ctx, args.Name, args.File, args.EnvironmentGroupIDs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("error creating stack", err), nil
 		}
@@ -85,24 +81,12 @@ func (s *PortainerMCPServer) HandleCreateStack() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleUpdateStack() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		file, err := parser.GetString("file", true)
+		args, err := ParseUpdateStackArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid file parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		environmentGroupIds, err := parser.GetArrayOfIntegers("environmentGroupIds", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid environmentGroupIds parameter", err), nil
-		}
-
-		err = s.cli.UpdateStack(id, file, environmentGroupIds)
+		err = s.client(ctx).UpdateStack(ctx, args.ID, args.File, args.EnvironmentGroupIDs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update stack", err), nil
 		}
@@ -113,14 +97,12 @@ func (s *PortainerMCPServer) HandleUpdateStack() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleDeleteEdgeStack() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteStackArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteEdgeStack(id)
+		err = s.client(ctx).DeleteEdgeStack(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete edge stack", err), nil
 		}
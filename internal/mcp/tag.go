@@ -7,7 +7,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddTagFeatures() {
@@ -21,7 +20,7 @@ func (s *PortainerMCPServer) AddTagFeatures() {
 
 func (s *PortainerMCPServer) HandleGetEnvironmentTags() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		environmentTags, err := s.cli.GetEnvironmentTags()
+		environmentTags, err := s.client(ctx).GetEnvironmentTags(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get environment tags", err), nil
 		}
@@ -31,20 +30,18 @@ func (s *PortainerMCPServer) HandleGetEnvironmentTags() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal environment tags", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(environmentTags, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleCreateEnvironmentTag() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseCreateEnvironmentTagArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		id, err := s.cli.CreateEnvironmentTag(name)
+		id, err := s.client(ctx).CreateEnvironmentTag(ctx, args.Name)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create environment tag", err), nil
 		}
@@ -55,14 +52,12 @@ func (s *PortainerMCPServer) HandleCreateEnvironmentTag() server.ToolHandlerFunc
 
 func (s *PortainerMCPServer) HandleDeleteTag() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteTagArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteTag(id)
+		err = s.client(ctx).DeleteTag(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete tag", err), nil
 		}
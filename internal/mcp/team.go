@@ -7,7 +7,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddTeamFeatures() {
@@ -23,14 +22,12 @@ func (s *PortainerMCPServer) AddTeamFeatures() {
 
 func (s *PortainerMCPServer) HandleCreateTeam() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseCreateTeamArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		teamID, err := s.cli.CreateTeam(name)
+		teamID, err := s.client(ctx).CreateTeam(ctx, args.Name)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create team", err), nil
 		}
@@ -41,7 +38,7 @@ func (s *PortainerMCPServer) HandleCreateTeam() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleGetTeams() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		teams, err := s.cli.GetTeams()
+		teams, err := s.client(ctx).GetTeams(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get teams", err), nil
 		}
@@ -51,25 +48,18 @@ func (s *PortainerMCPServer) HandleGetTeams() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal teams", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(teams, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleUpdateTeamName() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		name, err := parser.GetString("name", true)
+		args, err := ParseUpdateTeamNameArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid name parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.UpdateTeamName(id, name)
+		err = s.client(ctx).UpdateTeamName(ctx, args.ID, args.Name)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update team name", err), nil
 		}
@@ -80,19 +70,12 @@ func (s *PortainerMCPServer) HandleUpdateTeamName() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleUpdateTeamMembers() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseUpdateTeamMembersArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		userIDs, err := parser.GetArrayOfIntegers("userIds", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid userIds parameter", err), nil
-		}
-
-		err = s.cli.UpdateTeamMembers(id, userIDs)
+		err = s.client(ctx).UpdateTeamMembers(ctx, args.ID, args.UserIDs)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update team members", err), nil
 		}
@@ -103,14 +86,12 @@ func (s *PortainerMCPServer) HandleUpdateTeamMembers() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleDeleteTeam() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteTeamArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteTeam(id)
+		err = s.client(ctx).DeleteTeam(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete team", err), nil
 		}
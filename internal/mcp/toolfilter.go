@@ -0,0 +1,365 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tool groups, each gathering the tools of one feature
+const (
+	ToolGroupAccessGroups      = "access-groups"
+	ToolGroupAlerting          = "alerting"
+	ToolGroupCustomResources   = "custom-resources"
+	ToolGroupCustomTemplates   = "custom-templates"
+	ToolGroupDockerProxy       = "docker-proxy"
+	ToolGroupDockerStacks      = "docker-stacks"
+	ToolGroupEdgeJobs          = "edge-jobs"
+	ToolGroupEdgeStacks        = "edge-stacks"
+	ToolGroupEnvironmentGroups = "environment-groups"
+	ToolGroupEnvironments      = "environments"
+	ToolGroupGitCredentials    = "git-credentials"
+	ToolGroupInstances         = "instances"
+	ToolGroupKubernetesProxy   = "kubernetes-proxy"
+	ToolGroupPolicies          = "policies"
+	ToolGroupRegistries        = "registries"
+	ToolGroupResults           = "results"
+	ToolGroupSettings          = "settings"
+	ToolGroupTags              = "tags"
+	ToolGroupTeams             = "teams"
+	ToolGroupUsers             = "users"
+	ToolGroupWebhooks          = "webhooks"
+)
+
+// Reasons for which a tool is not registered
+const (
+	toolSkippedNotDefined = "not defined in the tools file"
+	toolSkippedReadOnly   = "write tool not loaded in read-only mode"
+	toolSkippedNoHandler  = "no handler implements this tool"
+	toolSkippedNotAllowed = "not matched by any allow pattern"
+	toolSkippedDenied     = "denied by pattern %q"
+)
+
+// toolGroups maps each tool group to the tools it contains
+var toolGroups = map[string][]string{
+	ToolGroupAccessGroups: {
+		ToolCreateAccessGroup,
+		ToolListAccessGroups,
+		ToolUpdateAccessGroupName,
+		ToolUpdateAccessGroupUserAccesses,
+		ToolUpdateAccessGroupTeamAccesses,
+		ToolAddEnvironmentToAccessGroup,
+		ToolRemoveEnvironmentFromAccessGroup,
+		ToolDeleteAccessGroup,
+	},
+	ToolGroupAlerting: {
+		ToolListAlerts,
+		ToolListAlertRules,
+		ToolGetAlertRule,
+		ToolUpdateAlertRule,
+		ToolDeleteAlertRule,
+		ToolGetAlertingSettings,
+		ToolCreateAlertSilence,
+		ToolDeleteAlertSilence,
+	},
+	ToolGroupCustomResources: {
+		ToolListCustomResourceDefinitions,
+		ToolGetCustomResourceDefinition,
+		ToolDeleteCustomResourceDefinition,
+		ToolListCustomResources,
+		ToolGetCustomResource,
+		ToolDeleteCustomResource,
+	},
+	ToolGroupCustomTemplates: {
+		ToolListCustomTemplates,
+		ToolCreateCustomTemplate,
+		ToolDeleteCustomTemplate,
+	},
+	ToolGroupDockerProxy: {
+		ToolDockerProxy,
+	},
+	ToolGroupDockerStacks: {
+		ToolListDockerStacks,
+		ToolGetDockerStackFile,
+		ToolCreateDockerStack,
+		ToolUpdateDockerStack,
+		ToolDeleteDockerStack,
+		ToolStartDockerStack,
+		ToolStopDockerStack,
+	},
+	ToolGroupEdgeJobs: {
+		ToolListEdgeJobs,
+		ToolGetEdgeJob,
+		ToolCreateEdgeJob,
+		ToolDeleteEdgeJob,
+	},
+	ToolGroupEdgeStacks: {
+		ToolListStacks,
+		ToolGetStackFile,
+		ToolCreateStack,
+		ToolUpdateStack,
+		ToolDeleteStack,
+	},
+	ToolGroupEnvironmentGroups: {
+		ToolCreateEnvironmentGroup,
+		ToolListEnvironmentGroups,
+		ToolUpdateEnvironmentGroupName,
+		ToolUpdateEnvironmentGroupEnvironments,
+		ToolUpdateEnvironmentGroupTags,
+		ToolDeleteEnvironmentGroup,
+	},
+	ToolGroupEnvironments: {
+		ToolListEnvironments,
+		ToolUpdateEnvironment,
+		ToolUpdateEnvironmentTags,
+		ToolUpdateEnvironmentUserAccesses,
+		ToolUpdateEnvironmentTeamAccesses,
+		ToolListAgentVersions,
+	},
+	ToolGroupInstances: {
+		ToolListInstances,
+	},
+	ToolGroupGitCredentials: {
+		ToolListGitCredentials,
+		ToolGetGitCredential,
+		ToolCreateGitCredential,
+		ToolUpdateGitCredential,
+		ToolDeleteGitCredential,
+	},
+	ToolGroupKubernetesProxy: {
+		ToolKubernetesProxy,
+		ToolGetKubernetesResourceStripped,
+	},
+	ToolGroupPolicies: {
+		ToolListPolicies,
+		ToolGetPolicy,
+		ToolCreatePolicy,
+		ToolUpdatePolicy,
+		ToolDeletePolicy,
+		ToolListPolicyTemplates,
+		ToolGetPolicyTemplate,
+		ToolGetPolicyMetadata,
+		ToolGetPolicyConflicts,
+	},
+	ToolGroupRegistries: {
+		ToolListRegistries,
+		ToolCreateRegistry,
+		ToolDeleteRegistry,
+		ToolTestRegistryConnection,
+	},
+	ToolGroupResults: {
+		ToolReadResultPage,
+	},
+	ToolGroupSettings: {
+		ToolGetSettings,
+		ToolUpdateSettings,
+	},
+	ToolGroupTags: {
+		ToolCreateEnvironmentTag,
+		ToolListEnvironmentTags,
+		ToolDeleteTag,
+	},
+	ToolGroupTeams: {
+		ToolCreateTeam,
+		ToolListTeams,
+		ToolUpdateTeamName,
+		ToolUpdateTeamMembers,
+		ToolDeleteTeam,
+	},
+	ToolGroupUsers: {
+		ToolListUsers,
+		ToolUpdateUserRole,
+	},
+	ToolGroupWebhooks: {
+		ToolListWebhooks,
+		ToolCreateWebhook,
+		ToolDeleteWebhook,
+	},
+}
+
+// toolGroupOf returns the group of a tool, or an empty string if it belongs to none
+func toolGroupOf(toolName string) string {
+	for group, tools := range toolGroups {
+		for _, tool := range tools {
+			if tool == toolName {
+				return group
+			}
+		}
+	}
+	return ""
+}
+
+// ToolFilter selects the tools registered by the server.
+//
+// Each pattern is a glob, as supported by path.Match, matched against both
+// the tool name and the name of its group, e.g. "delete*" or "docker-proxy".
+// When Allow is not empty, only the tools matching one of its patterns are
+// registered. Tools matching a Deny pattern are never registered.
+type ToolFilter struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// LoadToolFilter loads a tool filter from a YAML file.
+//
+// Parameters:
+//   - path: The path of a YAML file with allow and deny lists of patterns
+//
+// Returns:
+//   - The tool filter defined in the file
+//   - An error if the file cannot be read or parsed
+func LoadToolFilter(path string) (ToolFilter, error) {
+	var filter ToolFilter
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return filter, fmt.Errorf("failed to read tool filter file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &filter); err != nil {
+		return filter, fmt.Errorf("failed to parse tool filter file: %w", err)
+	}
+
+	return filter, nil
+}
+
+// WithToolFilter restricts the tools registered by the server.
+// The patterns of successive filters are combined.
+func WithToolFilter(filter ToolFilter) ServerOption {
+	return func(opts *serverOptions) {
+		opts.toolFilter.Allow = append(opts.toolFilter.Allow, filter.Allow...)
+		opts.toolFilter.Deny = append(opts.toolFilter.Deny, filter.Deny...)
+	}
+}
+
+// validate checks that every pattern is a valid glob matching at least one
+// of the given tools or a tool group, so that typos do not silently expose tools
+func (f ToolFilter) validate(toolNames []string) error {
+	for _, pattern := range append(append([]string{}, f.Allow...), f.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool filter pattern %q: %w", pattern, err)
+		}
+
+		matched := false
+		for _, name := range toolNames {
+			if f.matches(pattern, name) {
+				matched = true
+				break
+			}
+		}
+		for group := range toolGroups {
+			if ok, _ := path.Match(pattern, group); ok {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return fmt.Errorf("tool filter pattern %q matches no tool or tool group", pattern)
+		}
+	}
+
+	return nil
+}
+
+// allows checks if a tool can be registered.
+// When it cannot, the reason is returned.
+func (f ToolFilter) allows(toolName string) (bool, string) {
+	for _, pattern := range f.Deny {
+		if f.matches(pattern, toolName) {
+			return false, fmt.Sprintf(toolSkippedDenied, pattern)
+		}
+	}
+
+	if len(f.Allow) == 0 {
+		return true, ""
+	}
+
+	for _, pattern := range f.Allow {
+		if f.matches(pattern, toolName) {
+			return true, ""
+		}
+	}
+
+	return false, toolSkippedNotAllowed
+}
+
+// matches checks if a pattern matches the tool name or the name of its group
+func (f ToolFilter) matches(pattern, toolName string) bool {
+	if ok, _ := path.Match(pattern, toolName); ok {
+		return true
+	}
+
+	group := toolGroupOf(toolName)
+	if group == "" {
+		return false
+	}
+
+	ok, _ := path.Match(pattern, group)
+	return ok
+}
+
+// ToolStatus describes whether a tool was registered by the server
+type ToolStatus struct {
+	Name       string
+	Group      string
+	Registered bool
+	// Reason explains why the tool was not registered
+	Reason string
+}
+
+// recordToolStatus records the registration outcome of a tool for the startup report
+func (s *PortainerMCPServer) recordToolStatus(toolName string, registered bool, reason string) {
+	if s.toolStatuses == nil {
+		s.toolStatuses = make(map[string]ToolStatus)
+	}
+
+	s.toolStatuses[toolName] = ToolStatus{
+		Name:       toolName,
+		Group:      toolGroupOf(toolName),
+		Registered: registered,
+		Reason:     reason,
+	}
+}
+
+// ToolReport lists every tool known to the server, sorted by name, along with
+// whether it was registered and, if not, why it was skipped.
+// It should be called once every feature has been added.
+func (s *PortainerMCPServer) ToolReport() []ToolStatus {
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+
+	report := make([]ToolStatus, 0, len(s.tools))
+
+	for _, status := range s.toolStatuses {
+		report = append(report, status)
+	}
+
+	// Tools defined in the tools file but never added are either write tools
+	// skipped by their feature in read-only mode, or unknown to the server
+	for name, tool := range s.tools {
+		if _, ok := s.toolStatuses[name]; ok {
+			continue
+		}
+
+		reason := toolSkippedNoHandler
+		if s.readOnly && !isReadOnlyTool(tool) {
+			reason = toolSkippedReadOnly
+		}
+
+		report = append(report, ToolStatus{
+			Name:   name,
+			Group:  toolGroupOf(name),
+			Reason: reason,
+		})
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Name < report[j].Name
+	})
+
+	return report
+}
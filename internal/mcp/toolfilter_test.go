@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolFilterAllows(t *testing.T) {
+	tests := []struct {
+		name           string
+		filter         ToolFilter
+		toolName       string
+		expectAllowed  bool
+		expectedReason string
+	}{
+		{
+			name:          "empty filter",
+			toolName:      ToolDeleteTag,
+			expectAllowed: true,
+		},
+		{
+			name:           "denied tool name glob",
+			filter:         ToolFilter{Deny: []string{"delete*"}},
+			toolName:       ToolDeleteTag,
+			expectAllowed:  false,
+			expectedReason: `denied by pattern "delete*"`,
+		},
+		{
+			name:          "tool not matching deny patterns",
+			filter:        ToolFilter{Deny: []string{"delete*"}},
+			toolName:      ToolListEnvironmentTags,
+			expectAllowed: true,
+		},
+		{
+			name:           "denied tool group",
+			filter:         ToolFilter{Deny: []string{ToolGroupDockerProxy}},
+			toolName:       ToolDockerProxy,
+			expectAllowed:  false,
+			expectedReason: `denied by pattern "docker-proxy"`,
+		},
+		{
+			name:          "allowed tool group",
+			filter:        ToolFilter{Allow: []string{"*-stacks"}},
+			toolName:      ToolCreateDockerStack,
+			expectAllowed: true,
+		},
+		{
+			name:           "tool missing from allow list",
+			filter:         ToolFilter{Allow: []string{"list*"}},
+			toolName:       ToolDeleteTag,
+			expectAllowed:  false,
+			expectedReason: toolSkippedNotAllowed,
+		},
+		{
+			name:           "deny takes precedence over allow",
+			filter:         ToolFilter{Allow: []string{ToolGroupTags}, Deny: []string{ToolDeleteTag}},
+			toolName:       ToolDeleteTag,
+			expectAllowed:  false,
+			expectedReason: `denied by pattern "deleteTag"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, reason := tt.filter.allows(tt.toolName)
+			assert.Equal(t, tt.expectAllowed, allowed)
+			assert.Equal(t, tt.expectedReason, reason)
+		})
+	}
+}
+
+func TestToolFilterValidate(t *testing.T) {
+	toolNames := []string{ToolListEnvironments, ToolDeleteTag}
+
+	tests := []struct {
+		name          string
+		filter        ToolFilter
+		errorContains string
+	}{
+		{
+			name:   "tool and group patterns",
+			filter: ToolFilter{Allow: []string{"list*", ToolGroupAlerting}, Deny: []string{ToolDeleteTag}},
+		},
+		{
+			name:          "invalid glob",
+			filter:        ToolFilter{Deny: []string{"delete["}},
+			errorContains: "invalid tool filter pattern",
+		},
+		{
+			name:          "pattern matching nothing",
+			filter:        ToolFilter{Deny: []string{"removeEverything"}},
+			errorContains: `tool filter pattern "removeEverything" matches no tool or tool group`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.validate(toolNames)
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestLoadToolFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool-filter.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("allow:\n  - list*\ndeny:\n  - docker-proxy\n"), 0644))
+
+	filter, err := LoadToolFilter(path)
+	require.NoError(t, err)
+	assert.Equal(t, ToolFilter{Allow: []string{"list*"}, Deny: []string{"docker-proxy"}}, filter)
+
+	_, err = LoadToolFilter(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read tool filter file")
+}
+
+func TestWithToolFilterCombinesPatterns(t *testing.T) {
+	opts := &serverOptions{}
+	WithToolFilter(ToolFilter{Allow: []string{"list*"}})(opts)
+	WithToolFilter(ToolFilter{Deny: []string{"docker-proxy"}})(opts)
+
+	assert.Equal(t, ToolFilter{Allow: []string{"list*"}, Deny: []string{"docker-proxy"}}, opts.toolFilter)
+}
+
+func TestToolGroupsCoverDefinedTools(t *testing.T) {
+	tools, err := toolgen.LoadToolsFromYAML("../tooldef/tools.yaml", MinimumToolsVersion)
+	require.NoError(t, err)
+
+	groups := map[string]int{}
+	for _, group := range toolGroups {
+		for _, tool := range group {
+			groups[tool]++
+		}
+	}
+
+	for name := range tools {
+		assert.Equal(t, 1, groups[name], "tool %s must belong to exactly one group", name)
+	}
+}
+
+func TestToolReport(t *testing.T) {
+	s := &PortainerMCPServer{
+		srv: server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		tools: map[string]mcp.Tool{
+			ToolListEnvironmentTags:  newAuditTestTool(ToolListEnvironmentTags, true),
+			ToolCreateEnvironmentTag: newAuditTestTool(ToolCreateEnvironmentTag, false),
+			ToolDockerProxy:          newAuditTestTool(ToolDockerProxy, true),
+		},
+		readOnly:   true,
+		toolFilter: ToolFilter{Deny: []string{ToolGroupDockerProxy}},
+	}
+
+	handler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	s.addToolIfExists(ToolListEnvironmentTags, handler)
+	s.addToolIfExists(ToolDockerProxy, handler)
+	s.addToolIfExists(ToolListUsers, handler)
+
+	assert.Equal(t, []ToolStatus{
+		{Name: ToolCreateEnvironmentTag, Group: ToolGroupTags, Reason: toolSkippedReadOnly},
+		{Name: ToolDockerProxy, Group: ToolGroupDockerProxy, Reason: `denied by pattern "docker-proxy"`},
+		{Name: ToolListEnvironmentTags, Group: ToolGroupTags, Registered: true},
+		{Name: ToolListUsers, Group: ToolGroupUsers, Reason: toolSkippedNotDefined},
+	}, s.ToolReport())
+
+	assert.NotNil(t, s.srv.GetTool(ToolListEnvironmentTags))
+	assert.Nil(t, s.srv.GetTool(ToolDockerProxy))
+}
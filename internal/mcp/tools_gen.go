@@ -0,0 +1,2144 @@
+// Code generated by toolgen from tools.yaml. DO NOT EDIT.
+
+package mcp
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// Tool names as defined in the YAML file
+const (
+	// Access Groups
+	ToolListAccessGroups                 = "listAccessGroups"
+	ToolCreateAccessGroup                = "createAccessGroup"
+	ToolUpdateAccessGroupName            = "updateAccessGroupName"
+	ToolUpdateAccessGroupUserAccesses    = "updateAccessGroupUserAccesses"
+	ToolUpdateAccessGroupTeamAccesses    = "updateAccessGroupTeamAccesses"
+	ToolAddEnvironmentToAccessGroup      = "addEnvironmentToAccessGroup"
+	ToolRemoveEnvironmentFromAccessGroup = "removeEnvironmentFromAccessGroup"
+	ToolDeleteAccessGroup                = "deleteAccessGroup"
+
+	// Environment
+	ToolListEnvironments              = "listEnvironments"
+	ToolListAgentVersions             = "listAgentVersions"
+	ToolUpdateEnvironmentTags         = "updateEnvironmentTags"
+	ToolUpdateEnvironmentUserAccesses = "updateEnvironmentUserAccesses"
+	ToolUpdateEnvironmentTeamAccesses = "updateEnvironmentTeamAccesses"
+	ToolUpdateEnvironment             = "updateEnvironment"
+
+	// Environment Groups
+	ToolCreateEnvironmentGroup             = "createEnvironmentGroup"
+	ToolListEnvironmentGroups              = "listEnvironmentGroups"
+	ToolUpdateEnvironmentGroupName         = "updateEnvironmentGroupName"
+	ToolUpdateEnvironmentGroupEnvironments = "updateEnvironmentGroupEnvironments"
+	ToolUpdateEnvironmentGroupTags         = "updateEnvironmentGroupTags"
+	ToolDeleteEnvironmentGroup             = "deleteEnvironmentGroup"
+
+	// Settings
+	ToolGetSettings    = "getSettings"
+	ToolUpdateSettings = "updateSettings"
+
+	// Stacks
+	ToolListStacks   = "listStacks"
+	ToolGetStackFile = "getStackFile"
+	ToolCreateStack  = "createStack"
+	ToolUpdateStack  = "updateStack"
+	ToolDeleteStack  = "deleteStack"
+
+	// Docker Stacks (Standalone)
+	ToolListDockerStacks   = "listDockerStacks"
+	ToolGetDockerStackFile = "getDockerStackFile"
+	ToolCreateDockerStack  = "createDockerStack"
+	ToolUpdateDockerStack  = "updateDockerStack"
+	ToolDeleteDockerStack  = "deleteDockerStack"
+	ToolStartDockerStack   = "startDockerStack"
+	ToolStopDockerStack    = "stopDockerStack"
+
+	// Tags
+	ToolCreateEnvironmentTag = "createEnvironmentTag"
+	ToolListEnvironmentTags  = "listEnvironmentTags"
+	ToolDeleteTag            = "deleteTag"
+
+	// Teams
+	ToolCreateTeam        = "createTeam"
+	ToolListTeams         = "listTeams"
+	ToolUpdateTeamName    = "updateTeamName"
+	ToolUpdateTeamMembers = "updateTeamMembers"
+	ToolDeleteTeam        = "deleteTeam"
+
+	// Users
+	ToolListUsers      = "listUsers"
+	ToolUpdateUserRole = "updateUserRole"
+
+	// Registries
+	ToolListRegistries = "listRegistries"
+	ToolCreateRegistry = "createRegistry"
+	ToolDeleteRegistry = "deleteRegistry"
+
+	// Edge Jobs
+	ToolListEdgeJobs  = "listEdgeJobs"
+	ToolGetEdgeJob    = "getEdgeJob"
+	ToolCreateEdgeJob = "createEdgeJob"
+	ToolDeleteEdgeJob = "deleteEdgeJob"
+
+	// Custom Templates
+	ToolListCustomTemplates  = "listCustomTemplates"
+	ToolCreateCustomTemplate = "createCustomTemplate"
+	ToolDeleteCustomTemplate = "deleteCustomTemplate"
+
+	// Webhooks
+	ToolListWebhooks  = "listWebhooks"
+	ToolCreateWebhook = "createWebhook"
+	ToolDeleteWebhook = "deleteWebhook"
+
+	// Registries (continued)
+	ToolTestRegistryConnection = "testRegistryConnection"
+
+	// Fleetwide Policies
+	ToolListPolicies        = "listPolicies"
+	ToolGetPolicy           = "getPolicy"
+	ToolCreatePolicy        = "createPolicy"
+	ToolUpdatePolicy        = "updatePolicy"
+	ToolDeletePolicy        = "deletePolicy"
+	ToolListPolicyTemplates = "listPolicyTemplates"
+	ToolGetPolicyTemplate   = "getPolicyTemplate"
+	ToolGetPolicyMetadata   = "getPolicyMetadata"
+	ToolGetPolicyConflicts  = "getPolicyConflicts"
+
+	// Kubernetes Custom Resources
+	ToolListCustomResourceDefinitions  = "listCustomResourceDefinitions"
+	ToolGetCustomResourceDefinition    = "getCustomResourceDefinition"
+	ToolDeleteCustomResourceDefinition = "deleteCustomResourceDefinition"
+	ToolListCustomResources            = "listCustomResources"
+	ToolGetCustomResource              = "getCustomResource"
+	ToolDeleteCustomResource           = "deleteCustomResource"
+
+	// Docker Proxy
+	ToolDockerProxy = "dockerProxy"
+
+	// Kubernetes Proxy
+	ToolKubernetesProxy               = "kubernetesProxy"
+	ToolGetKubernetesResourceStripped = "getKubernetesResourceStripped"
+
+	// Git Credentials (Shared)
+	ToolListGitCredentials  = "listGitCredentials"
+	ToolGetGitCredential    = "getGitCredential"
+	ToolCreateGitCredential = "createGitCredential"
+	ToolUpdateGitCredential = "updateGitCredential"
+	ToolDeleteGitCredential = "deleteGitCredential"
+
+	// Observability / Alerting
+	ToolListAlerts          = "listAlerts"
+	ToolListAlertRules      = "listAlertRules"
+	ToolGetAlertRule        = "getAlertRule"
+	ToolUpdateAlertRule     = "updateAlertRule"
+	ToolDeleteAlertRule     = "deleteAlertRule"
+	ToolGetAlertingSettings = "getAlertingSettings"
+	ToolCreateAlertSilence  = "createAlertSilence"
+	ToolDeleteAlertSilence  = "deleteAlertSilence"
+
+	// Instances
+	ToolListInstances = "listInstances"
+
+	// Results
+	ToolReadResultPage = "readResultPage"
+)
+
+// CreateAccessGroupArgs holds the arguments of the createAccessGroup tool
+type CreateAccessGroupArgs struct {
+	Name           string
+	EnvironmentIDs []int
+}
+
+// ParseCreateAccessGroupArgs parses the arguments of a call of the createAccessGroup tool
+func ParseCreateAccessGroupArgs(request mcp.CallToolRequest) (CreateAccessGroupArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateAccessGroupArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.EnvironmentIDs, err = parser.GetArrayOfIntegers("environmentIds", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateAccessGroupNameArgs holds the arguments of the updateAccessGroupName tool
+type UpdateAccessGroupNameArgs struct {
+	ID   int
+	Name string
+}
+
+// ParseUpdateAccessGroupNameArgs parses the arguments of a call of the updateAccessGroupName tool
+func ParseUpdateAccessGroupNameArgs(request mcp.CallToolRequest) (UpdateAccessGroupNameArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateAccessGroupNameArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateAccessGroupUserAccessesArgs holds the arguments of the updateAccessGroupUserAccesses tool
+type UpdateAccessGroupUserAccessesArgs struct {
+	ID           int
+	UserAccesses []any
+}
+
+// ParseUpdateAccessGroupUserAccessesArgs parses the arguments of a call of the updateAccessGroupUserAccesses tool
+func ParseUpdateAccessGroupUserAccessesArgs(request mcp.CallToolRequest) (UpdateAccessGroupUserAccessesArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateAccessGroupUserAccessesArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.UserAccesses, err = parser.GetArrayOfObjects("userAccesses", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid userAccesses parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateAccessGroupTeamAccessesArgs holds the arguments of the updateAccessGroupTeamAccesses tool
+type UpdateAccessGroupTeamAccessesArgs struct {
+	ID           int
+	TeamAccesses []any
+}
+
+// ParseUpdateAccessGroupTeamAccessesArgs parses the arguments of a call of the updateAccessGroupTeamAccesses tool
+func ParseUpdateAccessGroupTeamAccessesArgs(request mcp.CallToolRequest) (UpdateAccessGroupTeamAccessesArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateAccessGroupTeamAccessesArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.TeamAccesses, err = parser.GetArrayOfObjects("teamAccesses", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid teamAccesses parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// AddEnvironmentToAccessGroupArgs holds the arguments of the addEnvironmentToAccessGroup tool
+type AddEnvironmentToAccessGroupArgs struct {
+	ID            int
+	EnvironmentID int
+}
+
+// ParseAddEnvironmentToAccessGroupArgs parses the arguments of a call of the addEnvironmentToAccessGroup tool
+func ParseAddEnvironmentToAccessGroupArgs(request mcp.CallToolRequest) (AddEnvironmentToAccessGroupArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args AddEnvironmentToAccessGroupArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// RemoveEnvironmentFromAccessGroupArgs holds the arguments of the removeEnvironmentFromAccessGroup tool
+type RemoveEnvironmentFromAccessGroupArgs struct {
+	ID            int
+	EnvironmentID int
+}
+
+// ParseRemoveEnvironmentFromAccessGroupArgs parses the arguments of a call of the removeEnvironmentFromAccessGroup tool
+func ParseRemoveEnvironmentFromAccessGroupArgs(request mcp.CallToolRequest) (RemoveEnvironmentFromAccessGroupArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args RemoveEnvironmentFromAccessGroupArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteAccessGroupArgs holds the arguments of the deleteAccessGroup tool
+type DeleteAccessGroupArgs struct {
+	ID int
+}
+
+// ParseDeleteAccessGroupArgs parses the arguments of a call of the deleteAccessGroup tool
+func ParseDeleteAccessGroupArgs(request mcp.CallToolRequest) (DeleteAccessGroupArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteAccessGroupArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateEnvironmentTagsArgs holds the arguments of the updateEnvironmentTags tool
+type UpdateEnvironmentTagsArgs struct {
+	ID     int
+	TagIDs []int
+}
+
+// ParseUpdateEnvironmentTagsArgs parses the arguments of a call of the updateEnvironmentTags tool
+func ParseUpdateEnvironmentTagsArgs(request mcp.CallToolRequest) (UpdateEnvironmentTagsArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateEnvironmentTagsArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.TagIDs, err = parser.GetArrayOfIntegers("tagIds", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid tagIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateEnvironmentUserAccessesArgs holds the arguments of the updateEnvironmentUserAccesses tool
+type UpdateEnvironmentUserAccessesArgs struct {
+	ID           int
+	UserAccesses []any
+}
+
+// ParseUpdateEnvironmentUserAccessesArgs parses the arguments of a call of the updateEnvironmentUserAccesses tool
+func ParseUpdateEnvironmentUserAccessesArgs(request mcp.CallToolRequest) (UpdateEnvironmentUserAccessesArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateEnvironmentUserAccessesArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.UserAccesses, err = parser.GetArrayOfObjects("userAccesses", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid userAccesses parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateEnvironmentTeamAccessesArgs holds the arguments of the updateEnvironmentTeamAccesses tool
+type UpdateEnvironmentTeamAccessesArgs struct {
+	ID           int
+	TeamAccesses []any
+}
+
+// ParseUpdateEnvironmentTeamAccessesArgs parses the arguments of a call of the updateEnvironmentTeamAccesses tool
+func ParseUpdateEnvironmentTeamAccessesArgs(request mcp.CallToolRequest) (UpdateEnvironmentTeamAccessesArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateEnvironmentTeamAccessesArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.TeamAccesses, err = parser.GetArrayOfObjects("teamAccesses", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid teamAccesses parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateEnvironmentArgs holds the arguments of the updateEnvironment tool
+type UpdateEnvironmentArgs struct {
+	ID        int
+	Name      string
+	PublicURL string
+	GroupID   int
+}
+
+// ParseUpdateEnvironmentArgs parses the arguments of a call of the updateEnvironment tool
+func ParseUpdateEnvironmentArgs(request mcp.CallToolRequest) (UpdateEnvironmentArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateEnvironmentArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.PublicURL, err = parser.GetString("publicURL", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid publicURL parameter: %w", err)
+	}
+
+	args.GroupID, err = parser.GetInt("groupID", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid groupID parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateEnvironmentGroupArgs holds the arguments of the createEnvironmentGroup tool
+type CreateEnvironmentGroupArgs struct {
+	Name           string
+	EnvironmentIDs []int
+}
+
+// ParseCreateEnvironmentGroupArgs parses the arguments of a call of the createEnvironmentGroup tool
+func ParseCreateEnvironmentGroupArgs(request mcp.CallToolRequest) (CreateEnvironmentGroupArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateEnvironmentGroupArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.EnvironmentIDs, err = parser.GetArrayOfIntegers("environmentIds", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateEnvironmentGroupNameArgs holds the arguments of the updateEnvironmentGroupName tool
+type UpdateEnvironmentGroupNameArgs struct {
+	ID   int
+	Name string
+}
+
+// ParseUpdateEnvironmentGroupNameArgs parses the arguments of a call of the updateEnvironmentGroupName tool
+func ParseUpdateEnvironmentGroupNameArgs(request mcp.CallToolRequest) (UpdateEnvironmentGroupNameArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateEnvironmentGroupNameArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateEnvironmentGroupEnvironmentsArgs holds the arguments of the updateEnvironmentGroupEnvironments tool
+type UpdateEnvironmentGroupEnvironmentsArgs struct {
+	ID             int
+	EnvironmentIDs []int
+}
+
+// ParseUpdateEnvironmentGroupEnvironmentsArgs parses the arguments of a call of the updateEnvironmentGroupEnvironments tool
+func ParseUpdateEnvironmentGroupEnvironmentsArgs(request mcp.CallToolRequest) (UpdateEnvironmentGroupEnvironmentsArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateEnvironmentGroupEnvironmentsArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.EnvironmentIDs, err = parser.GetArrayOfIntegers("environmentIds", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateEnvironmentGroupTagsArgs holds the arguments of the updateEnvironmentGroupTags tool
+type UpdateEnvironmentGroupTagsArgs struct {
+	ID     int
+	TagIDs []int
+}
+
+// ParseUpdateEnvironmentGroupTagsArgs parses the arguments of a call of the updateEnvironmentGroupTags tool
+func ParseUpdateEnvironmentGroupTagsArgs(request mcp.CallToolRequest) (UpdateEnvironmentGroupTagsArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateEnvironmentGroupTagsArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.TagIDs, err = parser.GetArrayOfIntegers("tagIds", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid tagIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteEnvironmentGroupArgs holds the arguments of the deleteEnvironmentGroup tool
+type DeleteEnvironmentGroupArgs struct {
+	ID int
+}
+
+// ParseDeleteEnvironmentGroupArgs parses the arguments of a call of the deleteEnvironmentGroup tool
+func ParseDeleteEnvironmentGroupArgs(request mcp.CallToolRequest) (DeleteEnvironmentGroupArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteEnvironmentGroupArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateSettingsArgs holds the arguments of the updateSettings tool
+type UpdateSettingsArgs struct {
+	SettingsJSON string
+}
+
+// ParseUpdateSettingsArgs parses the arguments of a call of the updateSettings tool
+func ParseUpdateSettingsArgs(request mcp.CallToolRequest) (UpdateSettingsArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateSettingsArgs
+	var err error
+
+	args.SettingsJSON, err = parser.GetString("settingsJSON", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid settingsJSON parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetStackFileArgs holds the arguments of the getStackFile tool
+type GetStackFileArgs struct {
+	ID int
+}
+
+// ParseGetStackFileArgs parses the arguments of a call of the getStackFile tool
+func ParseGetStackFileArgs(request mcp.CallToolRequest) (GetStackFileArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetStackFileArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateStackArgs holds the arguments of the createStack tool
+type CreateStackArgs struct {
+	Name                string
+	File                string
+	EnvironmentGroupIDs []int
+}
+
+// ParseCreateStackArgs parses the arguments of a call of the createStack tool
+func ParseCreateStackArgs(request mcp.CallToolRequest) (CreateStackArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateStackArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.File, err = parser.GetString("file", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid file parameter: %w", err)
+	}
+
+	args.EnvironmentGroupIDs, err = parser.GetArrayOfIntegers("environmentGroupIds", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentGroupIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateStackArgs holds the arguments of the updateStack tool
+type UpdateStackArgs struct {
+	ID                  int
+	File                string
+	EnvironmentGroupIDs []int
+}
+
+// ParseUpdateStackArgs parses the arguments of a call of the updateStack tool
+func ParseUpdateStackArgs(request mcp.CallToolRequest) (UpdateStackArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateStackArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.File, err = parser.GetString("file", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid file parameter: %w", err)
+	}
+
+	args.EnvironmentGroupIDs, err = parser.GetArrayOfIntegers("environmentGroupIds", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentGroupIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteStackArgs holds the arguments of the deleteStack tool
+type DeleteStackArgs struct {
+	ID int
+}
+
+// ParseDeleteStackArgs parses the arguments of a call of the deleteStack tool
+func ParseDeleteStackArgs(request mcp.CallToolRequest) (DeleteStackArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteStackArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetDockerStackFileArgs holds the arguments of the getDockerStackFile tool
+type GetDockerStackFileArgs struct {
+	ID int
+}
+
+// ParseGetDockerStackFileArgs parses the arguments of a call of the getDockerStackFile tool
+func ParseGetDockerStackFileArgs(request mcp.CallToolRequest) (GetDockerStackFileArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetDockerStackFileArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateDockerStackArgs holds the arguments of the createDockerStack tool
+type CreateDockerStackArgs struct {
+	EnvironmentID int
+	Name          string
+	File          string
+}
+
+// ParseCreateDockerStackArgs parses the arguments of a call of the createDockerStack tool
+func ParseCreateDockerStackArgs(request mcp.CallToolRequest) (CreateDockerStackArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateDockerStackArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.File, err = parser.GetString("file", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid file parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateDockerStackArgs holds the arguments of the updateDockerStack tool
+type UpdateDockerStackArgs struct {
+	ID            int
+	EnvironmentID int
+	File          string
+	Prune         bool
+	PullImage     bool
+}
+
+// ParseUpdateDockerStackArgs parses the arguments of a call of the updateDockerStack tool
+func ParseUpdateDockerStackArgs(request mcp.CallToolRequest) (UpdateDockerStackArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateDockerStackArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.File, err = parser.GetString("file", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid file parameter: %w", err)
+	}
+
+	args.Prune, err = parser.GetBoolean("prune", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid prune parameter: %w", err)
+	}
+
+	args.PullImage, err = parser.GetBoolean("pullImage", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid pullImage parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteDockerStackArgs holds the arguments of the deleteDockerStack tool
+type DeleteDockerStackArgs struct {
+	ID            int
+	EnvironmentID int
+}
+
+// ParseDeleteDockerStackArgs parses the arguments of a call of the deleteDockerStack tool
+func ParseDeleteDockerStackArgs(request mcp.CallToolRequest) (DeleteDockerStackArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteDockerStackArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// StartDockerStackArgs holds the arguments of the startDockerStack tool
+type StartDockerStackArgs struct {
+	ID            int
+	EnvironmentID int
+}
+
+// ParseStartDockerStackArgs parses the arguments of a call of the startDockerStack tool
+func ParseStartDockerStackArgs(request mcp.CallToolRequest) (StartDockerStackArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args StartDockerStackArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// StopDockerStackArgs holds the arguments of the stopDockerStack tool
+type StopDockerStackArgs struct {
+	ID            int
+	EnvironmentID int
+}
+
+// ParseStopDockerStackArgs parses the arguments of a call of the stopDockerStack tool
+func ParseStopDockerStackArgs(request mcp.CallToolRequest) (StopDockerStackArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args StopDockerStackArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateEnvironmentTagArgs holds the arguments of the createEnvironmentTag tool
+type CreateEnvironmentTagArgs struct {
+	Name string
+}
+
+// ParseCreateEnvironmentTagArgs parses the arguments of a call of the createEnvironmentTag tool
+func ParseCreateEnvironmentTagArgs(request mcp.CallToolRequest) (CreateEnvironmentTagArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateEnvironmentTagArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteTagArgs holds the arguments of the deleteTag tool
+type DeleteTagArgs struct {
+	ID int
+}
+
+// ParseDeleteTagArgs parses the arguments of a call of the deleteTag tool
+func ParseDeleteTagArgs(request mcp.CallToolRequest) (DeleteTagArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteTagArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateTeamArgs holds the arguments of the createTeam tool
+type CreateTeamArgs struct {
+	Name string
+}
+
+// ParseCreateTeamArgs parses the arguments of a call of the createTeam tool
+func ParseCreateTeamArgs(request mcp.CallToolRequest) (CreateTeamArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateTeamArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateTeamNameArgs holds the arguments of the updateTeamName tool
+type UpdateTeamNameArgs struct {
+	ID   int
+	Name string
+}
+
+// ParseUpdateTeamNameArgs parses the arguments of a call of the updateTeamName tool
+func ParseUpdateTeamNameArgs(request mcp.CallToolRequest) (UpdateTeamNameArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateTeamNameArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateTeamMembersArgs holds the arguments of the updateTeamMembers tool
+type UpdateTeamMembersArgs struct {
+	ID      int
+	UserIDs []int
+}
+
+// ParseUpdateTeamMembersArgs parses the arguments of a call of the updateTeamMembers tool
+func ParseUpdateTeamMembersArgs(request mcp.CallToolRequest) (UpdateTeamMembersArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateTeamMembersArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.UserIDs, err = parser.GetArrayOfIntegers("userIds", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid userIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteTeamArgs holds the arguments of the deleteTeam tool
+type DeleteTeamArgs struct {
+	ID int
+}
+
+// ParseDeleteTeamArgs parses the arguments of a call of the deleteTeam tool
+func ParseDeleteTeamArgs(request mcp.CallToolRequest) (DeleteTeamArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteTeamArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateUserRoleArgs holds the arguments of the updateUserRole tool
+type UpdateUserRoleArgs struct {
+	ID   int
+	Role string
+}
+
+// ParseUpdateUserRoleArgs parses the arguments of a call of the updateUserRole tool
+func ParseUpdateUserRoleArgs(request mcp.CallToolRequest) (UpdateUserRoleArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateUserRoleArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.Role, err = parser.GetString("role", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid role parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateRegistryArgs holds the arguments of the createRegistry tool
+type CreateRegistryArgs struct {
+	Name           string
+	Type           int
+	URL            string
+	Authentication bool
+	Username       string
+	Password       string
+}
+
+// ParseCreateRegistryArgs parses the arguments of a call of the createRegistry tool
+func ParseCreateRegistryArgs(request mcp.CallToolRequest) (CreateRegistryArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateRegistryArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.Type, err = parser.GetInt("type", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid type parameter: %w", err)
+	}
+
+	args.URL, err = parser.GetString("url", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid url parameter: %w", err)
+	}
+
+	args.Authentication, err = parser.GetBoolean("authentication", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid authentication parameter: %w", err)
+	}
+
+	args.Username, err = parser.GetString("username", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid username parameter: %w", err)
+	}
+
+	args.Password, err = parser.GetString("password", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid password parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteRegistryArgs holds the arguments of the deleteRegistry tool
+type DeleteRegistryArgs struct {
+	ID int
+}
+
+// ParseDeleteRegistryArgs parses the arguments of a call of the deleteRegistry tool
+func ParseDeleteRegistryArgs(request mcp.CallToolRequest) (DeleteRegistryArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteRegistryArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetEdgeJobArgs holds the arguments of the getEdgeJob tool
+type GetEdgeJobArgs struct {
+	ID int
+}
+
+// ParseGetEdgeJobArgs parses the arguments of a call of the getEdgeJob tool
+func ParseGetEdgeJobArgs(request mcp.CallToolRequest) (GetEdgeJobArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetEdgeJobArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateEdgeJobArgs holds the arguments of the createEdgeJob tool
+type CreateEdgeJobArgs struct {
+	Name           string
+	CronExpression string
+	Recurring      bool
+	ScriptContent  string
+	EdgeGroupIDs   []int
+}
+
+// ParseCreateEdgeJobArgs parses the arguments of a call of the createEdgeJob tool
+func ParseCreateEdgeJobArgs(request mcp.CallToolRequest) (CreateEdgeJobArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateEdgeJobArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.CronExpression, err = parser.GetString("cronExpression", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid cronExpression parameter: %w", err)
+	}
+
+	args.Recurring, err = parser.GetBoolean("recurring", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid recurring parameter: %w", err)
+	}
+
+	args.ScriptContent, err = parser.GetString("scriptContent", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid scriptContent parameter: %w", err)
+	}
+
+	args.EdgeGroupIDs, err = parser.GetArrayOfIntegers("edgeGroupIds", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid edgeGroupIds parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteEdgeJobArgs holds the arguments of the deleteEdgeJob tool
+type DeleteEdgeJobArgs struct {
+	ID int
+}
+
+// ParseDeleteEdgeJobArgs parses the arguments of a call of the deleteEdgeJob tool
+func ParseDeleteEdgeJobArgs(request mcp.CallToolRequest) (DeleteEdgeJobArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteEdgeJobArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateCustomTemplateArgs holds the arguments of the createCustomTemplate tool
+type CreateCustomTemplateArgs struct {
+	Title       string
+	Description string
+	FileContent string
+	Type        int
+	Platform    int
+}
+
+// ParseCreateCustomTemplateArgs parses the arguments of a call of the createCustomTemplate tool
+func ParseCreateCustomTemplateArgs(request mcp.CallToolRequest) (CreateCustomTemplateArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateCustomTemplateArgs
+	var err error
+
+	args.Title, err = parser.GetString("title", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid title parameter: %w", err)
+	}
+
+	args.Description, err = parser.GetString("description", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid description parameter: %w", err)
+	}
+
+	args.FileContent, err = parser.GetString("fileContent", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid fileContent parameter: %w", err)
+	}
+
+	args.Type, err = parser.GetInt("type", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid type parameter: %w", err)
+	}
+
+	args.Platform, err = parser.GetInt("platform", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid platform parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteCustomTemplateArgs holds the arguments of the deleteCustomTemplate tool
+type DeleteCustomTemplateArgs struct {
+	ID int
+}
+
+// ParseDeleteCustomTemplateArgs parses the arguments of a call of the deleteCustomTemplate tool
+func ParseDeleteCustomTemplateArgs(request mcp.CallToolRequest) (DeleteCustomTemplateArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteCustomTemplateArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateWebhookArgs holds the arguments of the createWebhook tool
+type CreateWebhookArgs struct {
+	ResourceID  string
+	EndpointID  int
+	WebhookType int
+}
+
+// ParseCreateWebhookArgs parses the arguments of a call of the createWebhook tool
+func ParseCreateWebhookArgs(request mcp.CallToolRequest) (CreateWebhookArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateWebhookArgs
+	var err error
+
+	args.ResourceID, err = parser.GetString("resourceId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid resourceId parameter: %w", err)
+	}
+
+	args.EndpointID, err = parser.GetInt("endpointId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid endpointId parameter: %w", err)
+	}
+
+	args.WebhookType, err = parser.GetInt("webhookType", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid webhookType parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteWebhookArgs holds the arguments of the deleteWebhook tool
+type DeleteWebhookArgs struct {
+	ID int
+}
+
+// ParseDeleteWebhookArgs parses the arguments of a call of the deleteWebhook tool
+func ParseDeleteWebhookArgs(request mcp.CallToolRequest) (DeleteWebhookArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteWebhookArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// TestRegistryConnectionArgs holds the arguments of the testRegistryConnection tool
+type TestRegistryConnectionArgs struct {
+	URL      string
+	Type     int
+	Username string
+	Password string
+}
+
+// ParseTestRegistryConnectionArgs parses the arguments of a call of the testRegistryConnection tool
+func ParseTestRegistryConnectionArgs(request mcp.CallToolRequest) (TestRegistryConnectionArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args TestRegistryConnectionArgs
+	var err error
+
+	args.URL, err = parser.GetString("url", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid url parameter: %w", err)
+	}
+
+	args.Type, err = parser.GetInt("type", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid type parameter: %w", err)
+	}
+
+	args.Username, err = parser.GetString("username", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid username parameter: %w", err)
+	}
+
+	args.Password, err = parser.GetString("password", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid password parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetPolicyArgs holds the arguments of the getPolicy tool
+type GetPolicyArgs struct {
+	ID int
+}
+
+// ParseGetPolicyArgs parses the arguments of a call of the getPolicy tool
+func ParseGetPolicyArgs(request mcp.CallToolRequest) (GetPolicyArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetPolicyArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreatePolicyArgs holds the arguments of the createPolicy tool
+type CreatePolicyArgs struct {
+	Name              string
+	Type              string
+	EnvironmentType   string
+	EnvironmentGroups []int
+	DataJSON          string
+}
+
+// ParseCreatePolicyArgs parses the arguments of a call of the createPolicy tool
+func ParseCreatePolicyArgs(request mcp.CallToolRequest) (CreatePolicyArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreatePolicyArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.Type, err = parser.GetString("type", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid type parameter: %w", err)
+	}
+
+	args.EnvironmentType, err = parser.GetString("environmentType", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentType parameter: %w", err)
+	}
+
+	args.EnvironmentGroups, err = parser.GetArrayOfIntegers("environmentGroups", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentGroups parameter: %w", err)
+	}
+
+	args.DataJSON, err = parser.GetString("dataJSON", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid dataJSON parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdatePolicyArgs holds the arguments of the updatePolicy tool
+type UpdatePolicyArgs struct {
+	ID                int
+	Name              string
+	Type              string
+	EnvironmentType   string
+	EnvironmentGroups []int
+	DataJSON          string
+}
+
+// ParseUpdatePolicyArgs parses the arguments of a call of the updatePolicy tool
+func ParseUpdatePolicyArgs(request mcp.CallToolRequest) (UpdatePolicyArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdatePolicyArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.Type, err = parser.GetString("type", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid type parameter: %w", err)
+	}
+
+	args.EnvironmentType, err = parser.GetString("environmentType", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentType parameter: %w", err)
+	}
+
+	args.EnvironmentGroups, err = parser.GetArrayOfIntegers("environmentGroups", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentGroups parameter: %w", err)
+	}
+
+	args.DataJSON, err = parser.GetString("dataJSON", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid dataJSON parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeletePolicyArgs holds the arguments of the deletePolicy tool
+type DeletePolicyArgs struct {
+	ID int
+}
+
+// ParseDeletePolicyArgs parses the arguments of a call of the deletePolicy tool
+func ParseDeletePolicyArgs(request mcp.CallToolRequest) (DeletePolicyArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeletePolicyArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// ListPolicyTemplatesArgs holds the arguments of the listPolicyTemplates tool
+type ListPolicyTemplatesArgs struct {
+	Category string
+	Type     string
+}
+
+// ParseListPolicyTemplatesArgs parses the arguments of a call of the listPolicyTemplates tool
+func ParseListPolicyTemplatesArgs(request mcp.CallToolRequest) (ListPolicyTemplatesArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args ListPolicyTemplatesArgs
+	var err error
+
+	args.Category, err = parser.GetString("category", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid category parameter: %w", err)
+	}
+
+	args.Type, err = parser.GetString("type", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid type parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetPolicyTemplateArgs holds the arguments of the getPolicyTemplate tool
+type GetPolicyTemplateArgs struct {
+	ID string
+}
+
+// ParseGetPolicyTemplateArgs parses the arguments of a call of the getPolicyTemplate tool
+func ParseGetPolicyTemplateArgs(request mcp.CallToolRequest) (GetPolicyTemplateArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetPolicyTemplateArgs
+	var err error
+
+	args.ID, err = parser.GetString("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetPolicyConflictsArgs holds the arguments of the getPolicyConflicts tool
+type GetPolicyConflictsArgs struct {
+	Name              string
+	Type              string
+	EnvironmentType   string
+	EnvironmentGroups []int
+	DataJSON          string
+}
+
+// ParseGetPolicyConflictsArgs parses the arguments of a call of the getPolicyConflicts tool
+func ParseGetPolicyConflictsArgs(request mcp.CallToolRequest) (GetPolicyConflictsArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetPolicyConflictsArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.Type, err = parser.GetString("type", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid type parameter: %w", err)
+	}
+
+	args.EnvironmentType, err = parser.GetString("environmentType", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentType parameter: %w", err)
+	}
+
+	args.EnvironmentGroups, err = parser.GetArrayOfIntegers("environmentGroups", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentGroups parameter: %w", err)
+	}
+
+	args.DataJSON, err = parser.GetString("dataJSON", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid dataJSON parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// ListCustomResourceDefinitionsArgs holds the arguments of the listCustomResourceDefinitions tool
+type ListCustomResourceDefinitionsArgs struct {
+	EnvironmentID int
+}
+
+// ParseListCustomResourceDefinitionsArgs parses the arguments of a call of the listCustomResourceDefinitions tool
+func ParseListCustomResourceDefinitionsArgs(request mcp.CallToolRequest) (ListCustomResourceDefinitionsArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args ListCustomResourceDefinitionsArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetCustomResourceDefinitionArgs holds the arguments of the getCustomResourceDefinition tool
+type GetCustomResourceDefinitionArgs struct {
+	EnvironmentID int
+	Name          string
+}
+
+// ParseGetCustomResourceDefinitionArgs parses the arguments of a call of the getCustomResourceDefinition tool
+func ParseGetCustomResourceDefinitionArgs(request mcp.CallToolRequest) (GetCustomResourceDefinitionArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetCustomResourceDefinitionArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteCustomResourceDefinitionArgs holds the arguments of the deleteCustomResourceDefinition tool
+type DeleteCustomResourceDefinitionArgs struct {
+	EnvironmentID int
+	Name          string
+}
+
+// ParseDeleteCustomResourceDefinitionArgs parses the arguments of a call of the deleteCustomResourceDefinition tool
+func ParseDeleteCustomResourceDefinitionArgs(request mcp.CallToolRequest) (DeleteCustomResourceDefinitionArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteCustomResourceDefinitionArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// ListCustomResourcesArgs holds the arguments of the listCustomResources tool
+type ListCustomResourcesArgs struct {
+	EnvironmentID int
+	Definition    string
+}
+
+// ParseListCustomResourcesArgs parses the arguments of a call of the listCustomResources tool
+func ParseListCustomResourcesArgs(request mcp.CallToolRequest) (ListCustomResourcesArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args ListCustomResourcesArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.Definition, err = parser.GetString("definition", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid definition parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetCustomResourceArgs holds the arguments of the getCustomResource tool
+type GetCustomResourceArgs struct {
+	EnvironmentID int
+	Name          string
+	Definition    string
+	Namespace     string
+	Format        string
+}
+
+// ParseGetCustomResourceArgs parses the arguments of a call of the getCustomResource tool
+func ParseGetCustomResourceArgs(request mcp.CallToolRequest) (GetCustomResourceArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetCustomResourceArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.Definition, err = parser.GetString("definition", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid definition parameter: %w", err)
+	}
+
+	args.Namespace, err = parser.GetString("namespace", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid namespace parameter: %w", err)
+	}
+
+	args.Format, err = parser.GetString("format", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid format parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteCustomResourceArgs holds the arguments of the deleteCustomResource tool
+type DeleteCustomResourceArgs struct {
+	EnvironmentID int
+	Name          string
+	Definition    string
+	Namespace     string
+}
+
+// ParseDeleteCustomResourceArgs parses the arguments of a call of the deleteCustomResource tool
+func ParseDeleteCustomResourceArgs(request mcp.CallToolRequest) (DeleteCustomResourceArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteCustomResourceArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.Definition, err = parser.GetString("definition", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid definition parameter: %w", err)
+	}
+
+	args.Namespace, err = parser.GetString("namespace", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid namespace parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DockerProxyArgs holds the arguments of the dockerProxy tool
+type DockerProxyArgs struct {
+	EnvironmentID int
+	Method        string
+	DockerAPIPath string
+	QueryParams   []any
+	Headers       []any
+	Body          string
+}
+
+// ParseDockerProxyArgs parses the arguments of a call of the dockerProxy tool
+func ParseDockerProxyArgs(request mcp.CallToolRequest) (DockerProxyArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DockerProxyArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.Method, err = parser.GetString("method", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid method parameter: %w", err)
+	}
+
+	args.DockerAPIPath, err = parser.GetString("dockerAPIPath", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid dockerAPIPath parameter: %w", err)
+	}
+
+	args.QueryParams, err = parser.GetArrayOfObjects("queryParams", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid queryParams parameter: %w", err)
+	}
+
+	args.Headers, err = parser.GetArrayOfObjects("headers", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid headers parameter: %w", err)
+	}
+
+	args.Body, err = parser.GetString("body", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid body parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// KubernetesProxyArgs holds the arguments of the kubernetesProxy tool
+type KubernetesProxyArgs struct {
+	EnvironmentID     int
+	Method            string
+	KubernetesAPIPath string
+	QueryParams       []any
+	Headers           []any
+	Body              string
+}
+
+// ParseKubernetesProxyArgs parses the arguments of a call of the kubernetesProxy tool
+func ParseKubernetesProxyArgs(request mcp.CallToolRequest) (KubernetesProxyArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args KubernetesProxyArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.Method, err = parser.GetString("method", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid method parameter: %w", err)
+	}
+
+	args.KubernetesAPIPath, err = parser.GetString("kubernetesAPIPath", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid kubernetesAPIPath parameter: %w", err)
+	}
+
+	args.QueryParams, err = parser.GetArrayOfObjects("queryParams", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid queryParams parameter: %w", err)
+	}
+
+	args.Headers, err = parser.GetArrayOfObjects("headers", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid headers parameter: %w", err)
+	}
+
+	args.Body, err = parser.GetString("body", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid body parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetKubernetesResourceStrippedArgs holds the arguments of the getKubernetesResourceStripped tool
+type GetKubernetesResourceStrippedArgs struct {
+	EnvironmentID     int
+	KubernetesAPIPath string
+	QueryParams       []any
+	Headers           []any
+}
+
+// ParseGetKubernetesResourceStrippedArgs parses the arguments of a call of the getKubernetesResourceStripped tool
+func ParseGetKubernetesResourceStrippedArgs(request mcp.CallToolRequest) (GetKubernetesResourceStrippedArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetKubernetesResourceStrippedArgs
+	var err error
+
+	args.EnvironmentID, err = parser.GetInt("environmentId", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid environmentId parameter: %w", err)
+	}
+
+	args.KubernetesAPIPath, err = parser.GetString("kubernetesAPIPath", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid kubernetesAPIPath parameter: %w", err)
+	}
+
+	args.QueryParams, err = parser.GetArrayOfObjects("queryParams", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid queryParams parameter: %w", err)
+	}
+
+	args.Headers, err = parser.GetArrayOfObjects("headers", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid headers parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetGitCredentialArgs holds the arguments of the getGitCredential tool
+type GetGitCredentialArgs struct {
+	ID int
+}
+
+// ParseGetGitCredentialArgs parses the arguments of a call of the getGitCredential tool
+func ParseGetGitCredentialArgs(request mcp.CallToolRequest) (GetGitCredentialArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetGitCredentialArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateGitCredentialArgs holds the arguments of the createGitCredential tool
+type CreateGitCredentialArgs struct {
+	Name              string
+	Username          string
+	Password          string
+	AuthorizationType int
+}
+
+// ParseCreateGitCredentialArgs parses the arguments of a call of the createGitCredential tool
+func ParseCreateGitCredentialArgs(request mcp.CallToolRequest) (CreateGitCredentialArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateGitCredentialArgs
+	var err error
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.Username, err = parser.GetString("username", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid username parameter: %w", err)
+	}
+
+	args.Password, err = parser.GetString("password", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid password parameter: %w", err)
+	}
+
+	args.AuthorizationType, err = parser.GetInt("authorizationType", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid authorizationType parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateGitCredentialArgs holds the arguments of the updateGitCredential tool
+type UpdateGitCredentialArgs struct {
+	ID                int
+	Name              string
+	Username          string
+	Password          string
+	AuthorizationType int
+}
+
+// ParseUpdateGitCredentialArgs parses the arguments of a call of the updateGitCredential tool
+func ParseUpdateGitCredentialArgs(request mcp.CallToolRequest) (UpdateGitCredentialArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateGitCredentialArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.Name, err = parser.GetString("name", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid name parameter: %w", err)
+	}
+
+	args.Username, err = parser.GetString("username", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid username parameter: %w", err)
+	}
+
+	args.Password, err = parser.GetString("password", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid password parameter: %w", err)
+	}
+
+	args.AuthorizationType, err = parser.GetInt("authorizationType", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid authorizationType parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteGitCredentialArgs holds the arguments of the deleteGitCredential tool
+type DeleteGitCredentialArgs struct {
+	ID int
+}
+
+// ParseDeleteGitCredentialArgs parses the arguments of a call of the deleteGitCredential tool
+func ParseDeleteGitCredentialArgs(request mcp.CallToolRequest) (DeleteGitCredentialArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteGitCredentialArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// ListAlertsArgs holds the arguments of the listAlerts tool
+type ListAlertsArgs struct {
+	Status string
+}
+
+// ParseListAlertsArgs parses the arguments of a call of the listAlerts tool
+func ParseListAlertsArgs(request mcp.CallToolRequest) (ListAlertsArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args ListAlertsArgs
+	var err error
+
+	args.Status, err = parser.GetString("status", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid status parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// GetAlertRuleArgs holds the arguments of the getAlertRule tool
+type GetAlertRuleArgs struct {
+	ID int
+}
+
+// ParseGetAlertRuleArgs parses the arguments of a call of the getAlertRule tool
+func ParseGetAlertRuleArgs(request mcp.CallToolRequest) (GetAlertRuleArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args GetAlertRuleArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// UpdateAlertRuleArgs holds the arguments of the updateAlertRule tool
+type UpdateAlertRuleArgs struct {
+	ID       int
+	RuleJSON string
+}
+
+// ParseUpdateAlertRuleArgs parses the arguments of a call of the updateAlertRule tool
+func ParseUpdateAlertRuleArgs(request mcp.CallToolRequest) (UpdateAlertRuleArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args UpdateAlertRuleArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	args.RuleJSON, err = parser.GetString("ruleJSON", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid ruleJSON parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteAlertRuleArgs holds the arguments of the deleteAlertRule tool
+type DeleteAlertRuleArgs struct {
+	ID int
+}
+
+// ParseDeleteAlertRuleArgs parses the arguments of a call of the deleteAlertRule tool
+func ParseDeleteAlertRuleArgs(request mcp.CallToolRequest) (DeleteAlertRuleArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteAlertRuleArgs
+	var err error
+
+	args.ID, err = parser.GetInt("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// CreateAlertSilenceArgs holds the arguments of the createAlertSilence tool
+type CreateAlertSilenceArgs struct {
+	SilenceJSON     string
+	AlertManagerURL string
+}
+
+// ParseCreateAlertSilenceArgs parses the arguments of a call of the createAlertSilence tool
+func ParseCreateAlertSilenceArgs(request mcp.CallToolRequest) (CreateAlertSilenceArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args CreateAlertSilenceArgs
+	var err error
+
+	args.SilenceJSON, err = parser.GetString("silenceJSON", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid silenceJSON parameter: %w", err)
+	}
+
+	args.AlertManagerURL, err = parser.GetString("alertManagerURL", false)
+	if err != nil {
+		return args, fmt.Errorf("invalid alertManagerURL parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// DeleteAlertSilenceArgs holds the arguments of the deleteAlertSilence tool
+type DeleteAlertSilenceArgs struct {
+	ID string
+}
+
+// ParseDeleteAlertSilenceArgs parses the arguments of a call of the deleteAlertSilence tool
+func ParseDeleteAlertSilenceArgs(request mcp.CallToolRequest) (DeleteAlertSilenceArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args DeleteAlertSilenceArgs
+	var err error
+
+	args.ID, err = parser.GetString("id", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid id parameter: %w", err)
+	}
+
+	return args, nil
+}
+
+// ReadResultPageArgs holds the arguments of the readResultPage tool
+type ReadResultPageArgs struct {
+	Handle string
+}
+
+// ParseReadResultPageArgs parses the arguments of a call of the readResultPage tool
+func ParseReadResultPageArgs(request mcp.CallToolRequest) (ReadResultPageArgs, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args ReadResultPageArgs
+	var err error
+
+	args.Handle, err = parser.GetString("handle", true)
+	if err != nil {
+		return args, fmt.Errorf("invalid handle parameter: %w", err)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"os"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedToolsUpToDate(t *testing.T) {
+	code, err := toolgen.GenerateCode(tooldef.ToolsFile, "tools.yaml", "mcp")
+	require.NoError(t, err)
+
+	generated, err := os.ReadFile("tools_gen.go")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(code), string(generated), "tools_gen.go is outdated, run go generate ./internal/mcp")
+}
+
+func TestToolHandlersInSync(t *testing.T) {
+	config, err := tooldef.EmbeddedTools()
+	require.NoError(t, err)
+
+	assert.NoError(t, toolgen.CheckToolHandlers(config, ".", "addToolIfExists", "tools_gen.go"))
+}
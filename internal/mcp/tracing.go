@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the OpenTelemetry tracer of the MCP server
+	TracerName = "github.com/portainer/portainer-mcp/internal/mcp"
+
+	// defaultServiceName is the service name of the traces, unless set with OTEL_SERVICE_NAME
+	defaultServiceName = "portainer-mcp"
+)
+
+// SetupTracing configures the export of traces with the OTLP exporter when
+// it is enabled by the standard OpenTelemetry environment variables: tracing
+// is enabled when OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT
+// is set, unless OTEL_SDK_DISABLED is true or OTEL_TRACES_EXPORTER is none.
+// The exporter, resource and sampler read the other standard variables, such as
+// OTEL_EXPORTER_OTLP_PROTOCOL, OTEL_EXPORTER_OTLP_HEADERS, OTEL_SERVICE_NAME or
+// OTEL_TRACES_SAMPLER.
+//
+// Parameters:
+//   - ctx: The context used to create the exporter
+//   - version: The version of the MCP server, recorded as the service version
+//
+// Returns:
+//   - A function flushing and stopping the export of traces, or a no-op function when tracing is disabled
+//   - Whether tracing is enabled
+//   - An error if the exporter cannot be created
+func SetupTracing(ctx context.Context, version string) (func(context.Context) error, bool, error) {
+	noop := func(context.Context) error { return nil }
+
+	if !tracingEnabled() {
+		return noop, false, nil
+	}
+
+	exporter, err := newTraceExporter(ctx)
+	if err != nil {
+		return noop, false, err
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(semconv.ServiceVersion(version)),
+	)
+	if err != nil {
+		return noop, false, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence over the default service name
+	if os.Getenv("OTEL_SERVICE_NAME") == "" && !strings.Contains(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"), string(semconv.ServiceNameKey)+"=") {
+		res, err = resource.Merge(res, resource.NewSchemaless(semconv.ServiceName(defaultServiceName)))
+		if err != nil {
+			return noop, false, fmt.Errorf("failed to create trace resource: %w", err)
+		}
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, true, nil
+}
+
+// tracingEnabled checks if the environment enables the export of traces
+func tracingEnabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+
+	if strings.EqualFold(os.Getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return false
+	}
+
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// newTraceExporter creates the OTLP exporter for the protocol set in the environment.
+// Traces are exported over HTTP with protobuf unless the protocol is grpc.
+func newTraceExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+
+	switch protocol {
+	case "", "http/protobuf":
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP HTTP trace exporter: %w", err)
+		}
+		return exporter, nil
+	case "grpc":
+		exporter, err := otlptracegrpc.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP gRPC trace exporter: %w", err)
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol: %s, must be http/protobuf or grpc", protocol)
+	}
+}
+
+// tracingMiddleware opens a span for every tool call, carrying the tool name
+// and the identifiers passed as arguments, such as environmentId or id.
+// The Portainer API requests made by the handler are recorded as child spans.
+func tracingMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tool := request.Params.Name
+
+			ctx, span := otel.Tracer(TracerName).Start(ctx, "tools/call "+tool, trace.WithSpanKind(trace.SpanKindServer))
+			defer span.End()
+
+			span.SetAttributes(attribute.String("mcp.method.name", "tools/call"), attribute.String("gen_ai.tool.name", tool))
+			span.SetAttributes(identifierAttributes(request.GetArguments())...)
+
+			result, err := next(ctx, request)
+
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case result != nil && result.IsError:
+				span.SetStatus(codes.Error, resultText(result))
+			}
+
+			return result, err
+		}
+	}
+}
+
+// identifierAttributes returns the span attributes of the identifier arguments of a tool call:
+// the numeric id argument and the arguments named *Id, such as environmentId or stackId
+func identifierAttributes(args map[string]any) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+	for name, value := range args {
+		if name != "id" && !strings.HasSuffix(name, "Id") {
+			continue
+		}
+
+		if id, ok := value.(float64); ok {
+			attributes = append(attributes, attribute.Int64("portainer."+name, int64(id)))
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{
+			name:     "no endpoint",
+			expected: false,
+		},
+		{
+			name:     "endpoint",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"},
+			expected: true,
+		},
+		{
+			name:     "traces endpoint",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces"},
+			expected: true,
+		},
+		{
+			name:     "disabled SDK",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_SDK_DISABLED": "true"},
+			expected: false,
+		},
+		{
+			name:     "no traces exporter",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_TRACES_EXPORTER": "none"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			assert.Equal(t, tt.expected, tracingEnabled())
+		})
+	}
+}
+
+func TestSetupTracingUnsupportedProtocol(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+
+	_, enabled, err := SetupTracing(context.Background(), "1.0.0")
+	assert.ErrorContains(t, err, "unsupported OTLP protocol: http/json")
+	assert.False(t, enabled)
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var handlerSpan trace.SpanContext
+	handler := tracingMiddleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return mcp.NewToolResultError("failed to delete docker stack"), nil
+	})
+
+	request := CreateMCPRequest(map[string]any{"id": float64(3), "environmentId": float64(2), "name": "web"})
+	request.Params.Name = ToolDeleteDockerStack
+
+	_, err := handler(context.Background(), request)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	assert.Equal(t, "tools/call "+ToolDeleteDockerStack, span.Name())
+	assert.Equal(t, span.SpanContext(), handlerSpan)
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, "failed to delete docker stack", span.Status().Description)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("mcp.method.name", "tools/call"),
+		attribute.String("gen_ai.tool.name", ToolDeleteDockerStack),
+		attribute.Int64("portainer.id", 3),
+		attribute.Int64("portainer.environmentId", 2),
+	}, span.Attributes())
+}
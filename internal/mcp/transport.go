@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Transports supported by the MCP server
+const (
+	// TransportStdio serves a single client over standard input/output
+	TransportStdio = "stdio"
+	// TransportSSE serves clients over the legacy HTTP+SSE transport
+	TransportSSE = "sse"
+	// TransportStreamableHTTP serves clients over the streamable HTTP transport
+	TransportStreamableHTTP = "http"
+)
+
+const (
+	// DefaultListenAddr is the default listen address for network transports
+	DefaultListenAddr = ":8080"
+	// DefaultShutdownTimeout is the default time allowed for in-flight requests
+	// to complete when a network transport is shutting down
+	DefaultShutdownTimeout = 10 * time.Second
+
+	// streamableHTTPEndpoint is the endpoint of the streamable HTTP transport, relative to the base path
+	streamableHTTPEndpoint = "/mcp"
+)
+
+// All available transports
+var AllTransports = []string{
+	TransportStdio,
+	TransportSSE,
+	TransportStreamableHTTP,
+}
+
+// TransportConfig describes how the MCP server is exposed to clients
+type TransportConfig struct {
+	// Type is one of the supported transports. Defaults to stdio when empty.
+	Type string
+	// ListenAddr is the address the HTTP listener binds to (network transports only)
+	ListenAddr string
+	// BasePath is the URL prefix under which the MCP endpoints are mounted (network transports only).
+	// The streamable HTTP endpoint is served at <BasePath>/mcp, the SSE endpoints at
+	// <BasePath>/sse and <BasePath>/message.
+	BasePath string
+	// ShutdownTimeout bounds the graceful shutdown of network transports
+	ShutdownTimeout time.Duration
+}
+
+// httpTransport is implemented by the mcp-go network transports
+type httpTransport interface {
+	http.Handler
+	Shutdown(ctx context.Context) error
+}
+
+// isValidTransport checks if a given string is a supported transport
+func isValidTransport(transport string) bool {
+	return slices.Contains(AllTransports, transport)
+}
+
+// Serve exposes the MCP server using the given transport configuration.
+// This is a blocking call that runs until the context is cancelled or the
+// transport fails. When the context is cancelled, network transports are
+// shut down gracefully and Serve returns nil.
+func (s *PortainerMCPServer) Serve(ctx context.Context, cfg TransportConfig) error {
+	if cfg.Type == "" {
+		cfg.Type = TransportStdio
+	}
+
+	if !isValidTransport(cfg.Type) {
+		return fmt.Errorf("unsupported transport: %s, must be one of %s", cfg.Type, strings.Join(AllTransports, ", "))
+	}
+
+	if cfg.Type == TransportStdio {
+		err := server.NewStdioServer(s.srv).Listen(ctx, os.Stdin, os.Stdout)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+
+	return s.serveHTTP(ctx, cfg)
+}
+
+// serveHTTP runs a network transport until the context is cancelled
+func (s *PortainerMCPServer) serveHTTP(ctx context.Context, cfg TransportConfig) error {
+	if cfg.ListenAddr == "" {
+		cfg.ListenAddr = DefaultListenAddr
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	httpSrv := &http.Server{
+		Addr:              cfg.ListenAddr,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	transport, err := s.newHTTPTransport(cfg, httpSrv)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.ListenAddr, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpSrv.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := transport.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down HTTP transport: %w", err)
+	}
+
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// newHTTPTransport creates the mcp-go transport for the configured type and
+// mounts it on the given HTTP server.
+func (s *PortainerMCPServer) newHTTPTransport(cfg TransportConfig, httpSrv *http.Server) (httpTransport, error) {
+	basePath := normalizeBasePath(cfg.BasePath)
+	mux := http.NewServeMux()
+	sessionTokens := s.sessionTokenMode == SessionTokenOptional || s.sessionTokenMode == SessionTokenRequired
+
+	var transport httpTransport
+	switch cfg.Type {
+	case TransportSSE:
+		sseOpts := []server.SSEOption{
+			server.WithStaticBasePath(basePath),
+			server.WithHTTPServer(httpSrv),
+		}
+		if sessionTokens {
+			sseOpts = append(sseOpts, server.WithSSEContextFunc(sessionTokenContextFunc))
+		}
+		sseServer := server.NewSSEServer(s.srv, sseOpts...)
+		mux.Handle(sseServer.CompleteSsePath(), sseServer)
+		mux.Handle(sseServer.CompleteMessagePath(), sseServer)
+		transport = sseServer
+	case TransportStreamableHTTP:
+		streamableOpts := []server.StreamableHTTPOption{
+			server.WithEndpointPath(basePath + streamableHTTPEndpoint),
+			server.WithStreamableHTTPServer(httpSrv),
+		}
+		if sessionTokens {
+			streamableOpts = append(streamableOpts, server.WithHTTPContextFunc(sessionTokenContextFunc))
+		}
+		streamableServer := server.NewStreamableHTTPServer(s.srv, streamableOpts...)
+		mux.Handle(basePath+streamableHTTPEndpoint, streamableServer)
+		transport = streamableServer
+	default:
+		return nil, fmt.Errorf("transport %s is not served over HTTP", cfg.Type)
+	}
+
+	var handler http.Handler = mux
+	if s.sessionTokenMode == SessionTokenRequired {
+		handler = requireSessionToken(handler)
+	}
+
+	httpSrv.Handler = handler
+
+	return transport, nil
+}
+
+// normalizeBasePath ensures the base path has a leading slash and no trailing slash.
+// An empty path or "/" mounts the endpoints at the root.
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
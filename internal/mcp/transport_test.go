@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`
+
+func newTransportTestServer() *PortainerMCPServer {
+	return &PortainerMCPServer{
+		srv: server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+	}
+}
+
+func TestNormalizeBasePath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: ""},
+		{input: "/", expected: ""},
+		{input: "portainer", expected: "/portainer"},
+		{input: "/portainer/", expected: "/portainer"},
+		{input: "/a/b", expected: "/a/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizeBasePath(tt.input))
+		})
+	}
+}
+
+func TestServeUnsupportedTransport(t *testing.T) {
+	s := newTransportTestServer()
+
+	err := s.Serve(context.Background(), TransportConfig{Type: "websocket"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported transport: websocket")
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           TransportConfig
+		method        string
+		path          string
+		body          string
+		expectedCode  int
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "streamable http initialize at default path",
+			cfg:          TransportConfig{Type: TransportStreamableHTTP},
+			method:       http.MethodPost,
+			path:         "/mcp",
+			body:         initializeRequest,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "streamable http initialize under base path",
+			cfg:          TransportConfig{Type: TransportStreamableHTTP, BasePath: "/portainer/"},
+			method:       http.MethodPost,
+			path:         "/portainer/mcp",
+			body:         initializeRequest,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "streamable http unknown path",
+			cfg:          TransportConfig{Type: TransportStreamableHTTP, BasePath: "/portainer"},
+			method:       http.MethodPost,
+			path:         "/mcp",
+			body:         initializeRequest,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "sse message endpoint without session",
+			cfg:          TransportConfig{Type: TransportSSE, BasePath: "/portainer"},
+			method:       http.MethodPost,
+			path:         "/portainer/message",
+			body:         initializeRequest,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:          "stdio is not an HTTP transport",
+			cfg:           TransportConfig{Type: TransportStdio},
+			expectError:   true,
+			errorContains: "is not served over HTTP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTransportTestServer()
+			httpSrv := &http.Server{}
+
+			transport, err := s.newHTTPTransport(tt.cfg, httpSrv)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, transport)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			httpSrv.Handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServeHTTPGracefulShutdown(t *testing.T) {
+	for _, transport := range []string{TransportSSE, TransportStreamableHTTP} {
+		t.Run(transport, func(t *testing.T) {
+			s := newTransportTestServer()
+			ctx, cancel := context.WithCancel(context.Background())
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- s.Serve(ctx, TransportConfig{
+					Type:            transport,
+					ListenAddr:      "127.0.0.1:0",
+					ShutdownTimeout: time.Second,
+				})
+			}()
+
+			cancel()
+
+			select {
+			case err := <-errCh:
+				assert.NoError(t, err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("server did not shut down")
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// shortenedSuffix ends the strings shortened to fit a response budget
+const shortenedSuffix = "…[truncated]"
+
+// resultPage is a part of the text of a tool result fitting a response budget.
+//
+// A JSON result holding a list, either a JSON array or an object whose
+// largest member is an array, is paged item by item: every page holds whole
+// items, and the position of a page is the index of its first item. The first
+// page of another JSON object is the object shortened to valid JSON, after
+// which it is paged member by member, in the order of their names: every page
+// is an object holding whole members, and the position of a page is the index
+// of its first member. Other JSON values are shortened to a single page, and
+// results that are not JSON are paged by bytes of text, the position of a page
+// being the offset of its first byte.
+type resultPage struct {
+	// text is the text of the page
+	text string
+	// value is the JSON value of the page, nil for a page of raw text
+	value any
+	// offset is the offset of the first byte of a page of raw text
+	offset int
+	// next is the position of the next page, -1 after the last page
+	next int
+	// firstItem is the index of the first item of the page of a list, or
+	// of the first member of the page of an object
+	firstItem int
+	// items is the number of items or members of the page
+	items int
+	// totalItems is the number of items of the list or members of the
+	// object, zero when the result is not paged by items or members
+	totalItems int
+	// members is set when the page holds members of an object
+	members bool
+	// shortened is set when the page holds a value shortened to fit the budget
+	shortened bool
+}
+
+// resultPageAt returns the page of the text of a tool result starting at a
+// position, fitting in budget bytes. The first page of a result starts at
+// position 0.
+func resultPageAt(text string, budget, position int, first bool) resultPage {
+	value, err := decodeJSON(text)
+	if err != nil {
+		return rawPage(text, budget, position)
+	}
+
+	root, member, items := pagedList(value)
+	if items != nil {
+		return listPage(root, member, items, budget, position)
+	}
+
+	object, ok := value.(map[string]any)
+	if ok && !first {
+		return objectPage(object, budget, position)
+	}
+
+	// The whole value is shortened, then an object is read member by member
+	shortened := shortenValue(value, budget)
+	page := resultPage{text: string(encodeJSON(shortened)), value: shortened, next: -1, shortened: true}
+	if ok {
+		page.next = 0
+	}
+	return page
+}
+
+// rawPage returns the bytes of a text starting at an offset and fitting in
+// budget bytes, without splitting a UTF-8 character
+func rawPage(text string, budget, offset int) resultPage {
+	offset = min(max(offset, 0), len(text))
+	end := offset + budget
+	if end >= len(text) {
+		return resultPage{text: text[offset:], offset: offset, next: -1}
+	}
+
+	end = runeBoundary(text, end)
+	if end <= offset {
+		// A budget smaller than a character still makes progress
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		end = offset + size
+	}
+
+	return resultPage{text: text[offset:end], offset: offset, next: end}
+}
+
+// listPage returns the items of a list starting at an index and fitting in
+// budget bytes, within the root object holding the list when it is a member
+// of an object. An item larger than the budget is shortened.
+func listPage(root map[string]any, member string, items []any, budget, start int) resultPage {
+	start = min(max(start, 0), len(items))
+	page := resultPage{firstItem: start, totalItems: len(items), next: -1}
+
+	// The members of the root object other than the list take at most half
+	// of the budget
+	var base map[string]any
+	available := budget - 2
+	if root != nil {
+		base = maps.Clone(root)
+		base[member] = []any{}
+		if encodedSize(base) > budget/2 {
+			base = shortenValue(base, budget/2).(map[string]any)
+			page.shortened = true
+		}
+		available = budget - encodedSize(base)
+	}
+
+	used := 0
+	end := start
+	for end < len(items) {
+		size := encodedSize(items[end])
+		if end > start {
+			size++
+		}
+		if used+size > available {
+			break
+		}
+		used += size
+		end++
+	}
+
+	pageItems := items[start:end]
+	if end == start && start < len(items) {
+		pageItems = []any{shortenValue(items[start], available)}
+		page.shortened = true
+		end++
+	}
+
+	page.items = len(pageItems)
+	if end < len(items) {
+		page.next = end
+	}
+
+	page.value = pageItems
+	if base != nil {
+		base[member] = pageItems
+		page.value = base
+	}
+	page.text = string(encodeJSON(page.value))
+	return page
+}
+
+// objectPage returns the members of an object starting at an index, in the
+// order of their names, and fitting in budget bytes. A member larger than the
+// budget is shortened.
+func objectPage(object map[string]any, budget, start int) resultPage {
+	names := slices.Sorted(maps.Keys(object))
+	start = min(max(start, 0), len(names))
+	page := resultPage{firstItem: start, totalItems: len(names), members: true, next: -1}
+
+	members := make(map[string]any)
+	used := 2
+	end := start
+	for end < len(names) {
+		name := names[end]
+		size := encodedSize(name) + 1 + encodedSize(object[name])
+		if end > start {
+			size++
+		}
+		if used+size > budget {
+			break
+		}
+		used += size
+		members[name] = object[name]
+		end++
+	}
+
+	if end == start && start < len(names) {
+		name := names[start]
+		members[name] = shortenValue(object[name], budget-used-encodedSize(name)-1)
+		page.shortened = true
+		end++
+	}
+
+	page.items = end - start
+	if end < len(names) {
+		page.next = end
+	}
+
+	page.value = members
+	page.text = string(encodeJSON(members))
+	return page
+}
+
+// pagedList returns the list of a JSON value paged item by item: the value
+// itself when it is an array, or the largest member of an object when it is
+// an array holding at least half of the object. The root object is nil for
+// an array.
+func pagedList(value any) (map[string]any, string, []any) {
+	switch v := value.(type) {
+	case []any:
+		return nil, "", v
+	case map[string]any:
+		largest, largestSize := "", 0
+		for key, member := range v {
+			if _, ok := member.([]any); !ok {
+				continue
+			}
+			if size := encodedSize(member); size > largestSize {
+				largest, largestSize = key, size
+			}
+		}
+		if largest != "" && largestSize*2 >= encodedSize(v) {
+			return v, largest, v[largest].([]any)
+		}
+	}
+	return nil, "", nil
+}
+
+// shortenValue returns a JSON value shortened to fit in limit bytes when
+// encoded, keeping it valid JSON of the same structure: arrays keep their
+// first items, objects keep their members with their largest members
+// shortened, and strings are cut. Numbers, booleans and null are kept, so the
+// shortened value may exceed the limit.
+func shortenValue(value any, limit int) any {
+	if encodedSize(value) <= limit {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		return shortenString(v, limit)
+	case []any:
+		return shortenArray(v, limit)
+	case map[string]any:
+		return shortenObject(v, limit)
+	default:
+		return value
+	}
+}
+
+// shortenString cuts a string to fit in limit bytes when encoded, marking it
+// with a suffix
+func shortenString(s string, limit int) string {
+	if encodedSize(shortenedSuffix) > limit {
+		return ""
+	}
+
+	// Find the longest prefix fitting with the suffix, escaping included
+	low, high := 0, len(s)
+	for low < high {
+		mid := low + (high-low+1)/2
+		if encodedSize(s[:runeBoundary(s, mid)]+shortenedSuffix) <= limit {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return s[:runeBoundary(s, low)] + shortenedSuffix
+}
+
+// runeBoundary returns the largest offset of a string not after n that does
+// not split a UTF-8 character
+func runeBoundary(s string, n int) int {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
+
+// shortenArray keeps the first items of an array fitting in limit bytes, the
+// first item being shortened when it does not fit alone
+func shortenArray(items []any, limit int) []any {
+	kept := make([]any, 0)
+	used := 2
+
+	for i, item := range items {
+		size := encodedSize(item)
+		if i > 0 {
+			size++
+		}
+		if used+size > limit {
+			break
+		}
+		used += size
+		kept = append(kept, item)
+	}
+
+	if len(kept) == 0 && len(items) > 0 && limit > 2 {
+		kept = append(kept, shortenValue(items[0], limit-2))
+	}
+
+	return kept
+}
+
+// shortenObject shortens the largest members of an object until it fits in
+// limit bytes
+func shortenObject(object map[string]any, limit int) map[string]any {
+	result := maps.Clone(object)
+	sizes := make(map[string]int, len(object))
+	for key, member := range object {
+		sizes[key] = encodedSize(member)
+	}
+
+	total := encodedSize(object)
+	final := make(map[string]bool)
+
+	for total > limit {
+		largest := ""
+		for key, size := range sizes {
+			if !final[key] && (largest == "" || size > sizes[largest] || (size == sizes[largest] && key < largest)) {
+				largest = key
+			}
+		}
+		if largest == "" {
+			break
+		}
+
+		shortened := shortenValue(result[largest], max(sizes[largest]-(total-limit), 0))
+		size := encodedSize(shortened)
+		if size >= sizes[largest] {
+			final[largest] = true
+			continue
+		}
+
+		result[largest] = shortened
+		total -= sizes[largest] - size
+		sizes[largest] = size
+	}
+
+	return result
+}
+
+// decodeJSON decodes a JSON text, keeping the numbers as they are written
+func decodeJSON(text string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+// encodeJSON encodes a value as compact JSON, without escaping HTML characters
+func encodeJSON(value any) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// encodedSize returns the size in bytes of the JSON encoding of a value
+func encodedSize(value any) int {
+	return len(encodeJSON(value))
+}
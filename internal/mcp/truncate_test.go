@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// truncateTestList returns a JSON array of count objects
+func truncateTestList(count int) string {
+	items := make([]map[string]any, count)
+	for i := range items {
+		items[i] = map[string]any{"id": i, "name": fmt.Sprintf("container-%03d", i)}
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+func TestResultPageList(t *testing.T) {
+	text := truncateTestList(100)
+
+	var ids []float64
+	position, first := 0, true
+	for pages := 0; position >= 0; pages++ {
+		require.Less(t, pages, 100)
+
+		page := resultPageAt(text, 300, position, first)
+		assert.LessOrEqual(t, len(page.text), 300)
+		assert.Equal(t, 100, page.totalItems)
+		assert.Equal(t, position, page.firstItem)
+		assert.False(t, page.shortened)
+
+		var items []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(page.text), &items))
+		assert.Len(t, items, page.items)
+		for _, item := range items {
+			ids = append(ids, item["id"].(float64))
+		}
+
+		position, first = page.next, false
+	}
+
+	require.Len(t, ids, 100)
+	for i, id := range ids {
+		assert.Equal(t, float64(i), id)
+	}
+}
+
+func TestResultPageListMember(t *testing.T) {
+	text := `{"kind":"list","nextCursor":"abc","items":` + truncateTestList(50) + `}`
+
+	page := resultPageAt(text, 400, 0, true)
+	assert.LessOrEqual(t, len(page.text), 400)
+	assert.Equal(t, 50, page.totalItems)
+	assert.Positive(t, page.next)
+
+	var value map[string]any
+	require.NoError(t, json.Unmarshal([]byte(page.text), &value))
+	assert.Equal(t, "list", value["kind"])
+	assert.Equal(t, "abc", value["nextCursor"])
+	assert.Len(t, value["items"], page.items)
+
+	page = resultPageAt(text, 400, page.next, false)
+	require.NoError(t, json.Unmarshal([]byte(page.text), &value))
+	assert.Equal(t, "list", value["kind"])
+}
+
+func TestResultPageOversizedItem(t *testing.T) {
+	text := `[{"id":1,"log":"` + strings.Repeat("x", 1000) + `"},{"id":2}]`
+
+	page := resultPageAt(text, 200, 0, true)
+	assert.LessOrEqual(t, len(page.text), 200)
+	assert.True(t, page.shortened)
+	assert.Equal(t, 1, page.items)
+	assert.Equal(t, 1, page.next)
+
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(page.text), &items))
+	assert.Equal(t, float64(1), items[0]["id"])
+	assert.True(t, strings.HasSuffix(items[0]["log"].(string), shortenedSuffix))
+
+	page = resultPageAt(text, 200, 1, false)
+	assert.Equal(t, `[{"id":2}]`, page.text)
+	assert.Equal(t, -1, page.next)
+}
+
+func TestResultPageValue(t *testing.T) {
+	text := `{"Id":"abc","Config":{"Env":["A=1","B=2"],"Labels":{"a":"` + strings.Repeat("y", 300) + `"}},"Mounts":"` + strings.Repeat("m", 300) + `","Log":"` + strings.Repeat("x", 2000) + `","State":"running"}`
+
+	page := resultPageAt(text, 500, 0, true)
+	assert.LessOrEqual(t, len(page.text), 500)
+	assert.True(t, page.shortened)
+	assert.Equal(t, 0, page.next)
+	assert.Zero(t, page.totalItems)
+
+	var value map[string]any
+	require.NoError(t, json.Unmarshal([]byte(page.text), &value))
+	assert.Equal(t, "abc", value["Id"])
+	assert.Equal(t, "running", value["State"])
+
+	// The rest of the value is then read member by member, every page being
+	// an object of whole members, but for a member larger than the budget
+	rest := make(map[string]any)
+	for position := page.next; position >= 0; {
+		page = resultPageAt(text, 500, position, false)
+		assert.LessOrEqual(t, len(page.text), 500)
+		assert.True(t, page.members)
+		assert.Equal(t, position, page.firstItem)
+		assert.Equal(t, 5, page.totalItems)
+		assert.Equal(t, page.shortened, position == 2, position)
+
+		var members map[string]any
+		require.NoError(t, json.Unmarshal([]byte(page.text), &members))
+		assert.Len(t, members, page.items)
+		for name, member := range members {
+			rest[name] = member
+		}
+		position = page.next
+	}
+
+	var expected map[string]any
+	require.NoError(t, json.Unmarshal([]byte(text), &expected))
+	assert.True(t, strings.HasSuffix(rest["Log"].(string), shortenedSuffix))
+	rest["Log"] = expected["Log"]
+	assert.Equal(t, expected, rest)
+}
+
+func TestResultPageScalar(t *testing.T) {
+	page := resultPageAt(`"`+strings.Repeat("z", 1000)+`"`, 200, 0, true)
+	assert.LessOrEqual(t, len(page.text), 200)
+	assert.True(t, page.shortened)
+	assert.Equal(t, -1, page.next)
+}
+
+func TestRawPage(t *testing.T) {
+	text := strings.Repeat("héllo wörld ", 50)
+
+	var pages []string
+	for position := 0; position >= 0; {
+		page := resultPageAt(text, 7, position, position == 0)
+		assert.LessOrEqual(t, len(page.text), 7)
+		assert.True(t, utf8.ValidString(page.text))
+		assert.Nil(t, page.value)
+		pages = append(pages, page.text)
+		position = page.next
+	}
+
+	assert.Equal(t, text, strings.Join(pages, ""))
+}
+
+func TestShortenValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		limit int
+	}{
+		{name: "string", value: `"` + strings.Repeat("é", 100) + `"`, limit: 50},
+		{name: "escaped string", value: `"` + strings.Repeat(`\"<>`, 100) + `"`, limit: 60},
+		{name: "array", value: truncateTestList(20), limit: 100},
+		{name: "nested object", value: `{"a":{"b":["` + strings.Repeat("z", 500) + `"]},"c":"` + strings.Repeat("w", 300) + `","d":1}`, limit: 120},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := decodeJSON(tt.value)
+			require.NoError(t, err)
+
+			shortened := encodeJSON(shortenValue(value, tt.limit))
+			assert.LessOrEqual(t, len(shortened), tt.limit)
+			assert.True(t, json.Valid(shortened), string(shortened))
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	_, err := decodeJSON(`{"a":1} `)
+	assert.NoError(t, err)
+
+	_, err = decodeJSON(`{"a":1} {"b":2}`)
+	assert.Error(t, err)
+
+	_, err = decodeJSON(`plain text`)
+	assert.Error(t, err)
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddUserFeatures() {
@@ -20,7 +19,7 @@ func (s *PortainerMCPServer) AddUserFeatures() {
 
 func (s *PortainerMCPServer) HandleGetUsers() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		users, err := s.cli.GetUsers()
+		users, err := s.client(ctx).GetUsers(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get users", err), nil
 		}
@@ -30,29 +29,22 @@ func (s *PortainerMCPServer) HandleGetUsers() server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("failed to marshal users", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(users, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleUpdateUserRole() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
-		}
-
-		role, err := parser.GetString("role", true)
+		args, err := ParseUpdateUserRoleArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid role parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		if !isValidUserRole(role) {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid role %s: must be one of: %v", role, AllUserRoles)), nil
+		if !isValidUserRole(args.Role) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid role %s: must be one of: %v", args.Role, AllUserRoles)), nil
 		}
 
-		err = s.cli.UpdateUserRole(id, role)
+		err = s.client(ctx).UpdateUserRole(ctx, args.ID, args.Role)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to update user role", err), nil
 		}
@@ -2,9 +2,11 @@ package mcp
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 // parseAccessMap parses access entries from an array of objects and returns a map of ID to access level
@@ -77,3 +79,22 @@ func CreateMCPRequest(args map[string]any) mcp.CallToolRequest {
 		},
 	}
 }
+
+// withToolProperty returns a copy of the tool accepting an additional argument
+func withToolProperty(tool mcp.Tool, name string, schema map[string]any) mcp.Tool {
+	properties := maps.Clone(tool.InputSchema.Properties)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+
+	properties[name] = schema
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// structuredResult returns a tool result holding the JSON encoding of a value
+// as text, and the value as structured content matching the output schema of
+// the tool
+func structuredResult(value any, data []byte) *mcp.CallToolResult {
+	return mcp.NewToolResultStructured(toolgen.StructuredContent(value), string(data))
+}
@@ -0,0 +1,26 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// validationHandler returns a handler checking the arguments of a tool call
+// against the input schema of the tool. The handler is called with the
+// arguments completed by the default values of the schema, and is not called
+// when an argument violates a constraint of the schema.
+func validationHandler(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		parser := toolgen.NewParameterParserWithSchema(request, tool.InputSchema)
+
+		if err := parser.Validate(); err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid arguments", err), nil
+		}
+
+		request.Params.Arguments = parser.Arguments()
+		return handler(ctx, request)
+	}
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/portainer/portainer-mcp/internal/tooldef"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolArgumentValidation(t *testing.T) {
+	config, err := tooldef.EmbeddedTools()
+	require.NoError(t, err)
+	tools, _ := toolgen.ConvertToolsConfig(config)
+
+	mockClient := new(MockPortainerClient)
+	s := &PortainerMCPServer{
+		srv:   server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true)),
+		cli:   mockClient,
+		tools: tools,
+	}
+	s.AddDockerProxyFeatures()
+	s.AddKubernetesProxyFeatures()
+
+	tests := []struct {
+		name          string
+		tool          string
+		args          map[string]any
+		errorContains string
+	}{
+		{
+			name: "dockerAPIPath without leading slash",
+			tool: ToolDockerProxy,
+			args: map[string]any{
+				"environmentId": float64(1),
+				"dockerAPIPath": "containers/json",
+				"method":        "GET",
+			},
+			errorContains: "dockerAPIPath must match the pattern ^/",
+		},
+		{
+			name: "kubernetesAPIPath without leading slash",
+			tool: ToolKubernetesProxy,
+			args: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "api/v1/pods",
+				"method":            "GET",
+			},
+			errorContains: "kubernetesAPIPath must match the pattern ^/",
+		},
+		{
+			name: "stripped kubernetesAPIPath without leading slash",
+			tool: ToolGetKubernetesResourceStripped,
+			args: map[string]any{
+				"environmentId":     float64(1),
+				"kubernetesAPIPath": "api/v1/pods",
+			},
+			errorContains: "kubernetesAPIPath must match the pattern ^/",
+		},
+		{
+			name: "missing required argument",
+			tool: ToolDockerProxy,
+			args: map[string]any{
+				"dockerAPIPath": "/containers/json",
+				"method":        "GET",
+			},
+			errorContains: "environmentId is required",
+		},
+		{
+			name: "invalid query parameter item",
+			tool: ToolDockerProxy,
+			args: map[string]any{
+				"environmentId": float64(1),
+				"dockerAPIPath": "/containers/json",
+				"method":        "GET",
+				"queryParams":   []any{"all=true"},
+			},
+			errorContains: "queryParams[0] must be an object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, context.Background(), tt.tool, tt.args)
+
+			assert.True(t, result.IsError)
+			assert.Contains(t, resultText(result), "invalid arguments: "+tt.errorContains)
+		})
+	}
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestValidationHandlerDefaults(t *testing.T) {
+	tool := mcp.NewTool("listItems",
+		mcp.WithNumber("limit", mcp.DefaultNumber(10.0), mcp.Max(100.0)),
+		mcp.WithString("sort", mcp.Enum("name", "id")),
+	)
+
+	var received map[string]any
+	handler := validationHandler(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		received = request.GetArguments()
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	result, err := handler(context.Background(), CreateMCPRequest(map[string]any{"sort": "name"}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, map[string]any{"limit": 10.0, "sort": "name"}, received)
+
+	received = nil
+	result, err = handler(context.Background(), CreateMCPRequest(map[string]any{"limit": float64(500)}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "limit must be less than or equal to 100")
+	assert.Nil(t, received)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
-	"github.com/portainer/portainer-mcp/pkg/toolgen"
 )
 
 func (s *PortainerMCPServer) AddWebhookFeatures() {
@@ -22,46 +21,40 @@ func (s *PortainerMCPServer) AddWebhookFeatures() {
 
 func (s *PortainerMCPServer) HandleListWebhooks() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		webhooks, err := s.cli.GetWebhooks()
+		webhooks, err := s.client(ctx).GetWebhooks(ctx)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to get webhooks", err), nil
 		}
 
+		inScope, err := s.scopedEnvironmentIDs(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve environment scope", err), nil
+		}
+		webhooks = filterScoped(inScope, webhooks, func(w models.Webhook) []int { return []int{w.EndpointID} })
+
 		data, err := json.Marshal(webhooks)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to marshal webhooks", err), nil
 		}
 
-		return mcp.NewToolResultText(string(data)), nil
+		return structuredResult(webhooks, data), nil
 	}
 }
 
 func (s *PortainerMCPServer) HandleCreateWebhook() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		resourceID, err := parser.GetString("resourceId", true)
+		args, err := ParseCreateWebhookArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid resourceId parameter", err), nil
-		}
-
-		endpointID, err := parser.GetInt("endpointId", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid endpointId parameter", err), nil
-		}
-
-		webhookType, err := parser.GetInt("webhookType", true)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid webhookType parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		req := models.WebhookCreateRequest{
-			ResourceID: resourceID,
-			EndpointID: endpointID,
-			Type:       webhookType,
+			ResourceID: args.ResourceID,
+			EndpointID: args.EndpointID,
+			Type:       args.WebhookType,
 		}
 
-		id, err := s.cli.CreateWebhook(req)
+		id, err := s.client(ctx).CreateWebhook(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to create webhook", err), nil
 		}
@@ -72,14 +65,12 @@ func (s *PortainerMCPServer) HandleCreateWebhook() server.ToolHandlerFunc {
 
 func (s *PortainerMCPServer) HandleDeleteWebhook() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		parser := toolgen.NewParameterParser(request)
-
-		id, err := parser.GetInt("id", true)
+		args, err := ParseDeleteWebhookArgs(request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("invalid id parameter", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = s.cli.DeleteWebhook(id)
+		err = s.client(ctx).DeleteWebhook(ctx, args.ID)
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("failed to delete webhook", err), nil
 		}
@@ -2,17 +2,50 @@ package tooldef
 
 import (
 	_ "embed"
+	"fmt"
 	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed tools.yaml
 var ToolsFile []byte
 
-// CreateToolsFileIfNotExists creates the tools.yaml file if it doesn't exist
+// overlayHeader documents the tools files created as an overlay of the embedded tools
+const overlayHeader = `# Customisations of the tool definitions embedded in portainer-mcp.
+# Every tool keeps its embedded definition, including the tools added by
+# later releases, unless it is listed below. A listed tool can replace its
+# description, the descriptions of its parameters, or be disabled:
+#
+# tools:
+#   - name: listEnvironments
+#     description: List the environments, prefer this tool to find environment IDs
+#     parameters:
+#       - name: id
+#         description: A parameter description replacing the embedded one
+#   - name: dockerProxy
+#     disabled: true
+`
+
+// CreateToolsFileIfNotExists creates the tools.yaml file if it doesn't exist,
+// as an empty overlay of the embedded tools
 // It returns true if the file already exists, false if it was created or an error occurred
 func CreateToolsFileIfNotExists(path string) (bool, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		err = os.WriteFile(path, ToolsFile, 0644)
+		embedded, err := EmbeddedTools()
+		if err != nil {
+			return false, err
+		}
+
+		content, err := marshalOverlay(toolgen.ToolsConfig{Version: embedded.Version, Overlay: true})
+		if err != nil {
+			return false, err
+		}
+
+		err = os.WriteFile(path, content, 0644)
 		if err != nil {
 			return false, err
 		}
@@ -20,3 +53,69 @@ func CreateToolsFileIfNotExists(path string) (bool, error) {
 	}
 	return true, nil
 }
+
+// EmbeddedTools returns the configuration of the embedded tools file
+func EmbeddedTools() (toolgen.ToolsConfig, error) {
+	var config toolgen.ToolsConfig
+	if err := yaml.Unmarshal(ToolsFile, &config); err != nil {
+		return toolgen.ToolsConfig{}, fmt.Errorf("failed to parse the embedded tools: %w", err)
+	}
+	return config, nil
+}
+
+// LoadTools loads the tools of a tools file, along with their requirements.
+// An overlay file is applied to the embedded tools, any other file defines
+// every tool.
+func LoadTools(path, minimumVersion string) (map[string]mcp.Tool, map[string]toolgen.ToolRequirements, error) {
+	config, err := toolgen.LoadToolsConfigFromYAML(path, minimumVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if config.Overlay {
+		embedded, err := EmbeddedTools()
+		if err != nil {
+			return nil, nil, err
+		}
+		config = toolgen.ApplyOverlay(embedded, config)
+	}
+
+	tools, requirements := toolgen.ConvertToolsConfig(config)
+	return tools, requirements, nil
+}
+
+// IsOutdated reports whether a tools file is a complete copy of tools older
+// than the embedded tools, in which case it lacks the tools added since.
+// It also returns the version of the file.
+func IsOutdated(path string) (bool, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, "", err
+	}
+
+	var config toolgen.ToolsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return false, "", err
+	}
+
+	embedded, err := EmbeddedTools()
+	if err != nil {
+		return false, "", err
+	}
+
+	return !config.Overlay && semver.Compare(config.Version, embedded.Version) < 0, config.Version, nil
+}
+
+// marshalOverlay returns the content of an overlay tools file
+func marshalOverlay(config toolgen.ToolsConfig) ([]byte, error) {
+	if config.Tools == nil {
+		config.Tools = []toolgen.ToolDefinition{}
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the tools overlay: %w", err)
+	}
+
+	return append([]byte(overlayHeader+"---\n"), data...), nil
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,10 +32,16 @@ func TestCreateToolsFileIfNotExists(t *testing.T) {
 		_, err = os.Stat(filePath)
 		assert.NoError(t, err, "File should exist after function call")
 
-		// Verify the file has the embedded content
-		content, err := os.ReadFile(filePath)
-		require.NoError(t, err, "Should be able to read the created file")
-		assert.Equal(t, ToolsFile, content, "File should contain the embedded tools content")
+		// Verify the file is an empty overlay of the embedded tools
+		config, err := toolgen.LoadToolsConfigFromYAML(filePath, "v1.0")
+		require.NoError(t, err, "Should be able to load the created file")
+		embedded, err := EmbeddedTools()
+		require.NoError(t, err)
+		assert.Equal(t, toolgen.ToolsConfig{Version: embedded.Version, Overlay: true, Tools: []toolgen.ToolDefinition{}}, config)
+
+		tools, _, err := LoadTools(filePath, "v1.0")
+		require.NoError(t, err, "Should be able to load the tools of the created file")
+		assert.Len(t, tools, len(embedded.Tools), "File should provide every embedded tool")
 	})
 
 	t.Run("File Already Exists", func(t *testing.T) {
@@ -66,3 +73,96 @@ func TestCreateToolsFileIfNotExists(t *testing.T) {
 		assert.False(t, exists, "Function should return false when an error occurs")
 	})
 }
+
+func writeTestToolsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	return path
+}
+
+func TestLoadTools(t *testing.T) {
+	embedded, err := EmbeddedTools()
+	require.NoError(t, err)
+
+	t.Run("overlay", func(t *testing.T) {
+		path := writeTestToolsFile(t, `version: v1.0
+overlay: true
+tools:
+  - name: listEnvironments
+    description: List the environments, prefer this tool to find environment IDs
+  - name: getStackFile
+    parameters:
+      - name: id
+        description: The ID of the edge stack
+  - name: dockerProxy
+    disabled: true
+`)
+
+		tools, requirements, err := LoadTools(path, "v1.0")
+		require.NoError(t, err)
+
+		assert.Len(t, tools, len(embedded.Tools)-1)
+		assert.NotContains(t, tools, "dockerProxy")
+		assert.Equal(t, "List the environments, prefer this tool to find environment IDs", tools["listEnvironments"].Description)
+		assert.Equal(t, map[string]any{"type": "number", "description": "The ID of the edge stack"}, tools["getStackFile"].InputSchema.Properties["id"])
+		assert.Equal(t, toolgen.ToolRequirements{MinPortainerVersion: "2.37.0", Edition: toolgen.EditionBE}, requirements["listPolicies"])
+	})
+
+	t.Run("complete file", func(t *testing.T) {
+		tools, _, err := LoadTools("tools.yaml", "v1.0")
+		require.NoError(t, err)
+		assert.Len(t, tools, len(embedded.Tools))
+	})
+
+	t.Run("version below the minimum", func(t *testing.T) {
+		path := writeTestToolsFile(t, "version: v1.0\noverlay: true\ntools: []\n")
+		_, _, err := LoadTools(path, "v1.1")
+		assert.ErrorContains(t, err, "below the minimum required version")
+	})
+}
+
+func TestIsOutdated(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		expected        bool
+		expectedVersion string
+	}{
+		{
+			name:            "older complete file",
+			content:         "version: v1.0\ntools: []\n",
+			expected:        true,
+			expectedVersion: "v1.0",
+		},
+		{
+			name:            "older overlay",
+			content:         "version: v1.0\noverlay: true\ntools: []\n",
+			expected:        false,
+			expectedVersion: "v1.0",
+		},
+		{
+			name:            "complete file of the previous release",
+			content:         "version: v1.7\ntools: []\n",
+			expected:        true,
+			expectedVersion: "v1.7",
+		},
+		{
+			name:            "embedded file",
+			content:         string(ToolsFile),
+			expected:        false,
+			expectedVersion: "v1.8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outdated, version, err := IsOutdated(writeTestToolsFile(t, tt.content))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, outdated)
+			assert.Equal(t, tt.expectedVersion, version)
+		})
+	}
+}
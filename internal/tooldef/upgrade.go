@@ -0,0 +1,132 @@
+package tooldef
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"gopkg.in/yaml.v3"
+)
+
+// UpgradeResult is the outcome of the upgrade of a tools file to the embedded tools
+type UpgradeResult struct {
+	toolgen.MergeResult
+	// Content is the upgraded tools file, an overlay of the embedded tools
+	Content []byte
+	// Diff is a unified diff of the tool definitions before and after the upgrade
+	Diff string
+	// WithoutBase is set when the tools file was merged without a base, in
+	// which case every difference with the embedded tools is kept
+	WithoutBase bool
+}
+
+// UpgradeToolsFile upgrades a tools file to an overlay of the embedded tools.
+//
+// A complete tools file is merged onto the embedded tools with a three-way
+// merge, against the tools file it was customised from, read from basePath.
+// Without a base, the file is merged as is: tools missing from it are not
+// disabled, even when its version is the embedded one, as they cannot be told
+// apart from tools added since it was copied. An overlay file is kept,
+// without the overlays of removed tools.
+// The tools file itself is not modified.
+//
+// Parameters:
+//   - path: Path to the tools file to upgrade
+//   - basePath: Path to the tools file the tools file was customised from, optional
+//
+// Returns:
+//   - The upgraded tools file and the changes made to the tool definitions
+//   - An error if a tools file cannot be read or parsed
+func UpgradeToolsFile(path, basePath string) (UpgradeResult, error) {
+	custom, err := readToolsConfig(path)
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	latest, err := EmbeddedTools()
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	var result UpgradeResult
+	before := custom
+
+	switch {
+	case custom.Overlay:
+		// The overlay is merged as the customised copy of the embedded tools
+		// it produces, which keeps every customisation of known tools
+		before = toolgen.ApplyOverlay(latest, custom)
+		result.MergeResult = toolgen.MergeOverlay(latest, before, latest)
+
+		known := make(map[string]bool, len(latest.Tools))
+		for _, def := range latest.Tools {
+			known[def.Name] = true
+		}
+		for _, def := range custom.Tools {
+			if !known[def.Name] {
+				result.Dropped = append(result.Dropped, fmt.Sprintf("tool %s is not defined by the new version", def.Name))
+			}
+		}
+
+	case basePath != "":
+		base, err := readToolsConfig(basePath)
+		if err != nil {
+			return UpgradeResult{}, err
+		}
+		result.MergeResult = toolgen.MergeOverlay(base, custom, latest)
+
+	default:
+		result.WithoutBase = true
+		result.MergeResult = toolgen.MergeOverlay(toolgen.ToolsConfig{}, custom, latest)
+	}
+
+	result.Content, err = marshalOverlay(result.Overlay)
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	after := toolgen.ApplyOverlay(latest, result.Overlay)
+	result.Diff, err = diffTools(path, before, after)
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	return result, nil
+}
+
+// readToolsConfig reads the configuration of a tools file
+func readToolsConfig(path string) (toolgen.ToolsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return toolgen.ToolsConfig{}, err
+	}
+
+	var config toolgen.ToolsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return toolgen.ToolsConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// diffTools returns a unified diff of the tool definitions of two configurations
+func diffTools(path string, before, after toolgen.ToolsConfig) (string, error) {
+	beforeData, err := yaml.Marshal(before.Tools)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the tools: %w", err)
+	}
+
+	afterData, err := yaml.Marshal(after.Tools)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the tools: %w", err)
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(beforeData)),
+		B:        difflib.SplitLines(string(afterData)),
+		FromFile: path + " (" + before.Version + ")",
+		ToFile:   path + " (" + after.Version + ")",
+		Context:  3,
+	})
+}
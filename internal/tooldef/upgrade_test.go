@@ -0,0 +1,170 @@
+package tooldef
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// customiseEmbeddedTools returns the embedded tools, modified by the given function
+func customiseEmbeddedTools(t *testing.T, version string, customise func(tools []toolgen.ToolDefinition) []toolgen.ToolDefinition) toolgen.ToolsConfig {
+	t.Helper()
+
+	config, err := EmbeddedTools()
+	require.NoError(t, err)
+
+	config.Version = version
+	config.Tools = customise(config.Tools)
+
+	return config
+}
+
+func writeTestToolsConfig(t *testing.T, config toolgen.ToolsConfig) string {
+	t.Helper()
+
+	data, err := yaml.Marshal(config)
+	require.NoError(t, err)
+
+	return writeTestToolsFile(t, string(data))
+}
+
+func setToolDescription(tools []toolgen.ToolDefinition, name, description string) {
+	i := slices.IndexFunc(tools, func(def toolgen.ToolDefinition) bool { return def.Name == name })
+	tools[i].Description = description
+}
+
+func deleteTool(tools []toolgen.ToolDefinition, name string) []toolgen.ToolDefinition {
+	return slices.DeleteFunc(tools, func(def toolgen.ToolDefinition) bool { return def.Name == name })
+}
+
+func TestUpgradeToolsFile(t *testing.T) {
+	embedded, err := EmbeddedTools()
+	require.NoError(t, err)
+
+	// An older release of the tools, without policies and with another description
+	older := func(tools []toolgen.ToolDefinition) []toolgen.ToolDefinition {
+		setToolDescription(tools, "getStackFile", "Get the stack file")
+		return deleteTool(tools, "listPolicies")
+	}
+
+	// The customisations of the older release
+	customised := func(tools []toolgen.ToolDefinition) []toolgen.ToolDefinition {
+		tools = older(tools)
+		setToolDescription(tools, "listEnvironments", "List the environments, prefer this tool to find environment IDs")
+		return deleteTool(tools, "dockerProxy")
+	}
+
+	expectedOverlay := toolgen.ToolsConfig{
+		Version: embedded.Version,
+		Overlay: true,
+		Tools: []toolgen.ToolDefinition{
+			{Name: "listEnvironments", Description: "List the environments, prefer this tool to find environment IDs"},
+			{Name: "dockerProxy", Disabled: true},
+		},
+	}
+
+	t.Run("three-way merge with a base", func(t *testing.T) {
+		path := writeTestToolsConfig(t, customiseEmbeddedTools(t, "v1.6", customised))
+		basePath := writeTestToolsConfig(t, customiseEmbeddedTools(t, "v1.6", older))
+
+		result, err := UpgradeToolsFile(path, basePath)
+		require.NoError(t, err)
+
+		assert.False(t, result.WithoutBase)
+		assert.Equal(t, expectedOverlay, result.Overlay)
+		assert.Empty(t, result.Conflicts)
+		assert.Empty(t, result.Dropped)
+
+		// The upgraded file is an overlay of the embedded tools
+		upgraded, err := toolgen.ParseToolsConfig(result.Content, "v1.0")
+		require.NoError(t, err)
+		assert.Equal(t, expectedOverlay, upgraded)
+
+		assert.Contains(t, result.Diff, "-  description: Get the stack file\n")
+		assert.Contains(t, result.Diff, "+  description: Get the compose file for a specific stack ID\n")
+		assert.Contains(t, result.Diff, "+- name: listPolicies\n")
+		assert.NotContains(t, result.Diff, "name: dockerProxy")
+	})
+
+	t.Run("older file without a base", func(t *testing.T) {
+		path := writeTestToolsConfig(t, customiseEmbeddedTools(t, "v1.6", customised))
+
+		result, err := UpgradeToolsFile(path, "")
+		require.NoError(t, err)
+
+		// The older description is kept as a customisation, and the removed
+		// tool cannot be told apart from a new tool
+		assert.True(t, result.WithoutBase)
+		assert.Equal(t, []toolgen.ToolDefinition{
+			{Name: "listEnvironments", Description: "List the environments, prefer this tool to find environment IDs"},
+			{Name: "getStackFile", Description: "Get the stack file"},
+		}, result.Overlay.Tools)
+	})
+
+	t.Run("file of the embedded version", func(t *testing.T) {
+		path := writeTestToolsConfig(t, customiseEmbeddedTools(t, embedded.Version, func(tools []toolgen.ToolDefinition) []toolgen.ToolDefinition {
+			setToolDescription(tools, "listEnvironments", "List the environments, prefer this tool to find environment IDs")
+			return deleteTool(tools, "dockerProxy")
+		}))
+
+		// Without a base, the missing tool is not taken for a removal
+		result, err := UpgradeToolsFile(path, "")
+		require.NoError(t, err)
+
+		assert.True(t, result.WithoutBase)
+		assert.Equal(t, []toolgen.ToolDefinition{
+			{Name: "listEnvironments", Description: "List the environments, prefer this tool to find environment IDs"},
+		}, result.Overlay.Tools)
+		assert.Contains(t, result.Diff, "+- name: dockerProxy\n")
+
+		// The embedded tools given as the base tell the removal apart
+		result, err = UpgradeToolsFile(path, writeTestToolsConfig(t, embedded))
+		require.NoError(t, err)
+
+		assert.False(t, result.WithoutBase)
+		assert.Equal(t, expectedOverlay, result.Overlay)
+		assert.Empty(t, result.Diff)
+	})
+
+	t.Run("complete file lacking the tools added since", func(t *testing.T) {
+		path := writeTestToolsConfig(t, customiseEmbeddedTools(t, "v1.7", func(tools []toolgen.ToolDefinition) []toolgen.ToolDefinition {
+			return deleteTool(deleteTool(tools, "listInstances"), "readResultPage")
+		}))
+
+		result, err := UpgradeToolsFile(path, "")
+		require.NoError(t, err)
+
+		assert.Empty(t, result.Overlay.Tools)
+		assert.Contains(t, result.Diff, "+- name: listInstances\n")
+		assert.Contains(t, result.Diff, "+- name: readResultPage\n")
+	})
+
+	t.Run("overlay", func(t *testing.T) {
+		path := writeTestToolsFile(t, `version: v1.6
+overlay: true
+tools:
+  - name: dockerProxy
+    disabled: true
+  - name: listEnvironments
+    description: List the environments, prefer this tool to find environment IDs
+  - name: removedTool
+    description: A tool removed by the new version
+`)
+
+		result, err := UpgradeToolsFile(path, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedOverlay, result.Overlay)
+		assert.Equal(t, []string{"tool removedTool is not defined by the new version"}, result.Dropped)
+		assert.Empty(t, result.Diff)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := UpgradeToolsFile("nonexistent.yaml", "")
+		assert.Error(t, err)
+	})
+}
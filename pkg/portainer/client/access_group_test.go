@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -131,7 +132,7 @@ func TestGetAccessGroups(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			groups, err := client.GetAccessGroups()
+			groups, err := client.GetAccessGroups(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -177,7 +178,7 @@ func TestCreateAccessGroup(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateAccessGroup(tt.groupName, tt.envIDs)
+			id, err := client.CreateAccessGroup(context.Background(), tt.groupName, tt.envIDs)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -219,7 +220,7 @@ func TestUpdateAccessGroupName(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateAccessGroupName(tt.groupID, tt.newName)
+			err := client.UpdateAccessGroupName(context.Background(), tt.groupID, tt.newName)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -265,7 +266,7 @@ func TestUpdateAccessGroupUserAccesses(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateAccessGroupUserAccesses(tt.groupID, tt.userAccesses)
+			err := client.UpdateAccessGroupUserAccesses(context.Background(), tt.groupID, tt.userAccesses)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -311,7 +312,7 @@ func TestUpdateAccessGroupTeamAccesses(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateAccessGroupTeamAccesses(tt.groupID, tt.teamAccesses)
+			err := client.UpdateAccessGroupTeamAccesses(context.Background(), tt.groupID, tt.teamAccesses)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -352,7 +353,7 @@ func TestAddEnvironmentToAccessGroup(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.AddEnvironmentToAccessGroup(tt.groupID, tt.envID)
+			err := client.AddEnvironmentToAccessGroup(context.Background(), tt.groupID, tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -393,7 +394,7 @@ func TestRemoveEnvironmentFromAccessGroup(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.RemoveEnvironmentFromAccessGroup(tt.groupID, tt.envID)
+			err := client.RemoveEnvironmentFromAccessGroup(context.Background(), tt.groupID, tt.envID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
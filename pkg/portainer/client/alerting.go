@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -17,14 +18,14 @@ import (
 // Returns:
 //   - The raw JSON response as a byte slice (alert format varies by source)
 //   - An error if the operation fails
-func (c *PortainerClient) GetAlerts(status string) (json.RawMessage, error) {
+func (c *PortainerClient) GetAlerts(ctx context.Context, status string) (json.RawMessage, error) {
 	path := "/observability/alerting/alerts"
 	if status != "" {
 		path = fmt.Sprintf("%s?status=%s", path, status)
 	}
 
 	var alerts json.RawMessage
-	if err := c.doJSONAPIRequest(http.MethodGet, path, nil, &alerts); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, path, nil, &alerts); err != nil {
 		return nil, fmt.Errorf("failed to list alerts: %w", err)
 	}
 
@@ -36,9 +37,9 @@ func (c *PortainerClient) GetAlerts(status string) (json.RawMessage, error) {
 // Returns:
 //   - A slice of AlertingRule objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetAlertRules() ([]models.AlertingRule, error) {
+func (c *PortainerClient) GetAlertRules(ctx context.Context) ([]models.AlertingRule, error) {
 	var rules []models.AlertingRule
-	if err := c.doJSONAPIRequest(http.MethodGet, "/observability/alerting/rules", nil, &rules); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/observability/alerting/rules", nil, &rules); err != nil {
 		return nil, fmt.Errorf("failed to list alert rules: %w", err)
 	}
 
@@ -53,9 +54,9 @@ func (c *PortainerClient) GetAlertRules() ([]models.AlertingRule, error) {
 // Returns:
 //   - The AlertingRule object
 //   - An error if the operation fails
-func (c *PortainerClient) GetAlertRule(id int) (models.AlertingRule, error) {
+func (c *PortainerClient) GetAlertRule(ctx context.Context, id int) (models.AlertingRule, error) {
 	var rule models.AlertingRule
-	if err := c.doJSONAPIRequest(http.MethodGet, fmt.Sprintf("/observability/alerting/rules/%d", id), nil, &rule); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, fmt.Sprintf("/observability/alerting/rules/%d", id), nil, &rule); err != nil {
 		return models.AlertingRule{}, fmt.Errorf("failed to get alert rule: %w", err)
 	}
 
@@ -72,10 +73,10 @@ func (c *PortainerClient) GetAlertRule(id int) (models.AlertingRule, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateAlertRule(id int, ruleJSON string) error {
+func (c *PortainerClient) UpdateAlertRule(ctx context.Context, id int, ruleJSON string) error {
 	payload := fmt.Sprintf(`{"alertingRule":%s}`, ruleJSON)
 
-	if err := c.doJSONAPIRequest(http.MethodPut, fmt.Sprintf("/observability/alerting/rules/%d", id), bytes.NewReader([]byte(payload)), nil); err != nil {
+	if err := c.doJSONAPIRequest(withIdempotent(ctx), http.MethodPut, fmt.Sprintf("/observability/alerting/rules/%d", id), bytes.NewReader([]byte(payload)), nil); err != nil {
 		return fmt.Errorf("failed to update alert rule: %w", err)
 	}
 
@@ -89,8 +90,8 @@ func (c *PortainerClient) UpdateAlertRule(id int, ruleJSON string) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteAlertRule(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/observability/alerting/rules/%d", id)); err != nil {
+func (c *PortainerClient) DeleteAlertRule(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/observability/alerting/rules/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete alert rule: %w", err)
 	}
 
@@ -102,9 +103,9 @@ func (c *PortainerClient) DeleteAlertRule(id int) error {
 // Returns:
 //   - A slice of AlertingSettings objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetAlertingSettings() ([]models.AlertingSettings, error) {
+func (c *PortainerClient) GetAlertingSettings(ctx context.Context) ([]models.AlertingSettings, error) {
 	var settings []models.AlertingSettings
-	if err := c.doJSONAPIRequest(http.MethodGet, "/observability/alerting/settings", nil, &settings); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/observability/alerting/settings", nil, &settings); err != nil {
 		return nil, fmt.Errorf("failed to get alerting settings: %w", err)
 	}
 
@@ -121,11 +122,11 @@ func (c *PortainerClient) GetAlertingSettings() ([]models.AlertingSettings, erro
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) CreateAlertSilence(silenceJSON string, alertManagerURL string) error {
+func (c *PortainerClient) CreateAlertSilence(ctx context.Context, silenceJSON string, alertManagerURL string) error {
 	alertManagerURLJSON, _ := json.Marshal(alertManagerURL)
 	payload := fmt.Sprintf(`{"alertManagerURL":%s,"silence":%s}`, string(alertManagerURLJSON), silenceJSON)
 
-	if err := c.doJSONAPIRequest(http.MethodPost, "/observability/alerting/silence", bytes.NewReader([]byte(payload)), nil); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/observability/alerting/silence", bytes.NewReader([]byte(payload)), nil); err != nil {
 		return fmt.Errorf("failed to create alert silence: %w", err)
 	}
 
@@ -139,8 +140,8 @@ func (c *PortainerClient) CreateAlertSilence(silenceJSON string, alertManagerURL
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteAlertSilence(id string) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/observability/alerting/silence/%s", id)); err != nil {
+func (c *PortainerClient) DeleteAlertSilence(ctx context.Context, id string) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/observability/alerting/silence/%s", id)); err != nil {
 		return fmt.Errorf("failed to delete alert silence: %w", err)
 	}
 
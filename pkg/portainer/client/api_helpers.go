@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,20 +9,25 @@ import (
 )
 
 // doJSONAPIRequest performs an API request and decodes the JSON response into the target.
-// If the response status code is not in the 2xx range, it returns an error with the response body.
-func (c *PortainerClient) doJSONAPIRequest(method, path string, body io.Reader, target any) error {
-	resp, err := c.DoAPIRequest(method, path, body)
+// If the response status code is not in the 2xx range, it returns an *APIError.
+// When the request was intercepted in dry-run mode, there is no response to
+// decode into the target and it returns ErrDryRun.
+func (c *PortainerClient) doJSONAPIRequest(ctx context.Context, method, path string, body io.Reader, target any) error {
+	resp, err := c.DoAPIRequest(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return newAPIError(method, path, resp)
 	}
 
 	if target != nil {
+		if IsDryRunResponse(resp) {
+			return ErrDryRun
+		}
+
 		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
 			return fmt.Errorf("failed to decode API response: %w", err)
 		}
@@ -31,16 +37,16 @@ func (c *PortainerClient) doJSONAPIRequest(method, path string, body io.Reader,
 }
 
 // doAPIDelete performs a DELETE request and checks for a successful status code.
-func (c *PortainerClient) doAPIDelete(path string) error {
-	resp, err := c.DoAPIRequest(http.MethodDelete, path, nil)
+// If the response status code is not in the 2xx range, it returns an *APIError.
+func (c *PortainerClient) doAPIDelete(ctx context.Context, path string) error {
+	resp, err := c.DoAPIRequest(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return newAPIError(http.MethodDelete, path, resp)
 	}
 
 	return nil
@@ -1,7 +1,7 @@
 package client
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,37 +11,53 @@ import (
 	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
 )
 
-// PortainerAPIClient defines the interface for the underlying Portainer API client
+// PortainerAPIClient defines the interface for the underlying Portainer API client.
+// Every method takes the context of the originating request so that calls can
+// be cancelled and bounded by a deadline.
 type PortainerAPIClient interface {
-	ListEdgeGroups() ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error)
-	CreateEdgeGroup(name string, environmentIds []int64) (int64, error)
-	UpdateEdgeGroup(id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error
-	ListEdgeStacks() ([]*apimodels.PortainereeEdgeStack, error)
-	CreateEdgeStack(name string, file string, environmentGroupIds []int64) (int64, error)
-	UpdateEdgeStack(id int64, file string, environmentGroupIds []int64) error
-	GetEdgeStackFile(id int64) (string, error)
-	ListEndpointGroups() ([]*apimodels.PortainerEndpointGroup, error)
-	CreateEndpointGroup(name string, associatedEndpoints []int64) (int64, error)
-	UpdateEndpointGroup(id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
-	AddEnvironmentToEndpointGroup(groupId int64, environmentId int64) error
-	RemoveEnvironmentFromEndpointGroup(groupId int64, environmentId int64) error
-	ListEndpoints() ([]*apimodels.PortainereeEndpoint, error)
-	GetEndpoint(id int64) (*apimodels.PortainereeEndpoint, error)
-	UpdateEndpoint(id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
-	GetSettings() (*apimodels.PortainereeSettings, error)
-	ListTags() ([]*apimodels.PortainerTag, error)
-	CreateTag(name string) (int64, error)
-	ListTeams() ([]*apimodels.PortainerTeam, error)
-	ListTeamMemberships() ([]*apimodels.PortainerTeamMembership, error)
-	CreateTeam(name string) (int64, error)
-	UpdateTeamName(id int, name string) error
-	DeleteTeamMembership(id int) error
-	CreateTeamMembership(teamId int, userId int) error
-	ListUsers() ([]*apimodels.PortainereeUser, error)
-	UpdateUserRole(id int, role int64) error
-	GetVersion() (string, error)
-	ProxyDockerRequest(environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
-	ProxyKubernetesRequest(environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
+	ListEdgeGroups(ctx context.Context) ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error)
+	CreateEdgeGroup(ctx context.Context, name string, environmentIds []int64) (int64, error)
+	UpdateEdgeGroup(ctx context.Context, id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error
+	ListEdgeStacks(ctx context.Context) ([]*apimodels.PortainereeEdgeStack, error)
+	CreateEdgeStack(ctx context.Context, name string, file string, environmentGroupIds []int64) (int64, error)
+	UpdateEdgeStack(ctx context.Context, id int64, file string, environmentGroupIds []int64) error
+	GetEdgeStackFile(ctx context.Context, id int64) (string, error)
+	ListEndpointGroups(ctx context.Context) ([]*apimodels.PortainerEndpointGroup, error)
+	CreateEndpointGroup(ctx context.Context, name string, associatedEndpoints []int64) (int64, error)
+	UpdateEndpointGroup(ctx context.Context, id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
+	AddEnvironmentToEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error
+	RemoveEnvironmentFromEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error
+	ListEndpoints(ctx context.Context) ([]*apimodels.PortainereeEndpoint, error)
+	SearchEndpoints(ctx context.Context, query EndpointQuery) ([]*apimodels.PortainereeEndpoint, error)
+	GetEndpoint(ctx context.Context, id int64) (*apimodels.PortainereeEndpoint, error)
+	UpdateEndpoint(ctx context.Context, id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error
+	GetSettings(ctx context.Context) (*apimodels.PortainereeSettings, error)
+	ListTags(ctx context.Context) ([]*apimodels.PortainerTag, error)
+	CreateTag(ctx context.Context, name string) (int64, error)
+	ListTeams(ctx context.Context) ([]*apimodels.PortainerTeam, error)
+	ListTeamMemberships(ctx context.Context) ([]*apimodels.PortainerTeamMembership, error)
+	CreateTeam(ctx context.Context, name string) (int64, error)
+	UpdateTeamName(ctx context.Context, id int, name string) error
+	DeleteTeamMembership(ctx context.Context, id int) error
+	CreateTeamMembership(ctx context.Context, teamId int, userId int) error
+	ListUsers(ctx context.Context) ([]*apimodels.PortainereeUser, error)
+	UpdateUserRole(ctx context.Context, id int, role int64) error
+	GetVersion(ctx context.Context) (string, error)
+	ProxyDockerRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
+	ProxyKubernetesRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error)
+}
+
+// EndpointQuery holds the query parameters of the Portainer endpoint list
+// request narrowing the listed endpoints. Zero fields are not sent.
+type EndpointQuery struct {
+	EndpointIDs []int64
+	Name        string
+	Search      string
+	Statuses    []int64
+	Types       []int64
+	TagIDs      []int64
+	Start       int64
+	Limit       int64
 }
 
 // PortainerClient is a wrapper around the Portainer SDK client
@@ -60,7 +76,14 @@ type ClientOption func(*clientOptions)
 
 // clientOptions holds configuration options for the PortainerClient.
 type clientOptions struct {
-	skipTLSVerify bool
+	skipTLSVerify  bool
+	caCertFile     string
+	clientCertFile string
+	clientKeyFile  string
+	minTLSVersion  string
+
+	requestObserver RequestObserver
+	retryPolicy     RetryPolicy
 }
 
 // WithSkipTLSVerify configures whether to skip TLS certificate verification.
@@ -71,6 +94,31 @@ func WithSkipTLSVerify(skip bool) ClientOption {
 	}
 }
 
+// WithCACertFile configures a PEM encoded CA bundle used to verify the
+// Portainer server certificate, in addition to the system trust store.
+func WithCACertFile(path string) ClientOption {
+	return func(o *clientOptions) {
+		o.caCertFile = path
+	}
+}
+
+// WithClientCertificate configures a PEM encoded client certificate and
+// private key presented to the Portainer server for mutual TLS.
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(o *clientOptions) {
+		o.clientCertFile = certFile
+		o.clientKeyFile = keyFile
+	}
+}
+
+// WithMinTLSVersion configures the minimum TLS version accepted when
+// connecting to the Portainer server. Valid versions are 1.0, 1.1, 1.2 and 1.3.
+func WithMinTLSVersion(version string) ClientOption {
+	return func(o *clientOptions) {
+		o.minTLSVersion = version
+	}
+}
+
 // NewPortainerClient creates a new PortainerClient instance with the provided
 // server URL and authentication token.
 //
@@ -81,46 +129,75 @@ func WithSkipTLSVerify(skip bool) ClientOption {
 //
 // Returns:
 //   - A configured PortainerClient ready for API operations
-func NewPortainerClient(serverURL string, token string, opts ...ClientOption) *PortainerClient {
+//   - An error if the TLS configuration is invalid
+func NewPortainerClient(serverURL string, token string, opts ...ClientOption) (*PortainerClient, error) {
 	options := clientOptions{
 		skipTLSVerify: false, // Default to secure TLS verification
+		retryPolicy:   DefaultRetryPolicy(),
 	}
 
 	for _, opt := range opts {
 		opt(&options)
 	}
 
+	tlsConfig, err := newTLSConfig(options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+
+	if options.requestObserver != nil {
+		transport = &observedTransport{next: transport, observer: options.requestObserver}
+	}
+
+	httpCli := &http.Client{
+		Transport: &dryRunTransport{next: &retryTransport{next: &tracingTransport{next: transport}, policy: options.retryPolicy}},
+	}
+
+	return newPortainerClient(serverURL, token, httpCli), nil
+}
+
+// WithToken returns a copy of the client authenticated with another API token.
+// The copy shares the HTTP client, and therefore the TLS configuration and
+// connection pool, of the original client.
+func (c *PortainerClient) WithToken(token string) *PortainerClient {
+	return newPortainerClient(c.serverURL, token, c.httpCli)
+}
+
+// newPortainerClient creates a PortainerClient sending its requests with the given HTTP client
+func newPortainerClient(serverURL, token string, httpCli *http.Client) *PortainerClient {
 	// The SDK client expects host:port only (not a full URL) and takes the
 	// scheme separately. Parse the serverURL to extract these components so
 	// the SDK doesn't double-prefix the scheme.
-	sdkOpts := []client.ClientOption{client.WithSkipTLSVerify(options.skipTLSVerify)}
 	sdkHost := serverURL
+	sdkScheme := "https"
 
 	if u, err := url.Parse(serverURL); err == nil && u.Host != "" {
 		sdkHost = u.Host
 		if u.Scheme != "" {
-			sdkOpts = append(sdkOpts, client.WithScheme(u.Scheme))
+			sdkScheme = u.Scheme
 		}
 	}
 
 	return &PortainerClient{
-		cli:       client.NewPortainerClient(sdkHost, token, sdkOpts...),
+		cli:       &tracedAPIClient{next: newSDKClient(sdkHost, sdkScheme, token, httpCli)},
 		serverURL: serverURL,
 		token:     token,
-		httpCli: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: options.skipTLSVerify,
-				},
-			},
-		},
+		httpCli:   httpCli,
 	}
 }
 
 // DoAPIRequest makes a direct HTTP request to the Portainer API.
 // This is used for API endpoints not covered by the client-api-go SDK.
 //
+// The request is bound to the given context and is aborted when the
+// context is cancelled or its deadline expires.
+//
 // Parameters:
+//   - ctx: The context of the request
 //   - method: The HTTP method (GET, POST, PUT, DELETE)
 //   - path: The API path (e.g., "/stacks"). Must include leading slash.
 //   - body: The request body (can be nil)
@@ -128,10 +205,10 @@ func NewPortainerClient(serverURL string, token string, opts ...ClientOption) *P
 // Returns:
 //   - *http.Response: The raw HTTP response (caller must close body)
 //   - error: Any error that occurred during the request
-func (c *PortainerClient) DoAPIRequest(method, path string, body io.Reader) (*http.Response, error) {
+func (c *PortainerClient) DoAPIRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	url := fmt.Sprintf("%s/api%s", c.serverURL, path)
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API request: %w", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewPortainerClient(t *testing.T) {
@@ -26,14 +27,34 @@ func TestNewPortainerClient(t *testing.T) {
 			token:     "test-token",
 			opts:      []ClientOption{WithSkipTLSVerify(true)},
 		},
+		{
+			name:        "fails with unsupported minimum TLS version",
+			serverURL:   "https://portainer.example.com",
+			token:       "test-token",
+			opts:        []ClientOption{WithMinTLSVersion("2.0")},
+			expectError: true,
+		},
+		{
+			name:        "fails with missing CA certificate file",
+			serverURL:   "https://portainer.example.com",
+			token:       "test-token",
+			opts:        []ClientOption{WithCACertFile("testdata/nonexistent.pem")},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create client
-			c := NewPortainerClient(tt.serverURL, tt.token, tt.opts...)
+			c, err := NewPortainerClient(tt.serverURL, tt.token, tt.opts...)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, c)
+				return
+			}
 
 			// Assert client was created
+			require.NoError(t, err)
 			assert.NotNil(t, c)
 			assert.NotNil(t, c.cli)
 		})
@@ -70,3 +91,15 @@ func TestWithSkipTLSVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestWithToken(t *testing.T) {
+	c, err := NewPortainerClient("https://portainer.example.com", "server-token")
+	require.NoError(t, err)
+
+	other := c.WithToken("session-token")
+
+	assert.Equal(t, "session-token", other.token)
+	assert.Equal(t, c.serverURL, other.serverURL)
+	assert.Same(t, c.httpCli, other.httpCli, "the HTTP client should be shared")
+	assert.Equal(t, "server-token", c.token, "the original client should be unchanged")
+}
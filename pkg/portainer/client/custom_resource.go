@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,9 +17,9 @@ import (
 // Returns:
 //   - A slice of CustomResourceDefinition objects
 //   - An error if the operation fails
-func (c *PortainerClient) ListCustomResourceDefinitions(environmentID int) ([]models.CustomResourceDefinition, error) {
+func (c *PortainerClient) ListCustomResourceDefinitions(ctx context.Context, environmentID int) ([]models.CustomResourceDefinition, error) {
 	var crds []models.CustomResourceDefinition
-	if err := c.doJSONAPIRequest(http.MethodGet, fmt.Sprintf("/kubernetes/%d/customresourcedefinitions", environmentID), nil, &crds); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, fmt.Sprintf("/kubernetes/%d/customresourcedefinitions", environmentID), nil, &crds); err != nil {
 		return nil, fmt.Errorf("failed to list custom resource definitions: %w", err)
 	}
 
@@ -34,9 +35,9 @@ func (c *PortainerClient) ListCustomResourceDefinitions(environmentID int) ([]mo
 // Returns:
 //   - The CustomResourceDefinition object
 //   - An error if the operation fails
-func (c *PortainerClient) GetCustomResourceDefinition(environmentID int, name string) (models.CustomResourceDefinition, error) {
+func (c *PortainerClient) GetCustomResourceDefinition(ctx context.Context, environmentID int, name string) (models.CustomResourceDefinition, error) {
 	var crd models.CustomResourceDefinition
-	if err := c.doJSONAPIRequest(http.MethodGet, fmt.Sprintf("/kubernetes/%d/customresourcedefinitions/%s", environmentID, name), nil, &crd); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, fmt.Sprintf("/kubernetes/%d/customresourcedefinitions/%s", environmentID, name), nil, &crd); err != nil {
 		return models.CustomResourceDefinition{}, fmt.Errorf("failed to get custom resource definition: %w", err)
 	}
 
@@ -51,8 +52,8 @@ func (c *PortainerClient) GetCustomResourceDefinition(environmentID int, name st
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteCustomResourceDefinition(environmentID int, name string) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/kubernetes/%d/customresourcedefinitions/%s", environmentID, name)); err != nil {
+func (c *PortainerClient) DeleteCustomResourceDefinition(ctx context.Context, environmentID int, name string) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/kubernetes/%d/customresourcedefinitions/%s", environmentID, name)); err != nil {
 		return fmt.Errorf("failed to delete custom resource definition: %w", err)
 	}
 
@@ -68,10 +69,10 @@ func (c *PortainerClient) DeleteCustomResourceDefinition(environmentID int, name
 // Returns:
 //   - A slice of CustomResource objects
 //   - An error if the operation fails
-func (c *PortainerClient) ListCustomResources(environmentID int, definition string) ([]models.CustomResource, error) {
+func (c *PortainerClient) ListCustomResources(ctx context.Context, environmentID int, definition string) ([]models.CustomResource, error) {
 	var resources []models.CustomResource
 	path := fmt.Sprintf("/kubernetes/%d/customresources?definition=%s", environmentID, definition)
-	if err := c.doJSONAPIRequest(http.MethodGet, path, nil, &resources); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, path, nil, &resources); err != nil {
 		return nil, fmt.Errorf("failed to list custom resources: %w", err)
 	}
 
@@ -92,7 +93,7 @@ func (c *PortainerClient) ListCustomResources(environmentID int, definition stri
 // Returns:
 //   - The raw JSON response as a byte slice
 //   - An error if the operation fails
-func (c *PortainerClient) GetCustomResource(environmentID int, namespace, name, definition, format string) (json.RawMessage, error) {
+func (c *PortainerClient) GetCustomResource(ctx context.Context, environmentID int, namespace, name, definition, format string) (json.RawMessage, error) {
 	var path string
 	if namespace != "" {
 		path = fmt.Sprintf("/kubernetes/%d/customresources/%s/%s?definition=%s", environmentID, namespace, name, definition)
@@ -105,7 +106,7 @@ func (c *PortainerClient) GetCustomResource(environmentID int, namespace, name,
 	}
 
 	var result json.RawMessage
-	if err := c.doJSONAPIRequest(http.MethodGet, path, nil, &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
 		return nil, fmt.Errorf("failed to get custom resource: %w", err)
 	}
 
@@ -123,7 +124,7 @@ func (c *PortainerClient) GetCustomResource(environmentID int, namespace, name,
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteCustomResource(environmentID int, namespace, name, definition string) error {
+func (c *PortainerClient) DeleteCustomResource(ctx context.Context, environmentID int, namespace, name, definition string) error {
 	var path string
 	if namespace != "" {
 		path = fmt.Sprintf("/kubernetes/%d/customresources/%s/%s?definition=%s", environmentID, namespace, name, definition)
@@ -131,7 +132,7 @@ func (c *PortainerClient) DeleteCustomResource(environmentID int, namespace, nam
 		path = fmt.Sprintf("/kubernetes/%d/customresources/%s?definition=%s", environmentID, name, definition)
 	}
 
-	if err := c.doAPIDelete(path); err != nil {
+	if err := c.doAPIDelete(ctx, path); err != nil {
 		return fmt.Errorf("failed to delete custom resource: %w", err)
 	}
 
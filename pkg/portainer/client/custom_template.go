@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,9 +15,9 @@ import (
 // Returns:
 //   - A slice of CustomTemplate objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetCustomTemplates() ([]models.CustomTemplate, error) {
+func (c *PortainerClient) GetCustomTemplates(ctx context.Context) ([]models.CustomTemplate, error) {
 	var templates []models.CustomTemplate
-	if err := c.doJSONAPIRequest(http.MethodGet, "/custom_templates", nil, &templates); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/custom_templates", nil, &templates); err != nil {
 		return nil, fmt.Errorf("failed to list custom templates: %w", err)
 	}
 
@@ -31,7 +32,7 @@ func (c *PortainerClient) GetCustomTemplates() ([]models.CustomTemplate, error)
 // Returns:
 //   - The ID of the created custom template
 //   - An error if the operation fails
-func (c *PortainerClient) CreateCustomTemplate(req models.CustomTemplateCreateRequest) (int, error) {
+func (c *PortainerClient) CreateCustomTemplate(ctx context.Context, req models.CustomTemplateCreateRequest) (int, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return 0, fmt.Errorf("failed to marshal create request: %w", err)
@@ -40,7 +41,7 @@ func (c *PortainerClient) CreateCustomTemplate(req models.CustomTemplateCreateRe
 	var result struct {
 		ID int `json:"Id"`
 	}
-	if err := c.doJSONAPIRequest(http.MethodPost, "/custom_templates/create/string", bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/custom_templates/create/string", bytes.NewReader(body), &result); err != nil {
 		return 0, fmt.Errorf("failed to create custom template: %w", err)
 	}
 
@@ -54,8 +55,8 @@ func (c *PortainerClient) CreateCustomTemplate(req models.CustomTemplateCreateRe
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteCustomTemplate(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/custom_templates/%d", id)); err != nil {
+func (c *PortainerClient) DeleteCustomTemplate(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/custom_templates/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete custom template: %w", err)
 	}
 
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -11,8 +12,8 @@ import (
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteAccessGroup(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/endpoint_groups/%d", id)); err != nil {
+func (c *PortainerClient) DeleteAccessGroup(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/endpoint_groups/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete access group: %w", err)
 	}
 
@@ -26,8 +27,8 @@ func (c *PortainerClient) DeleteAccessGroup(id int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteEnvironmentGroup(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/edge_groups/%d", id)); err != nil {
+func (c *PortainerClient) DeleteEnvironmentGroup(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/edge_groups/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete environment group: %w", err)
 	}
 
@@ -41,8 +42,8 @@ func (c *PortainerClient) DeleteEnvironmentGroup(id int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteEdgeStack(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/edge_stacks/%d", id)); err != nil {
+func (c *PortainerClient) DeleteEdgeStack(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/edge_stacks/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete edge stack: %w", err)
 	}
 
@@ -56,8 +57,8 @@ func (c *PortainerClient) DeleteEdgeStack(id int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteTag(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/tags/%d", id)); err != nil {
+func (c *PortainerClient) DeleteTag(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/tags/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete tag: %w", err)
 	}
 
@@ -71,8 +72,8 @@ func (c *PortainerClient) DeleteTag(id int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteTeam(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/teams/%d", id)); err != nil {
+func (c *PortainerClient) DeleteTeam(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/teams/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete team: %w", err)
 	}
 
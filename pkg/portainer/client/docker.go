@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/portainer/client-api-go/v2/client"
@@ -15,7 +16,7 @@ import (
 // Returns:
 //   - *http.Response: The response from the Docker API
 //   - error: Any error that occurred during the request
-func (c *PortainerClient) ProxyDockerRequest(opts models.DockerProxyRequestOptions) (*http.Response, error) {
+func (c *PortainerClient) ProxyDockerRequest(ctx context.Context, opts models.DockerProxyRequestOptions) (*http.Response, error) {
 	proxyOpts := client.ProxyRequestOptions{
 		Method:  opts.Method,
 		APIPath: opts.Path,
@@ -30,5 +31,5 @@ func (c *PortainerClient) ProxyDockerRequest(opts models.DockerProxyRequestOptio
 		proxyOpts.Headers = opts.Headers
 	}
 
-	return c.cli.ProxyDockerRequest(opts.EnvironmentID, proxyOpts)
+	return c.cli.ProxyDockerRequest(ctx, opts.EnvironmentID, proxyOpts)
 }
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,9 +15,9 @@ import (
 // Returns:
 //   - A slice of DockerStack objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetDockerStacks() ([]models.DockerStack, error) {
+func (c *PortainerClient) GetDockerStacks(ctx context.Context) ([]models.DockerStack, error) {
 	var stacks []models.DockerStack
-	if err := c.doJSONAPIRequest(http.MethodGet, "/stacks", nil, &stacks); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/stacks", nil, &stacks); err != nil {
 		return nil, fmt.Errorf("failed to list docker stacks: %w", err)
 	}
 
@@ -31,11 +32,11 @@ func (c *PortainerClient) GetDockerStacks() ([]models.DockerStack, error) {
 // Returns:
 //   - The compose file content
 //   - An error if the operation fails
-func (c *PortainerClient) GetDockerStackFile(id int) (string, error) {
+func (c *PortainerClient) GetDockerStackFile(ctx context.Context, id int) (string, error) {
 	var result struct {
 		StackFileContent string `json:"StackFileContent"`
 	}
-	if err := c.doJSONAPIRequest(http.MethodGet, fmt.Sprintf("/stacks/%d/file", id), nil, &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, fmt.Sprintf("/stacks/%d/file", id), nil, &result); err != nil {
 		return "", fmt.Errorf("failed to get docker stack file: %w", err)
 	}
 
@@ -53,7 +54,7 @@ func (c *PortainerClient) GetDockerStackFile(id int) (string, error) {
 // Returns:
 //   - The ID of the created stack
 //   - An error if the operation fails
-func (c *PortainerClient) CreateDockerStack(endpointID int, name, composeFileContent string, env []models.StackEnvVar) (int, error) {
+func (c *PortainerClient) CreateDockerStack(ctx context.Context, endpointID int, name, composeFileContent string, env []models.StackEnvVar) (int, error) {
 	reqBody := models.DockerStackCreateRequest{
 		Name:             name,
 		StackFileContent: composeFileContent,
@@ -69,7 +70,7 @@ func (c *PortainerClient) CreateDockerStack(endpointID int, name, composeFileCon
 		ID int `json:"Id"`
 	}
 	path := fmt.Sprintf("/stacks/create/standalone/string?endpointId=%d", endpointID)
-	if err := c.doJSONAPIRequest(http.MethodPost, path, bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, path, bytes.NewReader(body), &result); err != nil {
 		return 0, fmt.Errorf("failed to create docker stack: %w", err)
 	}
 
@@ -88,7 +89,7 @@ func (c *PortainerClient) CreateDockerStack(endpointID int, name, composeFileCon
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateDockerStack(id, endpointID int, composeFileContent string, env []models.StackEnvVar, prune, pullImage bool) error {
+func (c *PortainerClient) UpdateDockerStack(ctx context.Context, id, endpointID int, composeFileContent string, env []models.StackEnvVar, prune, pullImage bool) error {
 	reqBody := models.DockerStackUpdateRequest{
 		StackFileContent: composeFileContent,
 		Env:              env,
@@ -102,7 +103,7 @@ func (c *PortainerClient) UpdateDockerStack(id, endpointID int, composeFileConte
 	}
 
 	path := fmt.Sprintf("/stacks/%d?endpointId=%d", id, endpointID)
-	if err := c.doJSONAPIRequest(http.MethodPut, path, bytes.NewReader(body), nil); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPut, path, bytes.NewReader(body), nil); err != nil {
 		return fmt.Errorf("failed to update docker stack: %w", err)
 	}
 
@@ -117,9 +118,9 @@ func (c *PortainerClient) UpdateDockerStack(id, endpointID int, composeFileConte
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteDockerStack(id, endpointID int) error {
+func (c *PortainerClient) DeleteDockerStack(ctx context.Context, id, endpointID int) error {
 	path := fmt.Sprintf("/stacks/%d?endpointId=%d", id, endpointID)
-	if err := c.doAPIDelete(path); err != nil {
+	if err := c.doAPIDelete(ctx, path); err != nil {
 		return fmt.Errorf("failed to delete docker stack: %w", err)
 	}
 
@@ -134,9 +135,9 @@ func (c *PortainerClient) DeleteDockerStack(id, endpointID int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) StartDockerStack(id, endpointID int) error {
+func (c *PortainerClient) StartDockerStack(ctx context.Context, id, endpointID int) error {
 	path := fmt.Sprintf("/stacks/%d/start?endpointId=%d", id, endpointID)
-	if err := c.doJSONAPIRequest(http.MethodPost, path, nil, nil); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, path, nil, nil); err != nil {
 		return fmt.Errorf("failed to start docker stack: %w", err)
 	}
 
@@ -151,9 +152,9 @@ func (c *PortainerClient) StartDockerStack(id, endpointID int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) StopDockerStack(id, endpointID int) error {
+func (c *PortainerClient) StopDockerStack(ctx context.Context, id, endpointID int) error {
 	path := fmt.Sprintf("/stacks/%d/stop?endpointId=%d", id, endpointID)
-	if err := c.doJSONAPIRequest(http.MethodPost, path, nil, nil); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, path, nil, nil); err != nil {
 		return fmt.Errorf("failed to stop docker stack: %w", err)
 	}
 
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DryRunHeader is the header marking the responses made up for the requests
+// intercepted in dry-run mode
+const DryRunHeader = "X-Portainer-Mcp-Dry-Run"
+
+// ErrDryRun is returned by the client methods reading the response of a
+// mutating request when the request was intercepted in dry-run mode, since
+// the response carries no data from Portainer.
+var ErrDryRun = errors.New("the request was not sent in dry-run mode, no response is available")
+
+// PlannedRequest describes a mutating Portainer API request that was
+// intercepted instead of being sent, because it was made in dry-run mode.
+type PlannedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// Body is the decoded JSON body of the request, or the raw body when it is not JSON
+	Body any `json:"body,omitempty"`
+}
+
+// DryRunRecorder collects the mutating requests made with a dry-run context
+type DryRunRecorder struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// dryRunKey is the context key for the dry-run recorder of a request
+type dryRunKey struct{}
+
+// WithDryRun returns a copy of the context in which mutating Portainer API
+// requests (any method other than GET, HEAD and OPTIONS) are recorded instead
+// of being sent. Read requests are still sent, so that the caller can inspect
+// the current state.
+//
+// Intercepted requests receive an empty successful response marked with
+// DryRunHeader: 204 No Content for DELETE and bodiless requests, 200 OK with
+// an empty JSON object otherwise, so that the SDK can parse it. The client
+// methods returning data from the response return ErrDryRun instead.
+//
+// Returns:
+//   - The dry-run context
+//   - The recorder collecting the intercepted requests
+func WithDryRun(ctx context.Context) (context.Context, *DryRunRecorder) {
+	recorder := &DryRunRecorder{}
+	return context.WithValue(ctx, dryRunKey{}, recorder), recorder
+}
+
+// IsDryRun checks if mutating requests made with the context are intercepted
+func IsDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(dryRunKey{}).(*DryRunRecorder)
+	return ok
+}
+
+// IsDryRunResponse checks if a response was made up for a request intercepted
+// in dry-run mode, rather than received from Portainer
+func IsDryRunResponse(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(DryRunHeader) != ""
+}
+
+// Requests returns the intercepted requests in the order they were made
+func (r *DryRunRecorder) Requests() []PlannedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]PlannedRequest(nil), r.requests...)
+}
+
+// record adds an intercepted request to the recorder
+func (r *DryRunRecorder) record(request PlannedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, request)
+}
+
+// dryRunTransport is an http.RoundTripper intercepting mutating requests
+// made with a dry-run context
+type dryRunTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip sends read requests and records mutating requests of dry-run contexts
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder, ok := req.Context().Value(dryRunKey{}).(*DryRunRecorder)
+	if !ok || isReadMethod(req.Method) {
+		return t.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recorder.record(PlannedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Body:   decodeRequestBody(body),
+	})
+
+	resp := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}, DryRunHeader: []string{"true"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}
+
+	if req.Method == http.MethodDelete || len(body) == 0 {
+		resp.Status = "204 No Content"
+		resp.StatusCode = http.StatusNoContent
+		resp.Body = http.NoBody
+	}
+
+	return resp, nil
+}
+
+// isReadMethod checks if an HTTP method does not modify the server state
+func isReadMethod(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// decodeRequestBody decodes a JSON request body, falling back to the raw text
+func decodeRequestBody(body []byte) any {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body)
+	}
+	return decoded
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDryRunTestClient creates a client against a test server recording the methods it receives
+func newDryRunTestClient(t *testing.T) (*PortainerClient, *[]string) {
+	t.Helper()
+
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Version":"2.33.0"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewPortainerClient(srv.URL, "test-token")
+	require.NoError(t, err)
+
+	return c, &received
+}
+
+func TestDryRunInterceptsMutatingRequests(t *testing.T) {
+	tests := []struct {
+		name             string
+		call             func(ctx context.Context, c *PortainerClient) error
+		expectedRequests []PlannedRequest
+		expectedError    error
+	}{
+		{
+			name: "delete request",
+			call: func(ctx context.Context, c *PortainerClient) error {
+				return c.DeleteTag(ctx, 3)
+			},
+			expectedRequests: []PlannedRequest{
+				{Method: http.MethodDelete, URL: "/api/tags/3"},
+			},
+		},
+		{
+			name: "JSON request",
+			call: func(ctx context.Context, c *PortainerClient) error {
+				return c.UpdateSettings(ctx, `{"EnableTelemetry":false}`)
+			},
+			expectedRequests: []PlannedRequest{
+				{Method: http.MethodPut, URL: "/api/settings", Body: map[string]any{"EnableTelemetry": false}},
+			},
+		},
+		{
+			name: "SDK request",
+			call: func(ctx context.Context, c *PortainerClient) error {
+				_, err := c.CreateEnvironmentTag(ctx, "production")
+				return err
+			},
+			expectedRequests: []PlannedRequest{
+				{Method: http.MethodPost, URL: "/api/tags", Body: map[string]any{"name": "production"}},
+			},
+			expectedError: ErrDryRun,
+		},
+		{
+			name: "JSON request reading the response",
+			call: func(ctx context.Context, c *PortainerClient) error {
+				_, err := c.PingRegistry(ctx, models.RegistryPingRequest{URL: "registry.example.com", Type: 3})
+				return err
+			},
+			expectedRequests: []PlannedRequest{
+				{Method: http.MethodPost, URL: "/api/registries/ping", Body: map[string]any{"url": "registry.example.com", "type": float64(3)}},
+			},
+			expectedError: ErrDryRun,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, received := newDryRunTestClient(t)
+
+			ctx, recorder := WithDryRun(context.Background())
+			err := tt.call(ctx, c)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Empty(t, *received, "mutating requests must not reach the server")
+
+			requests := recorder.Requests()
+			require.Len(t, requests, len(tt.expectedRequests))
+			for i, expected := range tt.expectedRequests {
+				assert.Equal(t, expected.Method, requests[i].Method)
+				assert.Contains(t, requests[i].URL, expected.URL)
+				assert.Equal(t, expected.Body, requests[i].Body)
+			}
+		})
+	}
+}
+
+func TestDryRunMarksResponses(t *testing.T) {
+	c, received := newDryRunTestClient(t)
+
+	ctx, _ := WithDryRun(context.Background())
+	resp, err := c.ProxyDockerRequest(ctx, models.DockerProxyRequestOptions{
+		EnvironmentID: 1,
+		Method:        http.MethodPost,
+		Path:          "/containers/create",
+		Body:          strings.NewReader(`{"Image":"nginx"}`),
+	})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.True(t, IsDryRunResponse(resp))
+	assert.Empty(t, *received)
+
+	resp, err = c.ProxyDockerRequest(ctx, models.DockerProxyRequestOptions{
+		EnvironmentID: 1,
+		Method:        http.MethodGet,
+		Path:          "/containers/json",
+	})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.False(t, IsDryRunResponse(resp))
+}
+
+func TestDryRunSendsReadRequests(t *testing.T) {
+	c, received := newDryRunTestClient(t)
+
+	ctx, recorder := WithDryRun(context.Background())
+	version, err := c.GetVersion(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2.33.0", version)
+	assert.Equal(t, []string{"GET /api/system/status"}, *received)
+	assert.Empty(t, recorder.Requests())
+}
+
+func TestDryRunDisabledWithoutRecorder(t *testing.T) {
+	c, received := newDryRunTestClient(t)
+
+	require.NoError(t, c.DeleteTag(context.Background(), 3))
+
+	assert.Equal(t, []string{"DELETE /api/tags/3"}, *received)
+}
+
+func TestIsDryRun(t *testing.T) {
+	ctx, _ := WithDryRun(context.Background())
+
+	assert.True(t, IsDryRun(ctx))
+	assert.False(t, IsDryRun(context.Background()))
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,9 +15,9 @@ import (
 // Returns:
 //   - A slice of EdgeJob objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetEdgeJobs() ([]models.EdgeJob, error) {
+func (c *PortainerClient) GetEdgeJobs(ctx context.Context) ([]models.EdgeJob, error) {
 	var jobs []models.EdgeJob
-	if err := c.doJSONAPIRequest(http.MethodGet, "/edge_jobs", nil, &jobs); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/edge_jobs", nil, &jobs); err != nil {
 		return nil, fmt.Errorf("failed to list edge jobs: %w", err)
 	}
 
@@ -31,9 +32,9 @@ func (c *PortainerClient) GetEdgeJobs() ([]models.EdgeJob, error) {
 // Returns:
 //   - The EdgeJob object
 //   - An error if the operation fails
-func (c *PortainerClient) GetEdgeJob(id int) (models.EdgeJob, error) {
+func (c *PortainerClient) GetEdgeJob(ctx context.Context, id int) (models.EdgeJob, error) {
 	var job models.EdgeJob
-	if err := c.doJSONAPIRequest(http.MethodGet, fmt.Sprintf("/edge_jobs/%d", id), nil, &job); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, fmt.Sprintf("/edge_jobs/%d", id), nil, &job); err != nil {
 		return models.EdgeJob{}, fmt.Errorf("failed to get edge job: %w", err)
 	}
 
@@ -48,7 +49,7 @@ func (c *PortainerClient) GetEdgeJob(id int) (models.EdgeJob, error) {
 // Returns:
 //   - The ID of the created edge job
 //   - An error if the operation fails
-func (c *PortainerClient) CreateEdgeJob(req models.EdgeJobCreateRequest) (int, error) {
+func (c *PortainerClient) CreateEdgeJob(ctx context.Context, req models.EdgeJobCreateRequest) (int, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return 0, fmt.Errorf("failed to marshal create request: %w", err)
@@ -57,7 +58,7 @@ func (c *PortainerClient) CreateEdgeJob(req models.EdgeJobCreateRequest) (int, e
 	var result struct {
 		ID int `json:"Id"`
 	}
-	if err := c.doJSONAPIRequest(http.MethodPost, "/edge_jobs/create/string", bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/edge_jobs/create/string", bytes.NewReader(body), &result); err != nil {
 		return 0, fmt.Errorf("failed to create edge job: %w", err)
 	}
 
@@ -71,8 +72,8 @@ func (c *PortainerClient) CreateEdgeJob(req models.EdgeJobCreateRequest) (int, e
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteEdgeJob(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/edge_jobs/%d", id)); err != nil {
+func (c *PortainerClient) DeleteEdgeJob(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/edge_jobs/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete edge job: %w", err)
 	}
 
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,8 +16,8 @@ import (
 // Returns:
 //   - A slice of Environment objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetEnvironments() ([]models.Environment, error) {
-	endpoints, err := c.cli.ListEndpoints()
+func (c *PortainerClient) GetEnvironments(ctx context.Context) ([]models.Environment, error) {
+	endpoints, err := c.cli.ListEndpoints(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list endpoints: %w", err)
 	}
@@ -29,6 +30,55 @@ func (c *PortainerClient) GetEnvironments() ([]models.Environment, error) {
 	return environments, nil
 }
 
+// SearchEnvironments retrieves the environments matching a query, narrowed
+// and paginated by the Portainer server.
+//
+// Parameters:
+//   - query: The conditions the environments must match, and the page of
+//     the environments to return
+//
+// Returns:
+//   - A slice of Environment objects
+//   - An error if the operation fails or the query has an unknown status or type
+func (c *PortainerClient) SearchEnvironments(ctx context.Context, query models.EnvironmentQuery) ([]models.Environment, error) {
+	endpointQuery := EndpointQuery{
+		EndpointIDs: utils.IntToInt64Slice(query.IDs),
+		Name:        query.Name,
+		Search:      query.Search,
+		TagIDs:      utils.IntToInt64Slice(query.TagIDs),
+		Start:       int64(query.Start),
+		Limit:       int64(query.Limit),
+	}
+
+	for _, status := range query.Statuses {
+		id, ok := models.EnvironmentStatusID(status)
+		if !ok {
+			return nil, fmt.Errorf("unknown environment status: %s", status)
+		}
+		endpointQuery.Statuses = append(endpointQuery.Statuses, id)
+	}
+
+	for _, environmentType := range query.Types {
+		id, ok := models.EnvironmentTypeID(environmentType)
+		if !ok {
+			return nil, fmt.Errorf("unknown environment type: %s", environmentType)
+		}
+		endpointQuery.Types = append(endpointQuery.Types, id)
+	}
+
+	endpoints, err := c.cli.SearchEndpoints(ctx, endpointQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search endpoints: %w", err)
+	}
+
+	environments := make([]models.Environment, len(endpoints))
+	for i, endpoint := range endpoints {
+		environments[i] = models.ConvertEndpointToEnvironment(endpoint)
+	}
+
+	return environments, nil
+}
+
 // UpdateEnvironmentTags updates the tags associated with an environment.
 //
 // Parameters:
@@ -37,9 +87,9 @@ func (c *PortainerClient) GetEnvironments() ([]models.Environment, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentTags(id int, tagIds []int) error {
+func (c *PortainerClient) UpdateEnvironmentTags(ctx context.Context, id int, tagIds []int) error {
 	tags := utils.IntToInt64Slice(tagIds)
-	err := c.cli.UpdateEndpoint(int64(id),
+	err := c.cli.UpdateEndpoint(ctx, int64(id),
 		&tags,
 		nil,
 		nil,
@@ -65,9 +115,9 @@ func (c *PortainerClient) UpdateEnvironmentTags(id int, tagIds []int) error {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentUserAccesses(id int, userAccesses map[int]string) error {
+func (c *PortainerClient) UpdateEnvironmentUserAccesses(ctx context.Context, id int, userAccesses map[int]string) error {
 	uac := utils.IntToInt64Map(userAccesses)
-	err := c.cli.UpdateEndpoint(int64(id),
+	err := c.cli.UpdateEndpoint(ctx, int64(id),
 		nil,
 		&uac,
 		nil,
@@ -93,9 +143,9 @@ func (c *PortainerClient) UpdateEnvironmentUserAccesses(id int, userAccesses map
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironmentTeamAccesses(id int, teamAccesses map[int]string) error {
+func (c *PortainerClient) UpdateEnvironmentTeamAccesses(ctx context.Context, id int, teamAccesses map[int]string) error {
 	tac := utils.IntToInt64Map(teamAccesses)
-	err := c.cli.UpdateEndpoint(int64(id),
+	err := c.cli.UpdateEndpoint(ctx, int64(id),
 		nil,
 		nil,
 		&tac,
@@ -116,7 +166,7 @@ func (c *PortainerClient) UpdateEnvironmentTeamAccesses(id int, teamAccesses map
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateEnvironment(id int, name, publicURL string, groupID int) error {
+func (c *PortainerClient) UpdateEnvironment(ctx context.Context, id int, name, publicURL string, groupID int) error {
 	payload := map[string]any{}
 	if name != "" {
 		payload["name"] = name
@@ -133,7 +183,7 @@ func (c *PortainerClient) UpdateEnvironment(id int, name, publicURL string, grou
 		return fmt.Errorf("failed to marshal update request: %w", err)
 	}
 
-	if err := c.doJSONAPIRequest(http.MethodPut, fmt.Sprintf("/endpoints/%d", id), bytes.NewReader(body), nil); err != nil {
+	if err := c.doJSONAPIRequest(withIdempotent(ctx), http.MethodPut, fmt.Sprintf("/endpoints/%d", id), bytes.NewReader(body), nil); err != nil {
 		return fmt.Errorf("failed to update environment: %w", err)
 	}
 
@@ -145,9 +195,9 @@ func (c *PortainerClient) UpdateEnvironment(id int, name, publicURL string, grou
 // Returns:
 //   - A slice of version strings
 //   - An error if the operation fails
-func (c *PortainerClient) GetAgentVersions() ([]string, error) {
+func (c *PortainerClient) GetAgentVersions(ctx context.Context) ([]string, error) {
 	var versions []string
-	if err := c.doJSONAPIRequest(http.MethodGet, "/endpoints/agent_versions", nil, &versions); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/endpoints/agent_versions", nil, &versions); err != nil {
 		return nil, fmt.Errorf("failed to list agent versions: %w", err)
 	}
 
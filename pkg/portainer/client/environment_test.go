@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -119,7 +120,7 @@ func TestGetEnvironments(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			environments, err := client.GetEnvironments()
+			environments, err := client.GetEnvironments(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -132,6 +133,92 @@ func TestGetEnvironments(t *testing.T) {
 	}
 }
 
+func TestSearchEnvironments(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         models.EnvironmentQuery
+		expectedQuery EndpointQuery
+		mockEndpoints []*apimodels.PortainereeEndpoint
+		mockError     error
+		expected      []models.Environment
+		expectedError string
+	}{
+		{
+			name: "converts the query",
+			query: models.EnvironmentQuery{
+				IDs:      []int{1},
+				Name:     "env1",
+				Search:   "prod",
+				Statuses: []string{models.EnvironmentStatusInactive},
+				Types:    []string{models.EnvironmentTypeKubernetesAgent},
+				TagIDs:   []int{3},
+				Start:    20,
+				Limit:    11,
+			},
+			expectedQuery: EndpointQuery{
+				EndpointIDs: []int64{1},
+				Name:        "env1",
+				Search:      "prod",
+				Statuses:    []int64{2},
+				Types:       []int64{6},
+				TagIDs:      []int64{3},
+				Start:       20,
+				Limit:       11,
+			},
+			mockEndpoints: []*apimodels.PortainereeEndpoint{
+				{ID: 1, Name: "env1", Status: 2, Type: 6, TagIds: []int64{3}},
+			},
+			expected: []models.Environment{
+				{
+					ID:           1,
+					Name:         "env1",
+					Status:       "inactive",
+					Type:         "kubernetes-agent",
+					TagIds:       []int{3},
+					UserAccesses: map[int]string{},
+					TeamAccesses: map[int]string{},
+				},
+			},
+		},
+		{
+			name:          "unknown status",
+			query:         models.EnvironmentQuery{Statuses: []string{"unknown"}},
+			expectedError: "unknown environment status: unknown",
+		},
+		{
+			name:          "unknown type",
+			query:         models.EnvironmentQuery{Types: []string{"podman"}},
+			expectedError: "unknown environment type: podman",
+		},
+		{
+			name:          "search error",
+			query:         models.EnvironmentQuery{Name: "env1"},
+			expectedQuery: EndpointQuery{EndpointIDs: []int64{}, Name: "env1", TagIDs: []int64{}},
+			mockError:     errors.New("failed to list endpoints"),
+			expectedError: "failed to search endpoints: failed to list endpoints",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockPortainerAPI)
+			mockAPI.On("SearchEndpoints", tt.expectedQuery).Return(tt.mockEndpoints, tt.mockError)
+
+			client := &PortainerClient{cli: mockAPI}
+
+			environments, err := client.SearchEnvironments(context.Background(), tt.query)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, environments)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUpdateEnvironmentTags(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -166,7 +253,7 @@ func TestUpdateEnvironmentTags(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentTags(tt.envID, tt.tagIds)
+			err := client.UpdateEnvironmentTags(context.Background(), tt.envID, tt.tagIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -220,7 +307,7 @@ func TestUpdateEnvironmentUserAccesses(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentUserAccesses(tt.envID, tt.userAccesses)
+			err := client.UpdateEnvironmentUserAccesses(context.Background(), tt.envID, tt.userAccesses)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -274,7 +361,7 @@ func TestUpdateEnvironmentTeamAccesses(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentTeamAccesses(tt.envID, tt.teamAccesses)
+			err := client.UpdateEnvironmentTeamAccesses(context.Background(), tt.envID, tt.teamAccesses)
 
 			if tt.expectedError {
 				assert.Error(t, err)
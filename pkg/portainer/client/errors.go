@@ -0,0 +1,117 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize bounds the part of an error response read into an APIError
+const maxErrorBodySize = 64 * 1024
+
+// APIError is returned when the Portainer API answers a request with a status
+// code outside the 2xx range.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Method is the HTTP method of the request
+	Method string
+	// Path is the API path of the request, without the /api prefix
+	Path string
+	// Message is the message field of the Portainer error response, or the
+	// response body when it is not a Portainer error
+	Message string
+	// Details is the details field of the Portainer error response
+	Details string
+}
+
+// Error describes the failed request along with a hint of the likely cause
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s failed with status %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+
+	if hint := e.hint(); hint != "" {
+		fmt.Fprintf(&b, " (%s)", hint)
+	}
+
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.Details != "" && e.Details != e.Message {
+		fmt.Fprintf(&b, ": %s", e.Details)
+	}
+
+	return b.String()
+}
+
+// hint returns the likely cause of the error for the statuses that have one
+func (e *APIError) hint() string {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return "the API token is invalid or expired"
+	case http.StatusForbidden:
+		return "the user of the API token is not allowed to perform this operation"
+	case http.StatusNotFound:
+		return "the resource does not exist"
+	case http.StatusConflict:
+		return "the request conflicts with an existing resource"
+	case http.StatusTooManyRequests:
+		return "the Portainer server is rate limiting requests, retry later"
+	default:
+		return ""
+	}
+}
+
+// newAPIError creates the error of an unsuccessful response, reading the
+// Portainer error message from its body
+func newAPIError(method, path string, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       path,
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var portainerErr struct {
+		Message string `json:"message"`
+		Details string `json:"details"`
+	}
+	if err := json.Unmarshal(body, &portainerErr); err == nil && (portainerErr.Message != "" || portainerErr.Details != "") {
+		apiErr.Message = portainerErr.Message
+		apiErr.Details = portainerErr.Details
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	return apiErr
+}
+
+// IsNotFound checks if the error is an APIError with a 404 Not Found status
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsForbidden checks if the error is an APIError with a 403 Forbidden status
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsUnauthorized checks if the error is an APIError with a 401 Unauthorized status
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsConflict checks if the error is an APIError with a 409 Conflict status
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// hasStatus checks if the error is an APIError with the given status code
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		call          func(ctx context.Context, c *PortainerClient) error
+		expected      APIError
+		expectedError string
+		check         func(error) bool
+	}{
+		{
+			name:   "Portainer error response",
+			status: http.StatusNotFound,
+			body:   `{"message":"Unable to find a webhook with the specified identifier","details":"object not found inside the database"}`,
+			call:   func(ctx context.Context, c *PortainerClient) error { return c.DeleteWebhook(ctx, 7) },
+			expected: APIError{
+				StatusCode: http.StatusNotFound,
+				Method:     http.MethodDelete,
+				Path:       "/webhooks/7",
+				Message:    "Unable to find a webhook with the specified identifier",
+				Details:    "object not found inside the database",
+			},
+			expectedError: "failed to delete webhook: DELETE /webhooks/7 failed with status 404 Not Found (the resource does not exist): Unable to find a webhook with the specified identifier: object not found inside the database",
+			check:         IsNotFound,
+		},
+		{
+			name:   "plain text error response",
+			status: http.StatusForbidden,
+			body:   "Access denied to resource\n",
+			call:   func(ctx context.Context, c *PortainerClient) error { return c.UpdateSettings(ctx, `{}`) },
+			expected: APIError{
+				StatusCode: http.StatusForbidden,
+				Method:     http.MethodPut,
+				Path:       "/settings",
+				Message:    "Access denied to resource",
+			},
+			expectedError: "failed to update settings: PUT /settings failed with status 403 Forbidden (the user of the API token is not allowed to perform this operation): Access denied to resource",
+			check:         IsForbidden,
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			body:   `{"message":"Invalid API key","details":"Unauthorized"}`,
+			call:   func(ctx context.Context, c *PortainerClient) error { return c.DeleteTag(ctx, 1) },
+			expected: APIError{
+				StatusCode: http.StatusUnauthorized,
+				Method:     http.MethodDelete,
+				Path:       "/tags/1",
+				Message:    "Invalid API key",
+				Details:    "Unauthorized",
+			},
+			expectedError: "failed to delete tag: DELETE /tags/1 failed with status 401 Unauthorized (the API token is invalid or expired): Invalid API key: Unauthorized",
+			check:         IsUnauthorized,
+		},
+		{
+			name:   "conflict with identical details",
+			status: http.StatusConflict,
+			body:   `{"message":"This name is already associated to a team","details":"This name is already associated to a team"}`,
+			call:   func(ctx context.Context, c *PortainerClient) error { return c.DeleteTeam(ctx, 2) },
+			expected: APIError{
+				StatusCode: http.StatusConflict,
+				Method:     http.MethodDelete,
+				Path:       "/teams/2",
+				Message:    "This name is already associated to a team",
+				Details:    "This name is already associated to a team",
+			},
+			expectedError: "failed to delete team: DELETE /teams/2 failed with status 409 Conflict (the request conflicts with an existing resource): This name is already associated to a team",
+			check:         IsConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			c, err := NewPortainerClient(srv.URL, "test-token")
+			require.NoError(t, err)
+
+			err = tt.call(context.Background(), c)
+			require.Error(t, err)
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.expected, *apiErr)
+			assert.Equal(t, tt.expectedError, err.Error())
+			assert.True(t, tt.check(err))
+		})
+	}
+}
+
+func TestAPIErrorStatusHelpers(t *testing.T) {
+	notFound := &APIError{StatusCode: http.StatusNotFound}
+
+	assert.True(t, IsNotFound(notFound))
+	assert.False(t, IsForbidden(notFound))
+	assert.False(t, IsUnauthorized(notFound))
+	assert.False(t, IsConflict(notFound))
+	assert.False(t, IsNotFound(errors.New("not found")))
+	assert.False(t, IsNotFound(nil))
+}
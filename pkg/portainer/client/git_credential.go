@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,9 +15,9 @@ import (
 // Returns:
 //   - A slice of GitCredential objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetGitCredentials() ([]models.GitCredential, error) {
+func (c *PortainerClient) GetGitCredentials(ctx context.Context) ([]models.GitCredential, error) {
 	var creds []models.GitCredential
-	if err := c.doJSONAPIRequest(http.MethodGet, "/cloud/gitcredentials", nil, &creds); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/cloud/gitcredentials", nil, &creds); err != nil {
 		return nil, fmt.Errorf("failed to list git credentials: %w", err)
 	}
 
@@ -31,9 +32,9 @@ func (c *PortainerClient) GetGitCredentials() ([]models.GitCredential, error) {
 // Returns:
 //   - The GitCredential object
 //   - An error if the operation fails
-func (c *PortainerClient) GetGitCredential(id int) (models.GitCredential, error) {
+func (c *PortainerClient) GetGitCredential(ctx context.Context, id int) (models.GitCredential, error) {
 	var cred models.GitCredential
-	if err := c.doJSONAPIRequest(http.MethodGet, fmt.Sprintf("/cloud/gitcredentials/%d", id), nil, &cred); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, fmt.Sprintf("/cloud/gitcredentials/%d", id), nil, &cred); err != nil {
 		return models.GitCredential{}, fmt.Errorf("failed to get git credential: %w", err)
 	}
 
@@ -48,7 +49,7 @@ func (c *PortainerClient) GetGitCredential(id int) (models.GitCredential, error)
 // Returns:
 //   - The ID of the created git credential
 //   - An error if the operation fails
-func (c *PortainerClient) CreateGitCredential(req models.GitCredentialCreateRequest) (int, error) {
+func (c *PortainerClient) CreateGitCredential(ctx context.Context, req models.GitCredentialCreateRequest) (int, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return 0, fmt.Errorf("failed to marshal create request: %w", err)
@@ -57,7 +58,7 @@ func (c *PortainerClient) CreateGitCredential(req models.GitCredentialCreateRequ
 	var result struct {
 		ID int `json:"Id"`
 	}
-	if err := c.doJSONAPIRequest(http.MethodPost, "/cloud/gitcredentials", bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/cloud/gitcredentials", bytes.NewReader(body), &result); err != nil {
 		return 0, fmt.Errorf("failed to create git credential: %w", err)
 	}
 
@@ -72,13 +73,13 @@ func (c *PortainerClient) CreateGitCredential(req models.GitCredentialCreateRequ
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateGitCredential(id int, req models.GitCredentialUpdateRequest) error {
+func (c *PortainerClient) UpdateGitCredential(ctx context.Context, id int, req models.GitCredentialUpdateRequest) error {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal update request: %w", err)
 	}
 
-	if err := c.doJSONAPIRequest(http.MethodPut, fmt.Sprintf("/cloud/gitcredentials/%d", id), bytes.NewReader(body), nil); err != nil {
+	if err := c.doJSONAPIRequest(withIdempotent(ctx), http.MethodPut, fmt.Sprintf("/cloud/gitcredentials/%d", id), bytes.NewReader(body), nil); err != nil {
 		return fmt.Errorf("failed to update git credential: %w", err)
 	}
 
@@ -92,8 +93,8 @@ func (c *PortainerClient) UpdateGitCredential(id int, req models.GitCredentialUp
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteGitCredential(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/cloud/gitcredentials/%d", id)); err != nil {
+func (c *PortainerClient) DeleteGitCredential(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/cloud/gitcredentials/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete git credential: %w", err)
 	}
 
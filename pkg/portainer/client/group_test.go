@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -68,7 +69,7 @@ func TestGetEnvironmentGroups(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			groups, err := client.GetEnvironmentGroups()
+			groups, err := client.GetEnvironmentGroups(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -121,7 +122,7 @@ func TestCreateEnvironmentGroup(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateEnvironmentGroup(tt.groupName, tt.environmentIds)
+			id, err := client.CreateEnvironmentGroup(context.Background(), tt.groupName, tt.environmentIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -163,7 +164,7 @@ func TestUpdateEnvironmentGroupName(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentGroupName(tt.groupID, tt.newName)
+			err := client.UpdateEnvironmentGroupName(context.Background(), tt.groupID, tt.newName)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -209,7 +210,7 @@ func TestUpdateEnvironmentGroupEnvironments(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentGroupEnvironments(tt.groupID, tt.environmentIds)
+			err := client.UpdateEnvironmentGroupEnvironments(context.Background(), tt.groupID, tt.environmentIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -255,7 +256,7 @@ func TestUpdateEnvironmentGroupTags(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateEnvironmentGroupTags(tt.groupID, tt.tagIds)
+			err := client.UpdateEnvironmentGroupTags(context.Background(), tt.groupID, tt.tagIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/portainer/client-api-go/v2/client"
@@ -15,7 +16,7 @@ import (
 // Returns:
 //   - *http.Response: The response from the Kubernetes API
 //   - error: Any error that occurred during the request
-func (c *PortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
+func (c *PortainerClient) ProxyKubernetesRequest(ctx context.Context, opts models.KubernetesProxyRequestOptions) (*http.Response, error) {
 	proxyOpts := client.ProxyRequestOptions{
 		Method:  opts.Method,
 		APIPath: opts.Path,
@@ -30,5 +31,5 @@ func (c *PortainerClient) ProxyKubernetesRequest(opts models.KubernetesProxyRequ
 		proxyOpts.Headers = opts.Headers
 	}
 
-	return c.cli.ProxyKubernetesRequest(opts.EnvironmentID, proxyOpts)
+	return c.cli.ProxyKubernetesRequest(ctx, opts.EnvironmentID, proxyOpts)
 }
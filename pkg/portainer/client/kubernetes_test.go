@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -103,7 +104,7 @@ func TestProxyKubernetesRequest(t *testing.T) {
 
 			portainerClient := &PortainerClient{cli: mockAPI}
 
-			resp, err := portainerClient.ProxyKubernetesRequest(tt.opts)
+			resp, err := portainerClient.ProxyKubernetesRequest(context.Background(), tt.opts)
 
 			if tt.expectedError {
 				assert.Error(t, err)
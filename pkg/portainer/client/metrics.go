@@ -0,0 +1,74 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RequestObserver is notified of every request sent to the Portainer API.
+// It is called with the method and the templated path of the request, such
+// as /endpoints/{id}, when the request is sent, and returns a function called
+// with the response status code once the request completes. The status code
+// is 0 when no response was received.
+type RequestObserver func(method, path string) func(statusCode int)
+
+// apiPathRoots are the API paths below which the path segments are not
+// identifiers, such as the proxied Docker and Kubernetes API paths or resource
+// names. Deeper paths are reported as <root>/* to bound the number of paths.
+var apiPathRoots = []string{
+	"/endpoints/{id}/docker",
+	"/endpoints/{id}/kubernetes",
+	"/kubernetes/{id}/customresourcedefinitions",
+	"/kubernetes/{id}/customresources",
+	"/observability/alerting/silence",
+	"/policies/templates",
+}
+
+// WithRequestObserver configures a function notified of every request sent
+// to the Portainer API, for instance to collect metrics.
+func WithRequestObserver(observer RequestObserver) ClientOption {
+	return func(o *clientOptions) {
+		o.requestObserver = observer
+	}
+}
+
+// observedTransport is an http.RoundTripper notifying an observer of every request
+type observedTransport struct {
+	next     http.RoundTripper
+	observer RequestObserver
+}
+
+// RoundTrip sends the request and notifies the observer of its outcome
+func (t *observedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	done := t.observer(req.Method, apiPathTemplate(req.URL.Path))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		done(0)
+		return nil, err
+	}
+
+	done(resp.StatusCode)
+	return resp, nil
+}
+
+// apiPathTemplate returns the path of an API request without its /api prefix
+// and with its numeric identifiers replaced by {id}
+func apiPathTemplate(urlPath string) string {
+	segments := strings.Split(strings.TrimPrefix(urlPath, "/api"), "/")
+	for i, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			segments[i] = "{id}"
+		}
+	}
+
+	template := strings.Join(segments, "/")
+	for _, root := range apiPathRoots {
+		if strings.HasPrefix(template, root+"/") {
+			return root + "/*"
+		}
+	}
+
+	return template
+}
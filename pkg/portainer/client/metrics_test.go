@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIPathTemplate(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/api/tags", expected: "/tags"},
+		{path: "/api/edge_jobs/12", expected: "/edge_jobs/{id}"},
+		{path: "/api/stacks/3/start", expected: "/stacks/{id}/start"},
+		{path: "/api/endpoints/2/docker/containers/json", expected: "/endpoints/{id}/docker/*"},
+		{path: "/api/endpoints/2/kubernetes/api/v1/namespaces/default/pods", expected: "/endpoints/{id}/kubernetes/*"},
+		{path: "/api/kubernetes/2/customresourcedefinitions", expected: "/kubernetes/{id}/customresourcedefinitions"},
+		{path: "/api/kubernetes/2/customresources/default/web", expected: "/kubernetes/{id}/customresources/*"},
+		{path: "/api/observability/alerting/silence/a1b2", expected: "/observability/alerting/silence/*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, apiPathTemplate(tt.path))
+		})
+	}
+}
+
+func TestRequestObserver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var observed []string
+	observer := func(method, path string) func(int) {
+		return func(statusCode int) {
+			observed = append(observed, method+" "+path+" "+http.StatusText(statusCode))
+		}
+	}
+
+	c, err := NewPortainerClient(srv.URL, "test-token", WithRequestObserver(observer))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = c.GetWebhooks(ctx)
+	require.NoError(t, err)
+
+	err = c.DeleteTag(ctx, 4)
+	require.Error(t, err)
+
+	resp, err := c.ProxyDockerRequest(ctx, models.DockerProxyRequestOptions{EnvironmentID: 1, Method: http.MethodGet, Path: "/containers/json"})
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// Requests intercepted in dry-run mode are not sent, and not observed
+	dryRunCtx, _ := WithDryRun(ctx)
+	require.NoError(t, c.DeleteTag(dryRunCtx, 4))
+
+	assert.Equal(t, []string{
+		"GET /webhooks OK",
+		"DELETE /tags/{id} Not Found",
+		"GET /endpoints/{id}/docker/* OK",
+	}, observed)
+}
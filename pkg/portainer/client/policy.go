@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -19,9 +20,9 @@ type policyListResponse struct {
 // Returns:
 //   - A slice of Policy objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetPolicies() ([]models.Policy, error) {
+func (c *PortainerClient) GetPolicies(ctx context.Context) ([]models.Policy, error) {
 	var result policyListResponse
-	if err := c.doJSONAPIRequest(http.MethodGet, "/policies", nil, &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/policies", nil, &result); err != nil {
 		return nil, fmt.Errorf("failed to list policies: %w", err)
 	}
 
@@ -36,9 +37,9 @@ func (c *PortainerClient) GetPolicies() ([]models.Policy, error) {
 // Returns:
 //   - The Policy object
 //   - An error if the operation fails
-func (c *PortainerClient) GetPolicy(id int) (models.Policy, error) {
+func (c *PortainerClient) GetPolicy(ctx context.Context, id int) (models.Policy, error) {
 	var policy models.Policy
-	if err := c.doJSONAPIRequest(http.MethodGet, fmt.Sprintf("/policies/%d", id), nil, &policy); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, fmt.Sprintf("/policies/%d", id), nil, &policy); err != nil {
 		return models.Policy{}, fmt.Errorf("failed to get policy: %w", err)
 	}
 
@@ -53,14 +54,14 @@ func (c *PortainerClient) GetPolicy(id int) (models.Policy, error) {
 // Returns:
 //   - The ID of the created policy
 //   - An error if the operation fails
-func (c *PortainerClient) CreatePolicy(req models.PolicyCreateRequest) (int, error) {
+func (c *PortainerClient) CreatePolicy(ctx context.Context, req models.PolicyCreateRequest) (int, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return 0, fmt.Errorf("failed to marshal create request: %w", err)
 	}
 
 	var result models.Policy
-	if err := c.doJSONAPIRequest(http.MethodPost, "/policies", bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/policies", bytes.NewReader(body), &result); err != nil {
 		return 0, fmt.Errorf("failed to create policy: %w", err)
 	}
 
@@ -75,13 +76,13 @@ func (c *PortainerClient) CreatePolicy(req models.PolicyCreateRequest) (int, err
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdatePolicy(id int, req models.PolicyUpdateRequest) error {
+func (c *PortainerClient) UpdatePolicy(ctx context.Context, id int, req models.PolicyUpdateRequest) error {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal update request: %w", err)
 	}
 
-	if err := c.doJSONAPIRequest(http.MethodPut, fmt.Sprintf("/policies/%d", id), bytes.NewReader(body), nil); err != nil {
+	if err := c.doJSONAPIRequest(withIdempotent(ctx), http.MethodPut, fmt.Sprintf("/policies/%d", id), bytes.NewReader(body), nil); err != nil {
 		return fmt.Errorf("failed to update policy: %w", err)
 	}
 
@@ -95,8 +96,8 @@ func (c *PortainerClient) UpdatePolicy(id int, req models.PolicyUpdateRequest) e
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeletePolicy(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/policies/%d", id)); err != nil {
+func (c *PortainerClient) DeletePolicy(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/policies/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete policy: %w", err)
 	}
 
@@ -117,7 +118,7 @@ type templateListResponse struct {
 // Returns:
 //   - A slice of PolicyTemplate objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetPolicyTemplates(category, policyType string) ([]models.PolicyTemplate, error) {
+func (c *PortainerClient) GetPolicyTemplates(ctx context.Context, category, policyType string) ([]models.PolicyTemplate, error) {
 	path := "/policies/templates"
 	params := []string{}
 
@@ -138,7 +139,7 @@ func (c *PortainerClient) GetPolicyTemplates(category, policyType string) ([]mod
 	}
 
 	var result templateListResponse
-	if err := c.doJSONAPIRequest(http.MethodGet, path, nil, &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
 		return nil, fmt.Errorf("failed to list policy templates: %w", err)
 	}
 
@@ -153,9 +154,9 @@ func (c *PortainerClient) GetPolicyTemplates(category, policyType string) ([]mod
 // Returns:
 //   - The PolicyTemplate object
 //   - An error if the operation fails
-func (c *PortainerClient) GetPolicyTemplate(id string) (models.PolicyTemplate, error) {
+func (c *PortainerClient) GetPolicyTemplate(ctx context.Context, id string) (models.PolicyTemplate, error) {
 	var template models.PolicyTemplate
-	if err := c.doJSONAPIRequest(http.MethodGet, fmt.Sprintf("/policies/templates/%s", id), nil, &template); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, fmt.Sprintf("/policies/templates/%s", id), nil, &template); err != nil {
 		return models.PolicyTemplate{}, fmt.Errorf("failed to get policy template: %w", err)
 	}
 
@@ -172,9 +173,9 @@ type policyMetadataResponse struct {
 // Returns:
 //   - The PolicyMetadata object
 //   - An error if the operation fails
-func (c *PortainerClient) GetPolicyMetadata() (models.PolicyMetadata, error) {
+func (c *PortainerClient) GetPolicyMetadata(ctx context.Context) (models.PolicyMetadata, error) {
 	var result policyMetadataResponse
-	if err := c.doJSONAPIRequest(http.MethodGet, "/policies/metadata", nil, &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/policies/metadata", nil, &result); err != nil {
 		return models.PolicyMetadata{}, fmt.Errorf("failed to get policy metadata: %w", err)
 	}
 
@@ -191,14 +192,14 @@ func (c *PortainerClient) GetPolicyMetadata() (models.PolicyMetadata, error) {
 // Returns:
 //   - The PolicyConflictsResponse with conflict details
 //   - An error if the operation fails
-func (c *PortainerClient) GetPolicyConflicts(req models.PolicyConflictsRequest) (models.PolicyConflictsResponse, error) {
+func (c *PortainerClient) GetPolicyConflicts(ctx context.Context, req models.PolicyConflictsRequest) (models.PolicyConflictsResponse, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return models.PolicyConflictsResponse{}, fmt.Errorf("failed to marshal conflicts request: %w", err)
 	}
 
 	var result models.PolicyConflictsResponse
-	if err := c.doJSONAPIRequest(http.MethodPost, "/policies/conflicts", bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/policies/conflicts", bytes.NewReader(body), &result); err != nil {
 		return models.PolicyConflictsResponse{}, fmt.Errorf("failed to get policy conflicts: %w", err)
 	}
 
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,9 +15,9 @@ import (
 // Returns:
 //   - A slice of Registry objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetRegistries() ([]models.Registry, error) {
+func (c *PortainerClient) GetRegistries(ctx context.Context) ([]models.Registry, error) {
 	var registries []models.Registry
-	if err := c.doJSONAPIRequest(http.MethodGet, "/registries", nil, &registries); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/registries", nil, &registries); err != nil {
 		return nil, fmt.Errorf("failed to list registries: %w", err)
 	}
 
@@ -31,7 +32,7 @@ func (c *PortainerClient) GetRegistries() ([]models.Registry, error) {
 // Returns:
 //   - The ID of the created registry
 //   - An error if the operation fails
-func (c *PortainerClient) CreateRegistry(req models.RegistryCreateRequest) (int, error) {
+func (c *PortainerClient) CreateRegistry(ctx context.Context, req models.RegistryCreateRequest) (int, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return 0, fmt.Errorf("failed to marshal create request: %w", err)
@@ -40,7 +41,7 @@ func (c *PortainerClient) CreateRegistry(req models.RegistryCreateRequest) (int,
 	var result struct {
 		ID int `json:"Id"`
 	}
-	if err := c.doJSONAPIRequest(http.MethodPost, "/registries", bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/registries", bytes.NewReader(body), &result); err != nil {
 		return 0, fmt.Errorf("failed to create registry: %w", err)
 	}
 
@@ -54,8 +55,8 @@ func (c *PortainerClient) CreateRegistry(req models.RegistryCreateRequest) (int,
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteRegistry(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/registries/%d", id)); err != nil {
+func (c *PortainerClient) DeleteRegistry(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/registries/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete registry: %w", err)
 	}
 
@@ -70,14 +71,14 @@ func (c *PortainerClient) DeleteRegistry(id int) error {
 // Returns:
 //   - The ping response indicating success or failure
 //   - An error if the operation fails
-func (c *PortainerClient) PingRegistry(req models.RegistryPingRequest) (models.RegistryPingResponse, error) {
+func (c *PortainerClient) PingRegistry(ctx context.Context, req models.RegistryPingRequest) (models.RegistryPingResponse, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return models.RegistryPingResponse{}, fmt.Errorf("failed to marshal ping request: %w", err)
 	}
 
 	var result models.RegistryPingResponse
-	if err := c.doJSONAPIRequest(http.MethodPost, "/registries/ping", bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/registries/ping", bytes.NewReader(body), &result); err != nil {
 		return models.RegistryPingResponse{}, fmt.Errorf("failed to ping registry: %w", err)
 	}
 
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Default retry policy of the Portainer client
+const (
+	DefaultMaxRetries     = 2
+	DefaultInitialBackoff = 250 * time.Millisecond
+	DefaultMaxBackoff     = 4 * time.Second
+)
+
+// RetryPolicy configures how idempotent requests are retried after a network
+// error, a 429 Too Many Requests or a 5xx response other than 501 Not Implemented.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt, 0 disables retries
+	MaxRetries int
+	// InitialBackoff is the upper bound of the delay before the first retry.
+	// The bound doubles on every retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay before a retry, including the delay requested
+	// by the Retry-After header of the response
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used unless configured with WithRetryPolicy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     DefaultMaxRetries,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// WithRetryPolicy configures the retries of idempotent requests.
+// Only GET, HEAD and OPTIONS requests, and requests marked idempotent by the
+// client methods, such as the PUT request updating the settings, are retried.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// idempotentKey is the context key marking a mutating request as safe to retry
+type idempotentKey struct{}
+
+// withIdempotent returns a copy of the context in which mutating requests are
+// retried like read requests. It is used for requests replacing a resource
+// with the same state however many times they are sent.
+func withIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// isIdempotent checks if a request can be sent again without changing its outcome
+func isIdempotent(req *http.Request) bool {
+	if isReadMethod(req.Method) {
+		return true
+	}
+
+	idempotent, _ := req.Context().Value(idempotentKey{}).(bool)
+	return idempotent
+}
+
+// retryTransport is an http.RoundTripper retrying idempotent requests
+// with a jittered exponential backoff
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+// RoundTrip sends the request, retrying it while it fails with a transient error
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.policy.MaxRetries <= 0 || !isIdempotent(req) || (req.Body != nil && req.GetBody == nil) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.policy.MaxRetries || !isRetryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := t.policy.backoff(attempt, resp)
+		if resp != nil {
+			// Drain the body so that the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// isRetryable checks if the outcome of a request is a transient failure
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && isTransientNetworkError(err)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+}
+
+// isTransientNetworkError checks if a request failed because of a network
+// error that may not happen again, such as a refused or reset connection.
+// Other failures, such as TLS certificate errors, are permanent.
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns the delay before the retry following the given attempt,
+// picked at random up to an exponentially growing bound (full jitter). A
+// Retry-After header in seconds takes precedence. The delay never exceeds
+// MaxBackoff.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, p.MaxBackoff)
+		}
+	}
+
+	bound := p.InitialBackoff << attempt
+	if bound <= 0 || bound > p.MaxBackoff {
+		bound = p.MaxBackoff
+	}
+	if bound <= 0 {
+		return 0
+	}
+
+	return rand.N(bound)
+}
+
+// sleep waits for the given delay, returning early with the context error
+// when the context is cancelled
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
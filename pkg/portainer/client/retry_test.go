@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRetryPolicy retries quickly so that the tests stay fast
+var testRetryPolicy = RetryPolicy{
+	MaxRetries:     2,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		call             func(ctx context.Context, c *PortainerClient) error
+		statuses         []int
+		expectedAttempts int
+		expectError      bool
+	}{
+		{
+			name:             "GET retried until success",
+			call:             func(ctx context.Context, c *PortainerClient) error { _, err := c.GetWebhooks(ctx); return err },
+			statuses:         []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "GET retried at most MaxRetries times",
+			call:             func(ctx context.Context, c *PortainerClient) error { _, err := c.GetWebhooks(ctx); return err },
+			statuses:         []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			expectedAttempts: 3,
+			expectError:      true,
+		},
+		{
+			name:             "GET rate limited",
+			call:             func(ctx context.Context, c *PortainerClient) error { _, err := c.GetWebhooks(ctx); return err },
+			statuses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			expectedAttempts: 2,
+		},
+		{
+			name:             "GET not retried on client errors",
+			call:             func(ctx context.Context, c *PortainerClient) error { _, err := c.GetWebhooks(ctx); return err },
+			statuses:         []int{http.StatusNotFound, http.StatusOK},
+			expectedAttempts: 1,
+			expectError:      true,
+		},
+		{
+			name:             "GET not retried on not implemented",
+			call:             func(ctx context.Context, c *PortainerClient) error { _, err := c.GetWebhooks(ctx); return err },
+			statuses:         []int{http.StatusNotImplemented, http.StatusOK},
+			expectedAttempts: 1,
+			expectError:      true,
+		},
+		{
+			name: "idempotent PUT retried with its body",
+			call: func(ctx context.Context, c *PortainerClient) error {
+				return c.UpdateSettings(ctx, `{"EnableTelemetry":false}`)
+			},
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 2,
+		},
+		{
+			name:             "DELETE not retried",
+			call:             func(ctx context.Context, c *PortainerClient) error { return c.DeleteWebhook(ctx, 1) },
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 1,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := int(attempts.Add(1))
+
+				if r.Method == http.MethodPut {
+					body, _ := io.ReadAll(r.Body)
+					assert.JSONEq(t, `{"EnableTelemetry":false}`, string(body))
+				}
+
+				w.WriteHeader(tt.statuses[attempt-1])
+				if r.Method == http.MethodGet {
+					w.Write([]byte(`[]`))
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			c, err := NewPortainerClient(srv.URL, "test-token", WithRetryPolicy(testRetryPolicy))
+			require.NoError(t, err)
+
+			err = tt.call(context.Background(), c)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedAttempts, int(attempts.Load()))
+		})
+	}
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serverURL := srv.URL
+	srv.Close()
+
+	var attempts int
+	c, err := NewPortainerClient(serverURL, "test-token",
+		WithRetryPolicy(testRetryPolicy),
+		WithRequestObserver(func(method, path string) func(int) {
+			attempts++
+			return func(int) {}
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = c.GetWebhooks(context.Background())
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryStopsWhenContextIsCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewPortainerClient(srv.URL, "test-token", WithRetryPolicy(RetryPolicy{
+		MaxRetries:     5,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Minute,
+	}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = c.GetWebhooks(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := range 10 {
+		delay := policy.backoff(attempt, nil)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.Less(t, delay, min(policy.InitialBackoff<<attempt, policy.MaxBackoff))
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"1"}}}
+	assert.Equal(t, time.Second, policy.backoff(0, resp))
+
+	resp.Header.Set("Retry-After", "120")
+	assert.Equal(t, policy.MaxBackoff, policy.backoff(0, resp))
+}
@@ -0,0 +1,532 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/portainer/client-api-go/v2/client"
+	"github.com/portainer/client-api-go/v2/client/utils"
+	apiclient "github.com/portainer/client-api-go/v2/pkg/client"
+	"github.com/portainer/client-api-go/v2/pkg/client/edge_groups"
+	"github.com/portainer/client-api-go/v2/pkg/client/edge_stacks"
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoint_groups"
+	"github.com/portainer/client-api-go/v2/pkg/client/endpoints"
+	"github.com/portainer/client-api-go/v2/pkg/client/settings"
+	"github.com/portainer/client-api-go/v2/pkg/client/system"
+	"github.com/portainer/client-api-go/v2/pkg/client/tags"
+	"github.com/portainer/client-api-go/v2/pkg/client/team_memberships"
+	"github.com/portainer/client-api-go/v2/pkg/client/teams"
+	"github.com/portainer/client-api-go/v2/pkg/client/users"
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+)
+
+// sdkClient implements PortainerAPIClient on top of the swagger generated
+// Portainer API client.
+//
+// It mirrors the client-api-go SDK wrapper, which neither accepts a context
+// nor a custom HTTP client, so that every SDK call is bound to the context of
+// the originating request and shares the HTTP client used for direct API calls.
+type sdkClient struct {
+	api     *apiclient.PortainerClientAPI
+	baseURL string
+	token   string
+	httpCli *http.Client
+}
+
+// newSDKClient creates a new sdkClient.
+//
+// Parameters:
+//   - host: The Portainer server host and port (e.g., "portainer.example.com:9443")
+//   - scheme: The scheme used to reach the server (http or https)
+//   - token: The authentication token for API access
+//   - httpCli: The HTTP client used for all requests
+func newSDKClient(host, scheme, token string, httpCli *http.Client) *sdkClient {
+	transport := httptransport.NewWithClient(host, "/api", []string{scheme}, httpCli)
+	transport.DefaultAuthentication = runtime.ClientAuthInfoWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+		return r.SetHeaderParam("x-api-key", token)
+	})
+
+	return &sdkClient{
+		api:     apiclient.New(transport, nil),
+		baseURL: fmt.Sprintf("%s://%s", scheme, host),
+		token:   token,
+		httpCli: httpCli,
+	}
+}
+
+// createdID returns the ID of a resource created through the SDK, or ErrDryRun
+// when the request was intercepted in dry-run mode, in which case the ID was
+// parsed from the empty response of the interceptor.
+func createdID(ctx context.Context, id int64) (int64, error) {
+	if IsDryRun(ctx) {
+		return 0, ErrDryRun
+	}
+	return id, nil
+}
+
+// sdkError converts the error of an SDK call answered with an unsuccessful
+// status into an APIError, so that it can be inspected like the errors of
+// direct API calls. The SDK discards the body of an error response, so the
+// APIError carries no message. Other errors are returned unchanged.
+func sdkError(method, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Statuses missing from the API specification
+	var runtimeErr *runtime.APIError
+	if errors.As(err, &runtimeErr) {
+		return &APIError{StatusCode: runtimeErr.Code, Method: method, Path: path}
+	}
+
+	// Statuses declared in the API specification have their own error type
+	var statusErr interface{ Code() int }
+	if errors.As(err, &statusErr) {
+		return &APIError{StatusCode: statusErr.Code(), Method: method, Path: path}
+	}
+
+	return err
+}
+
+// ListEdgeGroups lists all edge groups
+func (c *sdkClient) ListEdgeGroups(ctx context.Context) ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error) {
+	params := edge_groups.NewEdgeGroupListParamsWithContext(ctx)
+	resp, err := c.api.EdgeGroups.EdgeGroupList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list edge groups: %w", sdkError(http.MethodGet, "/edge_groups", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// CreateEdgeGroup creates a new edge group
+func (c *sdkClient) CreateEdgeGroup(ctx context.Context, name string, environmentIds []int64) (int64, error) {
+	params := edge_groups.NewEdgeGroupCreateParamsWithContext(ctx).WithBody(&apimodels.EdgegroupsEdgeGroupCreatePayload{
+		Name:      name,
+		Endpoints: environmentIds,
+		Dynamic:   false,
+	})
+
+	resp, err := c.api.EdgeGroups.EdgeGroupCreate(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create edge group: %w", sdkError(http.MethodPost, "/edge_groups", err))
+	}
+
+	return createdID(ctx, resp.Payload.ID)
+}
+
+// UpdateEdgeGroup updates an existing edge group.
+// Nil parameters keep the existing values.
+func (c *sdkClient) UpdateEdgeGroup(ctx context.Context, id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error {
+	params := edge_groups.NewEdgeGroupUpdateParamsWithContext(ctx).WithID(id).WithBody(&apimodels.EdgegroupsEdgeGroupUpdatePayload{})
+
+	if name != nil {
+		params.Body.Name = *name
+	}
+
+	if environmentIds != nil {
+		params.Body.Endpoints = *environmentIds
+	}
+
+	if tagIds != nil {
+		params.Body.TagIDs = *tagIds
+		params.Body.Dynamic = true
+	}
+
+	if _, err := c.api.EdgeGroups.EdgeGroupUpdate(params, nil); err != nil {
+		return fmt.Errorf("failed to update edge group: %w", sdkError(http.MethodPut, fmt.Sprintf("/edge_groups/%d", id), err))
+	}
+
+	return nil
+}
+
+// ListEdgeStacks lists all edge stacks
+func (c *sdkClient) ListEdgeStacks(ctx context.Context) ([]*apimodels.PortainereeEdgeStack, error) {
+	params := edge_stacks.NewEdgeStackListParamsWithContext(ctx)
+	resp, err := c.api.EdgeStacks.EdgeStackList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list edge stacks: %w", sdkError(http.MethodGet, "/edge_stacks", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// CreateEdgeStack creates a new edge stack
+func (c *sdkClient) CreateEdgeStack(ctx context.Context, name string, file string, environmentGroupIds []int64) (int64, error) {
+	params := edge_stacks.NewEdgeStackCreateStringParamsWithContext(ctx).WithBody(&apimodels.EdgestacksEdgeStackFromStringPayload{
+		Name:             &name,
+		StackFileContent: &file,
+		EdgeGroups:       environmentGroupIds,
+		DeploymentType:   0,
+	})
+
+	resp, err := c.api.EdgeStacks.EdgeStackCreateString(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create edge stack: %w", sdkError(http.MethodPost, "/edge_stacks/create/string", err))
+	}
+
+	return createdID(ctx, resp.Payload.ID)
+}
+
+// UpdateEdgeStack updates an existing edge stack
+func (c *sdkClient) UpdateEdgeStack(ctx context.Context, id int64, file string, environmentGroupIds []int64) error {
+	params := edge_stacks.NewEdgeStackUpdateParamsWithContext(ctx).WithID(id).WithBody(&apimodels.EdgestacksUpdateEdgeStackPayload{
+		StackFileContent: file,
+		EdgeGroups:       environmentGroupIds,
+		UpdateVersion:    true,
+	})
+
+	if _, err := c.api.EdgeStacks.EdgeStackUpdate(params, nil); err != nil {
+		return fmt.Errorf("failed to update edge stack: %w", sdkError(http.MethodPut, fmt.Sprintf("/edge_stacks/%d", id), err))
+	}
+
+	return nil
+}
+
+// GetEdgeStackFile gets the file for an edge stack
+func (c *sdkClient) GetEdgeStackFile(ctx context.Context, id int64) (string, error) {
+	params := edge_stacks.NewEdgeStackFileParamsWithContext(ctx).WithID(id)
+	resp, err := c.api.EdgeStacks.EdgeStackFile(params, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get edge stack file: %w", sdkError(http.MethodGet, fmt.Sprintf("/edge_stacks/%d/file", id), err))
+	}
+
+	return resp.Payload.StackFileContent, nil
+}
+
+// ListEndpointGroups lists all endpoint groups
+func (c *sdkClient) ListEndpointGroups(ctx context.Context) ([]*apimodels.PortainerEndpointGroup, error) {
+	params := endpoint_groups.NewEndpointGroupListParamsWithContext(ctx)
+	resp, err := c.api.EndpointGroups.EndpointGroupList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint groups: %w", sdkError(http.MethodGet, "/endpoint_groups", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// CreateEndpointGroup creates a new endpoint group
+func (c *sdkClient) CreateEndpointGroup(ctx context.Context, name string, associatedEndpoints []int64) (int64, error) {
+	params := endpoint_groups.NewPostEndpointGroupsParamsWithContext(ctx).WithBody(&apimodels.EndpointgroupsEndpointGroupCreatePayload{
+		Name:                &name,
+		AssociatedEndpoints: associatedEndpoints,
+	})
+
+	resp, err := c.api.EndpointGroups.PostEndpointGroups(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create endpoint group: %w", sdkError(http.MethodPost, "/endpoint_groups", err))
+	}
+
+	return createdID(ctx, resp.Payload.ID)
+}
+
+// UpdateEndpointGroup updates an existing endpoint group.
+// Nil parameters keep the existing values.
+func (c *sdkClient) UpdateEndpointGroup(ctx context.Context, id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
+	params := endpoint_groups.NewEndpointGroupUpdateParamsWithContext(ctx).WithID(id).WithBody(&apimodels.EndpointgroupsEndpointGroupUpdatePayload{})
+
+	if name != nil {
+		params.Body.Name = *name
+	}
+
+	if userAccesses != nil {
+		params.Body.UserAccessPolicies = utils.BuildAccessPolicies[apimodels.PortainerUserAccessPolicies](*userAccesses)
+	}
+
+	if teamAccesses != nil {
+		params.Body.TeamAccessPolicies = utils.BuildAccessPolicies[apimodels.PortainerTeamAccessPolicies](*teamAccesses)
+	}
+
+	if _, err := c.api.EndpointGroups.EndpointGroupUpdate(params, nil); err != nil {
+		return fmt.Errorf("failed to update endpoint group: %w", sdkError(http.MethodPut, fmt.Sprintf("/endpoint_groups/%d", id), err))
+	}
+
+	return nil
+}
+
+// AddEnvironmentToEndpointGroup adds an environment to an endpoint group
+func (c *sdkClient) AddEnvironmentToEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error {
+	params := endpoint_groups.NewEndpointGroupAddEndpointParamsWithContext(ctx).WithID(groupId).WithEndpointID(environmentId)
+	if _, err := c.api.EndpointGroups.EndpointGroupAddEndpoint(params, nil); err != nil {
+		return fmt.Errorf("failed to add environment to endpoint group: %w", sdkError(http.MethodPut, fmt.Sprintf("/endpoint_groups/%d/endpoints/%d", groupId, environmentId), err))
+	}
+
+	return nil
+}
+
+// RemoveEnvironmentFromEndpointGroup removes an environment from an endpoint group
+func (c *sdkClient) RemoveEnvironmentFromEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error {
+	params := endpoint_groups.NewEndpointGroupDeleteEndpointParamsWithContext(ctx).WithID(groupId).WithEndpointID(environmentId)
+	if _, err := c.api.EndpointGroups.EndpointGroupDeleteEndpoint(params, nil); err != nil {
+		return fmt.Errorf("failed to remove environment from endpoint group: %w", sdkError(http.MethodDelete, fmt.Sprintf("/endpoint_groups/%d/endpoints/%d", groupId, environmentId), err))
+	}
+
+	return nil
+}
+
+// ListEndpoints lists all endpoints
+func (c *sdkClient) ListEndpoints(ctx context.Context) ([]*apimodels.PortainereeEndpoint, error) {
+	params := endpoints.NewEndpointListParamsWithContext(ctx)
+	resp, err := c.api.Endpoints.EndpointList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints: %w", sdkError(http.MethodGet, "/endpoints", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// SearchEndpoints lists the endpoints matching a query. The snapshots of the
+// endpoints are excluded. The SDK sends the values of a list parameter
+// separated by commas.
+func (c *sdkClient) SearchEndpoints(ctx context.Context, query EndpointQuery) ([]*apimodels.PortainereeEndpoint, error) {
+	excludeSnapshots := true
+	params := endpoints.NewEndpointListParamsWithContext(ctx).WithExcludeSnapshots(&excludeSnapshots)
+
+	if len(query.EndpointIDs) > 0 {
+		params.SetEndpointIds(query.EndpointIDs)
+	}
+	if query.Name != "" {
+		params.SetName(&query.Name)
+	}
+	if query.Search != "" {
+		params.SetSearch(&query.Search)
+	}
+	if len(query.Statuses) > 0 {
+		params.SetStatus(query.Statuses)
+	}
+	if len(query.Types) > 0 {
+		params.SetTypes(query.Types)
+	}
+	if len(query.TagIDs) > 0 {
+		partialMatch := false
+		params.SetTagIds(query.TagIDs)
+		params.SetTagsPartialMatch(&partialMatch)
+	}
+	if query.Start > 0 {
+		params.SetStart(&query.Start)
+	}
+	if query.Limit > 0 {
+		params.SetLimit(&query.Limit)
+	}
+
+	resp, err := c.api.Endpoints.EndpointList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search endpoints: %w", sdkError(http.MethodGet, "/endpoints", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// GetEndpoint gets a specific endpoint by ID
+func (c *sdkClient) GetEndpoint(ctx context.Context, id int64) (*apimodels.PortainereeEndpoint, error) {
+	params := endpoints.NewEndpointInspectParamsWithContext(ctx).WithID(id)
+	resp, err := c.api.Endpoints.EndpointInspect(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endpoint: %w", sdkError(http.MethodGet, fmt.Sprintf("/endpoints/%d", id), err))
+	}
+
+	return resp.Payload, nil
+}
+
+// UpdateEndpoint updates the tags and access policies of an endpoint.
+// Nil parameters keep the existing values.
+func (c *sdkClient) UpdateEndpoint(ctx context.Context, id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
+	params := endpoints.NewEndpointUpdateParamsWithContext(ctx).WithID(id).WithBody(&apimodels.EndpointsEndpointUpdatePayload{})
+
+	if tagIds != nil {
+		params.Body.TagIDs = *tagIds
+	}
+
+	if userAccesses != nil {
+		params.Body.UserAccessPolicies = utils.BuildAccessPolicies[apimodels.PortainerUserAccessPolicies](*userAccesses)
+	}
+
+	if teamAccesses != nil {
+		params.Body.TeamAccessPolicies = utils.BuildAccessPolicies[apimodels.PortainerTeamAccessPolicies](*teamAccesses)
+	}
+
+	_, err := c.api.Endpoints.EndpointUpdate(params, nil)
+	return sdkError(http.MethodPut, fmt.Sprintf("/endpoints/%d", id), err)
+}
+
+// GetSettings retrieves the Portainer settings
+func (c *sdkClient) GetSettings(ctx context.Context) (*apimodels.PortainereeSettings, error) {
+	params := settings.NewSettingsInspectParamsWithContext(ctx)
+	resp, err := c.api.Settings.SettingsInspect(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", sdkError(http.MethodGet, "/settings", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// ListTags lists all tags
+func (c *sdkClient) ListTags(ctx context.Context) ([]*apimodels.PortainerTag, error) {
+	params := tags.NewTagListParamsWithContext(ctx)
+	resp, err := c.api.Tags.TagList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", sdkError(http.MethodGet, "/tags", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// CreateTag creates a new tag
+func (c *sdkClient) CreateTag(ctx context.Context, name string) (int64, error) {
+	params := tags.NewTagCreateParamsWithContext(ctx).WithBody(&apimodels.TagsTagCreatePayload{
+		Name: &name,
+	})
+
+	resp, err := c.api.Tags.TagCreate(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create tag: %w", sdkError(http.MethodPost, "/tags", err))
+	}
+
+	return createdID(ctx, resp.Payload.ID)
+}
+
+// ListTeams lists all teams
+func (c *sdkClient) ListTeams(ctx context.Context) ([]*apimodels.PortainerTeam, error) {
+	params := teams.NewTeamListParamsWithContext(ctx)
+	resp, err := c.api.Teams.TeamList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", sdkError(http.MethodGet, "/teams", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// ListTeamMemberships lists all team memberships
+func (c *sdkClient) ListTeamMemberships(ctx context.Context) ([]*apimodels.PortainerTeamMembership, error) {
+	params := team_memberships.NewTeamMembershipListParamsWithContext(ctx)
+	resp, err := c.api.TeamMemberships.TeamMembershipList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team memberships: %w", sdkError(http.MethodGet, "/team_memberships", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// CreateTeam creates a new team
+func (c *sdkClient) CreateTeam(ctx context.Context, name string) (int64, error) {
+	params := teams.NewTeamCreateParamsWithContext(ctx).WithBody(&apimodels.TeamsTeamCreatePayload{
+		Name: &name,
+	})
+
+	resp, err := c.api.Teams.TeamCreate(params, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create team: %w", sdkError(http.MethodPost, "/teams", err))
+	}
+
+	return createdID(ctx, resp.Payload.ID)
+}
+
+// UpdateTeamName updates the name of a team
+func (c *sdkClient) UpdateTeamName(ctx context.Context, id int, name string) error {
+	params := teams.NewTeamUpdateParamsWithContext(ctx).WithID(int64(id)).WithBody(&apimodels.TeamsTeamUpdatePayload{
+		Name: name,
+	})
+
+	_, err := c.api.Teams.TeamUpdate(params, nil)
+	return sdkError(http.MethodPut, fmt.Sprintf("/teams/%d", id), err)
+}
+
+// DeleteTeamMembership deletes a team membership
+func (c *sdkClient) DeleteTeamMembership(ctx context.Context, id int) error {
+	params := team_memberships.NewTeamMembershipDeleteParamsWithContext(ctx).WithID(int64(id))
+	_, err := c.api.TeamMemberships.TeamMembershipDelete(params, nil)
+	return sdkError(http.MethodDelete, fmt.Sprintf("/team_memberships/%d", id), err)
+}
+
+// CreateTeamMembership adds a user to a team with the team member role
+func (c *sdkClient) CreateTeamMembership(ctx context.Context, teamId int, userId int) error {
+	teamID := int64(teamId)
+	userID := int64(userId)
+	// Default to team member role
+	role := int64(2)
+
+	params := team_memberships.NewTeamMembershipCreateParamsWithContext(ctx).WithBody(&apimodels.TeammembershipsTeamMembershipCreatePayload{
+		Role:   &role,
+		TeamID: &teamID,
+		UserID: &userID,
+	})
+
+	_, err := c.api.TeamMemberships.TeamMembershipCreate(params, nil)
+	return sdkError(http.MethodPost, "/team_memberships", err)
+}
+
+// ListUsers lists all users
+func (c *sdkClient) ListUsers(ctx context.Context) ([]*apimodels.PortainereeUser, error) {
+	params := users.NewUserListParamsWithContext(ctx)
+	resp, err := c.api.Users.UserList(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", sdkError(http.MethodGet, "/users", err))
+	}
+
+	return resp.Payload, nil
+}
+
+// UpdateUserRole updates the role of a user
+func (c *sdkClient) UpdateUserRole(ctx context.Context, id int, role int64) error {
+	params := users.NewUserUpdateParamsWithContext(ctx).WithID(int64(id)).WithBody(&apimodels.UsersUserUpdatePayload{
+		Role: &role,
+	})
+
+	_, err := c.api.Users.UserUpdate(params, nil)
+	return sdkError(http.MethodPut, fmt.Sprintf("/users/%d", id), err)
+}
+
+// GetVersion returns the version of the Portainer server
+func (c *sdkClient) GetVersion(ctx context.Context) (string, error) {
+	params := system.NewSystemStatusParamsWithContext(ctx)
+	resp, err := c.api.System.SystemStatus(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to get version: %w", sdkError(http.MethodGet, "/system/status", err))
+	}
+
+	return resp.Payload.Version, nil
+}
+
+// ProxyDockerRequest proxies a request to the Docker API of an environment
+func (c *sdkClient) ProxyDockerRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error) {
+	return c.proxyRequest(ctx, fmt.Sprintf("%s/api/endpoints/%d/docker%s", c.baseURL, environmentId, opts.APIPath), opts)
+}
+
+// ProxyKubernetesRequest proxies a request to the Kubernetes API of an environment
+func (c *sdkClient) ProxyKubernetesRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error) {
+	return c.proxyRequest(ctx, fmt.Sprintf("%s/api/endpoints/%d/kubernetes%s", c.baseURL, environmentId, opts.APIPath), opts)
+}
+
+// proxyRequest sends a proxied request to the given URL
+func (c *sdkClient) proxyRequest(ctx context.Context, url string, opts client.ProxyRequestOptions) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, opts.Method, url, opts.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy request: %w", err)
+	}
+
+	if len(opts.QueryParams) > 0 {
+		q := req.URL.Query()
+		for k, v := range opts.QueryParams {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	req.Header.Set("x-api-key", c.token)
+
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send proxy request: %w", err)
+	}
+
+	return resp, nil
+}
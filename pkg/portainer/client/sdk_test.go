@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/portainer/client-api-go/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSDKClient(t *testing.T, handler http.HandlerFunc) *sdkClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	return newSDKClient(u.Host, u.Scheme, "test-token", srv.Client())
+}
+
+func TestSDKClientGetVersion(t *testing.T) {
+	var receivedPath, receivedToken string
+	c := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		receivedToken = r.Header.Get("x-api-key")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Version":"2.33.0"}`))
+	})
+
+	version, err := c.GetVersion(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "2.33.0", version)
+	assert.Equal(t, "/api/system/status", receivedPath)
+	assert.Equal(t, "test-token", receivedToken)
+}
+
+func TestSDKClientSearchEndpoints(t *testing.T) {
+	var receivedQuery url.Values
+	c := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+		receivedQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"Id":1,"Name":"env1"}]`))
+	})
+
+	endpoints, err := c.SearchEndpoints(context.Background(), EndpointQuery{
+		Name:     "env1",
+		Statuses: []int64{2},
+		Types:    []int64{6},
+		TagIDs:   []int64{3},
+		Start:    20,
+		Limit:    11,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "env1", endpoints[0].Name)
+	assert.Equal(t, "env1", receivedQuery.Get("name"))
+	assert.Equal(t, []string{"2"}, receivedQuery["status"])
+	assert.Equal(t, []string{"6"}, receivedQuery["types"])
+	assert.Equal(t, []string{"3"}, receivedQuery["tagIds"])
+	assert.Equal(t, "false", receivedQuery.Get("tagsPartialMatch"))
+	assert.Equal(t, "20", receivedQuery.Get("start"))
+	assert.Equal(t, "11", receivedQuery.Get("limit"))
+	assert.Equal(t, "true", receivedQuery.Get("excludeSnapshots"))
+	assert.False(t, receivedQuery.Has("search"))
+}
+
+func TestSDKClientHonoursContext(t *testing.T) {
+	c := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{
+			name: "sdk request",
+			call: func(ctx context.Context) error {
+				_, err := c.ListEndpoints(ctx)
+				return err
+			},
+		},
+		{
+			name: "proxy request",
+			call: func(ctx context.Context) error {
+				_, err := c.ProxyDockerRequest(ctx, 1, client.ProxyRequestOptions{Method: http.MethodGet, APIPath: "/containers/json"})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := tt.call(ctx)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+func TestSDKClientProxyRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		proxy         func(c *sdkClient, opts client.ProxyRequestOptions) (*http.Response, error)
+		opts          client.ProxyRequestOptions
+		expectedPath  string
+		expectedQuery string
+	}{
+		{
+			name: "docker proxy",
+			proxy: func(c *sdkClient, opts client.ProxyRequestOptions) (*http.Response, error) {
+				return c.ProxyDockerRequest(context.Background(), 2, opts)
+			},
+			opts: client.ProxyRequestOptions{
+				Method:      http.MethodGet,
+				APIPath:     "/containers/json",
+				QueryParams: map[string]string{"all": "true"},
+			},
+			expectedPath:  "/api/endpoints/2/docker/containers/json",
+			expectedQuery: "all=true",
+		},
+		{
+			name: "kubernetes proxy",
+			proxy: func(c *sdkClient, opts client.ProxyRequestOptions) (*http.Response, error) {
+				return c.ProxyKubernetesRequest(context.Background(), 3, opts)
+			},
+			opts: client.ProxyRequestOptions{
+				Method:  http.MethodGet,
+				APIPath: "/api/v1/namespaces",
+			},
+			expectedPath: "/api/endpoints/3/kubernetes/api/v1/namespaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedPath, receivedQuery, receivedToken string
+			c := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+				receivedPath = r.URL.Path
+				receivedQuery = r.URL.RawQuery
+				receivedToken = r.Header.Get("x-api-key")
+				w.WriteHeader(http.StatusOK)
+			})
+
+			resp, err := tt.proxy(c, tt.opts)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, tt.expectedPath, receivedPath)
+			assert.Equal(t, tt.expectedQuery, receivedQuery)
+			assert.Equal(t, "test-token", receivedToken)
+		})
+	}
+}
+
+func TestSDKClientAPIError(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		call           func(c *sdkClient) error
+		check          func(error) bool
+		expectedMethod string
+		expectedPath   string
+	}{
+		{
+			name:   "status declared by the API specification",
+			status: http.StatusNotFound,
+			call: func(c *sdkClient) error {
+				_, err := c.GetEndpoint(context.Background(), 7)
+				return err
+			},
+			check:          IsNotFound,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/endpoints/7",
+		},
+		{
+			name:   "status missing from the API specification",
+			status: http.StatusForbidden,
+			call: func(c *sdkClient) error {
+				_, err := c.ListEdgeGroups(context.Background())
+				return err
+			},
+			check:          IsForbidden,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/edge_groups",
+		},
+		{
+			name:   "call without a wrapped error",
+			status: http.StatusConflict,
+			call: func(c *sdkClient) error {
+				return c.UpdateTeamName(context.Background(), 3, "team")
+			},
+			check:          IsConflict,
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/teams/3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message":"failure"}`))
+			})
+
+			err := tt.call(c)
+			require.Error(t, err)
+			assert.True(t, tt.check(err))
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.expectedMethod, apiErr.Method)
+			assert.Equal(t, tt.expectedPath, apiErr.Path)
+		})
+	}
+}
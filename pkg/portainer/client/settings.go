@@ -2,14 +2,15 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
 )
 
-func (c *PortainerClient) GetSettings() (models.PortainerSettings, error) {
-	settings, err := c.cli.GetSettings()
+func (c *PortainerClient) GetSettings(ctx context.Context) (models.PortainerSettings, error) {
+	settings, err := c.cli.GetSettings(ctx)
 	if err != nil {
 		return models.PortainerSettings{}, fmt.Errorf("failed to get settings: %w", err)
 	}
@@ -25,8 +26,8 @@ func (c *PortainerClient) GetSettings() (models.PortainerSettings, error) {
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateSettings(settingsJSON string) error {
-	if err := c.doJSONAPIRequest(http.MethodPut, "/settings", bytes.NewReader([]byte(settingsJSON)), nil); err != nil {
+func (c *PortainerClient) UpdateSettings(ctx context.Context, settingsJSON string) error {
+	if err := c.doJSONAPIRequest(withIdempotent(ctx), http.MethodPut, "/settings", bytes.NewReader([]byte(settingsJSON)), nil); err != nil {
 		return fmt.Errorf("failed to update settings: %w", err)
 	}
 
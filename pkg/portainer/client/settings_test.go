@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -127,7 +128,7 @@ func TestGetSettings(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			settings, err := client.GetSettings()
+			settings, err := client.GetSettings(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
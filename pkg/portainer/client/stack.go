@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/portainer/portainer-mcp/pkg/portainer/models"
@@ -13,8 +14,8 @@ import (
 // Returns:
 //   - A slice of Stack objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetStacks() ([]models.Stack, error) {
-	edgeStacks, err := c.cli.ListEdgeStacks()
+func (c *PortainerClient) GetStacks(ctx context.Context) ([]models.Stack, error) {
+	edgeStacks, err := c.cli.ListEdgeStacks(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list edge stacks: %w", err)
 	}
@@ -36,8 +37,8 @@ func (c *PortainerClient) GetStacks() ([]models.Stack, error) {
 // Returns:
 //   - The file content of the stack (Compose file)
 //   - An error if the operation fails
-func (c *PortainerClient) GetStackFile(id int) (string, error) {
-	file, err := c.cli.GetEdgeStackFile(int64(id))
+func (c *PortainerClient) GetStackFile(ctx context.Context, id int) (string, error) {
+	file, err := c.cli.GetEdgeStackFile(ctx, int64(id))
 	if err != nil {
 		return "", fmt.Errorf("failed to get edge stack file: %w", err)
 	}
@@ -57,8 +58,8 @@ func (c *PortainerClient) GetStackFile(id int) (string, error) {
 // Returns:
 //   - The ID of the created stack
 //   - An error if the operation fails
-func (c *PortainerClient) CreateStack(name, file string, environmentGroupIds []int) (int, error) {
-	id, err := c.cli.CreateEdgeStack(name, file, utils.IntToInt64Slice(environmentGroupIds))
+func (c *PortainerClient) CreateStack(ctx context.Context, name, file string, environmentGroupIds []int) (int, error) {
+	id, err := c.cli.CreateEdgeStack(ctx, name, file, utils.IntToInt64Slice(environmentGroupIds))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create edge stack: %w", err)
 	}
@@ -77,8 +78,8 @@ func (c *PortainerClient) CreateStack(name, file string, environmentGroupIds []i
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) UpdateStack(id int, file string, environmentGroupIds []int) error {
-	err := c.cli.UpdateEdgeStack(int64(id), file, utils.IntToInt64Slice(environmentGroupIds))
+func (c *PortainerClient) UpdateStack(ctx context.Context, id int, file string, environmentGroupIds []int) error {
+	err := c.cli.UpdateEdgeStack(ctx, int64(id), file, utils.IntToInt64Slice(environmentGroupIds))
 	if err != nil {
 		return fmt.Errorf("failed to update edge stack: %w", err)
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -70,7 +71,7 @@ func TestGetStacks(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			stacks, err := client.GetStacks()
+			stacks, err := client.GetStacks(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -113,7 +114,7 @@ func TestGetStackFile(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			file, err := client.GetStackFile(tt.stackID)
+			file, err := client.GetStackFile(context.Background(), tt.stackID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -162,7 +163,7 @@ func TestCreateStack(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			id, err := client.CreateStack(tt.stackName, tt.stackFile, tt.environmentGroupIds)
+			id, err := client.CreateStack(context.Background(), tt.stackName, tt.stackFile, tt.environmentGroupIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -207,7 +208,7 @@ func TestUpdateStack(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateStack(tt.stackID, tt.stackFile, tt.environmentGroupIds)
+			err := client.UpdateStack(context.Background(), tt.stackID, tt.stackFile, tt.environmentGroupIds)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -55,7 +56,7 @@ func TestGetEnvironmentTags(t *testing.T) {
 				cli: mockAPI,
 			}
 
-			tags, err := client.GetEnvironmentTags()
+			tags, err := client.GetEnvironmentTags(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -105,7 +106,7 @@ func TestCreateEnvironmentTag(t *testing.T) {
 				cli: mockAPI,
 			}
 
-			id, err := client.CreateEnvironmentTag(tt.tagName)
+			id, err := client.CreateEnvironmentTag(context.Background(), tt.tagName)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// tlsVersions maps the supported minimum TLS version names to their values
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTLSConfig builds the TLS configuration used to connect to the Portainer server.
+//
+// Returns:
+//   - The TLS configuration
+//   - An error if a certificate file cannot be loaded or the minimum TLS version is unsupported
+func newTLSConfig(options clientOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: options.skipTLSVerify,
+	}
+
+	if options.minTLSVersion != "" {
+		version, ok := tlsVersions[options.minTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported minimum TLS version: %s", options.minTLSVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if options.caCertFile != "" {
+		pem, err := os.ReadFile(options.caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM certificate found in %s", options.caCertFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if options.clientCertFile != "" || options.clientKeyFile != "" {
+		if options.clientCertFile == "" || options.clientKeyFile == "" {
+			return nil, fmt.Errorf("both a client certificate and a client key are required for mutual TLS")
+		}
+
+		cert, err := tls.LoadX509KeyPair(options.clientCertFile, options.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
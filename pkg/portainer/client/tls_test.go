@@ -0,0 +1,249 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate generates a self-signed certificate and writes it
+// and its private key as PEM files in dir.
+func writeTestCertificate(t *testing.T, dir, name string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile, cert
+}
+
+// writeServerCA writes the certificate of a httptest TLS server as a PEM CA bundle
+func writeServerCA(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(path, block, 0o600))
+
+	return path
+}
+
+func newTLSTestServer(t *testing.T, configure func(*tls.Config)) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Version":"2.33.0"}`))
+	}))
+	srv.TLS = &tls.Config{}
+	if configure != nil {
+		configure(srv.TLS)
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestTLSConfiguration(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, clientKey, clientCA := writeTestCertificate(t, dir, "client")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA)
+
+	tests := []struct {
+		name        string
+		configure   func(*tls.Config)
+		opts        func(srv *httptest.Server) []ClientOption
+		expectError bool
+	}{
+		{
+			name: "untrusted server certificate is rejected by default",
+			opts: func(srv *httptest.Server) []ClientOption {
+				return nil
+			},
+			expectError: true,
+		},
+		{
+			name: "server certificate trusted through CA bundle",
+			opts: func(srv *httptest.Server) []ClientOption {
+				return []ClientOption{WithCACertFile(writeServerCA(t, srv))}
+			},
+		},
+		{
+			name: "insecure skips certificate verification",
+			opts: func(srv *httptest.Server) []ClientOption {
+				return []ClientOption{WithSkipTLSVerify(true)}
+			},
+		},
+		{
+			name: "minimum TLS version higher than the server maximum",
+			configure: func(c *tls.Config) {
+				c.MaxVersion = tls.VersionTLS12
+			},
+			opts: func(srv *httptest.Server) []ClientOption {
+				return []ClientOption{WithCACertFile(writeServerCA(t, srv)), WithMinTLSVersion("1.3")}
+			},
+			expectError: true,
+		},
+		{
+			name: "mutual TLS without client certificate",
+			configure: func(c *tls.Config) {
+				c.ClientAuth = tls.RequireAndVerifyClientCert
+				c.ClientCAs = clientCAs
+			},
+			opts: func(srv *httptest.Server) []ClientOption {
+				return []ClientOption{WithCACertFile(writeServerCA(t, srv))}
+			},
+			expectError: true,
+		},
+		{
+			name: "mutual TLS with client certificate",
+			configure: func(c *tls.Config) {
+				c.ClientAuth = tls.RequireAndVerifyClientCert
+				c.ClientCAs = clientCAs
+			},
+			opts: func(srv *httptest.Server) []ClientOption {
+				return []ClientOption{WithCACertFile(writeServerCA(t, srv)), WithClientCertificate(clientCert, clientKey)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTLSTestServer(t, tt.configure)
+
+			c, err := NewPortainerClient(srv.URL, "test-token", tt.opts(srv)...)
+			require.NoError(t, err)
+
+			// Both the SDK calls and the direct API calls must use the TLS configuration
+			_, sdkErr := c.GetVersion(context.Background())
+			resp, apiErr := c.DoAPIRequest(context.Background(), http.MethodGet, "/system/status", nil)
+			if apiErr == nil {
+				resp.Body.Close()
+			}
+
+			if tt.expectError {
+				assert.Error(t, sdkErr)
+				assert.Error(t, apiErr)
+			} else {
+				assert.NoError(t, sdkErr)
+				assert.NoError(t, apiErr)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeTestCertificate(t, dir, "client")
+
+	invalidPEM := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidPEM, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name          string
+		options       clientOptions
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "default options",
+			options: clientOptions{},
+		},
+		{
+			name:    "supported minimum TLS version",
+			options: clientOptions{minTLSVersion: "1.3"},
+		},
+		{
+			name:          "unsupported minimum TLS version",
+			options:       clientOptions{minTLSVersion: "1.4"},
+			expectError:   true,
+			errorContains: "unsupported minimum TLS version",
+		},
+		{
+			name:          "missing CA file",
+			options:       clientOptions{caCertFile: filepath.Join(dir, "missing.pem")},
+			expectError:   true,
+			errorContains: "failed to read CA certificate file",
+		},
+		{
+			name:          "CA file without certificates",
+			options:       clientOptions{caCertFile: invalidPEM},
+			expectError:   true,
+			errorContains: "no valid PEM certificate",
+		},
+		{
+			name:          "client certificate without key",
+			options:       clientOptions{clientCertFile: certFile},
+			expectError:   true,
+			errorContains: "both a client certificate and a client key are required",
+		},
+		{
+			name:          "mismatched client key",
+			options:       clientOptions{clientCertFile: certFile, clientKeyFile: invalidPEM},
+			expectError:   true,
+			errorContains: "failed to load client certificate",
+		},
+		{
+			name:    "client certificate and key",
+			options: clientOptions{clientCertFile: certFile, clientKeyFile: keyFile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := newTLSConfig(tt.options)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, config)
+		})
+	}
+}
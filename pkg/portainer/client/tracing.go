@@ -0,0 +1,288 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/portainer/client-api-go/v2/client"
+	apimodels "github.com/portainer/client-api-go/v2/pkg/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the OpenTelemetry tracer of the Portainer client
+const TracerName = "github.com/portainer/portainer-mcp/pkg/portainer/client"
+
+// startSpan starts a client span with the globally registered tracer provider.
+// Spans are not recorded until a tracer provider is registered.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+}
+
+// endSpan records the error of an operation, if any, and ends its span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingTransport is an http.RoundTripper opening a span for every request
+// sent to the Portainer API and propagating the trace context in its headers
+type tracingTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip sends the request within a span named after its method and templated path
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := apiPathTemplate(req.URL.Path)
+
+	ctx, span := startSpan(req.Context(), req.Method+" "+path)
+	span.SetAttributes(
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.path", req.URL.Path),
+		attribute.String("http.route", path),
+		attribute.String("server.address", req.URL.Host),
+	)
+
+	// A RoundTripper must not modify the request it is given
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	span.End()
+
+	return resp, nil
+}
+
+// tracedAPIClient is a PortainerAPIClient opening a span around every SDK call
+type tracedAPIClient struct {
+	next PortainerAPIClient
+}
+
+func (c *tracedAPIClient) ListEdgeGroups(ctx context.Context) ([]*apimodels.EdgegroupsDecoratedEdgeGroup, error) {
+	ctx, span := startSpan(ctx, "sdk.ListEdgeGroups")
+	result, err := c.next.ListEdgeGroups(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) CreateEdgeGroup(ctx context.Context, name string, environmentIds []int64) (int64, error) {
+	ctx, span := startSpan(ctx, "sdk.CreateEdgeGroup")
+	result, err := c.next.CreateEdgeGroup(ctx, name, environmentIds)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) UpdateEdgeGroup(ctx context.Context, id int64, name *string, environmentIds *[]int64, tagIds *[]int64) error {
+	ctx, span := startSpan(ctx, "sdk.UpdateEdgeGroup")
+	err := c.next.UpdateEdgeGroup(ctx, id, name, environmentIds, tagIds)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) ListEdgeStacks(ctx context.Context) ([]*apimodels.PortainereeEdgeStack, error) {
+	ctx, span := startSpan(ctx, "sdk.ListEdgeStacks")
+	result, err := c.next.ListEdgeStacks(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) CreateEdgeStack(ctx context.Context, name string, file string, environmentGroupIds []int64) (int64, error) {
+	ctx, span := startSpan(ctx, "sdk.CreateEdgeStack")
+	result, err := c.next.CreateEdgeStack(ctx, name, file, environmentGroupIds)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) UpdateEdgeStack(ctx context.Context, id int64, file string, environmentGroupIds []int64) error {
+	ctx, span := startSpan(ctx, "sdk.UpdateEdgeStack")
+	err := c.next.UpdateEdgeStack(ctx, id, file, environmentGroupIds)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) GetEdgeStackFile(ctx context.Context, id int64) (string, error) {
+	ctx, span := startSpan(ctx, "sdk.GetEdgeStackFile")
+	result, err := c.next.GetEdgeStackFile(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) ListEndpointGroups(ctx context.Context) ([]*apimodels.PortainerEndpointGroup, error) {
+	ctx, span := startSpan(ctx, "sdk.ListEndpointGroups")
+	result, err := c.next.ListEndpointGroups(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) CreateEndpointGroup(ctx context.Context, name string, associatedEndpoints []int64) (int64, error) {
+	ctx, span := startSpan(ctx, "sdk.CreateEndpointGroup")
+	result, err := c.next.CreateEndpointGroup(ctx, name, associatedEndpoints)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) UpdateEndpointGroup(ctx context.Context, id int64, name *string, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
+	ctx, span := startSpan(ctx, "sdk.UpdateEndpointGroup")
+	err := c.next.UpdateEndpointGroup(ctx, id, name, userAccesses, teamAccesses)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) AddEnvironmentToEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error {
+	ctx, span := startSpan(ctx, "sdk.AddEnvironmentToEndpointGroup")
+	err := c.next.AddEnvironmentToEndpointGroup(ctx, groupId, environmentId)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) RemoveEnvironmentFromEndpointGroup(ctx context.Context, groupId int64, environmentId int64) error {
+	ctx, span := startSpan(ctx, "sdk.RemoveEnvironmentFromEndpointGroup")
+	err := c.next.RemoveEnvironmentFromEndpointGroup(ctx, groupId, environmentId)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) ListEndpoints(ctx context.Context) ([]*apimodels.PortainereeEndpoint, error) {
+	ctx, span := startSpan(ctx, "sdk.ListEndpoints")
+	result, err := c.next.ListEndpoints(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) SearchEndpoints(ctx context.Context, query EndpointQuery) ([]*apimodels.PortainereeEndpoint, error) {
+	ctx, span := startSpan(ctx, "sdk.SearchEndpoints")
+	result, err := c.next.SearchEndpoints(ctx, query)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) GetEndpoint(ctx context.Context, id int64) (*apimodels.PortainereeEndpoint, error) {
+	ctx, span := startSpan(ctx, "sdk.GetEndpoint")
+	result, err := c.next.GetEndpoint(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) UpdateEndpoint(ctx context.Context, id int64, tagIds *[]int64, userAccesses *map[int64]string, teamAccesses *map[int64]string) error {
+	ctx, span := startSpan(ctx, "sdk.UpdateEndpoint")
+	err := c.next.UpdateEndpoint(ctx, id, tagIds, userAccesses, teamAccesses)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) GetSettings(ctx context.Context) (*apimodels.PortainereeSettings, error) {
+	ctx, span := startSpan(ctx, "sdk.GetSettings")
+	result, err := c.next.GetSettings(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) ListTags(ctx context.Context) ([]*apimodels.PortainerTag, error) {
+	ctx, span := startSpan(ctx, "sdk.ListTags")
+	result, err := c.next.ListTags(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) CreateTag(ctx context.Context, name string) (int64, error) {
+	ctx, span := startSpan(ctx, "sdk.CreateTag")
+	result, err := c.next.CreateTag(ctx, name)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) ListTeams(ctx context.Context) ([]*apimodels.PortainerTeam, error) {
+	ctx, span := startSpan(ctx, "sdk.ListTeams")
+	result, err := c.next.ListTeams(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) ListTeamMemberships(ctx context.Context) ([]*apimodels.PortainerTeamMembership, error) {
+	ctx, span := startSpan(ctx, "sdk.ListTeamMemberships")
+	result, err := c.next.ListTeamMemberships(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) CreateTeam(ctx context.Context, name string) (int64, error) {
+	ctx, span := startSpan(ctx, "sdk.CreateTeam")
+	result, err := c.next.CreateTeam(ctx, name)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) UpdateTeamName(ctx context.Context, id int, name string) error {
+	ctx, span := startSpan(ctx, "sdk.UpdateTeamName")
+	err := c.next.UpdateTeamName(ctx, id, name)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) DeleteTeamMembership(ctx context.Context, id int) error {
+	ctx, span := startSpan(ctx, "sdk.DeleteTeamMembership")
+	err := c.next.DeleteTeamMembership(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) CreateTeamMembership(ctx context.Context, teamId int, userId int) error {
+	ctx, span := startSpan(ctx, "sdk.CreateTeamMembership")
+	err := c.next.CreateTeamMembership(ctx, teamId, userId)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) ListUsers(ctx context.Context) ([]*apimodels.PortainereeUser, error) {
+	ctx, span := startSpan(ctx, "sdk.ListUsers")
+	result, err := c.next.ListUsers(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) UpdateUserRole(ctx context.Context, id int, role int64) error {
+	ctx, span := startSpan(ctx, "sdk.UpdateUserRole")
+	err := c.next.UpdateUserRole(ctx, id, role)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedAPIClient) GetVersion(ctx context.Context) (string, error) {
+	ctx, span := startSpan(ctx, "sdk.GetVersion")
+	result, err := c.next.GetVersion(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) ProxyDockerRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error) {
+	ctx, span := startSpan(ctx, "sdk.ProxyDockerRequest")
+	span.SetAttributes(attribute.Int("portainer.environment_id", environmentId), attribute.String("portainer.proxy.path", opts.APIPath))
+
+	result, err := c.next.ProxyDockerRequest(ctx, environmentId, opts)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedAPIClient) ProxyKubernetesRequest(ctx context.Context, environmentId int, opts client.ProxyRequestOptions) (*http.Response, error) {
+	ctx, span := startSpan(ctx, "sdk.ProxyKubernetesRequest")
+	span.SetAttributes(attribute.Int("portainer.environment_id", environmentId), attribute.String("portainer.proxy.path", opts.APIPath))
+
+	result, err := c.next.ProxyKubernetesRequest(ctx, environmentId, opts)
+	endSpan(span, err)
+	return result, err
+}
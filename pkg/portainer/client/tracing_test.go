@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/portainer/portainer-mcp/pkg/portainer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setupTestTracing registers a tracer provider recording spans in memory
+func setupTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	return recorder
+}
+
+// spanAttributes returns the attributes of a span as a map
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestTracingSpans(t *testing.T) {
+	recorder := setupTestTracing(t)
+
+	var traceparents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewPortainerClient(srv.URL, "test-token")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	resp, err := c.ProxyDockerRequest(ctx, models.DockerProxyRequestOptions{EnvironmentID: 2, Method: http.MethodGet, Path: "/containers/json"})
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Error(t, c.DeleteTag(ctx, 4))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	// The round trip of the proxied request is a child of the SDK call span
+	roundTrip, sdkCall, deleteTag := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "sdk.ProxyDockerRequest", sdkCall.Name())
+	assert.Equal(t, int64(2), spanAttributes(sdkCall)["portainer.environment_id"].AsInt64())
+	assert.Equal(t, "/containers/json", spanAttributes(sdkCall)["portainer.proxy.path"].AsString())
+
+	assert.Equal(t, "GET /endpoints/{id}/docker/*", roundTrip.Name())
+	assert.Equal(t, sdkCall.SpanContext().SpanID(), roundTrip.Parent().SpanID())
+	assert.Equal(t, "/api/endpoints/2/docker/containers/json", spanAttributes(roundTrip)["url.path"].AsString())
+	assert.Equal(t, int64(http.StatusOK), spanAttributes(roundTrip)["http.response.status_code"].AsInt64())
+
+	assert.Equal(t, "DELETE /tags/{id}", deleteTag.Name())
+	assert.Equal(t, codes.Error, deleteTag.Status().Code)
+
+	// The trace context is propagated to Portainer
+	require.Len(t, traceparents, 2)
+	assert.Contains(t, traceparents[0], roundTrip.SpanContext().TraceID().String())
+}
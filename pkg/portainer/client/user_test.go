@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -83,7 +84,7 @@ func TestGetUsers(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			users, err := client.GetUsers()
+			users, err := client.GetUsers(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -148,7 +149,7 @@ func TestUpdateUserRole(t *testing.T) {
 
 			client := &PortainerClient{cli: mockAPI}
 
-			err := client.UpdateUserRole(tt.userID, tt.role)
+			err := client.UpdateUserRole(context.Background(), tt.userID, tt.role)
 
 			if tt.expectedError {
 				assert.Error(t, err)
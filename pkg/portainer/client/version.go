@@ -1,12 +1,43 @@
 package client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
 
-func (c *PortainerClient) GetVersion() (string, error) {
-	version, err := c.cli.GetVersion()
+const (
+	// EditionCE is the Portainer Community Edition
+	EditionCE = "CE"
+	// EditionBE is the Portainer Business Edition
+	EditionBE = "BE"
+)
+
+func (c *PortainerClient) GetVersion(ctx context.Context) (string, error) {
+	version, err := c.cli.GetVersion(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get version: %w", err)
 	}
 
 	return version, nil
 }
+
+// GetEdition returns the edition of the Portainer server, either EditionCE or
+// EditionBE. The Business Edition reports itself as EE in older versions.
+func (c *PortainerClient) GetEdition(ctx context.Context) (string, error) {
+	var version struct {
+		ServerEdition string `json:"ServerEdition"`
+	}
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/system/version", nil, &version); err != nil {
+		return "", fmt.Errorf("failed to get edition: %w", err)
+	}
+
+	switch version.ServerEdition {
+	case EditionCE:
+		return EditionCE, nil
+	case EditionBE, "EE":
+		return EditionBE, nil
+	default:
+		return "", fmt.Errorf("failed to get edition: unknown edition %q", version.ServerEdition)
+	}
+}
@@ -1,10 +1,14 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetVersion(t *testing.T) {
@@ -40,7 +44,7 @@ func TestGetVersion(t *testing.T) {
 				cli: mockAPI,
 			}
 
-			version, err := client.GetVersion()
+			version, err := client.GetVersion(context.Background())
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -54,3 +58,58 @@ func TestGetVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEdition(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedResult string
+		errorContains  string
+	}{
+		{
+			name:           "community edition",
+			body:           `{"ServerEdition":"CE","LatestVersion":"2.33.0"}`,
+			expectedResult: EditionCE,
+		},
+		{
+			name:           "business edition",
+			body:           `{"ServerEdition":"BE"}`,
+			expectedResult: EditionBE,
+		},
+		{
+			name:           "enterprise edition label",
+			body:           `{"ServerEdition":"EE"}`,
+			expectedResult: EditionBE,
+		},
+		{
+			name:          "unknown edition",
+			body:          `{}`,
+			errorContains: `unknown edition ""`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedPath string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			c, err := NewPortainerClient(srv.URL, "test-token")
+			require.NoError(t, err)
+
+			edition, err := c.GetEdition(context.Background())
+			assert.Equal(t, "/api/system/version", receivedPath)
+
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, edition)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,9 +15,9 @@ import (
 // Returns:
 //   - A slice of Webhook objects
 //   - An error if the operation fails
-func (c *PortainerClient) GetWebhooks() ([]models.Webhook, error) {
+func (c *PortainerClient) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
 	var webhooks []models.Webhook
-	if err := c.doJSONAPIRequest(http.MethodGet, "/webhooks", nil, &webhooks); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodGet, "/webhooks", nil, &webhooks); err != nil {
 		return nil, fmt.Errorf("failed to list webhooks: %w", err)
 	}
 
@@ -31,7 +32,7 @@ func (c *PortainerClient) GetWebhooks() ([]models.Webhook, error) {
 // Returns:
 //   - The ID of the created webhook
 //   - An error if the operation fails
-func (c *PortainerClient) CreateWebhook(req models.WebhookCreateRequest) (int, error) {
+func (c *PortainerClient) CreateWebhook(ctx context.Context, req models.WebhookCreateRequest) (int, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return 0, fmt.Errorf("failed to marshal create request: %w", err)
@@ -40,7 +41,7 @@ func (c *PortainerClient) CreateWebhook(req models.WebhookCreateRequest) (int, e
 	var result struct {
 		ID int `json:"Id"`
 	}
-	if err := c.doJSONAPIRequest(http.MethodPost, "/webhooks", bytes.NewReader(body), &result); err != nil {
+	if err := c.doJSONAPIRequest(ctx, http.MethodPost, "/webhooks", bytes.NewReader(body), &result); err != nil {
 		return 0, fmt.Errorf("failed to create webhook: %w", err)
 	}
 
@@ -54,8 +55,8 @@ func (c *PortainerClient) CreateWebhook(req models.WebhookCreateRequest) (int, e
 //
 // Returns:
 //   - An error if the operation fails
-func (c *PortainerClient) DeleteWebhook(id int) error {
-	if err := c.doAPIDelete(fmt.Sprintf("/webhooks/%d", id)); err != nil {
+func (c *PortainerClient) DeleteWebhook(ctx context.Context, id int) error {
+	if err := c.doAPIDelete(ctx, fmt.Sprintf("/webhooks/%d", id)); err != nil {
 		return fmt.Errorf("failed to delete webhook: %w", err)
 	}
 
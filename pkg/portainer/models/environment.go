@@ -91,3 +91,61 @@ func convertEnvironmentType(rawEndpoint *apimodels.PortainereeEndpoint) string {
 		return EnvironmentTypeUnknown
 	}
 }
+
+// EnvironmentQuery narrows the environments listed by Portainer. Zero fields
+// do not narrow the environments.
+type EnvironmentQuery struct {
+	// IDs lists the IDs of the environments
+	IDs []int
+	// Name is the exact name of the environments
+	Name string
+	// Search is matched by Portainer against the names of the environments,
+	// and the names of their tags and group
+	Search string
+	// Statuses lists the statuses of the environments, such as active
+	Statuses []string
+	// Types lists the types of the environments, such as docker-agent
+	Types []string
+	// TagIDs lists the tags every environment must have
+	TagIDs []int
+	// Start skips the first environments
+	Start int
+	// Limit is the maximum number of environments, without limit when zero
+	Limit int
+}
+
+// EnvironmentStatusID returns the ID of an environment status in the
+// Portainer API, or false for an unknown status
+func EnvironmentStatusID(status string) (int64, bool) {
+	switch status {
+	case EnvironmentStatusActive:
+		return 1, true
+	case EnvironmentStatusInactive:
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
+// EnvironmentTypeID returns the ID of an environment type in the Portainer
+// API, or false for an unknown type
+func EnvironmentTypeID(environmentType string) (int64, bool) {
+	switch environmentType {
+	case EnvironmentTypeDockerLocal:
+		return 1, true
+	case EnvironmentTypeDockerAgent:
+		return 2, true
+	case EnvironmentTypeAzureACI:
+		return 3, true
+	case EnvironmentTypeDockerEdgeAgent:
+		return 4, true
+	case EnvironmentTypeKubernetesLocal:
+		return 5, true
+	case EnvironmentTypeKubernetesAgent:
+		return 6, true
+	case EnvironmentTypeKubernetesEdgeAgent:
+		return 7, true
+	default:
+		return 0, false
+	}
+}
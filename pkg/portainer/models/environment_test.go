@@ -231,3 +231,33 @@ func TestConvertEnvironmentType(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentTypeAndStatusIDs(t *testing.T) {
+	for id := int64(0); id <= 8; id++ {
+		environmentType := convertEnvironmentType(&models.PortainereeEndpoint{Type: id})
+		got, ok := EnvironmentTypeID(environmentType)
+		if environmentType == EnvironmentTypeUnknown {
+			if ok {
+				t.Errorf("EnvironmentTypeID(%q) = %d, want no ID", environmentType, got)
+			}
+			continue
+		}
+		if !ok || got != id {
+			t.Errorf("EnvironmentTypeID(%q) = %d, %v, want %d", environmentType, got, ok, id)
+		}
+	}
+
+	for id := int64(0); id <= 3; id++ {
+		status := convertStandardEnvironmentStatus(&models.PortainereeEndpoint{Status: id})
+		got, ok := EnvironmentStatusID(status)
+		if status == EnvironmentStatusUnknown {
+			if ok {
+				t.Errorf("EnvironmentStatusID(%q) = %d, want no ID", status, got)
+			}
+			continue
+		}
+		if !ok || got != id {
+			t.Errorf("EnvironmentStatusID(%q) = %d, %v, want %d", status, got, ok, id)
+		}
+	}
+}
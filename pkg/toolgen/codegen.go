@@ -0,0 +1,288 @@
+package toolgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// generatedCodeTemplate is the Go source generated from a tools configuration
+var generatedCodeTemplate = template.Must(template.New("tools").Parse(`// Code generated by toolgen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/portainer/portainer-mcp/pkg/toolgen"
+)
+
+// Tool names as defined in the YAML file
+const (
+{{- range $i, $tool := .Tools}}
+{{- if $tool.Group}}
+{{- if $i}}
+{{end}}
+	// {{$tool.Group}}
+{{- end}}
+	{{$tool.Constant}} = {{printf "%q" $tool.Name}}
+{{- end}}
+)
+{{range .Tools}}{{if .Params}}
+// {{.Struct}} holds the arguments of the {{.Name}} tool
+type {{.Struct}} struct {
+{{- range .Params}}
+	{{.Field}} {{.GoType}}
+{{- end}}
+}
+
+// Parse{{.Struct}} parses the arguments of a call of the {{.Name}} tool
+func Parse{{.Struct}}(request mcp.CallToolRequest) ({{.Struct}}, error) {
+	parser := toolgen.NewParameterParser(request)
+
+	var args {{.Struct}}
+	var err error
+{{range .Params}}
+	args.{{.Field}}, err = parser.{{.Getter}}({{printf "%q" .Name}}, {{.Required}})
+	if err != nil {
+		return args, fmt.Errorf("invalid {{.Name}} parameter: %w", err)
+	}
+{{end}}
+	return args, nil
+}
+{{end}}{{end}}`))
+
+// generatedTool describes a tool in the generated code
+type generatedTool struct {
+	Name     string
+	Group    string
+	Constant string
+	Struct   string
+	Params   []generatedParam
+}
+
+// generatedParam describes a tool parameter in the generated code
+type generatedParam struct {
+	Name     string
+	Field    string
+	GoType   string
+	Getter   string
+	Required bool
+}
+
+// GenerateCode returns the Go source declaring a constant holding the name of
+// each tool of a YAML configuration, and for each tool with parameters a
+// struct of its arguments along with a function parsing them from a tool call.
+// The tool groups are named after the first line of the "##" comments
+// preceding them in the YAML file. The source names the YAML file it was
+// generated from.
+func GenerateCode(data []byte, source, packageName string) ([]byte, error) {
+	var config ToolsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	groups, err := toolGroups(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var tools []generatedTool
+	constants := make(map[string]string)
+
+	for _, def := range config.Tools {
+		if def.Disabled {
+			continue
+		}
+
+		constant := ToolConstantName(def.Name)
+		if other, ok := constants[constant]; ok {
+			return nil, fmt.Errorf("tools %s and %s have the same constant name %s", other, def.Name, constant)
+		}
+		constants[constant] = def.Name
+
+		tool := generatedTool{
+			Name:     def.Name,
+			Group:    groups[def.Name],
+			Constant: constant,
+			Struct:   goName(def.Name) + "Args",
+		}
+
+		fields := make(map[string]bool)
+		for _, param := range def.Parameters {
+			field := goName(param.Name)
+			if field == "" || fields[field] {
+				return nil, fmt.Errorf("invalid or duplicate field name for parameter %s of tool %s", param.Name, def.Name)
+			}
+			fields[field] = true
+
+			goType, getter := parameterGoType(param)
+			tool.Params = append(tool.Params, generatedParam{
+				Name:     param.Name,
+				Field:    field,
+				GoType:   goType,
+				Getter:   getter,
+				Required: param.Required,
+			})
+		}
+
+		tools = append(tools, tool)
+	}
+
+	var buf bytes.Buffer
+	err = generatedCodeTemplate.Execute(&buf, struct {
+		Source  string
+		Package string
+		Tools   []generatedTool
+	}{source, packageName, tools})
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// ToolConstantName returns the name of the generated constant holding the
+// name of a tool, such as ToolListStacks for listStacks
+func ToolConstantName(name string) string {
+	return "Tool" + goName(name)
+}
+
+// parameterGoType returns the Go type of a parameter and the ParameterParser
+// method reading it. Every number parameter of the tools is an ID or an enum
+// value, read as an int.
+func parameterGoType(param ParameterDefinition) (string, string) {
+	switch param.Type {
+	case "number", "integer":
+		return "int", "GetInt"
+	case "boolean":
+		return "bool", "GetBoolean"
+	case "array":
+		if itemType, _ := param.Items["type"].(string); itemType == "number" || itemType == "integer" {
+			return "[]int", "GetArrayOfIntegers"
+		}
+		return "[]any", "GetArrayOfObjects"
+	case "object":
+		return "map[string]any", "GetObject"
+	case "":
+		if len(param.OneOf) > 0 {
+			return "any", "GetValue"
+		}
+		return "string", "GetString"
+	default:
+		return "string", "GetString"
+	}
+}
+
+// initialisms maps the words of the tool and parameter names to the
+// initialisms Go identifiers write in a consistent case
+var initialisms = map[string]string{
+	"Api":  "API",
+	"Crd":  "CRD",
+	"Dns":  "DNS",
+	"Http": "HTTP",
+	"Id":   "ID",
+	"Ids":  "IDs",
+	"Ip":   "IP",
+	"Json": "JSON",
+	"Tls":  "TLS",
+	"Ttl":  "TTL",
+	"Uri":  "URI",
+	"Url":  "URL",
+	"Urls": "URLs",
+	"Yaml": "YAML",
+}
+
+// goName converts a tool or parameter name to an exported Go identifier,
+// such as EnvironmentIDs for environmentIds
+func goName(name string) string {
+	var words []string
+	var word []rune
+	upper := true
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if len(words) == 0 && len(word) == 0 && unicode.IsDigit(r) {
+			word = append(word, 'X')
+		}
+		if upper || unicode.IsUpper(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = []rune{unicode.ToUpper(r)}
+			upper = false
+			continue
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	for i, word := range words {
+		if initialism, ok := initialisms[word]; ok {
+			words[i] = initialism
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// toolGroups returns the group of the tools starting a group in a YAML
+// configuration, from the "##" comments preceding them
+func toolGroups(data []byte) (map[string]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]string)
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return groups, nil
+	}
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "tools" {
+			continue
+		}
+
+		for _, item := range root.Content[i+1].Content {
+			var def struct {
+				Name string `yaml:"name"`
+			}
+			if err := item.Decode(&def); err != nil {
+				return nil, err
+			}
+
+			comment := item.HeadComment
+			if comment == "" && len(item.Content) > 0 {
+				comment = item.Content[0].HeadComment
+			}
+			if group := groupName(comment); group != "" {
+				groups[def.Name] = group
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// groupName returns the first line of a "##" comment
+func groupName(comment string) string {
+	for _, line := range strings.Split(comment, "\n") {
+		if !strings.HasPrefix(line, "##") {
+			continue
+		}
+		return strings.TrimSpace(strings.TrimLeft(line, "#"))
+	}
+	return ""
+}
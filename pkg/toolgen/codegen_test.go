@@ -0,0 +1,113 @@
+package toolgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const codegenTestTools = `version: v1.0
+tools:
+  ## Tags
+  ## ----
+  - name: listEnvironmentTags
+    description: List the tags
+  - name: deleteTag
+    description: Delete a tag
+    parameters:
+      - name: id
+        type: number
+        required: true
+  ## Docker Proxy
+  - name: dockerProxy
+    description: Proxy a Docker API request
+    parameters:
+      - name: dockerAPIPath
+        type: string
+        required: true
+      - name: queryParams
+        type: array
+        items:
+          type: object
+      - name: environmentIds
+        type: array
+        items:
+          type: number
+      - name: all
+        type: boolean
+  - name: removedTool
+    disabled: true
+`
+
+func TestGenerateCode(t *testing.T) {
+	code, err := GenerateCode([]byte(codegenTestTools), "tools.yaml", "mcp")
+	require.NoError(t, err)
+
+	source := string(code)
+	assert.Contains(t, source, "// Code generated by toolgen from tools.yaml. DO NOT EDIT.")
+	assert.Contains(t, source, "package mcp")
+	assert.Contains(t, source, "const (\n\t// Tags\n\tToolListEnvironmentTags = \"listEnvironmentTags\"\n\tToolDeleteTag           = \"deleteTag\"\n\n\t// Docker Proxy\n\tToolDockerProxy = \"dockerProxy\"\n)")
+	assert.NotContains(t, source, "removedTool")
+	assert.NotContains(t, source, "ListEnvironmentTagsArgs")
+
+	assert.Contains(t, source, "type DeleteTagArgs struct {\n\tID int\n}")
+	assert.Contains(t, source, "type DockerProxyArgs struct {\n\tDockerAPIPath  string\n\tQueryParams    []any\n\tEnvironmentIDs []int\n\tAll            bool\n}")
+	assert.Contains(t, source, "func ParseDockerProxyArgs(request mcp.CallToolRequest) (DockerProxyArgs, error) {")
+	assert.Contains(t, source, "args.DockerAPIPath, err = parser.GetString(\"dockerAPIPath\", true)")
+	assert.Contains(t, source, "args.EnvironmentIDs, err = parser.GetArrayOfIntegers(\"environmentIds\", false)")
+	assert.Contains(t, source, "return args, fmt.Errorf(\"invalid all parameter: %w\", err)")
+}
+
+func TestGenerateCodeErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{
+			name:          "tools with the same constant name",
+			content:       "version: v1.0\ntools:\n  - name: list-tags\n  - name: listTags\n",
+			errorContains: "tools list-tags and listTags have the same constant name ToolListTags",
+		},
+		{
+			name:          "duplicate field name",
+			content:       "version: v1.0\ntools:\n  - name: listTags\n    parameters:\n      - name: tag_id\n      - name: tagId\n",
+			errorContains: "invalid or duplicate field name for parameter tagId of tool listTags",
+		},
+		{
+			name:          "invalid YAML",
+			content:       "version: v1.0\ntools: [\n",
+			errorContains: "yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateCode([]byte(tt.content), "tools.yaml", "mcp")
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"listStacks", "ListStacks"},
+		{"environmentId", "EnvironmentID"},
+		{"environmentIds", "EnvironmentIDs"},
+		{"dockerAPIPath", "DockerAPIPath"},
+		{"git-credential_id", "GitCredentialID"},
+		{"repositoryUrl", "RepositoryURL"},
+		{"identity", "Identity"},
+		{"2fa", "X2fa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, goName(tt.name))
+		})
+	}
+}
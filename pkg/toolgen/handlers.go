@@ -0,0 +1,142 @@
+package toolgen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CheckToolHandlers checks that the tools of a configuration match the tools
+// registered by the Go files of a directory, excluding tests and the skipped
+// files. A tool is registered by a call of the register method whose first
+// argument is the name of the tool, as a string literal or a string constant.
+// The constants of GenerateCode are known even when their file is skipped.
+//
+// It returns an error listing every tool without a handler, and every handler
+// registering a tool missing from the configuration.
+func CheckToolHandlers(config ToolsConfig, dir, register string, skipFiles ...string) error {
+	fset := token.NewFileSet()
+	var files []*ast.File
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || slices.Contains(skipFiles, name) {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+	}
+
+	constants := stringConstants(files)
+	defined := make(map[string]bool, len(config.Tools))
+	for _, def := range config.Tools {
+		if def.Disabled {
+			continue
+		}
+		defined[def.Name] = true
+		constants[ToolConstantName(def.Name)] = def.Name
+	}
+
+	var errs []error
+	registered := make(map[string]bool)
+
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || selector.Sel.Name != register {
+				return true
+			}
+
+			position := fset.Position(call.Pos())
+			name, err := resolveToolName(call.Args[0], constants)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", position, err))
+				return true
+			}
+
+			registered[name] = true
+			if !defined[name] {
+				errs = append(errs, fmt.Errorf("%s: handler registered for tool %s, which is not defined in the tools file", position, name))
+			}
+			return true
+		})
+	}
+
+	for _, def := range config.Tools {
+		if defined[def.Name] && !registered[def.Name] {
+			errs = append(errs, fmt.Errorf("tool %s is defined in the tools file but has no handler", def.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// resolveToolName returns the tool name of the first argument of a register call
+func resolveToolName(arg ast.Expr, constants map[string]string) (string, error) {
+	switch expr := arg.(type) {
+	case *ast.BasicLit:
+		if expr.Kind == token.STRING {
+			return strconv.Unquote(expr.Value)
+		}
+	case *ast.Ident:
+		if name, ok := constants[expr.Name]; ok {
+			return name, nil
+		}
+		return "", fmt.Errorf("handler registered for %s, which is not a tool defined in the tools file", expr.Name)
+	}
+
+	return "", fmt.Errorf("cannot resolve the tool name of a handler registration")
+}
+
+// stringConstants returns the string constants declared by Go files
+func stringConstants(files []*ast.File) map[string]string {
+	constants := make(map[string]string)
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, ident := range valueSpec.Names {
+					if i >= len(valueSpec.Values) {
+						continue
+					}
+					lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+					if value, err := strconv.Unquote(lit.Value); err == nil {
+						constants[ident.Name] = value
+					}
+				}
+			}
+		}
+	}
+
+	return constants
+}
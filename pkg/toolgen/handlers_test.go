@@ -0,0 +1,84 @@
+package toolgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckToolHandlers(t *testing.T) {
+	config := ToolsConfig{
+		Version: "v1.0",
+		Tools: []ToolDefinition{
+			{Name: "listTags"},
+			{Name: "deleteTag"},
+			{Name: "oldTool", Disabled: true},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		files         map[string]string
+		errorContains []string
+	}{
+		{
+			name: "in sync",
+			files: map[string]string{
+				"tag.go": "package mcp\n\nfunc (s *Server) AddTagFeatures() {\n\ts.addToolIfExists(ToolListTags, nil)\n\ts.addToolIfExists(\"deleteTag\", nil)\n}\n",
+			},
+		},
+		{
+			name: "constant declared by the package",
+			files: map[string]string{
+				"tag.go":    "package mcp\n\nfunc (s *Server) AddTagFeatures() {\n\ts.addToolIfExists(ToolListTags, nil)\n\ts.addToolIfExists(removeTag, nil)\n}\n",
+				"schema.go": "package mcp\n\nconst removeTag = \"deleteTag\"\n",
+			},
+		},
+		{
+			name: "tool without handler",
+			files: map[string]string{
+				"tag.go": "package mcp\n\nfunc (s *Server) AddTagFeatures() {\n\ts.addToolIfExists(ToolListTags, nil)\n}\n",
+			},
+			errorContains: []string{"tool deleteTag is defined in the tools file but has no handler"},
+		},
+		{
+			name: "handler of an undefined tool",
+			files: map[string]string{
+				"tag.go": "package mcp\n\nfunc (s *Server) AddTagFeatures() {\n\ts.addToolIfExists(ToolListTags, nil)\n\ts.addToolIfExists(ToolDeleteTag, nil)\n\ts.addToolIfExists(ToolRenameTag, nil)\n\ts.addToolIfExists(\"oldTool\", nil)\n}\n",
+			},
+			errorContains: []string{
+				"tag.go:6:2: handler registered for ToolRenameTag, which is not a tool defined in the tools file",
+				"tag.go:7:2: handler registered for tool oldTool, which is not defined in the tools file",
+			},
+		},
+		{
+			name: "skipped and test files are ignored",
+			files: map[string]string{
+				"tag.go":       "package mcp\n\nfunc (s *Server) AddTagFeatures() {\n\ts.addToolIfExists(ToolListTags, nil)\n\ts.addToolIfExists(ToolDeleteTag, nil)\n}\n",
+				"tools_gen.go": "package mcp\n\nconst ToolListTags = 1\n",
+				"tag_test.go":  "package mcp\n\nfunc init() {\n\tvar s *Server\n\ts.addToolIfExists(ToolRenameTag, nil)\n}\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+			}
+
+			err := CheckToolHandlers(config, dir, "addToolIfExists", "tools_gen.go")
+			if len(tt.errorContains) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, msg := range tt.errorContains {
+				assert.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package toolgen
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the text of a tool output
+const (
+	OutputFormatJSON  = "json"
+	OutputFormatYAML  = "yaml"
+	OutputFormatTable = "table"
+	OutputFormatCSV   = "csv"
+)
+
+// OutputFormats lists the formats of the text of a tool output
+var OutputFormats = []string{OutputFormatJSON, OutputFormatYAML, OutputFormatTable, OutputFormatCSV}
+
+// IsValidOutputFormat reports whether a format is one of OutputFormats
+func IsValidOutputFormat(format string) bool {
+	return slices.Contains(OutputFormats, format)
+}
+
+// OutputFields returns the fields of the values of a Go output type, in the
+// order of the struct: the fields of the items of a list, or of the value
+// itself. It returns nil when the values are not structs.
+func OutputFields(t reflect.Type) []string {
+	fields := outputStructFields(t)
+	if fields == nil {
+		return nil
+	}
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// DefaultColumns returns the columns shown by the table and CSV formats of a
+// Go output type. The items of a list show their fields holding a scalar or
+// a list of scalars, so that every row stays short, while a single value
+// shows all its fields.
+func DefaultColumns(t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice {
+		return OutputFields(t)
+	}
+
+	var columns []string
+	for _, field := range outputStructFields(t) {
+		fieldType := field.typ
+		if fieldType.Kind() == reflect.Slice && fieldType != rawMessageType && fieldType.Elem().Kind() != reflect.Uint8 {
+			fieldType = fieldType.Elem()
+		}
+		if isScalarType(fieldType) {
+			columns = append(columns, field.name)
+		}
+	}
+	return columns
+}
+
+// outputStructFields returns the fields of the struct values of an output
+// type, either the type itself or the items of a list
+func outputStructFields(t reflect.Type) []jsonField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice && t != rawMessageType {
+		t = t.Elem()
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+
+	if t.Kind() != reflect.Struct || t == timeType {
+		return nil
+	}
+	return jsonFields(t)
+}
+
+// isScalarType reports whether a Go type is encoded as a JSON string,
+// number or boolean
+func isScalarType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Struct:
+		return t == timeType
+	case reflect.Slice:
+		// Byte slices are encoded as base64 strings
+		return t != rawMessageType && t.Elem().Kind() == reflect.Uint8
+	default:
+		return false
+	}
+}
+
+// FormatOutput returns the text of the structured content of a tool output in
+// one of OutputFormats. As for the JSON text of a tool, a list wrapped in an
+// object by StructuredContent is formatted on its own.
+//
+// The table format is a Markdown table, and the CSV format has a header row.
+// Both show the given columns of the items of a list, one row per item, and
+// the given fields of a single object, one row per field in the table format
+// and a single row in the CSV format. Nested values are written as compact
+// JSON, except for lists of scalars whose values are separated by commas. The
+// other members of an object holding a list, such as the cursor of the next
+// page, follow the table as name: value lines.
+func FormatOutput(content any, format string, columns []string) (string, error) {
+	if object, ok := content.(map[string]any); ok && len(object) == 1 {
+		if items, ok := object[StructuredItemsProperty]; ok {
+			content = items
+		}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case OutputFormatJSON:
+		return string(data), nil
+	case OutputFormatYAML:
+		return formatYAML(data)
+	case OutputFormatTable, OutputFormatCSV:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return "", err
+		}
+		return formatRows(value, format, columns)
+	default:
+		return "", fmt.Errorf("unknown output format %s, must be one of: %s", format, strings.Join(OutputFormats, ", "))
+	}
+}
+
+// formatYAML returns the YAML of a JSON text, keeping the order of the
+// members of its objects
+func formatYAML(data []byte) (string, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return "", err
+	}
+	resetStyle(&document)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// resetStyle drops the flow and quoting styles of the nodes decoded from JSON,
+// so that they are written in the block style of YAML
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// formatRows returns the table or CSV of a value decoded from JSON
+func formatRows(value any, format string, columns []string) (string, error) {
+	var header []string
+	var rows [][]string
+	var trailer []string
+
+	object, isObject := value.(map[string]any)
+	if isObject {
+		if items, ok := object[StructuredItemsProperty].([]any); ok {
+			for _, key := range sortedKeys(object) {
+				if key != StructuredItemsProperty {
+					trailer = append(trailer, key+": "+formatCell(object[key]))
+				}
+			}
+			value, isObject = items, false
+		}
+	}
+
+	switch {
+	case isObject && format == OutputFormatTable:
+		header = []string{"field", "value"}
+		for _, column := range objectColumns(object, columns) {
+			rows = append(rows, []string{column, formatCell(object[column])})
+		}
+	case isObject:
+		header = objectColumns(object, columns)
+		row := make([]string, len(header))
+		for i, column := range header {
+			row[i] = formatCell(object[column])
+		}
+		rows = append(rows, row)
+	default:
+		items, ok := value.([]any)
+		if !ok {
+			items = []any{value}
+		}
+		header = listColumns(items, columns)
+		for _, item := range items {
+			row := make([]string, len(header))
+			for i, column := range header {
+				if object, ok := item.(map[string]any); ok {
+					row[i] = formatCell(object[column])
+				} else if len(columns) == 0 {
+					row[i] = formatCell(item)
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	var text string
+	if format == OutputFormatTable {
+		text = markdownTable(header, rows)
+	} else {
+		var err error
+		if text, err = csvTable(header, rows); err != nil {
+			return "", err
+		}
+	}
+
+	if len(trailer) > 0 {
+		text += "\n" + strings.Join(trailer, "\n") + "\n"
+	}
+	return text, nil
+}
+
+// objectColumns returns the columns of an object, its sorted members when no
+// columns are given
+func objectColumns(object map[string]any, columns []string) []string {
+	if len(columns) > 0 {
+		return columns
+	}
+	return sortedKeys(object)
+}
+
+// listColumns returns the columns of the items of a list. Without given
+// columns, these are the sorted members of the object items, or a single
+// value column for items that are not objects.
+func listColumns(items []any, columns []string) []string {
+	if len(columns) > 0 {
+		return columns
+	}
+
+	members := make(map[string]any)
+	for _, item := range items {
+		object, ok := item.(map[string]any)
+		if !ok {
+			return []string{"value"}
+		}
+		for key, value := range object {
+			members[key] = value
+		}
+	}
+	return sortedKeys(members)
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// formatCell returns the text of a value decoded from JSON in a table cell
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		values := make([]string, len(v))
+		for i, item := range v {
+			switch item.(type) {
+			case map[string]any, []any:
+				data, _ := json.Marshal(v)
+				return string(data)
+			}
+			values[i] = formatCell(item)
+		}
+		return strings.Join(values, ", ")
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// markdownTable returns a Markdown table, escaping the pipes and line breaks
+// of the cells
+func markdownTable(header []string, rows [][]string) string {
+	escape := strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" ")
+			b.WriteString(escape.Replace(cell))
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(separator)
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	return b.String()
+}
+
+// csvTable returns a CSV table with a header row
+func csvTable(header []string, rows [][]string) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(header); err != nil {
+		return "", err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,145 @@
+package toolgen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type formatTestItem struct {
+	ID        int               `json:"id"`
+	Name      string            `json:"name"`
+	Tags      []int             `json:"tags"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Enabled   bool              `json:"enabled"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Owner     *formatTestOwner  `json:"owner,omitempty"`
+}
+
+type formatTestOwner struct {
+	Name string `json:"name"`
+}
+
+func TestOutputColumns(t *testing.T) {
+	itemType := reflect.TypeFor[formatTestItem]()
+	all := []string{"id", "name", "tags", "labels", "enabled", "createdAt", "owner"}
+
+	assert.Equal(t, all, OutputFields(itemType))
+	assert.Equal(t, all, OutputFields(reflect.TypeFor[[]*formatTestItem]()))
+	assert.Nil(t, OutputFields(reflect.TypeFor[[]string]()))
+
+	assert.Equal(t, all, DefaultColumns(itemType))
+	assert.Equal(t, []string{"id", "name", "tags", "enabled", "createdAt"}, DefaultColumns(reflect.TypeFor[[]formatTestItem]()))
+	assert.Nil(t, DefaultColumns(reflect.TypeFor[[]string]()))
+}
+
+func TestFormatOutput(t *testing.T) {
+	items := []formatTestItem{
+		{ID: 1, Name: "web | api", Tags: []int{1, 2}, Labels: map[string]string{"env": "prod"}, Enabled: true},
+		{ID: 2, Name: "true", Owner: &formatTestOwner{Name: "ops"}},
+	}
+	columns := []string{"id", "name", "tags", "owner"}
+
+	tests := []struct {
+		name     string
+		content  any
+		format   string
+		columns  []string
+		expected string
+	}{
+		{
+			name:     "json list",
+			content:  StructuredContent([]formatTestOwner{{Name: "ops"}}),
+			format:   OutputFormatJSON,
+			expected: `[{"name":"ops"}]`,
+		},
+		{
+			name:    "yaml list",
+			content: StructuredContent(items[1:]),
+			format:  OutputFormatYAML,
+			expected: `- id: 2
+  name: "true"
+  tags: null
+  enabled: false
+  createdAt: "0001-01-01T00:00:00Z"
+  owner:
+    name: ops
+`,
+		},
+		{
+			name:    "table list",
+			content: StructuredContent(items),
+			format:  OutputFormatTable,
+			columns: columns,
+			expected: `| id | name | tags | owner |
+| --- | --- | --- | --- |
+| 1 | web \| api | 1, 2 |  |
+| 2 | true |  | {"name":"ops"} |
+`,
+		},
+		{
+			name:    "csv list",
+			content: StructuredContent(items),
+			format:  OutputFormatCSV,
+			columns: columns,
+			expected: `id,name,tags,owner
+1,web | api,"1, 2",
+2,true,,"{""name"":""ops""}"
+`,
+		},
+		{
+			name:    "table object",
+			content: StructuredContent(formatTestOwner{Name: "ops"}),
+			format:  OutputFormatTable,
+			columns: []string{"name"},
+			expected: `| field | value |
+| --- | --- |
+| name | ops |
+`,
+		},
+		{
+			name:    "csv object",
+			content: StructuredContent(formatTestOwner{Name: "ops"}),
+			format:  OutputFormatCSV,
+			expected: `name
+ops
+`,
+		},
+		{
+			name:    "table of scalars",
+			content: StructuredContent([]string{"2.27.0", "2.33.1"}),
+			format:  OutputFormatTable,
+			expected: `| value |
+| --- |
+| 2.27.0 |
+| 2.33.1 |
+`,
+		},
+		{
+			name:    "table with next cursor",
+			content: map[string]any{StructuredItemsProperty: []formatTestOwner{{Name: "ops"}}, "nextCursor": "abc"},
+			format:  OutputFormatTable,
+			columns: []string{"name"},
+			expected: `| name |
+| --- |
+| ops |
+
+nextCursor: abc
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := FormatOutput(tt.content, tt.format, tt.columns)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, text)
+		})
+	}
+
+	_, err := FormatOutput(items, "xml", nil)
+	assert.ErrorContains(t, err, "unknown output format xml, must be one of: json, yaml, table, csv")
+}
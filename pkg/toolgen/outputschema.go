@@ -0,0 +1,200 @@
+package toolgen
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// StructuredItemsProperty is the property holding the items of a tool output
+// that is not a JSON object, as structured content must be an object
+const StructuredItemsProperty = "items"
+
+var (
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+	timeType       = reflect.TypeFor[time.Time]()
+)
+
+// OutputSchemaFor returns the JSON Schema of the structured content of a tool
+// returning values of the given Go type, following their JSON encoding. A type
+// that is not encoded as a JSON object is wrapped in an object, as done by
+// StructuredContent.
+func OutputSchemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct && t != timeType {
+		return typeSchema(t)
+	}
+
+	items := typeSchema(t)
+	if t.Kind() == reflect.Slice && t != rawMessageType && t.Elem().Kind() != reflect.Uint8 {
+		// StructuredContent replaces a nil slice with an empty list
+		items["type"] = "array"
+	}
+
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			StructuredItemsProperty: items,
+		},
+		"required": []string{StructuredItemsProperty},
+	}
+}
+
+// StructuredContent returns the structured content of a tool output, the
+// value itself when it is encoded as a JSON object, otherwise an object
+// holding the value in its items property. A nil slice becomes an empty list.
+func StructuredContent(value any) any {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Struct && v.Type() != timeType {
+		return value
+	}
+
+	if v.Kind() == reflect.Slice && v.IsNil() && v.Type() != rawMessageType {
+		value = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+
+	return map[string]any{StructuredItemsProperty: value}
+}
+
+// typeSchema returns the JSON Schema of the JSON encoding of a Go type.
+// Slices, maps and pointers may be encoded as null.
+func typeSchema(t reflect.Type) map[string]any {
+	switch {
+	case t == rawMessageType:
+		return map[string]any{}
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(typeSchema(t.Elem()))
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			return map[string]any{"type": "string"}
+		}
+		schema := map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+		if t.Kind() == reflect.Slice {
+			return nullable(schema)
+		}
+		return schema
+	case reflect.Map:
+		schema := map[string]any{"type": "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			schema["additionalProperties"] = typeSchema(t.Elem())
+		}
+		return nullable(schema)
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		// Interfaces accept any value
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the JSON Schema of a struct, following the json tags
+// of its fields. Fields without omitempty are required, and the fields of
+// embedded structs are promoted.
+func structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	for _, field := range jsonFields(t) {
+		properties[field.name] = typeSchema(field.typ)
+		if !field.optional {
+			required = append(required, field.name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// jsonField is a field of a struct as encoded in JSON
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	optional bool
+}
+
+// jsonFields returns the fields of a struct encoded in JSON, in their order,
+// following their json tags. The fields of embedded structs are promoted.
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonField{
+			name:     name,
+			typ:      field.Type,
+			optional: strings.Contains(options, "omitempty") || strings.Contains(options, "omitzero"),
+		})
+	}
+
+	return fields
+}
+
+// nullable returns a schema also accepting null
+func nullable(schema map[string]any) map[string]any {
+	if schemaType, ok := schema["type"].(string); ok {
+		schema["type"] = []string{schemaType, "null"}
+	}
+	return schema
+}
+
+// ValidateOutput checks the structured content of a tool result against the
+// output schema of the tool, following the JSON encoding of the content
+func ValidateOutput(schema map[string]any, content any) error {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return validateValue("structuredContent", value, schema)
+}
@@ -0,0 +1,136 @@
+package toolgen
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEmbedded struct {
+	Owner string `json:"owner"`
+}
+
+type testOutput struct {
+	testEmbedded
+	ID       int               `json:"id"`
+	Name     string            `json:"name,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Parent   *testEmbedded     `json:"parent"`
+	Created  time.Time         `json:"created"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func TestOutputSchemaFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      reflect.Type
+		expected map[string]any
+	}{
+		{
+			name: "struct",
+			typ:  reflect.TypeFor[testOutput](),
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"owner": map[string]any{"type": "string"},
+					"id":    map[string]any{"type": "integer"},
+					"name":  map[string]any{"type": "string"},
+					"tags": map[string]any{
+						"type":  []string{"array", "null"},
+						"items": map[string]any{"type": "string"},
+					},
+					"labels": map[string]any{
+						"type":                 []string{"object", "null"},
+						"additionalProperties": map[string]any{"type": "string"},
+					},
+					"parent": map[string]any{
+						"type": []string{"object", "null"},
+						"properties": map[string]any{
+							"owner": map[string]any{"type": "string"},
+						},
+						"required": []string{"owner"},
+					},
+					"created": map[string]any{"type": "string", "format": "date-time"},
+					"raw":     map[string]any{},
+				},
+				"required": []string{"owner", "id", "tags", "parent", "created"},
+			},
+		},
+		{
+			name: "pointer to struct",
+			typ:  reflect.TypeFor[*testEmbedded](),
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"owner": map[string]any{"type": "string"},
+				},
+				"required": []string{"owner"},
+			},
+		},
+		{
+			name: "slice",
+			typ:  reflect.TypeFor[[]int](),
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"items": map[string]any{
+						"type":  "array",
+						"items": map[string]any{"type": "integer"},
+					},
+				},
+				"required": []string{"items"},
+			},
+		},
+		{
+			name: "string",
+			typ:  reflect.TypeFor[string](),
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"items": map[string]any{"type": "string"},
+				},
+				"required": []string{"items"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, OutputSchemaFor(tt.typ))
+		})
+	}
+}
+
+func TestStructuredContent(t *testing.T) {
+	output := testOutput{ID: 1}
+	assert.Equal(t, output, StructuredContent(output))
+	assert.Equal(t, &output, StructuredContent(&output))
+
+	assert.Equal(t, map[string]any{"items": []int{1, 2}}, StructuredContent([]int{1, 2}))
+	assert.Equal(t, map[string]any{"items": []int{}}, StructuredContent([]int(nil)))
+	assert.Equal(t, map[string]any{"items": "text"}, StructuredContent("text"))
+}
+
+func TestValidateOutput(t *testing.T) {
+	schema := OutputSchemaFor(reflect.TypeFor[[]testOutput]())
+
+	assert.NoError(t, ValidateOutput(schema, StructuredContent([]testOutput(nil))))
+	assert.NoError(t, ValidateOutput(schema, StructuredContent([]testOutput{{ID: 1, Tags: []string{"a"}}})))
+
+	err := ValidateOutput(schema, map[string]any{"items": []any{map[string]any{"id": "1"}}})
+	assert.EqualError(t, err, "structuredContent.items[0].owner is required")
+
+	err = ValidateOutput(schema, map[string]any{"items": []any{map[string]any{
+		"owner": "admin", "id": 1.5, "tags": nil, "parent": nil, "created": "2024-01-01T00:00:00Z",
+	}}})
+	assert.EqualError(t, err, "structuredContent.items[0].id must be an integer")
+
+	err = ValidateOutput(schema, map[string]any{"items": nil})
+	assert.EqualError(t, err, "structuredContent.items must be an array")
+}
@@ -0,0 +1,209 @@
+package toolgen
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+)
+
+// ApplyOverlay returns the tools of a base configuration customised by an
+// overlay configuration. Every tool of the base keeps its definition, unless
+// the overlay lists it, in which case:
+//   - a disabled tool is removed
+//   - a non-empty description replaces the description of the tool
+//   - a non-empty parameter description replaces the description of the
+//     parameter of the same name
+//
+// Other fields of the overlay are ignored, as the tools and their parameters
+// are implemented by the server. Overlays of tools or parameters missing from
+// the base are skipped.
+// The returned configuration has the version of the base.
+func ApplyOverlay(base, overlay ToolsConfig) ToolsConfig {
+	overrides := make(map[string]ToolDefinition, len(overlay.Tools))
+	for _, def := range overlay.Tools {
+		overrides[def.Name] = def
+	}
+
+	result := ToolsConfig{Version: base.Version}
+
+	for _, def := range base.Tools {
+		override, ok := overrides[def.Name]
+		if !ok {
+			result.Tools = append(result.Tools, def)
+			continue
+		}
+		delete(overrides, def.Name)
+
+		if override.Disabled {
+			continue
+		}
+
+		result.Tools = append(result.Tools, overrideTool(def, override))
+	}
+
+	for name := range overrides {
+		log.Printf("skipping overlay of unknown tool %s", name)
+	}
+
+	return result
+}
+
+// overrideTool applies the overlay of a single tool to its base definition
+func overrideTool(def, override ToolDefinition) ToolDefinition {
+	if override.Description != "" {
+		def.Description = override.Description
+	}
+
+	if len(override.Parameters) == 0 {
+		return def
+	}
+
+	// The parameters are copied so that the base definition is left untouched
+	params := make([]ParameterDefinition, len(def.Parameters))
+	copy(params, def.Parameters)
+
+	for _, paramOverride := range override.Parameters {
+		i := parameterIndex(params, paramOverride.Name)
+		if i < 0 {
+			log.Printf("skipping overlay of unknown parameter %s of tool %s", paramOverride.Name, def.Name)
+			continue
+		}
+
+		if paramOverride.Description != "" {
+			params[i].Description = paramOverride.Description
+		}
+	}
+
+	def.Parameters = params
+	return def
+}
+
+// parameterIndex returns the index of the parameter of the given name, or -1
+func parameterIndex(params []ParameterDefinition, name string) int {
+	for i, param := range params {
+		if param.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// MergeResult is the outcome of a three-way merge of customised tools onto
+// new tool definitions
+type MergeResult struct {
+	// Overlay holds the customisations kept, to be applied to the new definitions
+	Overlay ToolsConfig
+	// Conflicts lists the customisations of definitions also changed by the
+	// new version, in which case the customisation is kept
+	Conflicts []string
+	// Dropped lists the customisations that cannot be expressed as an overlay,
+	// or target tools and parameters removed by the new version
+	Dropped []string
+}
+
+// MergeOverlay performs a three-way merge of a customised copy of the base
+// tools onto the new tools. A field is customised when its value differs from
+// the base. Customised descriptions, parameter descriptions and removed tools
+// are kept in the overlay of the result, everything else follows the new
+// tools, including the tools they add.
+//
+// Without a base, that is a zero ToolsConfig, every difference between the
+// customised and the new tools is considered a customisation.
+func MergeOverlay(base, custom, latest ToolsConfig) MergeResult {
+	result := MergeResult{
+		Overlay: ToolsConfig{Version: latest.Version, Overlay: true},
+	}
+
+	baseTools := toolsByName(base.Tools)
+	customTools := toolsByName(custom.Tools)
+	latestTools := toolsByName(latest.Tools)
+	hasBase := len(base.Tools) > 0
+
+	for _, latestDef := range latest.Tools {
+		name := latestDef.Name
+		baseDef, inBase := baseTools[name]
+		if !hasBase {
+			baseDef, inBase = latestDef, true
+		}
+
+		customDef, inCustom := customTools[name]
+		if !inCustom {
+			// A tool missing from the customised tools was removed, unless
+			// it is new
+			if inBase && hasBase {
+				result.Overlay.Tools = append(result.Overlay.Tools, ToolDefinition{Name: name, Disabled: true})
+			}
+			continue
+		}
+
+		if !inBase {
+			// The customised tools define a tool that is new in the latest
+			// tools, the definition is compared to the latest one
+			baseDef = latestDef
+		}
+
+		override := ToolDefinition{Name: name}
+
+		if customDef.Description != baseDef.Description && customDef.Description != latestDef.Description {
+			override.Description = customDef.Description
+			if baseDef.Description != latestDef.Description {
+				result.Conflicts = append(result.Conflicts, fmt.Sprintf("description of tool %s", name))
+			}
+		}
+
+		for _, customParam := range customDef.Parameters {
+			latestIndex := parameterIndex(latestDef.Parameters, customParam.Name)
+			baseIndex := parameterIndex(baseDef.Parameters, customParam.Name)
+
+			if latestIndex < 0 {
+				if baseIndex < 0 || !reflect.DeepEqual(baseDef.Parameters[baseIndex], customParam) {
+					result.Dropped = append(result.Dropped, fmt.Sprintf("parameter %s of tool %s is not defined by the new version", customParam.Name, name))
+				}
+				continue
+			}
+
+			latestParam := latestDef.Parameters[latestIndex]
+			baseParam := latestParam
+			if baseIndex >= 0 {
+				baseParam = baseDef.Parameters[baseIndex]
+			}
+
+			if customParam.Description != baseParam.Description && customParam.Description != latestParam.Description {
+				override.Parameters = append(override.Parameters, ParameterDefinition{Name: customParam.Name, Description: customParam.Description})
+				if baseParam.Description != latestParam.Description {
+					result.Conflicts = append(result.Conflicts, fmt.Sprintf("description of parameter %s of tool %s", customParam.Name, name))
+				}
+			}
+
+			customParam.Description = baseParam.Description
+			if !reflect.DeepEqual(customParam, baseParam) {
+				result.Dropped = append(result.Dropped, fmt.Sprintf("definition of parameter %s of tool %s other than its description", customParam.Name, name))
+			}
+		}
+
+		if customDef.Annotations != baseDef.Annotations {
+			result.Dropped = append(result.Dropped, fmt.Sprintf("annotations of tool %s", name))
+		}
+
+		if override.Description != "" || len(override.Parameters) > 0 {
+			result.Overlay.Tools = append(result.Overlay.Tools, override)
+		}
+	}
+
+	for _, customDef := range custom.Tools {
+		if _, ok := latestTools[customDef.Name]; !ok {
+			result.Dropped = append(result.Dropped, fmt.Sprintf("tool %s is not defined by the new version", customDef.Name))
+		}
+	}
+
+	return result
+}
+
+// toolsByName indexes tool definitions by name
+func toolsByName(defs []ToolDefinition) map[string]ToolDefinition {
+	tools := make(map[string]ToolDefinition, len(defs))
+	for _, def := range defs {
+		tools[def.Name] = def
+	}
+	return tools
+}
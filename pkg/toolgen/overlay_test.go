@@ -0,0 +1,143 @@
+package toolgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var overlayTestAnnotations = Annotations{Title: "Test Tool", ReadOnlyHint: true, IdempotentHint: true}
+
+func overlayTestBase() ToolsConfig {
+	return ToolsConfig{
+		Version: "v1.1",
+		Tools: []ToolDefinition{
+			{
+				Name:        "listItems",
+				Description: "List the items",
+				Annotations: overlayTestAnnotations,
+			},
+			{
+				Name:        "getItem",
+				Description: "Get an item",
+				Parameters: []ParameterDefinition{
+					{Name: "id", Type: "number", Required: true, Description: "The ID of the item"},
+					{Name: "format", Type: "string", Enum: []string{"json", "yaml"}, Description: "The output format"},
+				},
+				Annotations: overlayTestAnnotations,
+			},
+			{
+				Name:        "deleteItem",
+				Description: "Delete an item",
+				Annotations: Annotations{Title: "Delete Item", DestructiveHint: true},
+			},
+		},
+	}
+}
+
+func TestApplyOverlay(t *testing.T) {
+	base := overlayTestBase()
+	overlay := ToolsConfig{
+		Version: "v1.0",
+		Overlay: true,
+		Tools: []ToolDefinition{
+			{Name: "listItems", Description: "List every item, prefer this tool to find item IDs"},
+			{
+				Name: "getItem",
+				Parameters: []ParameterDefinition{
+					{Name: "format", Type: "number", Description: "Prefer yaml"},
+					{Name: "unknown", Description: "Not a parameter of the tool"},
+				},
+			},
+			{Name: "deleteItem", Disabled: true},
+			{Name: "unknownTool", Description: "Not a tool of the base"},
+		},
+	}
+
+	result := ApplyOverlay(base, overlay)
+
+	assert.Equal(t, ToolsConfig{
+		Version: "v1.1",
+		Tools: []ToolDefinition{
+			{
+				Name:        "listItems",
+				Description: "List every item, prefer this tool to find item IDs",
+				Annotations: overlayTestAnnotations,
+			},
+			{
+				Name:        "getItem",
+				Description: "Get an item",
+				Parameters: []ParameterDefinition{
+					{Name: "id", Type: "number", Required: true, Description: "The ID of the item"},
+					{Name: "format", Type: "string", Enum: []string{"json", "yaml"}, Description: "Prefer yaml"},
+				},
+				Annotations: overlayTestAnnotations,
+			},
+		},
+	}, result)
+
+	// The base is left untouched
+	assert.Equal(t, overlayTestBase(), base)
+}
+
+func TestMergeOverlay(t *testing.T) {
+	base := overlayTestBase()
+
+	latest := overlayTestBase()
+	latest.Version = "v1.2"
+	latest.Tools[1].Description = "Get a single item"
+	latest.Tools[1].Parameters[0].Description = "The identifier of the item"
+	latest.Tools = append(latest.Tools, ToolDefinition{Name: "createItem", Description: "Create an item", Annotations: overlayTestAnnotations})
+
+	custom := overlayTestBase()
+	custom.Tools[0].Description = "List every item"
+	custom.Tools[1].Description = "Get one item"
+	custom.Tools[1].Parameters[1].Description = "Prefer yaml"
+	custom.Tools[1].Parameters[1].Enum = []string{"yaml"}
+	custom.Tools[1].Parameters = append(custom.Tools[1].Parameters, ParameterDefinition{Name: "verbose", Type: "boolean"})
+	custom.Tools = custom.Tools[:2]
+	custom.Tools = append(custom.Tools, ToolDefinition{Name: "customTool", Description: "A tool of my own", Annotations: overlayTestAnnotations})
+
+	result := MergeOverlay(base, custom, latest)
+
+	assert.Equal(t, ToolsConfig{
+		Version: "v1.2",
+		Overlay: true,
+		Tools: []ToolDefinition{
+			{Name: "listItems", Description: "List every item"},
+			{
+				Name:        "getItem",
+				Description: "Get one item",
+				Parameters:  []ParameterDefinition{{Name: "format", Description: "Prefer yaml"}},
+			},
+			{Name: "deleteItem", Disabled: true},
+		},
+	}, result.Overlay)
+	assert.Equal(t, []string{"description of tool getItem"}, result.Conflicts)
+	assert.Equal(t, []string{
+		"definition of parameter format of tool getItem other than its description",
+		"parameter verbose of tool getItem is not defined by the new version",
+		"tool customTool is not defined by the new version",
+	}, result.Dropped)
+
+	// The customisations applied to the latest tools only differ from the
+	// customised tools by the changes that cannot be kept
+	merged := ApplyOverlay(latest, result.Overlay)
+	assert.Equal(t, "The identifier of the item", merged.Tools[1].Parameters[0].Description)
+	assert.Equal(t, "createItem", merged.Tools[2].Name)
+}
+
+func TestMergeOverlayWithoutBase(t *testing.T) {
+	latest := overlayTestBase()
+
+	custom := overlayTestBase()
+	custom.Tools[0].Description = "List every item"
+	custom.Tools = custom.Tools[:2]
+
+	result := MergeOverlay(ToolsConfig{}, custom, latest)
+
+	// Without a base, a missing tool cannot be told apart from a new tool
+	assert.Equal(t, []ToolDefinition{{Name: "listItems", Description: "List every item"}}, result.Overlay.Tools)
+	assert.Empty(t, result.Conflicts)
+	assert.Empty(t, result.Dropped)
+}
@@ -2,13 +2,16 @@ package toolgen
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // ParameterParser provides methods to safely extract parameters from request arguments
 type ParameterParser struct {
-	args map[string]any
+	args   map[string]any
+	schema *mcp.ToolInputSchema
 }
 
 // NewParameterParser creates a new parameter parser for the given request
@@ -18,6 +21,81 @@ func NewParameterParser(request mcp.CallToolRequest) *ParameterParser {
 	}
 }
 
+// NewParameterParserWithSchema creates a parameter parser enforcing the
+// input schema of the tool called by the request. Missing arguments are set
+// to the default value of their property, and the getters return an error
+// for a value violating the constraints of its property.
+func NewParameterParserWithSchema(request mcp.CallToolRequest, schema mcp.ToolInputSchema) *ParameterParser {
+	// The arguments are copied so that the defaults are not added to the request
+	args := maps.Clone(request.GetArguments())
+	if args == nil {
+		args = make(map[string]any)
+	}
+
+	for name, property := range schema.Properties {
+		propertySchema, ok := property.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		defaultValue, ok := propertySchema["default"]
+		if value, exists := args[name]; ok && (!exists || value == nil) {
+			args[name] = defaultValue
+		}
+	}
+
+	return &ParameterParser{
+		args:   args,
+		schema: &schema,
+	}
+}
+
+// Arguments returns the arguments of the request, including the default
+// values set by the schema
+func (p *ParameterParser) Arguments() map[string]any {
+	return p.args
+}
+
+// Validate checks every argument against the schema of the parser: the
+// required arguments must be set and each argument must satisfy the
+// constraints of its property. Arguments without a property are not checked.
+// A parser without a schema accepts any arguments.
+func (p *ParameterParser) Validate() error {
+	if p.schema == nil {
+		return nil
+	}
+
+	for _, name := range p.schema.Required {
+		if value, ok := p.args[name]; !ok || value == nil {
+			return fmt.Errorf("%s is required", name)
+		}
+	}
+
+	names := slices.Sorted(maps.Keys(p.args))
+	for _, name := range names {
+		if err := p.checkConstraints(name, p.args[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkConstraints checks an argument against the schema of its property,
+// if any. Missing arguments are not checked.
+func (p *ParameterParser) checkConstraints(name string, value any) error {
+	if p.schema == nil || value == nil {
+		return nil
+	}
+
+	property, ok := p.schema.Properties[name].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	return validateValue(name, value, property)
+}
+
 // GetString extracts a string parameter from the request
 func (p *ParameterParser) GetString(name string, required bool) (string, error) {
 	value, ok := p.args[name]
@@ -33,6 +111,10 @@ func (p *ParameterParser) GetString(name string, required bool) (string, error)
 		return "", fmt.Errorf("%s must be a string", name)
 	}
 
+	if err := p.checkConstraints(name, value); err != nil {
+		return "", err
+	}
+
 	return strValue, nil
 }
 
@@ -51,6 +133,10 @@ func (p *ParameterParser) GetNumber(name string, required bool) (float64, error)
 		return 0, fmt.Errorf("%s must be a number", name)
 	}
 
+	if err := p.checkConstraints(name, value); err != nil {
+		return 0, err
+	}
+
 	return numValue, nil
 }
 
@@ -78,6 +164,10 @@ func (p *ParameterParser) GetBoolean(name string, required bool) (bool, error) {
 		return false, fmt.Errorf("%s must be a boolean", name)
 	}
 
+	if err := p.checkConstraints(name, value); err != nil {
+		return false, err
+	}
+
 	return boolValue, nil
 }
 
@@ -96,6 +186,10 @@ func (p *ParameterParser) GetArrayOfIntegers(name string, required bool) ([]int,
 		return nil, fmt.Errorf("%s must be an array", name)
 	}
 
+	if err := p.checkConstraints(name, value); err != nil {
+		return nil, err
+	}
+
 	return parseArrayOfIntegers(arrayValue)
 }
 
@@ -114,9 +208,53 @@ func (p *ParameterParser) GetArrayOfObjects(name string, required bool) ([]any,
 		return nil, fmt.Errorf("%s must be an array", name)
 	}
 
+	if err := p.checkConstraints(name, value); err != nil {
+		return nil, err
+	}
+
 	return arrayValue, nil
 }
 
+// GetObject extracts an object parameter from the request
+func (p *ParameterParser) GetObject(name string, required bool) (map[string]any, error) {
+	value, ok := p.args[name]
+	if !ok || value == nil {
+		if required {
+			return nil, fmt.Errorf("%s is required", name)
+		}
+		return nil, nil
+	}
+
+	objectValue, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object", name)
+	}
+
+	if err := p.checkConstraints(name, value); err != nil {
+		return nil, err
+	}
+
+	return objectValue, nil
+}
+
+// GetValue extracts a parameter of any type from the request, such as a
+// parameter defined by alternatives
+func (p *ParameterParser) GetValue(name string, required bool) (any, error) {
+	value, ok := p.args[name]
+	if !ok || value == nil {
+		if required {
+			return nil, fmt.Errorf("%s is required", name)
+		}
+		return nil, nil
+	}
+
+	if err := p.checkConstraints(name, value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
 // parseArrayOfIntegers converts a slice of any type to a slice of integers.
 // Returns an error if any value cannot be parsed as an integer.
 //
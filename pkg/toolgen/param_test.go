@@ -579,3 +579,213 @@ func TestGetArrayOfIntegers(t *testing.T) {
 		})
 	}
 }
+
+// newTestSchema returns the input schema of a tool with the given parameters
+func newTestSchema(params ...ParameterDefinition) mcp.ToolInputSchema {
+	options := make([]mcp.ToolOption, 0, len(params))
+	for _, param := range params {
+		options = append(options, convertParameter(param))
+	}
+	return mcp.NewTool("testTool", options...).InputSchema
+}
+
+func TestNewParameterParserWithSchemaDefaults(t *testing.T) {
+	schema := newTestSchema(
+		ParameterDefinition{Name: "limit", Type: "number", Default: 20},
+		ParameterDefinition{Name: "sort", Type: "string", Default: "name"},
+		ParameterDefinition{Name: "all", Type: "boolean"},
+	)
+
+	args := map[string]any{"sort": "id"}
+	p := NewParameterParserWithSchema(mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Arguments: args},
+	}, schema)
+
+	want := map[string]any{"limit": float64(20), "sort": "id"}
+	if got := p.Arguments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Arguments() = %v, want %v", got, want)
+	}
+
+	if len(args) != 1 {
+		t.Errorf("the request arguments must not be modified, got %v", args)
+	}
+
+	limit, err := p.GetInt("limit", false)
+	if err != nil || limit != 20 {
+		t.Errorf("GetInt() = %v, %v, want 20", limit, err)
+	}
+}
+
+func TestParameterParserValidate(t *testing.T) {
+	minimum := 1.0
+	maxLength := 5
+
+	schema := newTestSchema(
+		ParameterDefinition{Name: "id", Type: "number", Required: true, Minimum: &minimum},
+		ParameterDefinition{Name: "path", Type: "string", Pattern: "^/"},
+		ParameterDefinition{Name: "name", Type: "string", MaxLength: &maxLength},
+		ParameterDefinition{Name: "role", Type: "string", Enum: []string{"admin", "user"}},
+		ParameterDefinition{Name: "ids", Type: "array", Items: map[string]any{"type": "number", "minimum": 1}},
+	)
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr string
+	}{
+		{
+			name: "valid arguments",
+			args: map[string]any{"id": float64(1), "path": "/containers", "name": "web", "role": "admin", "ids": []any{float64(1)}},
+		},
+		{
+			name: "unknown arguments are not checked",
+			args: map[string]any{"id": float64(1), "other": 42},
+		},
+		{
+			name:    "missing required argument",
+			args:    map[string]any{"path": "/containers"},
+			wantErr: "id is required",
+		},
+		{
+			name:    "below minimum",
+			args:    map[string]any{"id": float64(0)},
+			wantErr: "id must be greater than or equal to 1",
+		},
+		{
+			name:    "pattern mismatch",
+			args:    map[string]any{"id": float64(1), "path": "containers"},
+			wantErr: "path must match the pattern ^/",
+		},
+		{
+			name:    "too long",
+			args:    map[string]any{"id": float64(1), "name": "webserver"},
+			wantErr: "name must be at most 5 characters long",
+		},
+		{
+			name:    "not in enum",
+			args:    map[string]any{"id": float64(1), "role": "root"},
+			wantErr: "role must be one of: admin, user",
+		},
+		{
+			name:    "invalid item",
+			args:    map[string]any{"id": float64(1), "ids": []any{float64(1), float64(0)}},
+			wantErr: "ids[1] must be greater than or equal to 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParameterParserWithSchema(mcp.CallToolRequest{
+				Params: mcp.CallToolParams{Arguments: tt.args},
+			}, schema)
+
+			err := p.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGettersEnforceSchemaConstraints(t *testing.T) {
+	schema := newTestSchema(ParameterDefinition{Name: "dockerAPIPath", Type: "string", Pattern: "^/"})
+
+	p := NewParameterParserWithSchema(mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Arguments: map[string]any{"dockerAPIPath": "containers/json"}},
+	}, schema)
+
+	if _, err := p.GetString("dockerAPIPath", true); err == nil {
+		t.Error("GetString() expected an error for a value violating the pattern")
+	}
+
+	// A parser without a schema only checks the type
+	p = newTestParser(map[string]any{"dockerAPIPath": "containers/json"})
+	if _, err := p.GetString("dockerAPIPath", true); err != nil {
+		t.Errorf("GetString() error = %v, want nil", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestGetObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]any
+		required bool
+		want     map[string]any
+		wantErr  bool
+	}{
+		{
+			name:     "valid object",
+			args:     map[string]any{"target": map[string]any{"port": float64(80)}},
+			required: true,
+			want:     map[string]any{"port": float64(80)},
+		},
+		{
+			name:     "missing required param",
+			args:     map[string]any{},
+			required: true,
+			wantErr:  true,
+		},
+		{
+			name:     "missing optional param",
+			args:     map[string]any{},
+			required: false,
+		},
+		{
+			name:     "wrong type",
+			args:     map[string]any{"target": "port=80"},
+			required: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParser(tt.args)
+			got, err := p.GetObject("target", tt.required)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetObject() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetObject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetValue(t *testing.T) {
+	schema := newTestSchema(ParameterDefinition{Name: "id", OneOf: []ParameterDefinition{
+		{Type: "number"},
+		{Type: "string", Pattern: "^[a-z]+$"},
+	}})
+
+	for _, value := range []any{float64(1), "web"} {
+		p := NewParameterParserWithSchema(mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Arguments: map[string]any{"id": value}},
+		}, schema)
+		got, err := p.GetValue("id", true)
+		if err != nil || got != value {
+			t.Errorf("GetValue() = %v, %v, want %v", got, err, value)
+		}
+	}
+
+	p := NewParameterParserWithSchema(mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Arguments: map[string]any{"id": "Web"}},
+	}, schema)
+	if _, err := p.GetValue("id", true); err == nil {
+		t.Error("GetValue() expected an error for a value matching no alternative")
+	}
+
+	if _, err := newTestParser(map[string]any{}).GetValue("id", true); err == nil {
+		t.Error("GetValue() expected an error for a missing required param")
+	}
+}
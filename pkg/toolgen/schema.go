@@ -0,0 +1,300 @@
+package toolgen
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// validateValue checks a value against the JSON Schema of a property. The
+// supported keywords are type, enum, minimum, maximum, minLength, maxLength,
+// pattern, format, items, properties, additionalProperties, required and
+// oneOf; other keywords are ignored. The path names the value in the returned error.
+func validateValue(path string, value any, schema map[string]any) error {
+	if alternatives, ok := schema["oneOf"].([]any); ok {
+		matches := 0
+		for _, alternative := range alternatives {
+			alternativeSchema, ok := alternative.(map[string]any)
+			if ok && validateValue(path, value, alternativeSchema) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s must match exactly one of the allowed definitions, it matches %d", path, matches)
+		}
+	}
+
+	if types := stringList(schema["type"]); len(types) > 0 {
+		if value == nil && slices.Contains(types, "null") {
+			return nil
+		}
+		if err := checkTypes(path, value, types); err != nil {
+			return err
+		}
+	} else if schemaType, ok := schema["type"].(string); ok {
+		if err := checkType(path, value, schemaType); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := schema["enum"]; ok && !inEnum(value, enum) {
+		return fmt.Errorf("%s must be one of: %s", path, formatEnum(enum))
+	}
+
+	switch v := value.(type) {
+	case string:
+		return checkString(path, v, schema)
+	case []any:
+		return checkArray(path, v, schema)
+	case map[string]any:
+		return checkObject(path, v, schema)
+	default:
+		if number, ok := toFloat(value); ok {
+			return checkNumber(path, number, schema)
+		}
+	}
+
+	return nil
+}
+
+// checkTypes checks that a value has one of the JSON Schema types of a property
+func checkTypes(path string, value any, types []string) error {
+	for _, schemaType := range types {
+		if checkType(path, value, schemaType) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be one of the types %s", path, strings.Join(types, ", "))
+}
+
+// checkType checks that a value has the JSON Schema type of a property
+func checkType(path string, value any, schemaType string) error {
+	var valid bool
+
+	switch schemaType {
+	case "string":
+		_, valid = value.(string)
+	case "number":
+		_, valid = toFloat(value)
+	case "integer":
+		number, ok := toFloat(value)
+		valid = ok && number == math.Trunc(number)
+	case "boolean":
+		_, valid = value.(bool)
+	case "array":
+		_, valid = value.([]any)
+	case "object":
+		_, valid = value.(map[string]any)
+	case "null":
+		valid = value == nil
+	default:
+		valid = true
+	}
+
+	if !valid {
+		article := "a"
+		if schemaType == "array" || schemaType == "object" || schemaType == "integer" {
+			article = "an"
+		}
+		return fmt.Errorf("%s must be %s %s", path, article, schemaType)
+	}
+
+	return nil
+}
+
+// checkNumber checks a number against the minimum and maximum of a property
+func checkNumber(path string, number float64, schema map[string]any) error {
+	if minimum, ok := toFloat(schema["minimum"]); ok && number < minimum {
+		return fmt.Errorf("%s must be greater than or equal to %v", path, minimum)
+	}
+
+	if maximum, ok := toFloat(schema["maximum"]); ok && number > maximum {
+		return fmt.Errorf("%s must be less than or equal to %v", path, maximum)
+	}
+
+	return nil
+}
+
+// checkString checks a string against the length, pattern and format of a property
+func checkString(path, value string, schema map[string]any) error {
+	length := utf8.RuneCountInString(value)
+
+	if minLength, ok := toFloat(schema["minLength"]); ok && float64(length) < minLength {
+		return fmt.Errorf("%s must be at least %v characters long", path, minLength)
+	}
+
+	if maxLength, ok := toFloat(schema["maxLength"]); ok && float64(length) > maxLength {
+		return fmt.Errorf("%s must be at most %v characters long", path, maxLength)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern of %s: %w", path, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%s must match the pattern %s", path, pattern)
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && !isValidFormat(value, format) {
+		return fmt.Errorf("%s must be a valid %s", path, format)
+	}
+
+	return nil
+}
+
+// isValidFormat reports whether a string has a JSON Schema format. Formats
+// other than date-time, date, uri, email, ipv4 and ipv6 are not checked.
+func isValidFormat(value, format string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	case "uri":
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != ""
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "ipv4":
+		addr, err := netip.ParseAddr(value)
+		return err == nil && addr.Is4()
+	case "ipv6":
+		addr, err := netip.ParseAddr(value)
+		return err == nil && addr.Is6()
+	default:
+		return true
+	}
+}
+
+// checkArray checks the items of an array against the items schema of a property
+func checkArray(path string, values []any, schema map[string]any) error {
+	items, ok := schema["items"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	for i, item := range values {
+		if err := validateValue(fmt.Sprintf("%s[%d]", path, i), item, items); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkObject checks the properties of an object against the properties,
+// additional properties and required properties of its schema. A null
+// property is only checked when it is required.
+func checkObject(path string, value map[string]any, schema map[string]any) error {
+	required := stringList(schema["required"])
+	for _, name := range required {
+		if _, ok := value[name]; !ok {
+			return fmt.Errorf("%s.%s is required", path, name)
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	additional, _ := schema["additionalProperties"].(map[string]any)
+
+	for _, name := range slices.Sorted(maps.Keys(value)) {
+		propertySchema, ok := properties[name].(map[string]any)
+		if !ok {
+			propertySchema = additional
+		}
+		if propertySchema == nil || (value[name] == nil && !slices.Contains(required, name)) {
+			continue
+		}
+		if err := validateValue(path+"."+name, value[name], propertySchema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inEnum reports whether a value is one of the values of an enum
+func inEnum(value any, enum any) bool {
+	switch values := enum.(type) {
+	case []string:
+		s, ok := value.(string)
+		return ok && slices.Contains(values, s)
+	case []any:
+		for _, allowed := range values {
+			if reflect.DeepEqual(jsonValue(allowed), value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// formatEnum lists the values of an enum in an error message
+func formatEnum(enum any) string {
+	switch values := enum.(type) {
+	case []string:
+		return strings.Join(values, ", ")
+	case []any:
+		parts := make([]string, 0, len(values))
+		for _, value := range values {
+			parts = append(parts, fmt.Sprint(value))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(enum)
+	}
+}
+
+// stringList returns the strings of a list decoded from YAML, JSON or
+// built in Go
+func stringList(value any) []string {
+	switch values := value.(type) {
+	case []string:
+		return values
+	case []any:
+		result := make([]string, 0, len(values))
+		for _, item := range values {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// toFloat converts a number decoded from YAML, JSON or built in Go to a float64
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,132 @@
+package toolgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         any
+		schema        map[string]any
+		errorContains string
+	}{
+		{
+			name:   "integer",
+			value:  float64(3),
+			schema: map[string]any{"type": "integer"},
+		},
+		{
+			name:          "fractional integer",
+			value:         3.5,
+			schema:        map[string]any{"type": "integer"},
+			errorContains: "value must be an integer",
+		},
+		{
+			name:          "minimum length counts characters",
+			value:         "é",
+			schema:        map[string]any{"type": "string", "minLength": 2},
+			errorContains: "value must be at least 2 characters long",
+		},
+		{
+			name:          "enum decoded from JSON",
+			value:         float64(3),
+			schema:        map[string]any{"enum": []any{1, 2}},
+			errorContains: "value must be one of: 1, 2",
+		},
+		{
+			name:  "nested object",
+			value: map[string]any{"key": "all", "value": "true"},
+			schema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"key": map[string]any{"type": "string"}},
+				"required":   []any{"key", "value"},
+			},
+		},
+		{
+			name:  "missing nested property",
+			value: map[string]any{"value": "true"},
+			schema: map[string]any{
+				"type":     "object",
+				"required": []string{"key"},
+			},
+			errorContains: "value.key is required",
+		},
+		{
+			name:  "invalid nested property",
+			value: map[string]any{"port": "80"},
+			schema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"port": map[string]any{"type": "number", "maximum": 65535}},
+			},
+			errorContains: "value.port must be a number",
+		},
+		{
+			name:  "oneOf matching one alternative",
+			value: "web",
+			schema: map[string]any{"oneOf": []any{
+				map[string]any{"type": "number"},
+				map[string]any{"type": "string"},
+			}},
+		},
+		{
+			name:  "oneOf matching no alternative",
+			value: true,
+			schema: map[string]any{"oneOf": []any{
+				map[string]any{"type": "number"},
+				map[string]any{"type": "string"},
+			}},
+			errorContains: "value must match exactly one of the allowed definitions, it matches 0",
+		},
+		{
+			name:  "oneOf matching several alternatives",
+			value: "web",
+			schema: map[string]any{"oneOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "string", "maxLength": 5},
+			}},
+			errorContains: "it matches 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateValue("value", tt.value, tt.schema)
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+}
+
+func TestIsValidFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		want   bool
+	}{
+		{"date-time", "2026-01-02T15:04:05Z", true},
+		{"date-time", "2026-01-02", false},
+		{"date", "2026-01-02", true},
+		{"date", "02/01/2026", false},
+		{"uri", "https://portainer.example.com/api", true},
+		{"uri", "portainer.example.com", false},
+		{"email", "admin@example.com", true},
+		{"email", "Admin <admin@example.com>", false},
+		{"ipv4", "10.0.0.1", true},
+		{"ipv4", "::1", false},
+		{"ipv6", "::1", true},
+		{"ipv6", "10.0.0.1", false},
+		{"hostname", "anything goes", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, isValidFormat(tt.value, tt.format))
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package toolgen
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"golang.org/x/mod/semver"
@@ -12,26 +15,72 @@ import (
 
 // ToolsConfig represents the entire YAML configuration
 type ToolsConfig struct {
-	Version string           `yaml:"version"`
+	Version string `yaml:"version"`
+	// Overlay marks a configuration that only customises the tools of a base
+	// configuration, see ApplyOverlay
+	Overlay bool             `yaml:"overlay,omitempty"`
 	Tools   []ToolDefinition `yaml:"tools"`
 }
 
 // ToolDefinition represents a single tool in the YAML config
 type ToolDefinition struct {
-	Name        string                `yaml:"name"`
-	Description string                `yaml:"description"`
-	Parameters  []ParameterDefinition `yaml:"parameters"`
-	Annotations Annotations           `yaml:"annotations"`
+	Name                string                `yaml:"name"`
+	Description         string                `yaml:"description,omitempty"`
+	Parameters          []ParameterDefinition `yaml:"parameters,omitempty"`
+	Annotations         Annotations           `yaml:"annotations,omitempty"`
+	MinPortainerVersion string                `yaml:"minPortainerVersion,omitempty"`
+	MaxPortainerVersion string                `yaml:"maxPortainerVersion,omitempty"`
+	Edition             string                `yaml:"edition,omitempty"`
+	// OutputSchema is the JSON Schema of the structured content of the tool
+	// results, an object
+	OutputSchema map[string]any `yaml:"outputSchema,omitempty"`
+	// Disabled removes the tool of the base configuration, in an overlay
+	Disabled bool `yaml:"disabled,omitempty"`
 }
 
-// ParameterDefinition represents a tool parameter in the YAML config
+// Portainer editions a tool can be restricted to
+const (
+	EditionCE = "CE"
+	EditionBE = "BE"
+)
+
+// ToolRequirements describes the Portainer servers able to serve a tool.
+// Versions are written without the "v" prefix, such as 2.37.0. A maximum
+// version without a patch number, such as 2.36, accepts any patch release.
+type ToolRequirements struct {
+	MinPortainerVersion string
+	MaxPortainerVersion string
+	// Edition is either EditionCE or EditionBE, empty when the tool is
+	// available in both editions
+	Edition string
+}
+
+// ParameterDefinition represents a tool parameter in the YAML config.
+// The constraints follow their JSON Schema meaning and are enforced on the
+// tool arguments by a ParameterParser created with the schema of the tool.
 type ParameterDefinition struct {
-	Name        string         `yaml:"name"`
-	Type        string         `yaml:"type"`
-	Required    bool           `yaml:"required"`
+	Name        string         `yaml:"name,omitempty"`
+	Type        string         `yaml:"type,omitempty"`
+	Required    bool           `yaml:"required,omitempty"`
 	Enum        []string       `yaml:"enum,omitempty"`
-	Description string         `yaml:"description"`
+	Description string         `yaml:"description,omitempty"`
 	Items       map[string]any `yaml:"items,omitempty"`
+	// Default is the value of the parameter when the argument is missing
+	Default   any      `yaml:"default,omitempty"`
+	Minimum   *float64 `yaml:"minimum,omitempty"`
+	Maximum   *float64 `yaml:"maximum,omitempty"`
+	MinLength *int     `yaml:"minLength,omitempty"`
+	MaxLength *int     `yaml:"maxLength,omitempty"`
+	// Pattern is a regular expression a string argument must match
+	Pattern string `yaml:"pattern,omitempty"`
+	// Format is one of the formats checked by the parameter parser, such as
+	// date-time or uri, other formats are only documented in the schema
+	Format string `yaml:"format,omitempty"`
+	// Properties defines the properties of an object parameter
+	Properties []ParameterDefinition `yaml:"properties,omitempty"`
+	// OneOf lists alternative definitions of the parameter, the argument must
+	// match exactly one of them. Their names are ignored.
+	OneOf []ParameterDefinition `yaml:"oneOf,omitempty"`
 }
 
 // Annotations represents a tool annotations in the YAML config
@@ -43,49 +92,157 @@ type Annotations struct {
 	OpenWorldHint   bool   `yaml:"openWorldHint"`
 }
 
+// Check returns an error explaining why a Portainer server of the given
+// version and edition cannot serve the tool, or nil when it can.
+// The edition is not checked when it is empty.
+func (r ToolRequirements) Check(version, edition string) error {
+	// semver requires a "v" prefix
+	v := "v" + version
+	if !semver.IsValid(v) {
+		return fmt.Errorf("invalid Portainer server version: %s", version)
+	}
+
+	if r.MinPortainerVersion != "" && semver.Compare(v, "v"+r.MinPortainerVersion) < 0 {
+		return fmt.Errorf("requires Portainer %s or later, the server runs %s", r.MinPortainerVersion, version)
+	}
+
+	if r.MaxPortainerVersion != "" && exceedsMaxVersion(v, "v"+r.MaxPortainerVersion) {
+		return fmt.Errorf("requires Portainer %s or earlier, the server runs %s", r.MaxPortainerVersion, version)
+	}
+
+	if r.Edition != "" && edition != "" && r.Edition != edition {
+		return fmt.Errorf("requires Portainer %s, the server runs Portainer %s", r.Edition, edition)
+	}
+
+	return nil
+}
+
+// exceedsMaxVersion reports whether a version is above a maximum version.
+// A maximum version without a patch number only limits the minor version.
+func exceedsMaxVersion(version, max string) bool {
+	if strings.Count(max, ".") < 2 {
+		return semver.Compare(semver.MajorMinor(version), max) > 0
+	}
+	return semver.Compare(version, max) > 0
+}
+
 // LoadToolsFromYAML loads tool definitions from a YAML file
 // It returns the tools and the version of the tools.yaml file
 func LoadToolsFromYAML(filePath string, minimumVersion string) (map[string]mcp.Tool, error) {
+	tools, _, err := LoadToolsWithRequirementsFromYAML(filePath, minimumVersion)
+	return tools, err
+}
+
+// LoadToolsWithRequirementsFromYAML loads tool definitions from a YAML file,
+// along with the requirements of the tools restricted to some Portainer
+// versions or editions. Tools without requirements are not in the
+// requirements map.
+func LoadToolsWithRequirementsFromYAML(filePath string, minimumVersion string) (map[string]mcp.Tool, map[string]ToolRequirements, error) {
+	config, err := LoadToolsConfigFromYAML(filePath, minimumVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tools, requirements := ConvertToolsConfig(config)
+	return tools, requirements, nil
+}
+
+// LoadToolsConfigFromYAML loads the configuration of a YAML file, without
+// converting its tool definitions
+func LoadToolsConfigFromYAML(filePath string, minimumVersion string) (ToolsConfig, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, err
+		return ToolsConfig{}, err
 	}
 
+	return ParseToolsConfig(data, minimumVersion)
+}
+
+// ParseToolsConfig parses a YAML configuration and validates its version
+func ParseToolsConfig(data []byte, minimumVersion string) (ToolsConfig, error) {
 	var config ToolsConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, err
+		return ToolsConfig{}, err
 	}
 
 	if config.Version == "" {
-		return nil, fmt.Errorf("missing version in tools.yaml")
+		return ToolsConfig{}, fmt.Errorf("missing version in tools.yaml")
 	}
 
 	if !semver.IsValid(config.Version) {
-		return nil, fmt.Errorf("invalid version in tools.yaml: %s", config.Version)
+		return ToolsConfig{}, fmt.Errorf("invalid version in tools.yaml: %s", config.Version)
 	}
 
 	if semver.Compare(config.Version, minimumVersion) < 0 {
-		return nil, fmt.Errorf("tools.yaml version %s is below the minimum required version %s", config.Version, minimumVersion)
+		return ToolsConfig{}, fmt.Errorf("tools.yaml version %s is below the minimum required version %s", config.Version, minimumVersion)
 	}
 
-	return convertToolDefinitions(config.Tools), nil
+	return config, nil
+}
+
+// ConvertToolsConfig converts the tool definitions of a configuration to
+// mcp.Tool objects and collects their requirements. Invalid definitions are skipped.
+func ConvertToolsConfig(config ToolsConfig) (map[string]mcp.Tool, map[string]ToolRequirements) {
+	return convertToolDefinitions(config.Tools)
 }
 
 // convertToolDefinitions converts YAML tool definitions to mcp.Tool objects
-func convertToolDefinitions(defs []ToolDefinition) map[string]mcp.Tool {
+// and collects their requirements
+func convertToolDefinitions(defs []ToolDefinition) (map[string]mcp.Tool, map[string]ToolRequirements) {
 	tools := make(map[string]mcp.Tool, len(defs))
+	requirements := make(map[string]ToolRequirements)
 
 	for _, def := range defs {
+		if def.Disabled {
+			continue
+		}
+
 		tool, err := convertToolDefinition(def)
 		if err != nil {
 			log.Printf("skipping invalid tool definition %s: %s", def.Name, err)
 			continue
 		}
 
+		req, err := convertRequirements(def)
+		if err != nil {
+			log.Printf("skipping invalid tool definition %s: %s", def.Name, err)
+			continue
+		}
+
 		tools[def.Name] = tool
+		if req != (ToolRequirements{}) {
+			requirements[def.Name] = req
+		}
 	}
 
-	return tools
+	return tools, requirements
+}
+
+// convertRequirements validates and returns the requirements of a YAML tool definition
+func convertRequirements(def ToolDefinition) (ToolRequirements, error) {
+	req := ToolRequirements{
+		MinPortainerVersion: def.MinPortainerVersion,
+		MaxPortainerVersion: def.MaxPortainerVersion,
+		Edition:             def.Edition,
+	}
+
+	for _, version := range []string{req.MinPortainerVersion, req.MaxPortainerVersion} {
+		// semver requires a "v" prefix
+		if version != "" && !semver.IsValid("v"+version) {
+			return ToolRequirements{}, fmt.Errorf("invalid Portainer version %q for tool '%s'", version, def.Name)
+		}
+	}
+
+	if req.MinPortainerVersion != "" && req.MaxPortainerVersion != "" &&
+		exceedsMaxVersion("v"+req.MinPortainerVersion, "v"+req.MaxPortainerVersion) {
+		return ToolRequirements{}, fmt.Errorf("minimum Portainer version %s is above the maximum version %s for tool '%s'", req.MinPortainerVersion, req.MaxPortainerVersion, def.Name)
+	}
+
+	if req.Edition != "" && req.Edition != EditionCE && req.Edition != EditionBE {
+		return ToolRequirements{}, fmt.Errorf("invalid Portainer edition %q for tool '%s', must be %s or %s", req.Edition, def.Name, EditionCE, EditionBE)
+	}
+
+	return req, nil
 }
 
 // convertToolDefinition converts a single YAML tool definition to an mcp.Tool
@@ -108,11 +265,26 @@ func convertToolDefinition(def ToolDefinition) (mcp.Tool, error) {
 	}
 
 	for _, param := range def.Parameters {
+		if err := validateParameter(param); err != nil {
+			return mcp.Tool{}, fmt.Errorf("%w for tool '%s'", err, def.Name)
+		}
 		options = append(options, convertParameter(param))
 	}
 
 	options = append(options, convertAnnotation(def.Annotations))
 
+	if def.OutputSchema != nil {
+		if schemaType, _ := def.OutputSchema["type"].(string); schemaType != "object" {
+			return mcp.Tool{}, fmt.Errorf("output schema of tool '%s' must be of type object", def.Name)
+		}
+
+		schema, err := json.Marshal(jsonValue(def.OutputSchema))
+		if err != nil {
+			return mcp.Tool{}, fmt.Errorf("invalid output schema for tool '%s': %w", def.Name, err)
+		}
+		options = append(options, mcp.WithRawOutputSchema(schema))
+	}
+
 	return mcp.NewTool(def.Name, options...), nil
 }
 
@@ -129,35 +301,173 @@ func convertAnnotation(annotation Annotations) mcp.ToolOption {
 
 // convertParameter converts a YAML parameter definition to an mcp option
 func convertParameter(param ParameterDefinition) mcp.ToolOption {
-	var options []mcp.PropertyOption
+	return func(t *mcp.Tool) {
+		schema := parameterSchema(param)
+		// The description is always set on the tool parameters
+		schema["description"] = param.Description
 
-	options = append(options, mcp.Description(param.Description))
+		if param.Required {
+			t.InputSchema.Required = append(t.InputSchema.Required, param.Name)
+		}
 
-	if param.Required {
-		options = append(options, mcp.Required())
+		t.InputSchema.Properties[param.Name] = schema
+	}
+}
+
+// parameterSchema returns the JSON Schema of a YAML parameter definition
+func parameterSchema(param ParameterDefinition) map[string]any {
+	schema := map[string]any{}
+
+	switch param.Type {
+	case "string", "number", "integer", "boolean", "array", "object":
+		schema["type"] = param.Type
+	case "":
+		// A parameter defined by its alternatives has no type of its own
+		if len(param.OneOf) == 0 {
+			schema["type"] = "string"
+		}
+	default:
+		// Default to string if type is unknown
+		schema["type"] = "string"
+	}
+
+	if param.Description != "" {
+		schema["description"] = param.Description
 	}
 
 	if param.Enum != nil {
-		options = append(options, mcp.Enum(param.Enum...))
+		schema["enum"] = param.Enum
 	}
 
 	if len(param.Items) > 0 {
-		options = append(options, mcp.Items(param.Items))
+		schema["items"] = param.Items
 	}
 
-	switch param.Type {
-	case "string":
-		return mcp.WithString(param.Name, options...)
-	case "number":
-		return mcp.WithNumber(param.Name, options...)
-	case "boolean":
-		return mcp.WithBoolean(param.Name, options...)
-	case "array":
-		return mcp.WithArray(param.Name, options...)
-	case "object":
-		return mcp.WithObject(param.Name, options...)
+	if param.Default != nil {
+		schema["default"] = jsonValue(param.Default)
+	}
+
+	if param.Minimum != nil {
+		schema["minimum"] = *param.Minimum
+	}
+
+	if param.Maximum != nil {
+		schema["maximum"] = *param.Maximum
+	}
+
+	if param.MinLength != nil {
+		schema["minLength"] = *param.MinLength
+	}
+
+	if param.MaxLength != nil {
+		schema["maxLength"] = *param.MaxLength
+	}
+
+	if param.Pattern != "" {
+		schema["pattern"] = param.Pattern
+	}
+
+	if param.Format != "" {
+		schema["format"] = param.Format
+	}
+
+	if len(param.Properties) > 0 {
+		properties := make(map[string]any, len(param.Properties))
+		var required []string
+		for _, property := range param.Properties {
+			properties[property.Name] = parameterSchema(property)
+			if property.Required {
+				required = append(required, property.Name)
+			}
+		}
+
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	}
+
+	if len(param.OneOf) > 0 {
+		alternatives := make([]any, 0, len(param.OneOf))
+		for _, alternative := range param.OneOf {
+			alternatives = append(alternatives, parameterSchema(alternative))
+		}
+		schema["oneOf"] = alternatives
+	}
+
+	return schema
+}
+
+// validateParameter checks that the constraints of a YAML parameter
+// definition are consistent, including its default value
+func validateParameter(param ParameterDefinition) error {
+	if param.Pattern != "" {
+		if _, err := regexp.Compile(param.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern of parameter %s: %w", param.Name, err)
+		}
+	}
+
+	if param.Minimum != nil && param.Maximum != nil && *param.Minimum > *param.Maximum {
+		return fmt.Errorf("minimum of parameter %s is above its maximum", param.Name)
+	}
+
+	if (param.MinLength != nil && *param.MinLength < 0) || (param.MaxLength != nil && *param.MaxLength < 0) {
+		return fmt.Errorf("length limits of parameter %s must not be negative", param.Name)
+	}
+
+	if param.MinLength != nil && param.MaxLength != nil && *param.MinLength > *param.MaxLength {
+		return fmt.Errorf("minLength of parameter %s is above its maxLength", param.Name)
+	}
+
+	for _, property := range param.Properties {
+		if property.Name == "" {
+			return fmt.Errorf("property name is required for parameter %s", param.Name)
+		}
+		if err := validateParameter(property); err != nil {
+			return err
+		}
+	}
+
+	for _, alternative := range param.OneOf {
+		alternative.Name = param.Name
+		if err := validateParameter(alternative); err != nil {
+			return err
+		}
+	}
+
+	if param.Default != nil {
+		schema := parameterSchema(param)
+		if err := validateValue(param.Name, schema["default"], schema); err != nil {
+			return fmt.Errorf("invalid default value: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// jsonValue converts a value decoded from YAML to the types of a value
+// decoded from JSON, numbers becoming float64, as received in tool arguments
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case []any:
+		values := make([]any, len(v))
+		for i, item := range v {
+			values[i] = jsonValue(item)
+		}
+		return values
+	case map[string]any:
+		values := make(map[string]any, len(v))
+		for key, item := range v {
+			values[key] = jsonValue(item)
+		}
+		return values
 	default:
-		// Default to string if type is unknown
-		return mcp.WithString(param.Name, options...)
+		return value
 	}
 }
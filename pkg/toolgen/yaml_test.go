@@ -456,17 +456,34 @@ func TestConvertToolDefinitions(t *testing.T) {
 			},
 			want: 2, // Only 2 valid tools should be returned
 		},
+		{
+			name: "disabled tools are skipped",
+			defs: []ToolDefinition{
+				{
+					Name:        "tool1",
+					Description: "Test tool 1",
+					Annotations: validAnnotations,
+				},
+				{
+					Name:        "tool2",
+					Description: "Test tool 2",
+					Annotations: validAnnotations,
+					Disabled:    true,
+				},
+			},
+			want: 1,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := convertToolDefinitions(tt.defs)
+			got, _ := convertToolDefinitions(tt.defs)
 			assert.Len(t, got, tt.want)
 
 			// Verify each tool expected to be converted exists and is valid
 			for _, def := range tt.defs {
 				// Skip definitions that are expected to cause errors
-				if def.Name == "" || def.Description == "" || (def.Annotations == Annotations{}) {
+				if def.Name == "" || def.Description == "" || (def.Annotations == Annotations{}) || def.Disabled {
 					continue
 				}
 
@@ -482,6 +499,152 @@ func TestConvertToolDefinitions(t *testing.T) {
 	}
 }
 
+func TestConvertRequirements(t *testing.T) {
+	validAnnotations := Annotations{Title: "Valid Title", ReadOnlyHint: true}
+
+	tests := []struct {
+		name             string
+		def              ToolDefinition
+		wantRequirements map[string]ToolRequirements
+		wantTools        int
+	}{
+		{
+			name:             "no requirements",
+			def:              ToolDefinition{Name: "tool1", Description: "Test tool", Annotations: validAnnotations},
+			wantRequirements: map[string]ToolRequirements{},
+			wantTools:        1,
+		},
+		{
+			name: "version range and edition",
+			def: ToolDefinition{
+				Name:                "tool1",
+				Description:         "Test tool",
+				Annotations:         validAnnotations,
+				MinPortainerVersion: "2.36.1",
+				MaxPortainerVersion: "2.36",
+				Edition:             EditionBE,
+			},
+			wantRequirements: map[string]ToolRequirements{
+				"tool1": {MinPortainerVersion: "2.36.1", MaxPortainerVersion: "2.36", Edition: EditionBE},
+			},
+			wantTools: 1,
+		},
+		{
+			name: "invalid version is skipped",
+			def: ToolDefinition{
+				Name:                "tool1",
+				Description:         "Test tool",
+				Annotations:         validAnnotations,
+				MinPortainerVersion: "v2.37",
+			},
+			wantRequirements: map[string]ToolRequirements{},
+		},
+		{
+			name: "minimum above maximum is skipped",
+			def: ToolDefinition{
+				Name:                "tool1",
+				Description:         "Test tool",
+				Annotations:         validAnnotations,
+				MinPortainerVersion: "2.37.0",
+				MaxPortainerVersion: "2.36.5",
+			},
+			wantRequirements: map[string]ToolRequirements{},
+		},
+		{
+			name: "invalid edition is skipped",
+			def: ToolDefinition{
+				Name:        "tool1",
+				Description: "Test tool",
+				Annotations: validAnnotations,
+				Edition:     "EE",
+			},
+			wantRequirements: map[string]ToolRequirements{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools, requirements := convertToolDefinitions([]ToolDefinition{tt.def})
+			assert.Len(t, tools, tt.wantTools)
+			assert.Equal(t, tt.wantRequirements, requirements)
+		})
+	}
+}
+
+func TestToolRequirementsCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		requirements  ToolRequirements
+		version       string
+		edition       string
+		errorContains string
+	}{
+		{
+			name:         "no requirements",
+			requirements: ToolRequirements{},
+			version:      "2.27.0",
+			edition:      EditionCE,
+		},
+		{
+			name:          "below minimum version",
+			requirements:  ToolRequirements{MinPortainerVersion: "2.37.0"},
+			version:       "2.33.4",
+			errorContains: "requires Portainer 2.37.0 or later, the server runs 2.33.4",
+		},
+		{
+			name:         "minimum version",
+			requirements: ToolRequirements{MinPortainerVersion: "2.37.0"},
+			version:      "2.37.0",
+		},
+		{
+			name:         "patch release of the maximum minor version",
+			requirements: ToolRequirements{MaxPortainerVersion: "2.36"},
+			version:      "2.36.3",
+		},
+		{
+			name:          "above maximum version",
+			requirements:  ToolRequirements{MaxPortainerVersion: "2.36"},
+			version:       "2.37.0",
+			errorContains: "requires Portainer 2.36 or earlier, the server runs 2.37.0",
+		},
+		{
+			name:          "above maximum patch version",
+			requirements:  ToolRequirements{MaxPortainerVersion: "2.36.1"},
+			version:       "2.36.2",
+			errorContains: "requires Portainer 2.36.1 or earlier",
+		},
+		{
+			name:          "other edition",
+			requirements:  ToolRequirements{Edition: EditionBE},
+			version:       "2.37.0",
+			edition:       EditionCE,
+			errorContains: "requires Portainer BE, the server runs Portainer CE",
+		},
+		{
+			name:         "unknown edition",
+			requirements: ToolRequirements{Edition: EditionBE},
+			version:      "2.37.0",
+		},
+		{
+			name:          "invalid server version",
+			requirements:  ToolRequirements{MinPortainerVersion: "2.37.0"},
+			version:       "develop",
+			errorContains: "invalid Portainer server version: develop",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.requirements.Check(tt.version, tt.edition)
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+}
+
 func TestConvertParameter(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -583,7 +746,209 @@ func TestConvertParameter(t *testing.T) {
 	}
 }
 
+func TestConvertParameterConstraints(t *testing.T) {
+	minimum, maximum := 1.0, 100.0
+	minLength, maxLength := 1, 64
+
+	tool := mcp.NewTool("testTool",
+		convertParameter(ParameterDefinition{
+			Name:        "limit",
+			Type:        "integer",
+			Description: "The maximum number of items",
+			Default:     20,
+			Minimum:     &minimum,
+			Maximum:     &maximum,
+		}),
+		convertParameter(ParameterDefinition{
+			Name:      "path",
+			Type:      "string",
+			Required:  true,
+			MinLength: &minLength,
+			MaxLength: &maxLength,
+			Pattern:   "^/",
+		}),
+		convertParameter(ParameterDefinition{
+			Name: "target",
+			Type: "object",
+			Properties: []ParameterDefinition{
+				{Name: "url", Type: "string", Format: "uri", Required: true},
+				{Name: "port", Type: "number"},
+			},
+		}),
+		convertParameter(ParameterDefinition{
+			Name: "id",
+			OneOf: []ParameterDefinition{
+				{Type: "number"},
+				{Type: "string", Pattern: "^[a-z]+$"},
+			},
+		}),
+	)
+
+	assert.Equal(t, []string{"path"}, tool.InputSchema.Required)
+	assert.Equal(t, map[string]any{
+		"type":        "integer",
+		"description": "The maximum number of items",
+		"default":     20.0,
+		"minimum":     1.0,
+		"maximum":     100.0,
+	}, tool.InputSchema.Properties["limit"])
+	assert.Equal(t, map[string]any{
+		"type":        "string",
+		"description": "",
+		"minLength":   1,
+		"maxLength":   64,
+		"pattern":     "^/",
+	}, tool.InputSchema.Properties["path"])
+	assert.Equal(t, map[string]any{
+		"type":        "object",
+		"description": "",
+		"properties": map[string]any{
+			"url":  map[string]any{"type": "string", "format": "uri"},
+			"port": map[string]any{"type": "number"},
+		},
+		"required": []string{"url"},
+	}, tool.InputSchema.Properties["target"])
+	assert.Equal(t, map[string]any{
+		"description": "",
+		"oneOf": []any{
+			map[string]any{"type": "number"},
+			map[string]any{"type": "string", "pattern": "^[a-z]+$"},
+		},
+	}, tool.InputSchema.Properties["id"])
+}
+
+func TestValidateParameter(t *testing.T) {
+	minimum, maximum := 10.0, 1.0
+	minLength, maxLength := 5, 2
+
+	tests := []struct {
+		name          string
+		param         ParameterDefinition
+		errorContains string
+	}{
+		{
+			name:  "valid constraints",
+			param: ParameterDefinition{Name: "path", Type: "string", Pattern: "^/", Default: "/"},
+		},
+		{
+			name:          "invalid pattern",
+			param:         ParameterDefinition{Name: "path", Type: "string", Pattern: "(["},
+			errorContains: "invalid pattern of parameter path",
+		},
+		{
+			name:          "minimum above maximum",
+			param:         ParameterDefinition{Name: "limit", Type: "number", Minimum: &minimum, Maximum: &maximum},
+			errorContains: "minimum of parameter limit is above its maximum",
+		},
+		{
+			name:          "minLength above maxLength",
+			param:         ParameterDefinition{Name: "name", Type: "string", MinLength: &minLength, MaxLength: &maxLength},
+			errorContains: "minLength of parameter name is above its maxLength",
+		},
+		{
+			name:          "default violating the constraints",
+			param:         ParameterDefinition{Name: "path", Type: "string", Pattern: "^/", Default: "containers"},
+			errorContains: "invalid default value: path must match the pattern ^/",
+		},
+		{
+			name:          "default of the wrong type",
+			param:         ParameterDefinition{Name: "limit", Type: "number", Default: "ten"},
+			errorContains: "invalid default value: limit must be a number",
+		},
+		{
+			name: "invalid nested property",
+			param: ParameterDefinition{Name: "target", Type: "object", Properties: []ParameterDefinition{
+				{Name: "url", Type: "string", Pattern: "(["},
+			}},
+			errorContains: "invalid pattern of parameter url",
+		},
+		{
+			name: "nested property without name",
+			param: ParameterDefinition{Name: "target", Type: "object", Properties: []ParameterDefinition{
+				{Type: "string"},
+			}},
+			errorContains: "property name is required for parameter target",
+		},
+		{
+			name: "invalid alternative",
+			param: ParameterDefinition{Name: "id", OneOf: []ParameterDefinition{
+				{Type: "string", Pattern: "(["},
+			}},
+			errorContains: "invalid pattern of parameter id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParameter(tt.param)
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+}
+
+func TestConvertToolDefinitionsSkipsInvalidParameters(t *testing.T) {
+	annotations := Annotations{Title: "Title", ReadOnlyHint: true}
+	defs := []ToolDefinition{
+		{
+			Name:        "validTool",
+			Description: "A valid tool",
+			Annotations: annotations,
+			Parameters:  []ParameterDefinition{{Name: "path", Type: "string", Pattern: "^/"}},
+		},
+		{
+			Name:        "invalidTool",
+			Description: "A tool with an invalid pattern",
+			Annotations: annotations,
+			Parameters:  []ParameterDefinition{{Name: "path", Type: "string", Pattern: "(["}},
+		},
+	}
+
+	tools, _ := convertToolDefinitions(defs)
+
+	assert.Contains(t, tools, "validTool")
+	assert.NotContains(t, tools, "invalidTool")
+}
+
 // Optional: Add a specific test for convertAnnotation if desired, though it's simple
+func TestConvertToolOutputSchema(t *testing.T) {
+	annotations := Annotations{Title: "Test Tool", ReadOnlyHint: true}
+
+	tool, err := convertToolDefinition(ToolDefinition{
+		Name:        "testTool",
+		Description: "A test tool",
+		Annotations: annotations,
+		OutputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":   map[string]any{"type": "integer", "minimum": 1},
+				"tags": map[string]any{"type": []any{"array", "null"}},
+			},
+			"required": []any{"id"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"tags": {"type": ["array", "null"]}
+		},
+		"required": ["id"]
+	}`, string(tool.RawOutputSchema))
+
+	_, err = convertToolDefinition(ToolDefinition{
+		Name:         "testTool",
+		Description:  "A test tool",
+		Annotations:  annotations,
+		OutputSchema: map[string]any{"type": "array"},
+	})
+	assert.EqualError(t, err, "output schema of tool 'testTool' must be of type object")
+}
+
 func TestConvertAnnotation(t *testing.T) {
 	input := Annotations{
 		Title:           "Test Title",
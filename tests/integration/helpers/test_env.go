@@ -7,6 +7,7 @@ import (
 
 	"github.com/portainer/client-api-go/v2/client"
 	"github.com/portainer/portainer-mcp/internal/mcp"
+	portainerclient "github.com/portainer/portainer-mcp/pkg/portainer/client"
 	"github.com/portainer/portainer-mcp/tests/integration/containers"
 	"github.com/stretchr/testify/require"
 )
@@ -39,7 +40,10 @@ func NewTestEnv(t *testing.T, opts ...containers.PortainerContainerOption) *Test
 		client.WithSkipTLSVerify(true),
 	)
 
-	mcpServer, err := mcp.NewPortainerMCPServer(serverURL, portainer.GetAPIToken(), ToolsPath)
+	// The Portainer container uses a self-signed certificate
+	mcpServer, err := mcp.NewPortainerMCPServer(serverURL, portainer.GetAPIToken(), ToolsPath,
+		mcp.WithClientOptions(portainerclient.WithSkipTLSVerify(true)),
+	)
 	require.NoError(t, err, "Failed to create MCP server")
 
 	return &TestEnv{
@@ -7,6 +7,7 @@ import (
 
 	mcpmodels "github.com/mark3labs/mcp-go/mcp"
 	"github.com/portainer/portainer-mcp/internal/mcp"
+	"github.com/portainer/portainer-mcp/pkg/portainer/client"
 	"github.com/portainer/portainer-mcp/tests/integration/containers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,6 +18,9 @@ const (
 	unsupportedImage = "portainer/portainer-ee:2.29.1" // Older version than SupportedPortainerVersion
 )
 
+// insecureClient skips the verification of the self-signed certificate of the Portainer container
+var insecureClient = mcp.WithClientOptions(client.WithSkipTLSVerify(true))
+
 // TestServerInitialization verifies that the Portainer MCP server
 // can be successfully initialized with a real Portainer instance.
 func TestServerInitialization(t *testing.T) {
@@ -39,7 +43,7 @@ func TestServerInitialization(t *testing.T) {
 	apiToken := portainer.GetAPIToken()
 
 	// Create the MCP server - this is the main test objective
-	mcpServer, err := mcp.NewPortainerMCPServer(serverURL, apiToken, toolsPath)
+	mcpServer, err := mcp.NewPortainerMCPServer(serverURL, apiToken, toolsPath, insecureClient)
 
 	// Assert the server was created successfully
 	require.NoError(t, err, "Failed to create MCP server")
@@ -68,7 +72,7 @@ func TestServerInitializationUnsupportedVersion(t *testing.T) {
 	apiToken := portainer.GetAPIToken()
 
 	// Try to create the MCP server - should fail with version error
-	mcpServer, err := mcp.NewPortainerMCPServer(serverURL, apiToken, toolsPath)
+	mcpServer, err := mcp.NewPortainerMCPServer(serverURL, apiToken, toolsPath, insecureClient)
 
 	// Assert the server creation failed with correct error
 	assert.Error(t, err, "Server creation should fail with unsupported version")
@@ -98,7 +102,7 @@ func TestServerInitializationDisabledVersionCheck(t *testing.T) {
 	apiToken := portainer.GetAPIToken()
 
 	// Create the MCP server with disabled version check - should succeed despite unsupported version
-	mcpServer, err := mcp.NewPortainerMCPServer(serverURL, apiToken, toolsPath, mcp.WithDisableVersionCheck(true))
+	mcpServer, err := mcp.NewPortainerMCPServer(serverURL, apiToken, toolsPath, insecureClient, mcp.WithDisableVersionCheck(true))
 
 	// Assert the server was created successfully
 	require.NoError(t, err, "Failed to create MCP server with disabled version check")